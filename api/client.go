@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -18,6 +19,10 @@ var (
 	TimeoutInSeconds = 60
 	// HTTPClient is the client used to make HTTP calls in the cli package.
 	HTTPClient = &http.Client{Timeout: time.Duration(TimeoutInSeconds) * time.Second}
+
+	// Context bounds every request made with a Client.
+	// It's overridden from the root command when the whole command has a deadline.
+	Context = context.Background()
 )
 
 // Client is an http client that is configured for Exercism.
@@ -65,7 +70,7 @@ func (c *Client) NewRequest(method, url string, body io.Reader) (*http.Request,
 func (c *Client) Do(req *http.Request) (*http.Response, error) {
 	debug.DumpRequest(req)
 
-	res, err := c.Client.Do(req)
+	res, err := c.Client.Do(req.WithContext(Context))
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -79,3 +80,24 @@ func TestDo(t *testing.T) {
 
 	assert.Equal(t, "world", body.Hello)
 }
+
+func TestDoStopsWhenContextIsDone(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `ok`)
+	}))
+	defer ts.Close()
+
+	oldContext := Context
+	defer func() { Context = oldContext }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	Context = ctx
+
+	client := &Client{}
+	req, err := client.NewRequest("GET", ts.URL, nil)
+	assert.NoError(t, err)
+
+	_, err = client.Do(req)
+	assert.Error(t, err)
+}
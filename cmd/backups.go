@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/exercism/cli/config"
+	"github.com/exercism/cli/snapshot"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// backupsCmd manages the backups taken before files are overwritten or removed.
+var backupsCmd = &cobra.Command{
+	Use:     "backups",
+	Aliases: []string{"snapshots"},
+	Short:   "List, restore and purge backups of overwritten files.",
+	Long: `List, restore and purge the backups of your files that are taken before
+they are overwritten or removed, e.g. by 'exercism download --force' or
+'exercism download --undo'.
+
+Each backup is a snapshot of the files at the time. Identical files are only
+stored once. Backups are kept independently of git, in one directory, which
+can be changed with the backups.dir setting in your user config.
+
+Old backups are discarded automatically:
+
+    backups.max_size_mb    the most space backups take up (100 by default)
+    backups.max_age_days   how long backups are kept (forever by default)
+
+    exercism backups list
+    exercism backups restore 20261015-183000-1a2b3c4d
+    exercism backups purge --older-than=7d
+`,
+}
+
+var backupsListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List the backups, newest first.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBackupsList(loadBackupsConfig())
+	},
+}
+
+var backupsRestoreCmd = &cobra.Command{
+	Use:   "restore ID",
+	Short: "Put the files of a backup back where they were.",
+	Long: `Put the files of a backup back where they were.
+
+The ID may be shortened, as long as only one backup starts with it.
+Any of the files that have changed since are themselves backed up first.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBackupsRestore(loadBackupsConfig(), args)
+	},
+}
+
+var backupsPurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Remove backups.",
+	Long: `Remove all backups, or with --older-than, only those older than the given age.
+
+The age is a number of days, e.g. 7d, or a duration such as 12h.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBackupsPurge(loadBackupsConfig(), cmd.Flags())
+	},
+}
+
+func loadBackupsConfig() config.Config {
+	cfg := config.NewConfig()
+
+	v := viper.New()
+	v.AddConfigPath(cfg.Dir)
+	v.SetConfigName("user")
+	v.SetConfigType("json")
+	// Ignore error. If the file doesn't exist, that is fine.
+	_ = v.ReadInConfig()
+	config.BindUserEnv(v)
+	cfg.UserViperConfig = v
+	return cfg
+}
+
+// backupsDir is where all backups are kept.
+// It is blank if it isn't configured and there is no state directory.
+func backupsDir(cfg config.Config) string {
+	if cfg.UserViperConfig != nil {
+		if dir := cfg.UserViperConfig.GetString("backups.dir"); dir != "" {
+			return dir
+		}
+	}
+	if cfg.StateDir == "" {
+		return ""
+	}
+	return filepath.Join(cfg.StateDir, "backups")
+}
+
+// snapshotStore is where backups are kept, with the configured retention.
+// It is nil if there is nowhere to keep them.
+func snapshotStore(cfg config.Config) *snapshot.Store {
+	dir := backupsDir(cfg)
+	if dir == "" {
+		return nil
+	}
+	var maxSize int64
+	var maxAge time.Duration
+	if v := cfg.UserViperConfig; v != nil {
+		maxSize = v.GetInt64("backups.max_size_mb") * 1024 * 1024
+		maxAge = time.Duration(v.GetInt64("backups.max_age_days")) * 24 * time.Hour
+	}
+	store := snapshot.New(dir, maxSize, maxAge)
+	return &store
+}
+
+var errNoBackupsDir = errors.New("there is nowhere to keep backups, set backups.dir in your user config")
+
+func runBackupsList(cfg config.Config) error {
+	store := snapshotStore(cfg)
+	if store == nil {
+		return errNoBackupsDir
+	}
+	snaps, err := store.List()
+	if err != nil {
+		return err
+	}
+	if len(snaps) == 0 {
+		fmt.Fprintln(Err, "There are no backups.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTAKEN\tFILES\tSIZE\tREASON\tDIRECTORY")
+	for _, snap := range snaps {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\n",
+			snap.ID,
+			snap.CreatedAt.Local().Format("2006-01-02 15:04"),
+			len(snap.Files),
+			humanSize(snap.Size()),
+			snap.Reason,
+			snap.Dir,
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(Err, "\n%d backup(s) in %s\n", len(snaps), store.Dir)
+	return nil
+}
+
+func runBackupsRestore(cfg config.Config, args []string) error {
+	store := snapshotStore(cfg)
+	if store == nil {
+		return errNoBackupsDir
+	}
+	snap, err := store.Get(args[0])
+	if err == snapshot.ErrNotFound {
+		return fmt.Errorf("no backup with the ID '%s', see '%s backups list'", args[0], BinaryName)
+	}
+	if err != nil {
+		return err
+	}
+
+	paths := make([]string, len(snap.Files))
+	for i, f := range snap.Files {
+		paths[i] = filepath.FromSlash(f.Path)
+	}
+	// Restoring overwrites the files too, so keep what is there now.
+	// Files that are unchanged since the backup are stored only once.
+	current, err := store.Take(snap.Dir, paths, fmt.Sprintf("backups restore %s", snap.ID))
+	if err != nil {
+		return fmt.Errorf("unable to back up the files before restoring: %s", err)
+	}
+
+	if err := store.Restore(*snap); err != nil {
+		return err
+	}
+	fmt.Fprintf(Err, "Restored %d file(s) to\n", len(snap.Files))
+	fmt.Fprintf(Out, "%s\n", snap.Dir)
+	if current != nil {
+		fmt.Fprintf(Err, "The files they replaced are in backup %s\n", current.ID)
+	}
+	return nil
+}
+
+func runBackupsPurge(cfg config.Config, flags *pflag.FlagSet) error {
+	store := snapshotStore(cfg)
+	if store == nil {
+		return errNoBackupsDir
+	}
+	olderThan, err := flags.GetString("older-than")
+	if err != nil {
+		return err
+	}
+
+	before := time.Now().Add(time.Second)
+	if olderThan != "" {
+		age, err := parseAge(olderThan)
+		if err != nil {
+			return err
+		}
+		before = time.Now().Add(-age)
+	}
+
+	n, err := store.Purge(before)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(Err, "Removed %d backup(s).\n", n)
+	return nil
+}
+
+// parseAge reads an age given in days, e.g. 7d, or as a duration, e.g. 12h.
+func parseAge(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err == nil && days >= 0 {
+			return time.Duration(days) * 24 * time.Hour, nil
+		}
+	}
+	age, err := time.ParseDuration(s)
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("cannot understand the age '%s', use e.g. 7d or 12h", s)
+	}
+	return age, nil
+}
+
+// humanSize formats a number of bytes for people to read.
+func humanSize(n int64) string {
+	switch {
+	case n >= 1024*1024:
+		return fmt.Sprintf("%.1fM", float64(n)/(1024*1024))
+	case n >= 1024:
+		return fmt.Sprintf("%.1fK", float64(n)/1024)
+	}
+	return fmt.Sprintf("%dB", n)
+}
+
+func setupBackupsPurgeFlags(flags *pflag.FlagSet) {
+	flags.String("older-than", "", "only remove backups older than this, e.g. 7d")
+}
+
+func init() {
+	RootCmd.AddCommand(backupsCmd)
+	backupsCmd.AddCommand(backupsListCmd, backupsRestoreCmd, backupsPurgeCmd)
+	setupBackupsPurgeFlags(backupsPurgeCmd.Flags())
+}
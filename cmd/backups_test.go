@@ -0,0 +1,466 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/exercism/cli/config"
+	"github.com/exercism/cli/workspace"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownloadForceKeepsSnapshot(t *testing.T) {
+	co := newCapturedOutput()
+	co.newOut = &bytes.Buffer{}
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	tmpDir, err := ioutil.TempDir("", "download-force")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	ts := fakeDownloadServer("true", "")
+	defer ts.Close()
+
+	v := viper.New()
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	v.Set("token", "abc123")
+	cfg := config.Config{
+		UserViperConfig: v,
+		StateDir:        filepath.Join(tmpDir, "state"),
+	}
+
+	download := func(force bool) error {
+		flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+		setupDownloadFlags(flags)
+		flags.Set("exercise", "bogus-exercise")
+		if force {
+			flags.Set("force", "true")
+		}
+		return runDownload(cfg, flags, []string{})
+	}
+
+	assert.NoError(t, download(false))
+
+	// Downloading again is fine as long as nothing has changed.
+	assert.NoError(t, download(false))
+
+	path := filepath.Join(tmpDir, "bogus-track", "bogus-exercise", "file-1.txt")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("my solution"), os.FileMode(0644)))
+
+	err = download(false)
+	if assert.Error(t, err) {
+		assert.Regexp(t, "file-1.txt", err.Error())
+		assert.Regexp(t, "--force", err.Error())
+	}
+	b, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "my solution", string(b))
+
+	assert.NoError(t, download(true))
+	b, err = ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "this is file 1", string(b))
+
+	Out.(*bytes.Buffer).Reset()
+	assert.NoError(t, runBackupsList(cfg))
+	lines := strings.Split(strings.TrimSpace(Out.(*bytes.Buffer).String()), "\n")
+	if assert.Len(t, lines, 2) {
+		assert.Regexp(t, "download bogus-track/bogus-exercise", lines[1])
+		id := strings.Fields(lines[1])[0]
+
+		assert.NoError(t, runBackupsRestore(cfg, []string{id}))
+		b, err = ioutil.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, "my solution", string(b))
+		assert.Regexp(t, "The files they replaced are in backup", Err.(*bytes.Buffer).String())
+	}
+
+	err = runBackupsRestore(cfg, []string{"bogus"})
+	if assert.Error(t, err) {
+		assert.Regexp(t, "no backup with the ID 'bogus'", err.Error())
+	}
+}
+
+func TestHumanSize(t *testing.T) {
+	assert.Equal(t, "512B", humanSize(512))
+	assert.Equal(t, "1.5K", humanSize(1536))
+	assert.Equal(t, "2.0M", humanSize(2*1024*1024))
+}
+
+func TestDownloadUndo(t *testing.T) {
+	co := newCapturedOutput()
+	co.newOut = &bytes.Buffer{}
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	tmpDir, err := ioutil.TempDir("", "download-undo")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	ts := fakeDownloadServer("true", "")
+	defer ts.Close()
+
+	v := viper.New()
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	v.Set("token", "abc123")
+	cfg := config.Config{
+		UserViperConfig: v,
+		StateDir:        filepath.Join(tmpDir, "state"),
+	}
+
+	run := func(flagValues map[string]string) error {
+		flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+		setupDownloadFlags(flags)
+		for name, value := range flagValues {
+			flags.Set(name, value)
+		}
+		return runDownload(cfg, flags, []string{})
+	}
+	undo := map[string]string{"undo": "true"}
+
+	err = run(undo)
+	if assert.Error(t, err) {
+		assert.Regexp(t, "no download to undo", err.Error())
+	}
+
+	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
+	path := filepath.Join(dir, "file-1.txt")
+
+	// A fresh download is undone entirely.
+	assert.NoError(t, run(map[string]string{"exercise": "bogus-exercise"}))
+	assert.NoError(t, run(undo))
+	_, err = os.Stat(dir)
+	assert.True(t, os.IsNotExist(err), "expected the exercise directory to be removed")
+
+	// A forced download is undone by restoring what it overwrote.
+	assert.NoError(t, run(map[string]string{"exercise": "bogus-exercise"}))
+	assert.NoError(t, ioutil.WriteFile(path, []byte("my solution"), os.FileMode(0644)))
+	notes := filepath.Join(dir, "notes.txt")
+	assert.NoError(t, ioutil.WriteFile(notes, []byte("my notes"), os.FileMode(0644)))
+	assert.NoError(t, os.Remove(filepath.Join(dir, "subdir", "file-2.txt")))
+
+	assert.NoError(t, run(map[string]string{"exercise": "bogus-exercise", "force": "true"}))
+	assert.NoError(t, run(undo))
+
+	b, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "my solution", string(b))
+	_, err = os.Stat(filepath.Join(dir, "subdir"))
+	assert.True(t, os.IsNotExist(err), "expected the file the download added to be removed")
+	_, err = os.Stat(notes)
+	assert.NoError(t, err)
+
+	// Undoing again reverts the first download, leaving the files that were added since.
+	assert.NoError(t, run(undo))
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(notes)
+	assert.NoError(t, err)
+	assert.Regexp(t, "kept in backup", Err.(*bytes.Buffer).String())
+
+	// Files that were only updated are restored as well, along with their checksums and originals.
+	assert.NoError(t, run(map[string]string{"exercise": "bogus-exercise"}))
+	older := []byte("an older version of file 1")
+	assert.NoError(t, ioutil.WriteFile(path, older, os.FileMode(0644)))
+	checksums, err := workspace.NewChecksums(dir)
+	assert.NoError(t, err)
+	checksums.Set("file-1.txt", older)
+	assert.NoError(t, checksums.Write(dir))
+	assert.NoError(t, workspace.WriteOriginal(dir, "file-1.txt", older))
+
+	assert.NoError(t, run(map[string]string{"exercise": "bogus-exercise"}))
+	b, err = ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "this is file 1", string(b))
+
+	assert.NoError(t, run(undo))
+	b, err = ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, string(older), string(b))
+	checksums, err = workspace.NewChecksums(dir)
+	assert.NoError(t, err)
+	assert.False(t, checksums.IsModified("file-1.txt", older))
+	original, err := workspace.ReadOriginal(dir, "file-1.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, string(older), string(original))
+}
+
+func TestBackupsDir(t *testing.T) {
+	v := viper.New()
+	cfg := config.Config{UserViperConfig: v, StateDir: "/state"}
+	assert.Equal(t, filepath.Join("/state", "backups"), backupsDir(cfg))
+
+	v.Set("backups.dir", "/elsewhere")
+	assert.Equal(t, "/elsewhere", backupsDir(cfg))
+
+	v.Set("backups.max_size_mb", 5)
+	v.Set("backups.max_age_days", 2)
+	store := snapshotStore(cfg)
+	assert.Equal(t, int64(5*1024*1024), store.MaxSize)
+	assert.Equal(t, 48*time.Hour, store.MaxAge)
+
+	assert.Nil(t, snapshotStore(config.Config{UserViperConfig: viper.New()}))
+}
+
+func TestParseAge(t *testing.T) {
+	age, err := parseAge("7d")
+	assert.NoError(t, err)
+	assert.Equal(t, 7*24*time.Hour, age)
+
+	age, err = parseAge("12h")
+	assert.NoError(t, err)
+	assert.Equal(t, 12*time.Hour, age)
+
+	for _, s := range []string{"week", "-1d", "-3h", "d"} {
+		_, err := parseAge(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestBackupsPurge(t *testing.T) {
+	co := newCapturedOutput()
+	co.override()
+	defer co.reset()
+
+	tmpDir, err := ioutil.TempDir("", "backups-purge")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	cfg := config.Config{UserViperConfig: viper.New(), StateDir: tmpDir}
+	store := snapshotStore(cfg)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(tmpDir, "main.go"), []byte("package main"), os.FileMode(0644)))
+	_, err = store.Take(tmpDir, []string{"main.go"}, "testing")
+	assert.NoError(t, err)
+
+	purge := func(olderThan string) {
+		flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+		setupBackupsPurgeFlags(flags)
+		if olderThan != "" {
+			flags.Set("older-than", olderThan)
+		}
+		assert.NoError(t, runBackupsPurge(cfg, flags))
+	}
+
+	purge("1d")
+	snaps, err := store.List()
+	assert.NoError(t, err)
+	assert.Len(t, snaps, 1)
+
+	purge("")
+	snaps, err = store.List()
+	assert.NoError(t, err)
+	assert.Empty(t, snaps)
+}
+
+func TestDownloadConflictResolution(t *testing.T) {
+	co := newCapturedOutput()
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	oldIn := In
+	defer func() { In = oldIn }()
+
+	tmpDir, err := ioutil.TempDir("", "download-conflicts")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	ts := fakeDownloadServer("true", "")
+	defer ts.Close()
+
+	v := viper.New()
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	v.Set("token", "abc123")
+	cfg := config.Config{
+		UserViperConfig: v,
+		StateDir:        filepath.Join(tmpDir, "state"),
+	}
+
+	run := func(flagValues map[string]string) error {
+		flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+		setupDownloadFlags(flags)
+		flags.Set("exercise", "bogus-exercise")
+		for name, value := range flagValues {
+			flags.Set(name, value)
+		}
+		return runDownload(cfg, flags, []string{})
+	}
+
+	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
+	file1 := filepath.Join(dir, "file-1.txt")
+	file2 := filepath.Join(dir, "subdir", "file-2.txt")
+	write := func(path, contents string) {
+		assert.NoError(t, ioutil.WriteFile(path, []byte(contents), os.FileMode(0644)))
+	}
+	read := func(path string) string {
+		b, err := ioutil.ReadFile(path)
+		assert.NoError(t, err)
+		return string(b)
+	}
+
+	assert.NoError(t, run(nil))
+
+	t.Run("files unchanged since the download are updated", func(t *testing.T) {
+		// Pretend the website's version changed since the last download.
+		write(file1, "an older version of file 1")
+		checksums, err := workspace.NewChecksums(dir)
+		assert.NoError(t, err)
+		checksums.Set("file-1.txt", []byte("an older version of file 1"))
+		assert.NoError(t, checksums.Write(dir))
+
+		assert.NoError(t, run(nil))
+		assert.Equal(t, "this is file 1", read(file1))
+	})
+
+	t.Run("resolutions can't be combined", func(t *testing.T) {
+		err := run(map[string]string{"ours": "true", "theirs": "true"})
+		if assert.Error(t, err) {
+			assert.Regexp(t, "only one of", err.Error())
+		}
+		assert.NoError(t, run(map[string]string{"force": "true", "theirs": "true"}))
+	})
+
+	t.Run("ours keeps local changes", func(t *testing.T) {
+		write(file1, "my file 1")
+		assert.NoError(t, run(map[string]string{"ours": "true"}))
+		assert.Equal(t, "my file 1", read(file1))
+
+		// The file still counts as changed locally.
+		err := run(nil)
+		if assert.Error(t, err) {
+			assert.Regexp(t, "file-1.txt", err.Error())
+		}
+	})
+
+	t.Run("interactive asks for each file", func(t *testing.T) {
+		write(file1, "my file 1")
+		write(file2, "my file 2")
+
+		In = strings.NewReader("what?\nm\nt\n")
+		assert.NoError(t, run(map[string]string{"interactive": "true"}))
+		assert.Equal(t, "my file 1", read(file1))
+		assert.Equal(t, "this is file 2", read(file2))
+
+		In = strings.NewReader("a\n")
+		err := run(map[string]string{"interactive": "true"})
+		if assert.Error(t, err) {
+			assert.Regexp(t, "aborted", err.Error())
+		}
+		assert.Equal(t, "my file 1", read(file1))
+	})
+
+	t.Run("on-conflict stands for the other resolutions", func(t *testing.T) {
+		write(file1, "my file 1")
+		assert.NoError(t, run(map[string]string{"on-conflict": "skip"}))
+		assert.Equal(t, "my file 1", read(file1))
+
+		assert.NoError(t, run(map[string]string{"on-conflict": "backup"}))
+		assert.Equal(t, "this is file 1", read(file1))
+		assert.Regexp(t, "backups restore", Err.(*bytes.Buffer).String())
+
+		// Without anywhere to keep the backup, nothing is overwritten.
+		write(file1, "my file 1")
+		stateDir := cfg.StateDir
+		cfg.StateDir = ""
+		err := run(map[string]string{"on-conflict": "backup"})
+		cfg.StateDir = stateDir
+		assert.Equal(t, errNoBackupsDir, err)
+		assert.Equal(t, "my file 1", read(file1))
+		assert.NoError(t, run(map[string]string{"on-conflict": "overwrite"}))
+
+		err = run(map[string]string{"on-conflict": "clobber"})
+		if assert.Error(t, err) {
+			assert.Regexp(t, "must be one of", err.Error())
+		}
+		err = run(map[string]string{"on-conflict": "overwrite", "theirs": "true"})
+		if assert.Error(t, err) {
+			assert.Regexp(t, "can't be combined with --theirs", err.Error())
+		}
+	})
+}
+
+func TestDownloadUpdateMergesChanges(t *testing.T) {
+	co := newCapturedOutput()
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	tmpDir, err := ioutil.TempDir("", "download-update")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	ts := fakeDownloadServer("true", "")
+	defer ts.Close()
+
+	v := viper.New()
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	v.Set("token", "abc123")
+	cfg := config.Config{
+		UserViperConfig: v,
+		StateDir:        filepath.Join(tmpDir, "state"),
+	}
+
+	update := func() error {
+		flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+		setupDownloadFlags(flags)
+		flags.Set("exercise", "bogus-exercise")
+		flags.Set("update", "true")
+		return runDownload(cfg, flags, []string{})
+	}
+
+	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
+	file1 := filepath.Join(dir, "file-1.txt")
+	write := func(path, contents string) {
+		assert.NoError(t, ioutil.WriteFile(path, []byte(contents), os.FileMode(0644)))
+	}
+	read := func(path string) string {
+		b, err := ioutil.ReadFile(path)
+		assert.NoError(t, err)
+		return string(b)
+	}
+
+	assert.NoError(t, update())
+	original, err := workspace.ReadOriginal(dir, "file-1.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "this is file 1", string(original))
+
+	// Only the local file changed since the download.
+	write(file1, "// my notes\nthis is file 1")
+	assert.NoError(t, update())
+	assert.Equal(t, "// my notes\nthis is file 1", read(file1))
+
+	// Both the local file and the website's changed the same line.
+	assert.NoError(t, workspace.WriteOriginal(dir, "file-1.txt", []byte("// notes\nthis was file 1")))
+	write(file1, "// my notes\nthis was my file 1")
+	assert.NoError(t, update())
+	assert.Equal(t, "<<<<<<< mine\n// my notes\nthis was my file 1\n=======\nthis is file 1\n>>>>>>> theirs\n", read(file1))
+	assert.Regexp(t, `file-1.txt \(1 conflict\(s\)\)`, Err.(*bytes.Buffer).String())
+
+	// The merged file was backed up before it was written.
+	snaps, err := snapshotStore(cfg).List()
+	assert.NoError(t, err)
+	if assert.NotEmpty(t, snaps) {
+		assert.Equal(t, "download bogus-track/bogus-exercise", snaps[0].Reason)
+	}
+
+	// Without an original, there is nothing to merge with.
+	assert.NoError(t, os.RemoveAll(workspace.OriginalsDir(dir)))
+	write(file1, "my file 1")
+	assert.NoError(t, update())
+	assert.Equal(t, "my file 1", read(file1))
+	assert.Regexp(t, "can't be merged", Err.(*bytes.Buffer).String())
+}
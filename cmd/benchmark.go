@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/exercism/cli/config"
+	"github.com/exercism/cli/workspace"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// benchmarkCmd runs the benchmarks of an exercise and compares them against the last run.
+var benchmarkCmd = &cobra.Command{
+	Use:     "benchmark [PATH] [-- ARGS...]",
+	Aliases: []string{"bench"},
+	Short:   "Run the benchmarks of an exercise.",
+	Long: `Run the benchmarks of an exercise with the track's benchmark tooling,
+such as go test -bench or cargo bench, and compare them against the last run.
+
+Pass the path to the exercise, or run the command from within it.
+Arguments after -- are passed on to the benchmark command.
+
+The results are kept in the exercise's .exercism/bench.json, so that the next
+run shows how much faster or slower each benchmark got. Pass --no-save to
+keep comparing against the same run.
+
+To run the benchmarks of a track differently, set the command with
+tracks.<track>.bench_command in your user config; {dir}, {track} and {slug}
+stand for the exercise directory, the track and the exercise.
+`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.NewConfig()
+
+		v := viper.New()
+		v.AddConfigPath(cfg.Dir)
+		v.SetConfigName("user")
+		v.SetConfigType("json")
+		// Ignore error. If the file doesn't exist, that is fine.
+		_ = v.ReadInConfig()
+		config.BindUserEnv(v)
+		cfg.UserViperConfig = v
+
+		var extra []string
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			args, extra = args[:dash], args[dash:]
+		}
+		if len(args) > 1 {
+			return fmt.Errorf("give at most one exercise to benchmark, not %d", len(args))
+		}
+		return runBenchmark(cfg, cmd.Flags(), args, extra)
+	},
+}
+
+// trackBenchmarkCommands are the commands that run the benchmarks of an exercise in each track,
+// from the exercise directory. Their output is read by parseBenchmarks.
+var trackBenchmarkCommands = map[string][]string{
+	"go":   {"go", "test", "-run", "^$", "-bench", "."},
+	"rust": {"cargo", "bench"},
+}
+
+func runBenchmark(cfg config.Config, flags *pflag.FlagSet, args, extra []string) error {
+	noSave, err := flags.GetBool("no-save")
+	if err != nil {
+		return err
+	}
+	dir, metadata, err := exerciseAt(cfg.UserViperConfig, args)
+	if err != nil {
+		return err
+	}
+	command := configuredCommand(cfg.UserViperConfig, "bench_command", metadata.Track, metadata.ExerciseSlug, dir)
+	if command == nil {
+		var ok bool
+		if command, ok = trackBenchmarkCommands[metadata.Track]; !ok {
+			return fmt.Errorf("there's no known command to run the benchmarks of the %s track; set one with tracks.%s.bench_command in your user config", metadata.Track, metadata.Track)
+		}
+	}
+	command = append(append([]string{}, command...), extra...)
+
+	previous, err := workspace.NewBenchmarkRun(dir)
+	if err != nil {
+		// A damaged run is replaced by this one.
+		previous = nil
+	}
+
+	// What the tooling prints goes to stderr; the comparison is the output.
+	fmt.Fprintf(Err, "Running the benchmarks of %s with %s\n\n", metadata, strings.Join(command, " "))
+	var output bytes.Buffer
+	w := io.MultiWriter(Err, &output)
+	err = runTestCommand(dir, command, w, w)
+	fmt.Fprintln(Err)
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return fmt.Errorf("the benchmarks of %s failed (exit code %d)", metadata, exitErr.ExitCode())
+	}
+	if err != nil {
+		return fmt.Errorf("the benchmarks couldn't be run with %s - %s", command[0], err)
+	}
+
+	run := workspace.BenchmarkRun{
+		RanAt:   time.Now(),
+		Command: strings.Join(command, " "),
+		Results: parseBenchmarks(&output),
+	}
+	if len(run.Results) == 0 {
+		return fmt.Errorf("no benchmark results were found in the output of %s", command[0])
+	}
+	if err := printBenchmarkComparison(Out, run, previous); err != nil {
+		return err
+	}
+	if noSave {
+		return nil
+	}
+	return run.Write(dir)
+}
+
+var (
+	// goBenchmarkLine is a result of go test -bench. The suffix for GOMAXPROCS is dropped,
+	// so that runs on different machines can be compared.
+	goBenchmarkLine = regexp.MustCompile(`^(Benchmark\S+?)(?:-\d+)?\s+\d+\s+([\d.]+) ns/op`)
+	// libtestBenchmarkLine is a result of cargo bench without criterion.
+	libtestBenchmarkLine = regexp.MustCompile(`^test (\S+)\s+\.\.\. bench:\s+([\d,.]+) ns/iter`)
+	// criterionBenchmarkLine is a result of criterion, whose name is on the line before when it is long.
+	criterionBenchmarkLine = regexp.MustCompile(`^(.*?)\s*time:\s+\[\S+ \S+ (\S+) (\S+) `)
+)
+
+// criterionUnits are the nanoseconds in each of the units criterion reports times in.
+var criterionUnits = map[string]float64{
+	"ps": 0.001,
+	"ns": 1,
+	"µs": 1e3,
+	"us": 1e3,
+	"ms": 1e6,
+	"s":  1e9,
+}
+
+// parseBenchmarks reads the results of the benchmarks from what the benchmark tooling printed.
+func parseBenchmarks(r io.Reader) []workspace.Benchmark {
+	var results []workspace.Benchmark
+	var previousLine string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if m := goBenchmarkLine.FindStringSubmatch(line); m != nil {
+			if ns, err := strconv.ParseFloat(m[2], 64); err == nil {
+				results = append(results, workspace.Benchmark{Name: m[1], NsPerOp: ns})
+			}
+		} else if m := libtestBenchmarkLine.FindStringSubmatch(line); m != nil {
+			if ns, err := strconv.ParseFloat(strings.Replace(m[2], ",", "", -1), 64); err == nil {
+				results = append(results, workspace.Benchmark{Name: m[1], NsPerOp: ns})
+			}
+		} else if m := criterionBenchmarkLine.FindStringSubmatch(line); m != nil {
+			name := strings.TrimSpace(m[1])
+			if name == "" {
+				name = strings.TrimSpace(previousLine)
+			}
+			ns, err := strconv.ParseFloat(m[2], 64)
+			unit, ok := criterionUnits[m[3]]
+			if err == nil && ok && name != "" {
+				results = append(results, workspace.Benchmark{Name: name, NsPerOp: ns * unit})
+			}
+		}
+		if strings.TrimSpace(line) != "" {
+			previousLine = line
+		}
+	}
+	return results
+}
+
+// printBenchmarkComparison shows how long each benchmark took, and how that changed since the previous run.
+func printBenchmarkComparison(w io.Writer, run workspace.BenchmarkRun, previous *workspace.BenchmarkRun) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "BENCHMARK\tPREVIOUS\tNOW\tCHANGE")
+	for _, result := range run.Results {
+		before, change := "-", "-"
+		if last, ok := previous.Lookup(result.Name); ok {
+			before = humanNsPerOp(last.NsPerOp)
+			if last.NsPerOp > 0 {
+				change = fmt.Sprintf("%+.1f%%", (result.NsPerOp-last.NsPerOp)/last.NsPerOp*100)
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", result.Name, before, humanNsPerOp(result.NsPerOp), change)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if previous != nil {
+		fmt.Fprintf(Err, "\nCompared against the run of %s.\n", previous.RanAt.Local().Format("2006-01-02 15:04"))
+	}
+	return nil
+}
+
+// humanNsPerOp formats the time an operation took for people to read.
+func humanNsPerOp(ns float64) string {
+	switch {
+	case ns >= 1e9:
+		return fmt.Sprintf("%.2fs/op", ns/1e9)
+	case ns >= 1e6:
+		return fmt.Sprintf("%.2fms/op", ns/1e6)
+	case ns >= 1e3:
+		return fmt.Sprintf("%.2fus/op", ns/1e3)
+	}
+	return fmt.Sprintf("%.2fns/op", ns)
+}
+
+func setupBenchmarkFlags(flags *pflag.FlagSet) {
+	flags.Bool("no-save", false, "don't replace the run that later runs are compared against")
+}
+
+func init() {
+	RootCmd.AddCommand(benchmarkCmd)
+	setupBenchmarkFlags(benchmarkCmd.Flags())
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/exercism/cli/workspace"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBenchmarks(t *testing.T) {
+	output := `goos: linux
+goarch: amd64
+BenchmarkLeapYears-8   	 5000000	       252.5 ns/op	       0 B/op
+PASS
+test bench_leap ... bench:       1,234 ns/iter (+/- 56)
+leap                    time:   [1.2000 µs 1.3000 µs 1.4000 µs]
+a very long benchmark name
+                        time:   [2.0000 ms 2.5000 ms 3.0000 ms]
+`
+	results := parseBenchmarks(strings.NewReader(output))
+	expected := []workspace.Benchmark{
+		{Name: "BenchmarkLeapYears", NsPerOp: 252.5},
+		{Name: "bench_leap", NsPerOp: 1234},
+		{Name: "leap", NsPerOp: 1300},
+		{Name: "a very long benchmark name", NsPerOp: 2.5e6},
+	}
+	assert.Equal(t, expected, results)
+}
+
+func TestPrintBenchmarkComparison(t *testing.T) {
+	co := newCapturedOutput()
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	previous := &workspace.BenchmarkRun{Results: []workspace.Benchmark{{Name: "BenchmarkLeap", NsPerOp: 200}}}
+	run := workspace.BenchmarkRun{Results: []workspace.Benchmark{
+		{Name: "BenchmarkLeap", NsPerOp: 150},
+		{Name: "BenchmarkCentury", NsPerOp: 2500},
+	}}
+
+	var out bytes.Buffer
+	assert.NoError(t, printBenchmarkComparison(&out, run, previous))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if assert.Len(t, lines, 3) {
+		assert.Regexp(t, `^BenchmarkLeap\s+200.00ns/op\s+150.00ns/op\s+-25.0%$`, lines[1])
+		assert.Regexp(t, `^BenchmarkCentury\s+-\s+2.50us/op\s+-$`, lines[2])
+	}
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/exercism/cli/api"
+	"github.com/exercism/cli/workspace"
+)
+
+// Exercise statuses as reported by the API.
+// Exercises found only in the workspace are reported as downloaded.
+const (
+	statusLocked     = "locked"
+	statusAvailable  = "available"
+	statusInProgress = "in_progress"
+	statusCompleted  = "completed"
+	statusPublished  = "published"
+	statusDownloaded = "downloaded"
+)
+
+// catalogExercise is an exercise as listed in a track's catalog.
+type catalogExercise struct {
+	Slug       string   `json:"slug"`
+	Type       string   `json:"type,omitempty"`
+	Difficulty string   `json:"difficulty,omitempty"`
+	Topics     []string `json:"topics,omitempty"`
+	Blurb      string   `json:"blurb,omitempty"`
+	Status     string   `json:"status,omitempty"`
+}
+
+// catalog is the list of exercises in a track, in the order the track presents them.
+type catalog struct {
+	Track     string            `json:"track"`
+	Exercises []catalogExercise `json:"exercises"`
+	FetchedAt time.Time         `json:"fetched_at"`
+}
+
+// catalogPath is where the catalog for a track is cached.
+func catalogPath(stateDir, track string) string {
+	return filepath.Join(stateDir, "catalog", fmt.Sprintf("%s.json", track))
+}
+
+// fetchCatalog requests the catalog for a track from the API.
+func fetchCatalog(token, baseURL, track string) (*catalog, error) {
+	client, err := api.NewClient(token, baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/tracks/%s/exercises", baseURL, track)
+	req, err := client.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, decodedAPIError(res)
+	}
+
+	var payload struct {
+		Exercises []catalogExercise `json:"exercises"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("unable to parse API response - %s", err)
+	}
+	return &catalog{
+		Track:     track,
+		Exercises: payload.Exercises,
+		FetchedAt: time.Now(),
+	}, nil
+}
+
+// trackSummary is a track as listed by the API.
+type trackSummary struct {
+	Slug   string `json:"slug"`
+	Title  string `json:"title,omitempty"`
+	Joined bool   `json:"is_joined"`
+	// LearningMode is whether the track teaches through concept exercises, rather than practice ones alone.
+	LearningMode           bool `json:"learning_mode"`
+	NumExercises           int  `json:"num_exercises"`
+	NumCompletedExercises  int  `json:"num_completed_exercises"`
+	NumInProgressExercises int  `json:"num_in_progress_exercises"`
+}
+
+// fetchTracks requests the list of all the tracks from the API.
+func fetchTracks(token, baseURL string) ([]trackSummary, error) {
+	client, err := api.NewClient(token, baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := client.NewRequest("GET", fmt.Sprintf("%s/tracks", baseURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, decodedAPIError(res)
+	}
+
+	var payload struct {
+		Tracks []trackSummary `json:"tracks"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("unable to parse API response - %s", err)
+	}
+	return payload.Tracks, nil
+}
+
+// loadCatalog reads the cached catalog for a track.
+// It returns nil if the catalog has not been cached.
+func loadCatalog(path string) (*catalog, error) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var c catalog
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (c *catalog) save(path string) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.FileMode(0755)); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, os.FileMode(0644))
+}
+
+// localCatalog builds a catalog from the exercises of a track that have
+// been downloaded into the workspace, using the details stored in their metadata.
+func localCatalog(ws workspace.Workspace, track string) (*catalog, error) {
+	exercises, err := ws.Exercises()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &catalog{Track: track}
+	seen := map[string]bool{}
+	for _, exercise := range exercises {
+		if exercise.Track != track {
+			continue
+		}
+		metadata, err := workspace.NewExerciseMetadata(exercise.MetadataDir())
+		if err != nil {
+			return nil, err
+		}
+		if seen[metadata.ExerciseSlug] {
+			continue
+		}
+		seen[metadata.ExerciseSlug] = true
+		c.Exercises = append(c.Exercises, catalogExercise{
+			Slug:       metadata.ExerciseSlug,
+			Type:       metadata.Type,
+			Difficulty: metadata.Difficulty,
+			Topics:     metadata.Topics,
+			Blurb:      metadata.Blurb,
+			Status:     statusDownloaded,
+		})
+	}
+	return c, nil
+}
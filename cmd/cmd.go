@@ -3,11 +3,11 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"strings"
 
-	"io"
-
 	"github.com/exercism/cli/config"
 	"github.com/spf13/viper"
 )
@@ -23,6 +23,8 @@ var (
 	Out io.Writer
 	// Err is used to write errors.
 	Err io.Writer
+	// In is used to read answers to prompts.
+	In io.Reader
 )
 
 const msgWelcomePleaseConfigure = `
@@ -40,6 +42,39 @@ const msgWelcomePleaseConfigure = `
 
 `
 
+// msgGetStarted is shown to people who haven't configured anything yet,
+// most likely because it's the first time they're using the CLI.
+const msgGetStarted = `
+
+    Welcome to Exercism!
+
+    It looks like this is your first time using the command-line client.
+    Here's how to get started:
+
+    1. Configure the tool with your API token. Find your token at
+
+           %[1]s
+
+       Then run the configure command:
+
+           %[2]s configure --token=YOUR_TOKEN
+
+    2. Exercises are downloaded into your workspace, which by default is
+
+           %[3]s
+
+       To use a different directory, add --workspace=PATH when you configure.
+
+    3. Join a track on the website:
+
+           %[4]s/tracks
+
+    4. Download your first exercise, for example:
+
+           %[2]s download --track=TRACK --exercise=hello-world
+
+`
+
 // Running configure without any arguments will attempt to
 // set the default workspace. If the default workspace directory
 // risks clobbering an existing directory, it will print an
@@ -59,43 +94,136 @@ const msgMissingMetadata = `
 
 `
 
+// userConfigError is a problem with the user config that keeps a command from running.
+type userConfigError struct {
+	code        string
+	message     string
+	remediation string
+}
+
+func (e *userConfigError) Error() string {
+	return e.message
+}
+
 // validateUserConfig validates the presence of required user config values
 func validateUserConfig(cfg *viper.Viper) error {
+	if isFirstRun(cfg) {
+		return &userConfigError{
+			code:        "not_configured",
+			message:     getStartedMessage(cfg),
+			remediation: fmt.Sprintf("%s configure --token=YOUR_TOKEN", BinaryName),
+		}
+	}
 	if cfg.GetString("token") == "" {
-		return fmt.Errorf(
-			msgWelcomePleaseConfigure,
-			config.SettingsURL(cfg.GetString("apibaseurl")),
-			BinaryName,
-		)
+		return &userConfigError{
+			code: "missing_token",
+			message: fmt.Sprintf(
+				msgWelcomePleaseConfigure,
+				config.SettingsURL(cfg.GetString("apibaseurl")),
+				BinaryName,
+			),
+			remediation: fmt.Sprintf("%s configure --token=YOUR_TOKEN", BinaryName),
+		}
 	}
 	if cfg.GetString("workspace") == "" || cfg.GetString("apibaseurl") == "" {
-		return fmt.Errorf(msgRerunConfigure, BinaryName)
+		return &userConfigError{
+			code:        "incomplete_config",
+			message:     fmt.Sprintf(msgRerunConfigure, BinaryName),
+			remediation: fmt.Sprintf("%s configure", BinaryName),
+		}
 	}
 	return nil
 }
 
+// isFirstRun guesses whether the CLI has ever been configured.
+func isFirstRun(cfg *viper.Viper) bool {
+	return cfg.GetString("token") == "" &&
+		cfg.GetString("workspace") == "" &&
+		cfg.GetString("apibaseurl") == ""
+}
+
+// getStartedMessage walks a newcomer through setting up the CLI.
+func getStartedMessage(cfg *viper.Viper) string {
+	apiURL := cfg.GetString("apibaseurl")
+	return fmt.Sprintf(
+		msgGetStarted,
+		config.SettingsURL(apiURL),
+		BinaryName,
+		config.DefaultWorkspaceDir(config.NewConfig()),
+		config.InferSiteURL(apiURL),
+	)
+}
+
+// apiError is an error response from the API.
+type apiError struct {
+	StatusCode int
+	// Status is the status line, e.g. "500 Internal Server Error".
+	Status           string
+	Type             string
+	Message          string
+	PossibleTrackIDs []string
+	// Body is the start of a response that didn't say what went wrong in the usual way.
+	Body string
+	// RequestID identifies the request in the API's logs.
+	RequestID string
+}
+
+func (e *apiError) Error() string {
+	var msg string
+	switch {
+	case e.Message != "" && e.Type == "track_ambiguous":
+		msg = fmt.Sprintf("%s: %s", e.Message, strings.Join(e.PossibleTrackIDs, ", "))
+	case e.Message != "":
+		msg = e.Message
+	case e.Body != "":
+		msg = fmt.Sprintf("unexpected API response: %s: %s", e.Status, e.Body)
+	default:
+		msg = fmt.Sprintf("unexpected API response: %s", e.Status)
+	}
+	if e.RequestID != "" {
+		msg = fmt.Sprintf("%s (request ID: %s)", msg, e.RequestID)
+	}
+	return msg
+}
+
+// maxErrorBodyLength is how much of a response that isn't in the usual error format is shown.
+const maxErrorBodyLength = 200
+
 // decodedAPIError decodes and returns the error message from the API response.
-// If the message is blank, it returns a fallback message with the status code.
+// If the response isn't in the usual format, the error shows the status and the start of the body instead.
 func decodedAPIError(resp *http.Response) error {
-	var apiError struct {
+	e := &apiError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		RequestID:  resp.Header.Get("X-Request-Id"),
+	}
+	if e.Status == "" {
+		e.Status = fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return e
+	}
+	var payload struct {
 		Error struct {
 			Type             string   `json:"type"`
 			Message          string   `json:"message"`
 			PossibleTrackIDs []string `json:"possible_track_ids"`
 		} `json:"error,omitempty"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&apiError); err != nil {
-		return fmt.Errorf("failed to parse API error response: %s", err)
+	if err := json.Unmarshal(body, &payload); err == nil && payload.Error.Message != "" {
+		e.Type = payload.Error.Type
+		e.Message = payload.Error.Message
+		e.PossibleTrackIDs = payload.Error.PossibleTrackIDs
+		return e
 	}
-	if apiError.Error.Message != "" {
-		if apiError.Error.Type == "track_ambiguous" {
-			return fmt.Errorf(
-				"%s: %s",
-				apiError.Error.Message,
-				strings.Join(apiError.Error.PossibleTrackIDs, ", "),
-			)
-		}
-		return fmt.Errorf(apiError.Error.Message)
+
+	// Collapse the whitespace, so that the body fits on a line.
+	text := strings.Join(strings.Fields(string(body)), " ")
+	if len(text) > maxErrorBodyLength {
+		text = text[:maxErrorBodyLength] + "..."
 	}
-	return fmt.Errorf("unexpected API response: %d", resp.StatusCode)
+	e.Body = text
+	return e
 }
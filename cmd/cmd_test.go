@@ -3,10 +3,13 @@ package cmd
 import (
 	"io"
 	"io/ioutil"
+	"net/http"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -118,3 +121,92 @@ func (co capturedOutput) reset() {
 	Out = co.oldOut
 	Err = co.oldErr
 }
+
+func TestValidateUserConfigOnFirstRun(t *testing.T) {
+	err := validateUserConfig(viper.New())
+	if assert.Error(t, err) {
+		assert.Regexp(t, "first time using the command-line client", err.Error())
+		assert.Regexp(t, "configure --token=YOUR_TOKEN", err.Error())
+		assert.Regexp(t, "https://exercism.io/tracks", err.Error())
+		assert.Regexp(t, "download --track=TRACK --exercise=hello-world", err.Error())
+	}
+}
+
+func TestValidateUserConfigWithPartialConfig(t *testing.T) {
+	v := viper.New()
+	v.Set("workspace", "/home/alice/exercism")
+	v.Set("apibaseurl", "http://example.com/api/v1")
+
+	err := validateUserConfig(v)
+	if assert.Error(t, err) {
+		assert.NotRegexp(t, "first time", err.Error())
+		assert.Regexp(t, "example.com/my/settings", err.Error())
+	}
+}
+
+func TestDecodedAPIError(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		status    int
+		body      string
+		requestID string
+		expected  string
+	}{
+		{
+			desc:     "message from the API",
+			status:   http.StatusNotFound,
+			body:     `{"error": {"type": "not_found", "message": "Solution not found"}}`,
+			expected: "Solution not found",
+		},
+		{
+			desc:     "ambiguous track",
+			status:   http.StatusBadRequest,
+			body:     `{"error": {"type": "track_ambiguous", "message": "Please specify a track", "possible_track_ids": ["go", "rust"]}}`,
+			expected: "Please specify a track: go, rust",
+		},
+		{
+			desc:      "message from the API with a request ID",
+			status:    http.StatusUnprocessableEntity,
+			body:      `{"error": {"type": "duplicate_submission", "message": "No files you submitted have changed"}}`,
+			requestID: "abc-123",
+			expected:  "No files you submitted have changed (request ID: abc-123)",
+		},
+		{
+			desc:      "server error that isn't JSON",
+			status:    http.StatusInternalServerError,
+			body:      "<html>\n  <h1>We're sorry,\n but something went wrong.</h1>\n</html>\n",
+			requestID: "abc-123",
+			expected:  "unexpected API response: 500 Internal Server Error: <html> <h1>We're sorry, but something went wrong.</h1> </html> (request ID: abc-123)",
+		},
+		{
+			desc:     "JSON without a message",
+			status:   http.StatusBadGateway,
+			body:     `{"status": "down"}`,
+			expected: `unexpected API response: 502 Bad Gateway: {"status": "down"}`,
+		},
+		{
+			desc:     "empty body",
+			status:   http.StatusServiceUnavailable,
+			expected: "unexpected API response: 503 Service Unavailable",
+		},
+		{
+			desc:     "long body",
+			status:   http.StatusInternalServerError,
+			body:     strings.Repeat("x", 500),
+			expected: "unexpected API response: 500 Internal Server Error: " + strings.Repeat("x", maxErrorBodyLength) + "...",
+		},
+	}
+
+	for _, tc := range testCases {
+		resp := &http.Response{
+			StatusCode: tc.status,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(strings.NewReader(tc.body)),
+		}
+		if tc.requestID != "" {
+			resp.Header.Set("X-Request-Id", tc.requestID)
+		}
+		err := decodedAPIError(resp)
+		assert.EqualError(t, err, tc.expected, tc.desc)
+	}
+}
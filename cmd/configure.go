@@ -1,13 +1,16 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"text/tabwriter"
 
 	"github.com/exercism/cli/api"
 	"github.com/exercism/cli/config"
+	"github.com/exercism/cli/debug"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
@@ -19,16 +22,52 @@ var (
 
 // configureCmd configures the command-line client with user-specific settings.
 var configureCmd = &cobra.Command{
-	Use:     "configure",
-	Aliases: []string{"c"},
-	Short:   "Configure the command-line client.",
+	Use:        "configure",
+	Aliases:    []string{"c"},
+	SuggestFor: []string{"login", "config", "setup", "auth"},
+	Short:      "Configure the command-line client.",
 	Long: `Configure the command-line client to customize it to your needs.
 
 This lets you set up the CLI to talk to the API on your behalf,
 and tells the CLI about your setup so it puts things in the right
 places.
 
+To keep your token out of your shell history, pass --token - to read it
+from stdin, or leave it out at a terminal to be asked for it without it
+being shown.
+
 You can also override certain default settings to suit your preferences.
+
+Set the flags you always pass to a command in the "commands" section of
+the user config, e.g. to always take the website's files on download:
+
+    "commands": {"download": {"force": true}}
+
+Flags given on the command line take precedence.
+
+If your terminal shows garbled symbols, set "ascii" to true in the user
+config, or pass --ascii, to only use plain ASCII characters in the output.
+
+When a download or submission fails because of the network or the API, you
+are asked whether to retry. Answer "always", or set "retry" to "always" in
+the user config, to retry a few times without being asked.
+
+To use a separate config for one invocation, e.g. for a second account or
+in tests, pass --config-dir=PATH to any command. Unless EXERCISM_STATE_HOME
+is set, the CLI's state is kept in PATH/state as well.
+
+Programs that call the CLI can pass --error-format=json, or set
+"error_format" to "json" in the user config, to get failures on stderr as
+a JSON object with a code, category, message, a command that might fix the
+problem, and the API's request ID.
+
+The token, workspace and API base URL can also be set in the environment, as
+EXERCISM_TOKEN, EXERCISM_WORKSPACE and EXERCISM_API_BASE_URL, which take
+precedence over the user config.
+
+Call the command with --show to see the configuration in effect, and where
+each value comes from. The token is redacted unless you pass --reveal-token,
+and --json prints the configuration in a machine-readable format.
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		configuration := config.NewConfig()
@@ -53,13 +92,28 @@ func runConfigure(configuration config.Config, flags *pflag.FlagSet) error {
 		return err
 	}
 	if show {
-		printCurrentConfig(configuration)
+		revealToken, err := flags.GetBool("reveal-token")
+		if err != nil {
+			return err
+		}
+		asJSON, err := flags.GetBool("json")
+		if err != nil {
+			return err
+		}
+		// The config isn't saved, so it can take the values from the environment like the other commands do.
+		config.BindUserEnv(cfg)
+		settings := newEffectiveConfig(configuration, revealToken)
+		if asJSON {
+			return json.NewEncoder(Out).Encode(settings)
+		}
+		settings.print(true)
 		return nil
 	}
 
 	// If the command is run 'bare' and we have no token,
 	// explain how to set the token.
-	if flags.NFlag() == 0 && cfg.GetString("token") == "" {
+	// At a terminal, the token is asked for instead.
+	if flags.NFlag() == 0 && cfg.GetString("token") == "" && !isInteractive() {
 		tokenURL := config.SettingsURL(cfg.GetString("apibaseurl"))
 		return fmt.Errorf("There is no token configured. Find your token on %s, and call this command again with --token=<your-token>.", tokenURL)
 	}
@@ -109,6 +163,17 @@ func runConfigure(configuration config.Config, flags *pflag.FlagSet) error {
 
 	tokenURL := config.SettingsURL(cfg.GetString("apibaseurl"))
 
+	// Read the token from stdin or a prompt rather than the command line,
+	// where it would end up in the shell history and the process list.
+	if token == "-" || (token == "" && isInteractive()) {
+		if isInteractive() {
+			fmt.Fprintf(Err, "Find your token on %s\n", tokenURL)
+		}
+		if token, err = promptSecret("Token: "); err != nil && err != io.EOF {
+			return fmt.Errorf("unable to read the token: %s", err)
+		}
+	}
+
 	// If we don't have a token then explain how to set it and bail.
 	if token == "" {
 		return fmt.Errorf("There is no token configured. Find your token on %s, and call this command again with --token=<your-token>.", tokenURL)
@@ -125,7 +190,11 @@ func runConfigure(configuration config.Config, flags *pflag.FlagSet) error {
 			return err
 		}
 		if !ok {
-			return fmt.Errorf("The token '%s' is invalid. Find your token on %s.", token, tokenURL)
+			// Only show the token if it was typed on the command line anyway.
+			if given, _ := flags.GetString("token"); given != "" && given != "-" {
+				return fmt.Errorf("The token '%s' is invalid. Find your token on %s.", token, tokenURL)
+			}
+			return fmt.Errorf("The token is invalid. Find your token on %s.", tokenURL)
 		}
 	}
 
@@ -192,21 +261,90 @@ func runConfigure(configuration config.Config, flags *pflag.FlagSet) error {
 		return err
 	}
 	fmt.Fprintln(Err, "\nYou have configured the Exercism command-line client:")
-	printCurrentConfig(configuration)
+	newEffectiveConfig(configuration, false).print(false)
 	return nil
 }
 
-func printCurrentConfig(configuration config.Config) {
+// Where a configuration value comes from.
+const (
+	sourceFile    = "file"
+	sourceEnv     = "env"
+	sourceDefault = "default"
+	sourceUnset   = "unset"
+)
+
+// setting is a configuration value along with where it comes from.
+type setting struct {
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// effectiveConfig is the configuration the commands will actually use.
+type effectiveConfig struct {
+	Dir        string  `json:"config_dir"`
+	Token      setting `json:"token"`
+	Workspace  setting `json:"workspace"`
+	APIBaseURL setting `json:"apibaseurl"`
+}
+
+// newEffectiveConfig resolves the user config, falling back to the defaults.
+// The token is redacted unless revealToken is set.
+func newEffectiveConfig(configuration config.Config, revealToken bool) effectiveConfig {
+	v := configuration.UserViperConfig
+
+	token := newSetting(v, "token", "")
+	if !revealToken {
+		token.Value = redactToken(token.Value)
+	}
+	return effectiveConfig{
+		Dir:        configuration.Dir,
+		Token:      token,
+		Workspace:  newSetting(v, "workspace", config.DefaultWorkspaceDir(configuration)),
+		APIBaseURL: newSetting(v, "apibaseurl", configuration.DefaultBaseURL),
+	}
+}
+
+// newSetting resolves a value of the user config.
+// A value that isn't set in the environment is the one in the user config, as the environment takes precedence.
+func newSetting(v *viper.Viper, key, fallback string) setting {
+	value := v.GetString(key)
+	switch {
+	case value != "" && os.Getenv(config.UserEnv(key)) != "":
+		return setting{Value: value, Source: sourceEnv}
+	case value != "":
+		return setting{Value: value, Source: sourceFile}
+	case fallback != "":
+		return setting{Value: fallback, Source: sourceDefault}
+	}
+	return setting{Source: sourceUnset}
+}
+
+// redactToken masks most of the token, leaving enough to recognize it.
+func redactToken(token string) string {
+	if len(token) < 8 {
+		return strings.Repeat("*", len(token))
+	}
+	return debug.Redact(token)
+}
+
+// print writes the configuration in a human-friendly format,
+// optionally noting where each value comes from.
+func (ec effectiveConfig) print(withSources bool) {
 	w := tabwriter.NewWriter(Err, 0, 0, 2, ' ', 0)
 	defer w.Flush()
 
-	v := configuration.UserViperConfig
+	source := func(s setting) string {
+		if !withSources {
+			return ""
+		}
+		return fmt.Sprintf("\t(%s)", s.Source)
+	}
 
 	fmt.Fprintln(w, "")
-	fmt.Fprintln(w, fmt.Sprintf("Config dir:\t\t%s", configuration.Dir))
-	fmt.Fprintln(w, fmt.Sprintf("Token:\t(-t, --token)\t%s", v.GetString("token")))
-	fmt.Fprintln(w, fmt.Sprintf("Workspace:\t(-w, --workspace)\t%s", v.GetString("workspace")))
-	fmt.Fprintln(w, fmt.Sprintf("API Base URL:\t(-a, --api)\t%s", v.GetString("apibaseurl")))
+	fmt.Fprintln(w, fmt.Sprintf("Config dir:\t\t%s", ec.Dir))
+	fmt.Fprintln(w, fmt.Sprintf("Token:\t(-t, --token)\t%s%s", ec.Token.Value, source(ec.Token)))
+	fmt.Fprintln(w, fmt.Sprintf("Workspace:\t(-w, --workspace)\t%s%s", ec.Workspace.Value, source(ec.Workspace)))
+	fmt.Fprintln(w, fmt.Sprintf("API Base URL:\t(-a, --api)\t%s%s", ec.APIBaseURL.Value, source(ec.APIBaseURL)))
 	fmt.Fprintln(w, "")
 }
 
@@ -227,10 +365,12 @@ func initConfigureCmd() {
 }
 
 func setupConfigureFlags(flags *pflag.FlagSet) {
-	flags.StringP("token", "t", "", "authentication token used to connect to the site")
+	flags.StringP("token", "t", "", "authentication token used to connect to the site, or - to read it from stdin")
 	flags.StringP("workspace", "w", "", "directory for exercism exercises")
 	flags.StringP("api", "a", "", "API base url")
 	flags.BoolP("show", "s", false, "show the current configuration")
+	flags.BoolP("reveal-token", "", false, "show the full token with --show, redacted by default")
+	flags.BoolP("json", "", false, "print the configuration as JSON with --show")
 	flags.BoolP("no-verify", "", false, "skip online token authorization check")
 }
 
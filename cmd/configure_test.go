@@ -4,11 +4,13 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/exercism/cli/config"
@@ -72,13 +74,137 @@ func TestConfigureShow(t *testing.T) {
 	assert.Regexp(t, "configured.example", Err)
 	assert.NotRegexp(t, "override.example", Err)
 
-	assert.Regexp(t, "configured-token", Err)
+	assert.Regexp(t, `conf\*+ken`, Err)
+	assert.NotRegexp(t, "configured-token", Err)
 	assert.NotRegexp(t, "token-override", Err)
 
 	assert.Regexp(t, "configured-workspace", Err)
 	assert.NotRegexp(t, "workspace-override", Err)
 }
 
+func TestConfigureShowRevealToken(t *testing.T) {
+	co := newCapturedOutput()
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupConfigureFlags(flags)
+
+	v := viper.New()
+	v.Set("token", "configured-token")
+
+	err := flags.Parse([]string{"--show", "--reveal-token"})
+	assert.NoError(t, err)
+
+	cfg := config.Config{
+		Persister:       config.InMemoryPersister{},
+		UserViperConfig: v,
+	}
+
+	err = runConfigure(cfg, flags)
+	assert.NoError(t, err)
+	assert.Regexp(t, "configured-token", Err)
+}
+
+func TestConfigureShowJSON(t *testing.T) {
+	co := newCapturedOutput()
+	co.newOut = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	tmpDir, err := ioutil.TempDir("", "configure-show-json")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	err = ioutil.WriteFile(filepath.Join(tmpDir, "user.json"), []byte(`{"token": "abc123def456"}`), os.FileMode(0600))
+	assert.NoError(t, err)
+
+	v := viper.New()
+	v.AddConfigPath(tmpDir)
+	v.SetConfigName("user")
+	v.SetConfigType("json")
+	err = v.ReadInConfig()
+	assert.NoError(t, err)
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupConfigureFlags(flags)
+	err = flags.Parse([]string{"--show", "--json"})
+	assert.NoError(t, err)
+
+	cfg := config.Config{
+		Persister:       config.InMemoryPersister{},
+		UserViperConfig: v,
+		Dir:             tmpDir,
+		Home:            "/home/alice",
+		OS:              "linux",
+		DefaultDirName:  "exercism",
+		DefaultBaseURL:  "http://example.com/v1",
+	}
+
+	err = runConfigure(cfg, flags)
+	assert.NoError(t, err)
+
+	var ec effectiveConfig
+	err = json.Unmarshal(Out.(*bytes.Buffer).Bytes(), &ec)
+	assert.NoError(t, err)
+
+	assert.Equal(t, tmpDir, ec.Dir)
+	assert.Equal(t, setting{Value: "abc1*****456", Source: sourceFile}, ec.Token)
+	assert.Equal(t, setting{Value: "/home/alice/exercism", Source: sourceDefault}, ec.Workspace)
+	assert.Equal(t, setting{Value: "http://example.com/v1", Source: sourceDefault}, ec.APIBaseURL)
+}
+
+func TestConfigureShowEnv(t *testing.T) {
+	co := newCapturedOutput()
+	co.newOut = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	tmpDir, err := ioutil.TempDir("", "configure-show-env")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	err = ioutil.WriteFile(filepath.Join(tmpDir, "user.json"), []byte(`{"token": "abc123def456", "apibaseurl": "http://configured.example.com"}`), os.FileMode(0600))
+	assert.NoError(t, err)
+
+	oldURL := os.Getenv("EXERCISM_API_BASE_URL")
+	defer os.Setenv("EXERCISM_API_BASE_URL", oldURL)
+	os.Setenv("EXERCISM_API_BASE_URL", "http://env.example.com")
+
+	v := viper.New()
+	v.AddConfigPath(tmpDir)
+	v.SetConfigName("user")
+	v.SetConfigType("json")
+	err = v.ReadInConfig()
+	assert.NoError(t, err)
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupConfigureFlags(flags)
+	err = flags.Parse([]string{"--show", "--json"})
+	assert.NoError(t, err)
+
+	cfg := config.Config{
+		Persister:       config.InMemoryPersister{},
+		UserViperConfig: v,
+		Dir:             tmpDir,
+		Home:            "/home/alice",
+		OS:              "linux",
+		DefaultDirName:  "exercism",
+		DefaultBaseURL:  "http://example.com/v1",
+	}
+
+	err = runConfigure(cfg, flags)
+	assert.NoError(t, err)
+
+	var ec effectiveConfig
+	err = json.Unmarshal(Out.(*bytes.Buffer).Bytes(), &ec)
+	assert.NoError(t, err)
+
+	assert.Equal(t, setting{Value: "http://env.example.com", Source: sourceEnv}, ec.APIBaseURL)
+	assert.Equal(t, setting{Value: "abc1*****456", Source: sourceFile}, ec.Token)
+}
+
 func TestConfigureToken(t *testing.T) {
 	co := newCapturedOutput()
 	co.override()
@@ -168,6 +294,75 @@ func TestConfigureToken(t *testing.T) {
 	}
 }
 
+func TestConfigureTokenFromStdin(t *testing.T) {
+	co := newCapturedOutput()
+	co.override()
+	defer co.reset()
+
+	oldIn := In
+	defer func() { In = oldIn }()
+
+	endpoint := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/validate_token" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})
+	ts := httptest.NewServer(endpoint)
+	defer ts.Close()
+
+	testCases := []struct {
+		desc     string
+		input    string
+		args     []string
+		expected string
+		message  string
+	}{
+		{
+			desc:     "It reads the token from stdin",
+			input:    "piped-token\n",
+			args:     []string{"--no-verify", "--token", "-"},
+			expected: "piped-token",
+		},
+		{
+			desc:    "It complains when stdin is empty",
+			args:    []string{"--no-verify", "--token=-"},
+			message: "no token configured",
+		},
+		{
+			desc:    "It doesn't show an invalid token read from stdin",
+			input:   "secret-token\n",
+			args:    []string{"--token", "-"},
+			message: "^The token is invalid",
+		},
+	}
+
+	for _, tc := range testCases {
+		In = strings.NewReader(tc.input)
+
+		flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+		setupConfigureFlags(flags)
+		err := flags.Parse(tc.args)
+		assert.NoError(t, err)
+
+		cfg := config.Config{
+			Persister:       config.InMemoryPersister{},
+			UserViperConfig: viper.New(),
+			DefaultBaseURL:  ts.URL,
+		}
+
+		err = runConfigure(cfg, flags)
+		if tc.message != "" {
+			if assert.Error(t, err, tc.desc) {
+				assert.Regexp(t, tc.message, err.Error(), tc.desc)
+				assert.NotContains(t, err.Error(), "secret-token", tc.desc)
+			}
+		} else {
+			assert.NoError(t, err, tc.desc)
+		}
+		assert.Equal(t, tc.expected, cfg.UserViperConfig.GetString("token"), tc.desc)
+	}
+}
+
 func TestConfigureAPIBaseURL(t *testing.T) {
 	co := newCapturedOutput()
 	co.override()
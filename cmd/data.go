@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/exercism/cli/api"
+	"github.com/exercism/cli/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// dataCmd groups the commands for the data kept about your account.
+var dataCmd = &cobra.Command{
+	Use:   "data",
+	Short: "Take a copy of your Exercism data.",
+}
+
+var dataExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export your account's data to a zip archive.",
+	Long: `Export your profile, solutions, iterations and mentoring discussions
+into a zip archive, to back them up or to take them elsewhere.
+
+The archive is laid out as:
+
+    export.json                                   when and what was exported
+    profile.json
+    solutions.json
+    solutions/TRACK/EXERCISE/iterations.json
+    solutions/TRACK/EXERCISE/discussions/ID.json
+
+By default it is written to exercism-data-DATE.zip in the current directory.
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.NewConfig()
+
+		v := viper.New()
+		v.AddConfigPath(cfg.Dir)
+		v.SetConfigName("user")
+		v.SetConfigType("json")
+		// Ignore error. If the file doesn't exist, that is fine.
+		_ = v.ReadInConfig()
+		config.BindUserEnv(v)
+		cfg.UserViperConfig = v
+
+		return runDataExport(cfg, cmd.Flags())
+	},
+}
+
+// exportedSolution is the part of a solution that the export needs to find the rest.
+type exportedSolution struct {
+	UUID  string `json:"uuid"`
+	Track struct {
+		Slug string `json:"slug"`
+	} `json:"track"`
+	Exercise struct {
+		Slug string `json:"slug"`
+	} `json:"exercise"`
+}
+
+// exportSummary is written to export.json in the archive.
+type exportSummary struct {
+	ExportedAt  time.Time `json:"exported_at"`
+	Solutions   int       `json:"solutions"`
+	Discussions int       `json:"discussions"`
+}
+
+func runDataExport(cfg config.Config, flags *pflag.FlagSet) error {
+	usrCfg := cfg.UserViperConfig
+	if err := validateUserConfig(usrCfg); err != nil {
+		return err
+	}
+	output, err := flags.GetString("output")
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if output == "" {
+		output = fmt.Sprintf("exercism-data-%s.zip", now.Format("2006-01-02"))
+	}
+
+	client, err := api.NewClient(usrCfg.GetString("token"), usrCfg.GetString("apibaseurl"))
+	if err != nil {
+		return err
+	}
+	baseURL := usrCfg.GetString("apibaseurl")
+
+	// Write next to the destination, so that a failed export doesn't leave half an archive behind.
+	f, err := ioutil.TempFile(filepath.Dir(output), ".exercism-data-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	archive := zip.NewWriter(f)
+
+	fmt.Fprintln(Err, "Exporting your profile...")
+	profile, err := fetchRawJSON(client, fmt.Sprintf("%s/profile", baseURL))
+	if err != nil {
+		return err
+	}
+	if err := writeArchiveJSON(archive, "profile.json", profile); err != nil {
+		return err
+	}
+
+	raw, solutions, err := fetchAllSolutions(client, baseURL)
+	if err != nil {
+		return err
+	}
+	if err := writeArchiveJSON(archive, "solutions.json", raw); err != nil {
+		return err
+	}
+
+	summary := exportSummary{ExportedAt: now, Solutions: len(solutions)}
+	for i, s := range solutions {
+		fmt.Fprintf(Err, "Exporting solution %d of %d: %s in %s\n", i+1, len(solutions), s.Exercise.Slug, s.Track.Slug)
+		dir := path.Join("solutions", s.Track.Slug, s.Exercise.Slug)
+
+		iterations, err := fetchRawJSON(client, fmt.Sprintf("%s/solutions/%s/iterations", baseURL, s.UUID))
+		if err != nil {
+			return err
+		}
+		if err := writeArchiveJSON(archive, path.Join(dir, "iterations.json"), iterations); err != nil {
+			return err
+		}
+
+		var payload struct {
+			Discussions []discussion `json:"discussions"`
+		}
+		if err := fetchJSON(client, fmt.Sprintf("%s/solutions/%s/mentor_discussions", baseURL, s.UUID), &payload); err != nil {
+			return err
+		}
+		for _, d := range payload.Discussions {
+			transcript, err := fetchDiscussionTranscript(client, baseURL, d.UUID)
+			if err != nil {
+				return err
+			}
+			if err := writeArchiveJSON(archive, path.Join(dir, "discussions", d.UUID+".json"), transcript); err != nil {
+				return err
+			}
+			summary.Discussions++
+		}
+	}
+
+	if err := writeArchiveJSON(archive, "export.json", summary); err != nil {
+		return err
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(f.Name(), output); err != nil {
+		return err
+	}
+	fmt.Fprintf(Err, "\nExported %d solutions and %d discussions to\n", summary.Solutions, summary.Discussions)
+	fmt.Fprintf(Out, "%s\n", output)
+	return nil
+}
+
+// fetchAllSolutions requests every page of the list of solutions.
+// It returns the solutions as the API gave them, along with the parts needed to export them.
+func fetchAllSolutions(client *api.Client, baseURL string) ([]json.RawMessage, []exportedSolution, error) {
+	var (
+		raw       []json.RawMessage
+		solutions []exportedSolution
+	)
+	for page := 1; ; page++ {
+		var payload struct {
+			Results []json.RawMessage `json:"results"`
+			Meta    struct {
+				TotalPages int `json:"total_pages"`
+			} `json:"meta"`
+		}
+		if err := fetchJSON(client, fmt.Sprintf("%s/solutions?page=%d", baseURL, page), &payload); err != nil {
+			return nil, nil, err
+		}
+		for _, result := range payload.Results {
+			var s exportedSolution
+			if err := json.Unmarshal(result, &s); err != nil {
+				return nil, nil, fmt.Errorf("unable to parse API response - %s", err)
+			}
+			raw = append(raw, result)
+			solutions = append(solutions, s)
+		}
+		if page >= payload.Meta.TotalPages {
+			return raw, solutions, nil
+		}
+	}
+}
+
+// fetchRawJSON requests a JSON document from the API, keeping it as it is.
+func fetchRawJSON(client *api.Client, url string) (json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := fetchJSON(client, url, &raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+func fetchJSON(client *api.Client, url string, v interface{}) error {
+	req, err := client.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return decodedAPIError(res)
+	}
+	if err := json.NewDecoder(res.Body).Decode(v); err != nil {
+		return fmt.Errorf("unable to parse API response - %s", err)
+	}
+	return nil
+}
+
+func writeArchiveJSON(archive *zip.Writer, name string, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+func setupDataExportFlags(flags *pflag.FlagSet) {
+	flags.StringP("output", "o", "", "the path of the archive to write")
+}
+
+func init() {
+	RootCmd.AddCommand(dataCmd)
+	dataCmd.AddCommand(dataExportCmd)
+	setupDataExportFlags(dataExportCmd.Flags())
+}
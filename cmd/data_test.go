@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataExport(t *testing.T) {
+	co := newCapturedOutput()
+	co.override()
+	defer co.reset()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/profile":
+			fmt.Fprint(w, `{"profile": {"handle": "sam"}}`)
+		case "/solutions":
+			if r.URL.Query().Get("page") == "1" {
+				fmt.Fprint(w, `{"results": [{"uuid": "s1", "track": {"slug": "go"}, "exercise": {"slug": "bob"}}], "meta": {"current_page": 1, "total_pages": 2}}`)
+				return
+			}
+			fmt.Fprint(w, `{"results": [{"uuid": "s2", "track": {"slug": "elixir"}, "exercise": {"slug": "leap"}}], "meta": {"current_page": 2, "total_pages": 2}}`)
+		case "/solutions/s1/iterations", "/solutions/s2/iterations":
+			fmt.Fprint(w, `{"iterations": [{"idx": 1}]}`)
+		case "/solutions/s1/mentor_discussions":
+			fmt.Fprint(w, `{"discussions": [{"uuid": "d1", "status": "finished"}]}`)
+		case "/solutions/s2/mentor_discussions":
+			fmt.Fprint(w, `{"discussions": []}`)
+		case "/mentoring/discussions/d1":
+			fmt.Fprint(w, `{"discussion": {"uuid": "d1", "track": "go", "exercise": "bob", "posts": [{"author_handle": "ana", "content_markdown": "Hi"}]}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	tmpDir, err := ioutil.TempDir("", "data-export")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	output := filepath.Join(tmpDir, "export.zip")
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupDataExportFlags(flags)
+	flags.Set("output", output)
+	assert.NoError(t, runDataExport(fakeUserConfig(ts.URL), flags))
+
+	archive, err := zip.OpenReader(output)
+	assert.NoError(t, err)
+	defer archive.Close()
+
+	contents := map[string]string{}
+	var names []string
+	for _, f := range archive.File {
+		r, err := f.Open()
+		assert.NoError(t, err)
+		b, err := ioutil.ReadAll(r)
+		assert.NoError(t, err)
+		r.Close()
+		names = append(names, f.Name)
+		contents[f.Name] = string(b)
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{
+		"export.json",
+		"profile.json",
+		"solutions.json",
+		"solutions/elixir/leap/iterations.json",
+		"solutions/go/bob/discussions/d1.json",
+		"solutions/go/bob/iterations.json",
+	}, names)
+
+	var summary exportSummary
+	assert.NoError(t, json.Unmarshal([]byte(contents["export.json"]), &summary))
+	assert.Equal(t, 2, summary.Solutions)
+	assert.Equal(t, 1, summary.Discussions)
+	assert.Regexp(t, `"handle": "sam"`, contents["profile.json"])
+	assert.Regexp(t, `"uuid": "s2"`, contents["solutions.json"])
+	assert.Regexp(t, `"content_markdown": "Hi"`, contents["solutions/go/bob/discussions/d1.json"])
+}
+
+func TestDataExportFailureLeavesNoArchive(t *testing.T) {
+	co := newCapturedOutput()
+	co.override()
+	defer co.reset()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/profile" {
+			fmt.Fprint(w, `{"profile": {}}`)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	tmpDir, err := ioutil.TempDir("", "data-export-failure")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupDataExportFlags(flags)
+	flags.Set("output", filepath.Join(tmpDir, "export.zip"))
+	assert.Error(t, runDataExport(fakeUserConfig(ts.URL), flags))
+
+	entries, err := ioutil.ReadDir(tmpDir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
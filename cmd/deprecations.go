@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// silenceDeprecationsEnvKey turns off deprecation notes, e.g. in scripts
+// that haven't been migrated yet.
+const silenceDeprecationsEnvKey = "EXERCISM_SILENCE_DEPRECATIONS"
+
+// deprecation describes a command or flag that has been renamed.
+// The old name keeps working until the release given in RemovedIn.
+type deprecation struct {
+	Kind string `json:"kind"`
+	// Command is the command a deprecated flag belongs to.
+	Command   string `json:"command,omitempty"`
+	Old       string `json:"old"`
+	New       string `json:"new"`
+	Since     string `json:"since"`
+	RemovedIn string `json:"removed_in"`
+}
+
+// Kinds of deprecation.
+const (
+	deprecatedCommand = "command"
+	deprecatedFlag    = "flag"
+)
+
+func (d deprecation) String() string {
+	if d.Kind == deprecatedFlag {
+		return fmt.Sprintf("'%s --%s' is deprecated and will be removed in %s. Use '%s --%s' instead.", d.Command, d.Old, d.RemovedIn, d.Command, d.New)
+	}
+	return fmt.Sprintf("'%s' is deprecated and will be removed in %s. Use '%s' instead.", d.Old, d.RemovedIn, d.New)
+}
+
+// deprecations lists every renamed command and flag.
+// Nothing has been renamed yet.
+var deprecations = []deprecation{}
+
+// applyDeprecations rewrites old command and flag names in the arguments to
+// their new names, printing a note for each one unless notes are silenced.
+func applyDeprecations(root *cobra.Command, args []string) []string {
+	silenced := os.Getenv(silenceDeprecationsEnvKey) != ""
+	for _, arg := range args {
+		if arg == "--silence-deprecations" {
+			silenced = true
+		}
+	}
+	note := func(d deprecation) {
+		if !silenced {
+			fmt.Fprintf(Err, "Deprecated: %s\n", d)
+		}
+	}
+
+	rewritten := make([]string, len(args))
+	copy(rewritten, args)
+
+	for i, arg := range rewritten {
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+		for _, d := range deprecations {
+			if d.Kind == deprecatedCommand && d.Old == arg {
+				rewritten[i] = d.New
+				note(d)
+			}
+		}
+		// Only the first positional argument can name a command.
+		break
+	}
+
+	cmd, _, err := root.Find(rewritten)
+	if err != nil || cmd == nil {
+		return rewritten
+	}
+	for i, arg := range rewritten {
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "--") {
+			continue
+		}
+		name := strings.SplitN(strings.TrimPrefix(arg, "--"), "=", 2)[0]
+		for _, d := range deprecations {
+			if d.Kind == deprecatedFlag && d.Command == cmd.Name() && d.Old == name {
+				rewritten[i] = strings.Replace(arg, "--"+d.Old, "--"+d.New, 1)
+				note(d)
+			}
+		}
+	}
+	return rewritten
+}
+
+// deprecationsCmd lists the commands and flags that are going away.
+var deprecationsCmd = &cobra.Command{
+	Use:   "deprecations",
+	Short: "List deprecated commands and flags.",
+	Long: `List the commands and flags that have been renamed.
+
+The old names keep working until the release in which they are removed,
+printing a short note each time they are used. Silence the notes with
+--silence-deprecations, or by setting EXERCISM_SILENCE_DEPRECATIONS.
+
+Use --json to get the list in a machine-readable format.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDeprecations(cmd.Flags())
+	},
+}
+
+func runDeprecations(flags *pflag.FlagSet) error {
+	asJSON, err := flags.GetBool("json")
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		return json.NewEncoder(Out).Encode(deprecations)
+	}
+
+	if len(deprecations) == 0 {
+		fmt.Fprintln(Err, "Nothing is deprecated.")
+		return nil
+	}
+	w := tabwriter.NewWriter(Out, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintln(w, "OLD\tNEW\tSINCE\tREMOVED IN")
+	for _, d := range deprecations {
+		old, new := d.Old, d.New
+		if d.Kind == deprecatedFlag {
+			old = fmt.Sprintf("%s --%s", d.Command, d.Old)
+			new = fmt.Sprintf("%s --%s", d.Command, d.New)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", old, new, d.Since, d.RemovedIn)
+	}
+	return nil
+}
+
+func setupDeprecationsFlags(flags *pflag.FlagSet) {
+	flags.BoolP("json", "", false, "print the deprecations as JSON")
+}
+
+func init() {
+	RootCmd.AddCommand(deprecationsCmd)
+	setupDeprecationsFlags(deprecationsCmd.Flags())
+}
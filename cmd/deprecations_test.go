@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyDeprecations(t *testing.T) {
+	co := newCapturedOutput()
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	original := deprecations
+	defer func() { deprecations = original }()
+	deprecations = []deprecation{
+		{Kind: deprecatedCommand, Old: "fetch", New: "download", Since: "3.0.14", RemovedIn: "4.0.0"},
+		{Kind: deprecatedFlag, Command: "download", Old: "slug", New: "exercise", Since: "3.0.14", RemovedIn: "4.0.0"},
+	}
+
+	root := &cobra.Command{Use: "exercism"}
+	download := &cobra.Command{Use: "download", Run: func(*cobra.Command, []string) {}}
+	setupDownloadFlags(download.Flags())
+	root.AddCommand(download)
+
+	args := applyDeprecations(root, []string{"fetch", "--slug=leap", "--track", "go"})
+	assert.Equal(t, []string{"download", "--exercise=leap", "--track", "go"}, args)
+	assert.Regexp(t, "'fetch' is deprecated and will be removed in 4.0.0. Use 'download' instead.", Err)
+	assert.Regexp(t, "'download --slug' is deprecated", Err)
+
+	// Other commands' flags are left alone.
+	args = applyDeprecations(root, []string{"submit", "--slug", "leap"})
+	assert.Equal(t, []string{"submit", "--slug", "leap"}, args)
+
+	Err = &bytes.Buffer{}
+	args = applyDeprecations(root, []string{"fetch", "--silence-deprecations"})
+	assert.Equal(t, []string{"download", "--silence-deprecations"}, args)
+	assert.Equal(t, "", Err.(*bytes.Buffer).String())
+
+	os.Setenv(silenceDeprecationsEnvKey, "1")
+	defer os.Unsetenv(silenceDeprecationsEnvKey)
+	applyDeprecations(root, []string{"fetch"})
+	assert.Equal(t, "", Err.(*bytes.Buffer).String())
+}
+
+func TestDeprecationsJSON(t *testing.T) {
+	co := newCapturedOutput()
+	co.newOut = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupDeprecationsFlags(flags)
+	err := flags.Parse([]string{"--json"})
+	assert.NoError(t, err)
+
+	err = runDeprecations(flags)
+	assert.NoError(t, err)
+
+	var listed []deprecation
+	err = json.Unmarshal(Out.(*bytes.Buffer).Bytes(), &listed)
+	assert.NoError(t, err)
+	assert.Equal(t, deprecations, listed)
+}
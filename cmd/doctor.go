@@ -0,0 +1,414 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/exercism/cli/api"
+	"github.com/exercism/cli/config"
+	"github.com/exercism/cli/snapshot"
+	"github.com/exercism/cli/workspace"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// doctorCmd checks that the CLI is set up to work, and says how to fix what isn't.
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that the CLI is set up correctly.",
+	Long: `Check that the CLI is set up correctly, and say how to fix what isn't.
+
+It checks the config file, the token, that the API can be reached, that the
+workspace exists and can be written to, and the metadata of the exercises in it.
+
+Pass --fix to make the repairs that are safe to make without asking:
+creating the workspace, moving legacy metadata to where it belongs, and
+removing copies of a solution that have the same files as the one that is
+kept. The copies are backed up first, to bring back with 'backups restore'.
+The other problems are left for you, with the commands to fix them.
+
+Pass --track to also check that the tools the track needs are installed and
+recent enough, and that its tests can be run. If the tests of an exercise don't
+run, start here:
+
+    exercism doctor --track=python
+
+To share the details of your setup in an issue, use the troubleshoot command.
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.NewConfig()
+
+		v := viper.New()
+		v.AddConfigPath(cfg.Dir)
+		v.SetConfigName("user")
+		v.SetConfigType("json")
+		// Ignore error. If the file doesn't exist, that is fine.
+		_ = v.ReadInConfig()
+		config.BindUserEnv(v)
+		cfg.UserViperConfig = v
+
+		return runDoctor(cfg, cmd.Flags())
+	},
+}
+
+// Outcomes of a doctor check.
+const (
+	doctorPass = "pass"
+	doctorFail = "fail"
+	// doctorSkip is for checks that depend on one that failed.
+	doctorSkip = "skip"
+)
+
+// doctorCheck is the outcome of one of the things doctor checks.
+type doctorCheck struct {
+	Name   string
+	Status string
+	Detail string
+	// Fixes are the commands or steps that fix the problem.
+	Fixes []string
+	// repair fixes the problem, for problems that are safe to fix without asking,
+	// and checks again.
+	repair func() (*doctorCheck, error)
+}
+
+func runDoctor(cfg config.Config, flags *pflag.FlagSet) error {
+	fix, err := flags.GetBool("fix")
+	if err != nil {
+		return err
+	}
+	track, err := flags.GetString("track")
+	if err != nil {
+		return err
+	}
+
+	checks := doctorChecks(cfg)
+	if track != "" {
+		checks = append(checks, toolchainChecks(cfg.UserViperConfig, track)...)
+	}
+	failed := 0
+	for i, check := range checks {
+		if check.Status == doctorFail && fix && check.repair != nil {
+			repaired, err := check.repair()
+			if err != nil {
+				check.Detail = fmt.Sprintf("%s (unable to fix: %s)", check.Detail, err)
+			} else {
+				fmt.Fprintf(Err, "Fixed: %s: %s\n", check.Name, check.Detail)
+				check, checks[i] = repaired, repaired
+			}
+		}
+		printDoctorCheck(check)
+		if check.Status == doctorFail {
+			failed++
+		}
+	}
+
+	if failed == 0 {
+		fmt.Fprintf(Out, "\nNo problems found.\n")
+		return nil
+	}
+	if !fix {
+		for _, check := range checks {
+			if check.Status == doctorFail && check.repair != nil {
+				fmt.Fprintf(Err, "\nSome of the problems can be fixed with '%s doctor --fix'.\n", BinaryName)
+				break
+			}
+		}
+	}
+	return fmt.Errorf("found %d problem(s) with the setup of the CLI", failed)
+}
+
+// doctorChecks runs the checks, in order, skipping those that depend on one that failed.
+func doctorChecks(cfg config.Config) []*doctorCheck {
+	usrCfg := cfg.UserViperConfig
+	var checks []*doctorCheck
+
+	configCheck := checkConfigFile(cfg.Dir)
+	checks = append(checks, configCheck)
+
+	tokenCheck := &doctorCheck{Name: "Token", Status: doctorPass, Detail: "configured"}
+	if usrCfg.GetString("token") == "" {
+		tokenCheck.Status = doctorFail
+		tokenCheck.Detail = "not configured"
+		tokenCheck.Fixes = []string{
+			fmt.Sprintf("Find your token on %s", config.SettingsURL(usrCfg.GetString("apibaseurl"))),
+			fmt.Sprintf("%s configure --token=YOUR_TOKEN", BinaryName),
+		}
+	}
+	checks = append(checks, tokenCheck)
+
+	baseURL := usrCfg.GetString("apibaseurl")
+	if baseURL == "" {
+		baseURL = cfg.DefaultBaseURL
+	}
+	apiCheck := &doctorCheck{Name: "API", Status: doctorPass, Detail: fmt.Sprintf("%s can be reached", baseURL)}
+	client, err := api.NewClient(usrCfg.GetString("token"), baseURL)
+	if err == nil {
+		err = client.IsPingable()
+	}
+	if err != nil {
+		apiCheck.Status = doctorFail
+		apiCheck.Detail = fmt.Sprintf("%s can't be reached: %s", baseURL, err)
+		apiCheck.Fixes = []string{
+			"Check your internet connection, and any proxy in HTTPS_PROXY",
+			fmt.Sprintf("%s configure --api=API_URL", BinaryName),
+		}
+	}
+	checks = append(checks, apiCheck)
+
+	tokenValidCheck := &doctorCheck{Name: "Token is valid", Status: doctorSkip, Detail: "the API can't be asked"}
+	if tokenCheck.Status == doctorPass && apiCheck.Status == doctorPass {
+		ok, err := client.TokenIsValid()
+		switch {
+		case err != nil:
+			tokenValidCheck.Status = doctorFail
+			tokenValidCheck.Detail = fmt.Sprintf("unable to check: %s", err)
+		case !ok:
+			tokenValidCheck.Status = doctorFail
+			tokenValidCheck.Detail = "the API doesn't accept the token"
+			tokenValidCheck.Fixes = []string{
+				fmt.Sprintf("Find your token on %s", config.SettingsURL(baseURL)),
+				fmt.Sprintf("%s configure --token=YOUR_TOKEN", BinaryName),
+			}
+		default:
+			tokenValidCheck.Status = doctorPass
+			tokenValidCheck.Detail = "the API accepts the token"
+		}
+	}
+	checks = append(checks, tokenValidCheck)
+
+	workspaceCheck := checkWorkspaceDir(usrCfg.GetString("workspace"))
+	checks = append(checks, workspaceCheck)
+
+	writableCheck := &doctorCheck{Name: "Workspace is writable", Status: doctorSkip, Detail: "there is no workspace"}
+	exercisesCheck := &doctorCheck{Name: "Exercises", Status: doctorSkip, Detail: "there is no workspace"}
+	if workspaceCheck.Status == doctorPass {
+		writableCheck = checkWritable("Workspace is writable", usrCfg.GetString("workspace"))
+		exercisesCheck = checkExercises(cfg)
+	}
+	checks = append(checks, writableCheck, exercisesCheck)
+
+	if cfg.StateDir != "" {
+		checks = append(checks, checkWritable("State directory is writable", cfg.StateDir))
+	}
+	return checks
+}
+
+// checkConfigFile checks that the user config exists and is valid JSON.
+func checkConfigFile(dir string) *doctorCheck {
+	path := filepath.Join(dir, "user.json")
+	check := &doctorCheck{Name: "Config file", Status: doctorPass, Detail: path}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		check.Status = doctorFail
+		check.Detail = fmt.Sprintf("%s doesn't exist", path)
+		check.Fixes = []string{fmt.Sprintf("%s configure --token=YOUR_TOKEN", BinaryName)}
+		return check
+	}
+	if err != nil {
+		check.Status = doctorFail
+		check.Detail = fmt.Sprintf("%s can't be read: %s", path, err)
+		return check
+	}
+	var settings map[string]interface{}
+	if err := json.Unmarshal(b, &settings); err != nil {
+		check.Status = doctorFail
+		check.Detail = fmt.Sprintf("%s isn't valid JSON: %s", path, err)
+		check.Fixes = []string{
+			"Fix the file by hand, or remove it and configure the CLI again:",
+			fmt.Sprintf("%s configure --token=YOUR_TOKEN", BinaryName),
+		}
+	}
+	return check
+}
+
+// checkWorkspaceDir checks that the workspace is configured and is a directory.
+// A workspace that doesn't exist yet is safe to create.
+func checkWorkspaceDir(dir string) *doctorCheck {
+	check := &doctorCheck{Name: "Workspace", Status: doctorPass, Detail: dir}
+	if dir == "" {
+		check.Status = doctorFail
+		check.Detail = "not configured"
+		check.Fixes = []string{fmt.Sprintf("%s configure --workspace=PATH", BinaryName)}
+		return check
+	}
+	info, err := os.Stat(dir)
+	switch {
+	case os.IsNotExist(err):
+		check.Status = doctorFail
+		check.Detail = fmt.Sprintf("%s doesn't exist", dir)
+		check.Fixes = []string{fmt.Sprintf("mkdir -p %q", dir)}
+		check.repair = func() (*doctorCheck, error) {
+			if err := os.MkdirAll(dir, os.FileMode(0755)); err != nil {
+				return nil, err
+			}
+			return checkWorkspaceDir(dir), nil
+		}
+	case err != nil:
+		check.Status = doctorFail
+		check.Detail = fmt.Sprintf("%s can't be read: %s", dir, err)
+	case !info.IsDir():
+		check.Status = doctorFail
+		check.Detail = fmt.Sprintf("%s is a file, not a directory", dir)
+		check.Fixes = []string{fmt.Sprintf("%s configure --workspace=PATH", BinaryName)}
+	}
+	return check
+}
+
+// checkWritable checks that files can be created in the directory.
+func checkWritable(name, dir string) *doctorCheck {
+	check := &doctorCheck{Name: name, Status: doctorPass, Detail: dir}
+	if err := os.MkdirAll(dir, os.FileMode(0755)); err != nil {
+		check.Status = doctorFail
+		check.Detail = fmt.Sprintf("%s can't be created: %s", dir, err)
+		return check
+	}
+	f, err := ioutil.TempFile(dir, ".exercism-doctor")
+	if err != nil {
+		check.Status = doctorFail
+		check.Detail = fmt.Sprintf("%s can't be written to: %s", dir, err)
+		check.Fixes = []string{fmt.Sprintf("chmod u+w %q", dir)}
+		return check
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return check
+}
+
+// checkExercises checks the metadata of the exercises in the workspace.
+// The repairs that are safe to make are moving legacy metadata to where it belongs,
+// and removing copies of a solution that have the same files as the one that is kept,
+// once they are backed up; the other problems need a person to decide what to keep or where to move it.
+func checkExercises(cfg config.Config) *doctorCheck {
+	usrCfg := cfg.UserViperConfig
+	check := &doctorCheck{Name: "Exercises", Status: doctorPass}
+	ws, err := openWorkspace(usrCfg)
+	if err != nil {
+		check.Status = doctorFail
+		check.Detail = err.Error()
+		return check
+	}
+	report, err := ws.CheckHealth()
+	if err != nil {
+		check.Status = doctorFail
+		check.Detail = fmt.Sprintf("unable to check the workspace: %s", err)
+		return check
+	}
+	check.Detail = fmt.Sprintf("%d exercise(s), no problems", report.Exercises)
+	if report.IsHealthy() {
+		return check
+	}
+
+	check.Status = doctorFail
+	var descriptions []string
+	for _, issue := range healthIssues(report) {
+		descriptions = append(descriptions, fmt.Sprintf("%s: %d", issue.Description, issue.Count))
+		check.Fixes = append(check.Fixes, issue.Fixes...)
+	}
+	check.Detail = fmt.Sprintf("%d exercise(s); %s", report.Exercises, strings.Join(descriptions, "; "))
+
+	copies := identicalCopies(ws, report)
+	for dir, kept := range copies {
+		check.Fixes = append(check.Fixes, fmt.Sprintf("%s has the same files as %s, so it can be removed", dir, kept))
+	}
+	if len(report.LegacyMetadata) > 0 || len(copies) > 0 {
+		check.repair = func() (*doctorCheck, error) {
+			for _, dir := range report.LegacyMetadata {
+				if _, err := workspace.NewExerciseFromDir(dir).MigrateLegacyMetadataFile(); err != nil {
+					return nil, err
+				}
+			}
+			if err := removeCopies(snapshotStore(cfg), copies); err != nil {
+				return nil, err
+			}
+			return checkExercises(cfg), nil
+		}
+	}
+	return check
+}
+
+// removeCopies removes the directories of identical copies, each backed up first.
+func removeCopies(store *snapshot.Store, copies map[string]string) error {
+	if len(copies) > 0 && store == nil {
+		return errNoBackupsDir
+	}
+	for dir := range copies {
+		var paths []string
+		err := filepath.Walk(workspace.LongPath(dir), func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(workspace.LongPath(dir), path)
+			paths = append(paths, rel)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		snap, err := store.Take(dir, paths, "doctor --fix")
+		if err != nil {
+			return fmt.Errorf("unable to back up %s before removing it: %s", dir, err)
+		}
+		if err := os.RemoveAll(workspace.LongPath(dir)); err != nil {
+			return err
+		}
+		if snap != nil {
+			fmt.Fprintf(Err, "Removed %s, kept in backup %s\n", dir, snap.ID)
+		}
+	}
+	return nil
+}
+
+// identicalCopies are the directories of duplicated solutions that have the same files
+// as the directory that is kept, mapped to that directory. The one that is kept is the one
+// where the exercise would be downloaded to, or else the first.
+func identicalCopies(ws workspace.Workspace, report workspace.HealthReport) map[string]string {
+	copies := map[string]string{}
+	for _, dirs := range report.DuplicateIDs {
+		kept := dirs[0]
+		for _, dir := range dirs {
+			if metadata, err := workspace.NewExerciseMetadata(dir); err == nil && ws.ExerciseFor(metadata).Filepath() == dir {
+				kept = dir
+				break
+			}
+		}
+		for _, dir := range dirs {
+			if dir == kept {
+				continue
+			}
+			if same, err := workspace.SameFiles(kept, dir); err == nil && same {
+				copies[dir] = kept
+			}
+		}
+	}
+	return copies
+}
+
+func printDoctorCheck(check *doctorCheck) {
+	symbol := glyphCompleted
+	switch check.Status {
+	case doctorFail:
+		symbol = glyphFailed
+	case doctorSkip:
+		symbol = glyphAvailable
+	}
+	fmt.Fprintf(Out, "%s %s: %s\n", symbol, check.Name, check.Detail)
+	for _, fix := range check.Fixes {
+		fmt.Fprintf(Out, "    %s\n", fix)
+	}
+}
+
+func setupDoctorFlags(flags *pflag.FlagSet) {
+	flags.Bool("fix", false, "make the repairs that are safe to make without asking")
+	flags.StringP("track", "t", "", "also check the tools that the track's exercises need")
+}
+
+func init() {
+	RootCmd.AddCommand(doctorCmd)
+	setupDoctorFlags(doctorCmd.Flags())
+}
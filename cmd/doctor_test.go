@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/exercism/cli/config"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoctor(t *testing.T) {
+	co := newCapturedOutput()
+	co.newOut = &bytes.Buffer{}
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/validate_token" && r.Header.Get("Authorization") != "Bearer abc123" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+		fmt.Fprint(w, "{}")
+	}))
+	defer ts.Close()
+
+	tmpDir, err := ioutil.TempDir("", "doctor")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+	configDir := filepath.Join(tmpDir, "config")
+	assert.NoError(t, os.MkdirAll(configDir, os.FileMode(0755)))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(configDir, "user.json"), []byte(`{"token": "abc123"}`), os.FileMode(0644)))
+
+	ws := filepath.Join(tmpDir, "workspace")
+	legacy := filepath.Join(ws, "go", "leap")
+	assert.NoError(t, os.MkdirAll(legacy, os.FileMode(0755)))
+	solution := `{"track": "go", "exercise": "leap", "id": "bogus-solution-uuid", "is_requester": true}`
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(legacy, ".solution.json"), []byte(solution), os.FileMode(0644)))
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", ws)
+	v.Set("apibaseurl", ts.URL)
+	cfg := config.Config{
+		Dir:             configDir,
+		StateDir:        filepath.Join(tmpDir, "state"),
+		UserViperConfig: v,
+	}
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupDoctorFlags(flags)
+
+	err = runDoctor(cfg, flags)
+	if assert.Error(t, err) {
+		assert.Regexp(t, "found 1 problem", err.Error())
+	}
+	out := co.newOut.(*bytes.Buffer).String()
+	assert.Regexp(t, "Token is valid: the API accepts the token", out)
+	assert.Regexp(t, "legacy metadata", out)
+	assert.Regexp(t, "doctor --fix", co.newErr.(*bytes.Buffer).String())
+
+	co.newOut.(*bytes.Buffer).Reset()
+	flags.Set("fix", "true")
+	assert.NoError(t, runDoctor(cfg, flags))
+	assert.Regexp(t, "1 exercise\\(s\\), no problems", co.newOut.(*bytes.Buffer).String())
+	_, err = os.Stat(filepath.Join(legacy, ".exercism", "metadata.json"))
+	assert.NoError(t, err)
+}
+
+func TestDoctorChecks(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/validate_token" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer ts.Close()
+
+	tmpDir, err := ioutil.TempDir("", "doctor-checks")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(tmpDir, "user.json"), []byte(`{"token": `), os.FileMode(0644)))
+
+	v := viper.New()
+	v.Set("token", "bogus")
+	v.Set("workspace", filepath.Join(tmpDir, "missing"))
+	v.Set("apibaseurl", ts.URL)
+	checks := doctorChecks(config.Config{Dir: tmpDir, UserViperConfig: v})
+
+	statuses := map[string]*doctorCheck{}
+	for _, check := range checks {
+		statuses[check.Name] = check
+	}
+	assert.Equal(t, doctorFail, statuses["Config file"].Status)
+	assert.Regexp(t, "isn't valid JSON", statuses["Config file"].Detail)
+	assert.Equal(t, doctorPass, statuses["API"].Status)
+	assert.Equal(t, doctorFail, statuses["Token is valid"].Status)
+	assert.Equal(t, doctorFail, statuses["Workspace"].Status)
+	assert.NotNil(t, statuses["Workspace"].repair)
+	assert.Equal(t, doctorSkip, statuses["Exercises"].Status)
+}
+
+func TestToolchainChecks(t *testing.T) {
+	// The go tool is at hand wherever these tests run.
+	trackToolchains["bogus-track"] = []toolchainProbe{
+		{Command: []string{"go", "version"}, MinVersion: "1.0"},
+		{Command: []string{"go", "version"}, MinVersion: "999.0"},
+		{Command: []string{"bogus-compiler", "--version"}},
+	}
+	defer delete(trackToolchains, "bogus-track")
+	trackTestCommands["bogus-track"] = []string{"go", "test"}
+	defer delete(trackTestCommands, "bogus-track")
+
+	checks := toolchainChecks(viper.New(), "bogus-track")
+	if assert.Len(t, checks, 4) {
+		assert.Equal(t, doctorPass, checks[0].Status)
+		assert.Regexp(t, "go version", checks[0].Detail)
+		assert.Equal(t, doctorFail, checks[1].Status)
+		assert.Regexp(t, "the track needs 999.0 or later", checks[1].Detail)
+		assert.Equal(t, doctorFail, checks[2].Status)
+		assert.Regexp(t, "bogus-compiler isn't installed", checks[2].Detail)
+		assert.Regexp(t, "tracks/bogus-track/installation", checks[2].Fixes[0])
+		assert.Equal(t, doctorPass, checks[3].Status)
+	}
+
+	delete(trackTestCommands, "bogus-track")
+	checks = toolchainChecks(viper.New(), "bogus-track")
+	assert.Equal(t, doctorFail, checks[3].Status)
+	assert.Regexp(t, "no known command", checks[3].Detail)
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, 0, compareVersions("1.18", "1.18.0"))
+	assert.Equal(t, 1, compareVersions("1.20.3", "1.18"))
+	assert.Equal(t, -1, compareVersions("3.6.9", "3.7"))
+	assert.Equal(t, 1, compareVersions("11", "8"))
+}
+
+func TestDoctorRemovesIdenticalCopies(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "doctor-copies")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	leap := filepath.Join(tmpDir, "go", "leap")
+	backup := filepath.Join(tmpDir, "go", "leap-backup")
+	for _, dir := range []string{leap, backup} {
+		assert.NoError(t, os.MkdirAll(dir, os.FileMode(0755)))
+		writeFakeMetadata(t, dir, "go", "leap")
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "leap.go"), []byte("package leap"), os.FileMode(0644)))
+	}
+
+	v := viper.New()
+	v.Set("workspace", tmpDir)
+	cfg := config.Config{UserViperConfig: v}
+
+	// Without anywhere to back them up, the copies are left alone.
+	check := checkExercises(cfg)
+	assert.Equal(t, doctorFail, check.Status)
+	if assert.NotNil(t, check.repair) {
+		_, err = check.repair()
+		assert.Equal(t, errNoBackupsDir, err)
+	}
+	_, err = os.Stat(backup)
+	assert.NoError(t, err)
+
+	co := newCapturedOutput()
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	cfg.StateDir = filepath.Join(tmpDir, "state")
+	check = checkExercises(cfg)
+	assert.Equal(t, doctorFail, check.Status)
+	if assert.NotNil(t, check.repair) {
+		check, err = check.repair()
+		assert.NoError(t, err)
+		assert.Equal(t, doctorPass, check.Status)
+	}
+	_, err = os.Stat(backup)
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(leap)
+	assert.NoError(t, err)
+
+	// The copy can be brought back.
+	snaps, err := snapshotStore(cfg).List()
+	assert.NoError(t, err)
+	if assert.Len(t, snaps, 1) {
+		assert.Equal(t, "doctor --fix", snaps[0].Reason)
+		assert.Equal(t, backup, snaps[0].Dir)
+		assert.Len(t, snaps[0].Files, 2)
+		assert.Regexp(t, "Removed "+regexp.QuoteMeta(backup)+", kept in backup "+snaps[0].ID, co.newErr.(*bytes.Buffer).String())
+	}
+}
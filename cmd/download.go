@@ -5,17 +5,23 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"net/http"
 	netURL "net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/exercism/cli/api"
 	"github.com/exercism/cli/config"
+	"github.com/exercism/cli/debug"
+	"github.com/exercism/cli/editor"
+	"github.com/exercism/cli/merge"
+	"github.com/exercism/cli/snapshot"
 	"github.com/exercism/cli/workspace"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -24,9 +30,10 @@ import (
 
 // downloadCmd represents the download command
 var downloadCmd = &cobra.Command{
-	Use:     "download",
-	Aliases: []string{"d"},
-	Short:   "Download an exercise.",
+	Use:        "download [LINK]",
+	Aliases:    []string{"d"},
+	SuggestFor: []string{"get", "fetch", "pull"},
+	Short:      "Download an exercise.",
 	Long: `Download an exercise.
 
 You may download an exercise to work on. If you've already
@@ -34,6 +41,76 @@ started working on it, the command will also download your
 latest solution.
 
 Download other people's solutions by providing the UUID.
+
+Instead of the flags, you can paste the link to the exercise or the
+solution from the website, or the UUID of the solution:
+
+    exercism download https://exercism.org/tracks/go/exercises/hamming
+
+Run it without saying what to download to choose the track and then the
+exercise from a list, which narrows down as you type. Pass --no-input to
+get an error instead, as scripts would want.
+
+Download several exercises from the same track at once by listing them:
+
+    exercism download --track=python --exercise=two-fer,leap,hamming
+
+Or download the whole track with --all, or only the exercises you have
+unlocked with --all-unlocked:
+
+    exercism download --track=go --all-unlocked
+
+Files are downloaded several at a time; pass --jobs to say how many.
+If a download is interrupted, running it again only fetches the files
+that are still missing.
+
+Files you have changed are never overwritten without asking. Pass --theirs
+(or --force) to take the files from the website, --ours to keep yours, or
+--interactive to choose for each file. Overwritten files are backed up first,
+see 'exercism backups --help' for how to get them back.
+
+Or say what to do with --on-conflict: skip is the same as --ours, overwrite
+the same as --theirs, and ask the same as --interactive. backup is --theirs,
+but fails rather than overwrite anything if there's nowhere to keep the backup.
+
+Get back the files of an earlier iteration with --iteration. They are written
+to the exercise directory, where files that differ from them are handled like
+files you've changed, or to a directory in it given with --into:
+
+    exercism download --track=go --exercise=leap --iteration=3 --into=v3
+
+Tracks often improve the instructions of their exercises. Pass --docs-only
+to refresh the README.md, HELP.md, HINTS.md and .docs of an exercise you've
+downloaded without touching its solution or test files.
+
+When a track revises an exercise you've started, pass --update to merge the
+changes into your files. Lines that both you and the track changed are marked
+with <<<<<<< mine, ======= and >>>>>>> theirs for you to sort out.
+
+Downloaded files get the permissions 0644, or 0755 for the files the track
+marks as executable, such as scripts, less your umask. Files that are already
+there keep theirs. Change the defaults with download.file_mode and
+download.executable_mode in your user config. Files are dated when they were
+last changed on the website, if the server says.
+
+The line_endings setting in your user config converts the line endings of
+downloaded files: auto (the default) leaves them as they are, lf and crlf
+convert them, and native uses the ones of your platform.
+
+When following a track's syllabus, pass --with-concepts to also download the
+introduction and about documents of the concepts a concept exercise builds on,
+as the website would show them. They go in .docs/concepts in the exercise.
+
+To keep concept exercises apart from practice exercises, set concept_dir in
+your user config to the name of a directory, such as concepts. Concept
+exercises are then downloaded to <workspace>/concepts/<track>/<exercise>.
+
+Pass --open to open the exercise in your editor once it's downloaded. The
+editor is found the same way as for 'exercism open --editor'.
+
+Revert the most recent download with --undo. This removes the files it added
+and restores the files it overwrote, whether you had changed them or not.
+Run it again to revert the download before.
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg := config.NewConfig()
@@ -44,6 +121,7 @@ Download other people's solutions by providing the UUID.
 		v.SetConfigType("json")
 		// Ignore error. If the file doesn't exist, that is fine.
 		_ = v.ReadInConfig()
+		config.BindUserEnv(v)
 		cfg.UserViperConfig = v
 
 		return runDownload(cfg, cmd.Flags(), args)
@@ -56,75 +134,886 @@ func runDownload(cfg config.Config, flags *pflag.FlagSet, args []string) error {
 		return err
 	}
 
-	download, err := newDownload(flags, usrCfg)
+	undo, err := flags.GetBool("undo")
 	if err != nil {
 		return err
 	}
+	if undo {
+		return runDownloadUndo(cfg)
+	}
+	if len(args) > 0 {
+		if err := applyDownloadLink(flags, args); err != nil {
+			return err
+		}
+	}
 
-	metadata := download.payload.metadata()
-	dir := metadata.Exercise(usrCfg.GetString("workspace")).MetadataDir()
+	slugs, err := exerciseSlugs(flags)
+	if err != nil {
+		return err
+	}
+	all, err := flags.GetBool("all")
+	if err != nil {
+		return err
+	}
+	allUnlocked, err := flags.GetBool("all-unlocked")
+	if err != nil {
+		return err
+	}
+	open, err := flags.GetBool("open")
+	if err != nil {
+		return err
+	}
+	if open && (all || allUnlocked || len(slugs) > 1) {
+		return errors.New("--open opens one exercise at a time")
+	}
+	uuid, err := flags.GetString("uuid")
+	if err != nil {
+		return err
+	}
+	if len(slugs) == 0 && uuid == "" && !all && !allUnlocked {
+		picking, err := wantsPicker(flags)
+		if err != nil {
+			return err
+		}
+		if picking {
+			if err := pickDownload(cfg, flags); err != nil {
+				return err
+			}
+			if slugs, err = exerciseSlugs(flags); err != nil {
+				return err
+			}
+		}
+	}
+	if all || allUnlocked {
+		if slugs, err = trackSlugs(cfg, flags, slugs, allUnlocked); err != nil || len(slugs) == 0 {
+			return err
+		}
+		return runDownloadMany(cfg, flags, slugs)
+	}
+	if len(slugs) > 1 {
+		return runDownloadMany(cfg, flags, slugs)
+	}
 
-	if err := os.MkdirAll(dir, os.FileMode(0755)); err != nil {
+	var download *download
+	err = withRetry(cfg, func() error {
+		download, err = newDownload(flags, usrCfg)
+		return err
+	})
+	if err != nil {
 		return err
 	}
+	download.snapshots = snapshotStore(cfg)
+	download.stateDir = cfg.StateDir
 
-	if err := metadata.Write(dir); err != nil {
+	var dir string
+	// Nothing is written until all the files have been fetched, so a failure to fetch them can be retried.
+	err = withRetry(cfg, func() error {
+		dir, err = download.write()
+		return err
+	})
+	if err != nil {
 		return err
 	}
+	printExerciseSummary(download.payload.metadata())
+	fmt.Fprintf(Err, "\nDownloaded to\n")
+	fmt.Fprintf(Out, "%s\n", dir)
+	if open {
+		if err := openInEditor(usrCfg, dir); err != nil {
+			return fmt.Errorf("the exercise was downloaded, but couldn't be opened: %s", err)
+		}
+	}
+	return nil
+}
 
-	client, err := api.NewClient(usrCfg.GetString("token"), usrCfg.GetString("apibaseurl"))
+// openInEditor opens a downloaded exercise in the user's editor. It's swapped out in tests.
+var openInEditor = func(usrCfg *viper.Viper, dir string) error {
+	template, err := editor.Resolve(usrCfg.GetString("editor.command"))
 	if err != nil {
 		return err
 	}
+	return editor.Open(template, dir, 0)
+}
 
-	for _, sf := range download.payload.files() {
-		url, err := sf.url()
+// runDownloadUndo reverts the most recent download.
+// The files it removes are kept in a snapshot, in case they have been worked on since.
+func runDownloadUndo(cfg config.Config) error {
+	store := snapshotStore(cfg)
+	if store == nil {
+		return errors.New("there is no record of any downloads to undo")
+	}
+	op, err := lastOperation(cfg.StateDir)
+	if err != nil {
+		return err
+	}
+	if op == nil {
+		return errors.New("there is no download to undo")
+	}
+
+	var removable []string
+	for _, path := range op.Created {
+		if _, err := os.Lstat(filepath.Join(op.Dir, path)); err == nil {
+			removable = append(removable, path)
+		}
+	}
+	if len(removable) > 0 {
+		snap, err := store.Take(op.Dir, removable, fmt.Sprintf("download --undo %s/%s", op.Track, op.Exercise))
 		if err != nil {
-			return err
+			return fmt.Errorf("unable to back up the files before removing them: %s", err)
 		}
+		for _, path := range removable {
+			if err := os.Remove(filepath.Join(op.Dir, path)); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(Err, "Removed %d file(s), kept in backup %s\n", len(removable), snap.ID)
+	}
 
-		req, err := client.NewRequest("GET", url, nil)
+	if op.Snapshot != "" {
+		snap, err := store.Get(op.Snapshot)
 		if err != nil {
+			return fmt.Errorf("unable to find the backup of the overwritten files (%s): %s", op.Snapshot, err)
+		}
+		if err := store.Restore(*snap); err != nil {
 			return err
 		}
+		fmt.Fprintf(Err, "Restored %d overwritten file(s)\n", len(snap.Files))
+	}
 
-		res, err := client.Do(req)
-		if err != nil {
+	removed := removable
+	if !op.CreatedDir {
+		for _, path := range op.CreatedOriginals {
+			if err := os.Remove(filepath.Join(workspace.OriginalsDir(op.Dir), path)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	if op.CreatedDir {
+		exercise := workspace.NewExerciseFromDir(op.Dir)
+		if err := os.RemoveAll(workspace.OriginalsDir(op.Dir)); err != nil {
 			return err
 		}
-		defer res.Body.Close()
+		for _, path := range []string{exercise.MetadataFilepath(), workspace.ChecksumsFilepath(op.Dir)} {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			rel, err := filepath.Rel(op.Dir, path)
+			if err != nil {
+				return err
+			}
+			removed = append(removed, rel)
+		}
+	}
+	removeEmptyParents(op.Dir, removed, op.CreatedDir)
+
+	if err := dropLastOperation(cfg.StateDir); err != nil {
+		return err
+	}
+	fmt.Fprintf(Err, "\nUndid the download of %s/%s in\n", op.Track, op.Exercise)
+	fmt.Fprintf(Out, "%s\n", op.Dir)
+	return nil
+}
+
+// removeEmptyParents removes the directories that held the given files, relative to root,
+// if they are now empty. The root itself is only removed if asked to.
+func removeEmptyParents(root string, paths []string, includeRoot bool) {
+	for _, path := range paths {
+		for dir := filepath.Dir(filepath.Join(root, path)); dir != root && strings.HasPrefix(dir, root); dir = filepath.Dir(dir) {
+			if err := os.Remove(dir); err != nil {
+				// Not empty, or already gone.
+				break
+			}
+		}
+	}
+	if includeRoot {
+		// Fails harmlessly if anything else is in there.
+		os.Remove(root)
+	}
+}
 
-		if res.StatusCode != http.StatusOK {
-			// TODO: deal with it
+// runDownloadMany downloads several exercises from the same track in one go.
+// A failure to download one exercise doesn't stop the others from being downloaded.
+func runDownloadMany(cfg config.Config, flags *pflag.FlagSet, slugs []string) error {
+	base, err := newDownloadForSlugs(flags, cfg.UserViperConfig, slugs)
+	if err != nil {
+		return err
+	}
+	base.snapshots = snapshotStore(cfg)
+	base.stateDir = cfg.StateDir
+
+	// Fetching is done concurrently, but the files are written one exercise at a time,
+	// so that conflicts can be resolved and the output stays in order.
+	downloads := make([]download, len(slugs))
+	fetched := make([][]downloadedFile, len(slugs))
+	errs := each(len(slugs), func(i int) (err error) {
+		downloads[i] = *base
+		downloads[i].slug = slugs[i]
+		if err = base.limit.do(downloads[i].requestPayload); err != nil {
+			return err
+		}
+		fetched[i], err = downloads[i].fetchFiles()
+		return err
+	})
+
+	dirs := make([]string, 0, len(slugs))
+	failures := make(map[string]error)
+	for i, slug := range slugs {
+		fmt.Fprintf(Err, "[%d/%d] Downloading %s\n", i+1, len(slugs), slug)
+
+		if errs[i] != nil {
+			fmt.Fprintf(Err, "      %s failed: %s\n", glyphFailed, errs[i])
+			failures[slug] = errs[i]
 			continue
 		}
-		// Don't bother with empty files.
-		if res.Header.Get("Content-Length") == "0" {
+		d := downloads[i]
+		dir, err := d.writeFiles(fetched[i])
+		if err != nil {
+			fmt.Fprintf(Err, "      %s failed: %s\n", glyphFailed, err)
+			failures[slug] = err
 			continue
 		}
+		metadata := d.payload.metadata()
+		if details := metadata.Details(); details != "" {
+			fmt.Fprintf(Err, "      %s\n", details)
+		}
+		dirs = append(dirs, dir)
+	}
 
-		// TODO: handle collisions
-		path := sf.relativePath()
-		dir := filepath.Join(metadata.Dir, filepath.Dir(path))
-		if err = os.MkdirAll(dir, os.FileMode(0755)); err != nil {
-			return err
+	if len(dirs) > 0 {
+		fmt.Fprintf(Err, "\nDownloaded to\n")
+		for _, dir := range dirs {
+			fmt.Fprintf(Out, "%s\n", dir)
+		}
+	}
+
+	if len(failures) > 0 {
+		failed := make([]string, 0, len(failures))
+		for _, slug := range slugs {
+			if _, ok := failures[slug]; ok {
+				failed = append(failed, slug)
+			}
+		}
+		return fmt.Errorf("failed to download %d of %d exercises: %s", len(failed), len(slugs), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// trackSlugs lists the exercises of the track given with --track, for --all and --all-unlocked,
+// in the order the track presents them. With unlockedOnly, locked exercises are left out.
+func trackSlugs(cfg config.Config, flags *pflag.FlagSet, given []string, unlockedOnly bool) ([]string, error) {
+	if len(given) > 0 {
+		return nil, errors.New("--all and --all-unlocked download every exercise, so they can't be used with --exercise")
+	}
+	if uuid, _ := flags.GetString("uuid"); uuid != "" {
+		return nil, errors.New("--all and --all-unlocked can't be used with --uuid")
+	}
+	track, err := flags.GetString("track")
+	if err != nil {
+		return nil, err
+	}
+	if track == "" {
+		return nil, errors.New("--all and --all-unlocked need the --track to download the exercises of")
+	}
+	c, err := loadExercisesCatalog(cfg, track)
+	if err != nil {
+		return nil, err
+	}
+	var slugs []string
+	locked := 0
+	for _, exercise := range c.Exercises {
+		if unlockedOnly && exercise.Status == statusLocked {
+			locked++
+			continue
+		}
+		slugs = append(slugs, exercise.Slug)
+	}
+	if locked > 0 {
+		fmt.Fprintf(Err, "Leaving out %d locked exercise(s).\n", locked)
+	}
+	if len(slugs) == 0 {
+		fmt.Fprintf(Err, "There are no exercises to download in the %s track.\n", track)
+		return nil, nil
+	}
+	fmt.Fprintf(Err, "Downloading %d exercise(s) of the %s track\n\n", len(slugs), track)
+	return slugs, nil
+}
+
+// printExerciseSummary describes the exercise, if the API told us about it.
+func printExerciseSummary(metadata workspace.ExerciseMetadata) {
+	if metadata.Details() == "" && metadata.Blurb == "" && len(metadata.Topics) == 0 {
+		return
+	}
+	fmt.Fprintf(Err, "\n    %s", metadata.ExerciseSlug)
+	if details := metadata.Details(); details != "" {
+		fmt.Fprintf(Err, " (%s)", details)
+	}
+	fmt.Fprintln(Err)
+	if metadata.Blurb != "" {
+		fmt.Fprintf(Err, "    %s\n", metadata.Blurb)
+	}
+	if len(metadata.Topics) > 0 {
+		fmt.Fprintf(Err, "    Topics: %s\n", strings.Join(metadata.Topics, ", "))
+	}
+}
+
+// exerciseSlugs returns the exercise slugs passed to the download command.
+// Slugs can be given as a comma-separated list, or by repeating the flag.
+func exerciseSlugs(flags *pflag.FlagSet) ([]string, error) {
+	values, err := flags.GetStringSlice("exercise")
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	slugs := make([]string, 0, len(values))
+	for _, value := range values {
+		slug := strings.TrimSpace(value)
+		if slug == "" || seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		slugs = append(slugs, slug)
+	}
+	return slugs, nil
+}
+
+const msgDownloadWouldOverwrite = `
+
+    Downloading would overwrite files in %s
+    that have been changed locally and differ from the ones on the website:
+
+%s
+
+    Run the command again with one of:
+
+        --interactive   to choose for each file
+        --ours          to keep your files
+        --theirs        to take the website's files (the same as --force)
+        --update        to merge the website's changes into your files
+
+    Your files are backed up before they are overwritten,
+    and '%s backups restore' can bring them back.
+`
+
+// write saves the exercise metadata and the solution files into the workspace.
+// It returns the directory the exercise was downloaded to.
+func (d *download) write() (string, error) {
+	files, err := d.fetchFiles()
+	if err != nil {
+		return "", err
+	}
+	if d.iteration > 0 {
+		return d.writeIteration(files)
+	}
+	return d.writeFiles(files)
+}
+
+// writeFiles writes the files that were fetched to the exercise directory,
+// resolving any conflicts with the files changed locally.
+func (d *download) writeFiles(files []downloadedFile) (string, error) {
+	metadata := d.payload.metadata()
+	ws := workspace.Workspace{Dir: d.workspace, ConceptDir: d.conceptDir}
+	dir := ws.ExerciseFor(&metadata).MetadataDir()
+	if d.docsOnly {
+		if _, err := os.Stat(workspace.NewExerciseFromDir(dir).MetadataFilepath()); os.IsNotExist(err) {
+			return "", fmt.Errorf("%s hasn't been downloaded to %s, so there are no documents to refresh; download it without --docs-only", metadata.ExerciseSlug, dir)
 		}
+		fmt.Fprintf(Err, "Refreshing %d document(s) of %s.\n", len(files), metadata.ExerciseSlug)
+	}
+
+	d.convertLineEndings(files)
+
+	op := downloadOperation{
+		At:       time.Now(),
+		Track:    metadata.Track,
+		Exercise: metadata.ExerciseSlug,
+		Dir:      dir,
+	}
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		op.CreatedDir = true
+	}
 
-		f, err := os.Create(filepath.Join(metadata.Dir, path))
+	checksums, err := workspace.NewChecksums(dir)
+	if err != nil {
+		// Without usable checksums, every differing file counts as changed locally.
+		checksums = workspace.Checksums{}
+	}
+
+	var conflicts []downloadedFile
+	var updated []string
+	for _, file := range files {
+		existing, err := ioutil.ReadFile(workspace.LongPath(filepath.Join(dir, file.path)))
+		if os.IsNotExist(err) {
+			op.Created = append(op.Created, file.path)
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if bytes.Equal(existing, file.contents) {
+			continue
+		}
+		// Files that haven't been touched since they were downloaded are simply updated.
+		if checksums.IsModified(file.path, existing) {
+			conflicts = append(conflicts, downloadedFile{path: file.path, contents: existing})
+			continue
+		}
+		updated = append(updated, file.path)
+	}
+
+	resolved, err := d.resolveConflicts(dir, conflicts, files)
+	if err != nil {
+		return "", err
+	}
+	if err := d.backUp(dir, files, updated, resolved, &op); err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(workspace.LongPath(dir), os.FileMode(0755)); err != nil {
+		return "", fmt.Errorf("unable to create the exercise directory %s: %s", dir, err)
+	}
+
+	for _, file := range files {
+		// Record the website's version even for the files that are kept,
+		// so that they still count as changed locally next time.
+		checksums.Set(file.path, file.contents)
+		if resolved.keep[file.path] {
+			// The files that are kept are still based on the original that was there.
+			continue
+		}
+		if _, err := workspace.ReadOriginal(dir, file.path); os.IsNotExist(err) {
+			op.CreatedOriginals = append(op.CreatedOriginals, file.path)
+		}
+		if err := workspace.WriteOriginal(dir, file.path, file.contents); err != nil {
+			return "", err
+		}
+		contents := file.contents
+		if merged, ok := resolved.merged[file.path]; ok {
+			contents = merged
+		}
+		path := filepath.Join(dir, file.path)
+		if err = os.MkdirAll(workspace.LongPath(filepath.Dir(path)), os.FileMode(0755)); err != nil {
+			return "", fmt.Errorf("unable to create the directory for %s: %s", path, err)
+		}
+		if err := d.writeDownloadedFile(path, contents, file.executable); err != nil {
+			return "", fmt.Errorf("unable to write %s: %s", path, err)
+		}
+		// A merged file is new, so it keeps the time it was written.
+		if _, ok := resolved.merged[file.path]; !ok {
+			setModTime(path, file.modTime)
+		}
+	}
+	if err := checksums.Write(dir); err != nil {
+		return "", err
+	}
+	// The metadata is written last, so that an exercise whose files weren't all written
+	// isn't taken for one that is ready to be worked on.
+	if err := metadata.Write(dir); err != nil {
+		return "", err
+	}
+	if err := d.partial.remove(); err != nil {
+		debug.Printf("Unable to remove the files kept while downloading: %s\n", err)
+	}
+
+	if d.stateDir != "" {
+		if err := recordOperation(d.stateDir, op); err != nil {
+			fmt.Fprintf(Err, "Warning: unable to record the download, so it can't be undone: %s\n", err)
+		}
+	}
+	return metadata.Dir, nil
+}
+
+// backUp takes a snapshot of every file the download is about to replace, if there's somewhere to keep it,
+// and records it in the operation so that the download can be undone.
+// Along with the files that were updated or changed locally, it keeps the checksums, the originals
+// and the metadata, so that undoing the download puts the exercise back the way it was.
+func (d *download) backUp(dir string, files []downloadedFile, updated []string, resolved *resolution, op *downloadOperation) error {
+	changed := append([]string{}, resolved.overwrite...)
+	for path := range resolved.merged {
+		changed = append(changed, path)
+	}
+	if len(changed) > 0 && d.snapshots == nil && d.mustBackUp {
+		return errNoBackupsDir
+	}
+	if op.CreatedDir || d.snapshots == nil {
+		return nil
+	}
+	if len(changed) == 0 && len(updated) == 0 && len(resolved.keep) == 0 && len(op.Created) == 0 {
+		// Nothing is any different.
+		return nil
+	}
+
+	paths := append(append([]string{}, updated...), changed...)
+	exercise := workspace.NewExerciseFromDir(dir)
+	bookkeeping := []string{exercise.MetadataFilepath(), workspace.ChecksumsFilepath(dir)}
+	for _, file := range files {
+		bookkeeping = append(bookkeeping, filepath.Join(workspace.OriginalsDir(dir), file.path))
+	}
+	for _, path := range bookkeeping {
+		rel, err := filepath.Rel(dir, path)
 		if err != nil {
 			return err
 		}
-		defer f.Close()
-		_, err = io.Copy(f, res.Body)
+		paths = append(paths, rel)
+	}
+
+	snap, err := d.snapshots.Take(dir, paths, fmt.Sprintf("download %s/%s", op.Track, op.Exercise))
+	if err != nil {
+		return fmt.Errorf("unable to back up the files before overwriting them: %s", err)
+	}
+	if snap == nil {
+		return nil
+	}
+	op.Snapshot = snap.ID
+	if len(changed) > 0 {
+		fmt.Fprintf(Err, "Overwriting %d changed file(s). Restore them with '%s backups restore %s'\n", len(changed), BinaryName, snap.ID)
+	}
+	return nil
+}
+
+// convertLineEndings converts the line endings of the files to the ones asked for in the user config.
+func (d *download) convertLineEndings(files []downloadedFile) {
+	ending := d.lineEndings.ForDownload()
+	for i, file := range files {
+		var converted bool
+		files[i].contents, converted = workspace.ConvertLineEndings(file.contents, ending)
+		if converted {
+			debug.Printf("Converted the line endings of %s to %s\n", file.path, workspace.LineEndingName(ending))
+		}
+	}
+}
+
+// setModTime dates a file that was written to when it was last changed on the website, if that is known.
+// The file is still usable if it can't be dated, so a failure is only reported when debugging.
+func setModTime(path string, modTime time.Time) {
+	if modTime.IsZero() {
+		return
+	}
+	if err := os.Chtimes(workspace.LongPath(path), time.Now(), modTime); err != nil {
+		debug.Printf("Unable to set the modification time of %s: %s\n", path, err)
+	}
+}
+
+// Ways of resolving conflicts between files changed locally and the ones on the website.
+const (
+	resolveTheirs      = "theirs"
+	resolveOurs        = "ours"
+	resolveInteractive = "interactive"
+	resolveUpdate      = "update"
+)
+
+// resolution is what to do with each of the files changed locally.
+type resolution struct {
+	// keep are the files to leave as they are.
+	keep map[string]bool
+	// overwrite are the files to replace, which are backed up first.
+	overwrite []string
+	// merged are the files to replace with the merge of both versions, rather than the website's.
+	merged map[string][]byte
+}
+
+// resolveConflicts decides what to do with the files changed locally, given the files on the website.
+func (d *download) resolveConflicts(dir string, conflicts, files []downloadedFile) (*resolution, error) {
+	r := &resolution{keep: map[string]bool{}, merged: map[string][]byte{}}
+	if len(conflicts) == 0 {
+		return r, nil
+	}
+
+	switch d.resolution {
+	case resolveTheirs:
+		for _, conflict := range conflicts {
+			r.overwrite = append(r.overwrite, conflict.path)
+		}
+		return r, nil
+	case resolveOurs:
+		for _, conflict := range conflicts {
+			r.keep[conflict.path] = true
+		}
+		fmt.Fprintf(Err, "Keeping %d changed file(s).\n", len(conflicts))
+		return r, nil
+	case resolveInteractive:
+		fmt.Fprintf(Err, "\n%d file(s) in %s have been changed locally and differ from the website.\n", len(conflicts), dir)
+		for _, conflict := range conflicts {
+			for {
+				answer, err := prompt(fmt.Sprintf("%s: keep (m)ine, take (t)heirs, or (a)bort? ", conflict.path))
+				if err != nil {
+					return nil, errors.New("download aborted, no answer was given")
+				}
+				if answer == "m" || answer == "mine" {
+					r.keep[conflict.path] = true
+					break
+				}
+				if answer == "t" || answer == "theirs" {
+					r.overwrite = append(r.overwrite, conflict.path)
+					break
+				}
+				if answer == "a" || answer == "abort" {
+					return nil, errors.New("download aborted, nothing was changed")
+				}
+			}
+		}
+		return r, nil
+	case resolveUpdate:
+		return r, r.merge(dir, conflicts, files)
+	}
+
+	paths := make([]string, len(conflicts))
+	for i, conflict := range conflicts {
+		paths[i] = conflict.path
+	}
+	return nil, fmt.Errorf(msgDownloadWouldOverwrite, dir, "        "+strings.Join(paths, "\n        "), BinaryName)
+}
+
+// merge combines the changes made locally with those made on the website since the files were downloaded.
+// Files without a stored original can't be merged, and are kept as they are.
+func (r *resolution) merge(dir string, conflicts, files []downloadedFile) error {
+	theirs := make(map[string][]byte, len(files))
+	for _, file := range files {
+		theirs[file.path] = file.contents
+	}
+
+	var clean, conflicted, unmerged []string
+	for _, conflict := range conflicts {
+		original, err := workspace.ReadOriginal(dir, conflict.path)
+		if os.IsNotExist(err) {
+			r.keep[conflict.path] = true
+			unmerged = append(unmerged, conflict.path)
+			continue
+		}
 		if err != nil {
 			return err
 		}
+
+		result := merge.ThreeWay(original, conflict.contents, theirs[conflict.path])
+		r.merged[conflict.path] = result.Contents
+		r.overwrite = append(r.overwrite, conflict.path)
+		if result.Conflicts > 0 {
+			conflicted = append(conflicted, fmt.Sprintf("%s (%d conflict(s))", conflict.path, result.Conflicts))
+		} else {
+			clean = append(clean, conflict.path)
+		}
+	}
+
+	if len(clean) > 0 {
+		fmt.Fprintf(Err, "Merged the changes into %d file(s): %s\n", len(clean), strings.Join(clean, ", "))
+	}
+	if len(conflicted) > 0 {
+		fmt.Fprintf(Err, "Both you and the track changed the same lines in:\n\n        %s\n\n", strings.Join(conflicted, "\n        "))
+		fmt.Fprintf(Err, "Look for the lines between %s and %s to resolve them.\n", merge.MarkerMine, merge.MarkerTheirs)
+	}
+	if len(unmerged) > 0 {
+		fmt.Fprintf(Err, "Kept %d file(s) that were downloaded before the originals were stored, so can't be merged: %s\n", len(unmerged), strings.Join(unmerged, ", "))
+		fmt.Fprintf(Err, "Pass --theirs instead to take the website's version.\n")
 	}
-	fmt.Fprintf(Err, "\nDownloaded to\n")
-	fmt.Fprintf(Out, "%s\n", metadata.Dir)
 	return nil
 }
 
+// downloadedFile is the contents of a solution file, ready to be written to the workspace.
+type downloadedFile struct {
+	path       string
+	contents   []byte
+	executable bool
+	// modTime is when the file was last changed on the website, if it said.
+	modTime time.Time
+}
+
+// Default permissions of downloaded files, before the umask is applied.
+const (
+	defaultFileMode       = os.FileMode(0644)
+	defaultExecutableMode = os.FileMode(0755)
+)
+
+// fileModeFor returns the permissions to write a file with, and whether the file is already there.
+// A file that is already there keeps its permissions, gaining the executable bits
+// if the website says it should be executable. Those aren't subject to the umask.
+func (d *download) fileModeFor(path string, executable bool) (os.FileMode, bool) {
+	fileMode, executableMode := d.fileMode, d.executableMode
+	if fileMode == 0 {
+		fileMode = defaultFileMode
+	}
+	if executableMode == 0 {
+		executableMode = defaultExecutableMode
+	}
+
+	if info, err := os.Stat(workspace.LongPath(path)); err == nil {
+		mode := info.Mode().Perm()
+		if executable {
+			mode |= executableMode & 0111
+		}
+		return mode, true
+	}
+	if executable {
+		return executableMode, false
+	}
+	return fileMode, false
+}
+
+// writeDownloadedFile replaces a file whole, so that an interrupted download doesn't leave one half written.
+// Files that were already there keep the permissions they had, unless they have to be made executable.
+func (d *download) writeDownloadedFile(path string, contents []byte, executable bool) error {
+	mode, existing := d.fileModeFor(path, executable)
+	if existing {
+		return workspace.ReplaceFileAtomically(workspace.LongPath(path), contents, mode)
+	}
+	return workspace.WriteFileAtomically(workspace.LongPath(path), contents, mode)
+}
+
+// downloadFileModes reads the permissions to give downloaded files from the user config,
+// as octal numbers, e.g. "download.file_mode": "0640".
+func downloadFileModes(usrCfg *viper.Viper) (fileMode, executableMode os.FileMode, err error) {
+	parse := func(key string, fallback os.FileMode) (os.FileMode, error) {
+		value := usrCfg.GetString(key)
+		if value == "" {
+			return fallback, nil
+		}
+		mode, err := strconv.ParseUint(value, 8, 32)
+		if err != nil || mode > 0777 {
+			return 0, fmt.Errorf("cannot understand %s '%s' in your config, use permissions such as 0644", key, value)
+		}
+		return os.FileMode(mode), nil
+	}
+	if fileMode, err = parse("download.file_mode", defaultFileMode); err != nil {
+		return 0, 0, err
+	}
+	if executableMode, err = parse("download.executable_mode", defaultExecutableMode); err != nil {
+		return 0, 0, err
+	}
+	return fileMode, executableMode, nil
+}
+
+// fetchFiles downloads the solution files, so that they can be checked
+// against the workspace before anything is written.
+func (d *download) fetchFiles() ([]downloadedFile, error) {
+	client, err := api.NewClient(d.token, d.apibaseurl)
+	if err != nil {
+		return nil, err
+	}
+
+	type fetch struct {
+		path, url  string
+		executable bool
+		// optional files are skipped if they aren't there, rather than failing the download.
+		optional bool
+	}
+	var fetches []fetch
+	for _, sf := range d.payload.files() {
+		if d.docsOnly && !workspace.IsDocFile(filepath.ToSlash(sf.relativePath())) {
+			continue
+		}
+		url, err := sf.url()
+		if err != nil {
+			return nil, err
+		}
+		fetches = append(fetches, fetch{path: sf.relativePath(), url: url, executable: sf.executable})
+	}
+	if d.withConcepts {
+		if d.payload.Solution.Exercise.Type == "concept" {
+			for _, doc := range d.payload.conceptDocs() {
+				fetches = append(fetches, fetch{path: doc.path, url: doc.url, optional: true})
+			}
+		} else {
+			debug.Printf("Not downloading concepts, %s isn't a concept exercise\n", d.payload.Solution.Exercise.ID)
+		}
+	}
+
+	// Files fetched by a download that was interrupted, or ahead of time, aren't fetched again.
+	if d.iteration == 0 {
+		d.partial = openPartialDownload(d.stateDir, d.payload)
+	}
+	if d.prefetching && d.partial != nil && d.partial.PrefetchedAt.IsZero() {
+		d.partial.PrefetchedAt = time.Now()
+	}
+	contents := make([][]byte, len(fetches))
+	modTimes := make([]time.Time, len(fetches))
+	var resumed int32
+	errs := each(len(fetches), func(i int) error {
+		if kept, modTime := d.partial.read(fetches[i].path); kept != nil {
+			contents[i], modTimes[i] = kept, modTime
+			atomic.AddInt32(&resumed, 1)
+			return nil
+		}
+		return d.limit.do(func() (err error) {
+			if contents[i], modTimes[i], err = fetchVerifiedFile(client, fetches[i].url); err != nil || contents[i] == nil {
+				return err
+			}
+			if err := d.partial.keep(fetches[i].path, contents[i], modTimes[i]); err != nil {
+				debug.Printf("Unable to keep %s in case the download is interrupted: %s\n", fetches[i].path, err)
+			}
+			return nil
+		})
+	})
+	switch {
+	case resumed == 0 || d.prefetching:
+	case !d.partial.PrefetchedAt.IsZero():
+		fmt.Fprintf(Err, "Using the %d file(s) of %s that were fetched ahead of time.\n", resumed, d.payload.Solution.Exercise.ID)
+	default:
+		fmt.Fprintf(Err, "Resuming the download of %s: %d file(s) had already been fetched.\n", d.payload.Solution.Exercise.ID, resumed)
+	}
+
+	var files []downloadedFile
+	for i, f := range fetches {
+		if f.optional && isNotFound(errs[i]) {
+			fmt.Fprintf(Err, "Warning: skipping %s, it isn't on the website.\n", f.path)
+			continue
+		}
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		if contents[i] == nil {
+			debug.Printf("Skipping %s\n", f.path)
+			continue
+		}
+		files = append(files, downloadedFile{path: f.path, contents: contents[i], executable: f.executable, modTime: modTimes[i]})
+	}
+	return files, nil
+}
+
+// isNotFound tells whether the error is the API saying that what was asked for isn't there.
+func isNotFound(err error) bool {
+	apiErr, ok := err.(*apiError)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+// fetchFile downloads a file, along with when it was last modified, if the server says.
+// It returns nil contents for a file that is empty or missing.
+func fetchFile(client *api.Client, url string) ([]byte, time.Time, error) {
+	var modTime time.Time
+	req, err := client.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, modTime, err
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, modTime, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, modTime, decodedAPIError(res)
+	}
+	// Don't bother with empty files.
+	if res.Header.Get("Content-Length") == "0" {
+		return nil, modTime, nil
+	}
+
+	contents, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, modTime, err
+	}
+	if len(contents) == 0 {
+		return nil, modTime, nil
+	}
+	if err := checkDigest(url, res, contents); err != nil {
+		return nil, modTime, err
+	}
+	if lastModified := res.Header.Get("Last-Modified"); lastModified != "" {
+		if t, err := http.ParseTime(lastModified); err == nil {
+			modTime = t
+		}
+	}
+	return contents, modTime, nil
+}
+
 type download struct {
 	// either/or
 	slug, uuid string
@@ -134,20 +1023,71 @@ type download struct {
 
 	// optional
 	track, team string
+	// resolution is how to treat files that have been changed locally.
+	resolution string
+	// mustBackUp refuses to overwrite files changed locally unless they can be backed up first.
+	mustBackUp bool
+	// fileMode and executableMode are the permissions of new files.
+	fileMode, executableMode os.FileMode
+	// lineEndings is what to convert the line endings of the files to.
+	lineEndings workspace.LineEndings
+	// withConcepts also downloads the documents about the concepts a concept exercise builds on.
+	withConcepts bool
+	// conceptDir is the directory in the workspace that concept exercises go in, if set.
+	conceptDir string
+	// docsOnly fetches only the instructions and other documents, leaving the solution and tests alone.
+	docsOnly bool
+	// iteration is the number of a past iteration to restore the files of, if set.
+	iteration int
+	// into is the directory, relative to the exercise, that the files of the iteration are restored to.
+	into string
+
+	// snapshots keeps the files that --force overwrites, if set.
+	snapshots *snapshot.Store
+	// stateDir is where the download is recorded so that it can be undone, if set.
+	stateDir string
+	// limit bounds how many files are fetched at once.
+	limit jobLimit
+	// partial keeps the files as they are fetched, if there's a state directory.
+	partial *partialDownload
+	// prefetching fetches the files ahead of time, to be kept for when the exercise is downloaded.
+	prefetching bool
 
 	payload *downloadPayload
 }
 
 func newDownload(flags *pflag.FlagSet, usrCfg *viper.Viper) (*download, error) {
+	d, err := newDownloadFromFlags(flags, usrCfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.requestPayload(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// newDownloadFromFlags validates the download options without talking to the API.
+// When several exercises are given, the first one is used.
+func newDownloadFromFlags(flags *pflag.FlagSet, usrCfg *viper.Viper) (*download, error) {
+	slugs, err := exerciseSlugs(flags)
+	if err != nil {
+		return nil, err
+	}
+	return newDownloadForSlugs(flags, usrCfg, slugs)
+}
+
+// newDownloadForSlugs validates the download options for the given exercises,
+// which may have been picked some other way than with --exercise.
+func newDownloadForSlugs(flags *pflag.FlagSet, usrCfg *viper.Viper, slugs []string) (*download, error) {
 	var err error
 	d := &download{}
 	d.uuid, err = flags.GetString("uuid")
 	if err != nil {
 		return nil, err
 	}
-	d.slug, err = flags.GetString("exercise")
-	if err != nil {
-		return nil, err
+	if len(slugs) > 0 {
+		d.slug = slugs[0]
 	}
 	d.track, err = flags.GetString("track")
 	if err != nil {
@@ -157,10 +1097,51 @@ func newDownload(flags *pflag.FlagSet, usrCfg *viper.Viper) (*download, error) {
 	if err != nil {
 		return nil, err
 	}
+	d.resolution, d.mustBackUp, err = conflictResolution(flags)
+	if err != nil {
+		return nil, err
+	}
+	if flags.Lookup("docs-only") != nil {
+		d.docsOnly, err = flags.GetBool("docs-only")
+		if err != nil {
+			return nil, err
+		}
+	}
+	if flags.Lookup("with-concepts") != nil {
+		d.withConcepts, err = flags.GetBool("with-concepts")
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	d.token = usrCfg.GetString("token")
 	d.apibaseurl = usrCfg.GetString("apibaseurl")
 	d.workspace = usrCfg.GetString("workspace")
+	d.conceptDir, err = conceptDir(usrCfg)
+	if err != nil {
+		return nil, err
+	}
+	d.fileMode, d.executableMode, err = downloadFileModes(usrCfg)
+	if err != nil {
+		return nil, err
+	}
+	d.lineEndings, err = workspace.NewLineEndings(usrCfg.GetString("line_endings"))
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := jobsFromFlags(flags)
+	if err != nil {
+		return nil, err
+	}
+	d.limit = newJobLimit(jobs)
+	if flags.Lookup("iteration") != nil {
+		if err = d.iterationFromFlags(flags, len(slugs)); err != nil {
+			return nil, err
+		}
+	}
+	if d.docsOnly && d.iteration > 0 {
+		return nil, errors.New("--docs-only can't be used with --iteration; iterations don't have documents")
+	}
 
 	if err = d.needsSlugXorUUID(); err != nil {
 		return nil, err
@@ -171,36 +1152,100 @@ func newDownload(flags *pflag.FlagSet, usrCfg *viper.Viper) (*download, error) {
 	if err = d.needsSlugWhenGivenTrackOrTeam(); err != nil {
 		return nil, err
 	}
+	return d, nil
+}
+
+// onConflictBackup is the value of --on-conflict that overwrites files changed locally
+// only if they can be backed up first.
+const onConflictBackup = "backup"
+
+// onConflictResolutions are the values of --on-conflict, and the resolutions they stand for.
+var onConflictResolutions = map[string]string{
+	onConflictBackup: resolveTheirs,
+	"skip":           resolveOurs,
+	"overwrite":      resolveTheirs,
+	"ask":            resolveInteractive,
+}
+
+// conflictResolution reads which of the mutually exclusive ways of resolving conflicts was asked for,
+// and whether the files it overwrites must be backed up.
+func conflictResolution(flags *pflag.FlagSet) (string, bool, error) {
+	var chosen []string
+	for _, name := range []string{"force", resolveTheirs, resolveOurs, resolveInteractive, resolveUpdate} {
+		set, err := flags.GetBool(name)
+		if err != nil {
+			return "", false, err
+		}
+		if set {
+			if name == "force" {
+				name = resolveTheirs
+			}
+			if len(chosen) == 0 || chosen[0] != name {
+				chosen = append(chosen, name)
+			}
+		}
+	}
+
+	var onConflict string
+	if flags.Lookup("on-conflict") != nil {
+		var err error
+		if onConflict, err = flags.GetString("on-conflict"); err != nil {
+			return "", false, err
+		}
+	}
+	if onConflict != "" {
+		name, ok := onConflictResolutions[onConflict]
+		if !ok {
+			return "", false, fmt.Errorf("--on-conflict must be one of backup, skip, overwrite or ask, not %q", onConflict)
+		}
+		if len(chosen) > 0 {
+			return "", false, fmt.Errorf("--on-conflict can't be combined with --%s", chosen[0])
+		}
+		return name, onConflict == onConflictBackup, nil
+	}
+
+	switch len(chosen) {
+	case 0:
+		return "", false, nil
+	case 1:
+		return chosen[0], false, nil
+	}
+	return "", false, errors.New("choose only one of --theirs, --ours, --interactive and --update")
+}
 
+// requestPayload fetches the solution details from the API.
+func (d *download) requestPayload() error {
 	client, err := api.NewClient(d.token, d.apibaseurl)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	req, err := client.NewRequest("GET", d.url(), nil)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	d.buildQueryParams(req.URL)
 
 	res, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer res.Body.Close()
 
 	if res.StatusCode < 200 || res.StatusCode > 299 {
-		return nil, decodedAPIError(res)
+		if d.slug == "" {
+			return decodedAPIError(res)
+		}
+		return suggestSlugs(d.token, d.apibaseurl, d.track, d.slug, decodedAPIError(res))
 	}
 
 	body, _ := ioutil.ReadAll(res.Body)
 	res.Body = ioutil.NopCloser(bytes.NewReader(body))
 
 	if err := json.Unmarshal(body, &d.payload); err != nil {
-		return nil, decodedAPIError(res)
+		return decodedAPIError(res)
 	}
-
-	return d, nil
+	return nil
 }
 
 func (d download) url() string {
@@ -222,6 +1267,9 @@ func (d download) buildQueryParams(url *netURL.URL) {
 			query.Add("team_id", d.team)
 		}
 	}
+	if d.iteration > 0 {
+		query.Add("iteration", strconv.Itoa(d.iteration))
+	}
 	url.RawQuery = query.Encode()
 }
 
@@ -270,17 +1318,29 @@ type downloadPayload struct {
 			IsRequester bool   `json:"is_requester"`
 		} `json:"user"`
 		Exercise struct {
-			ID              string `json:"id"`
-			InstructionsURL string `json:"instructions_url"`
-			AutoApprove     bool   `json:"auto_approve"`
+			ID              string   `json:"id"`
+			InstructionsURL string   `json:"instructions_url"`
+			AutoApprove     bool     `json:"auto_approve"`
+			Type            string   `json:"type"`
+			Difficulty      string   `json:"difficulty"`
+			Topics          []string `json:"topics"`
+			Blurb           string   `json:"blurb"`
 			Track           struct {
 				ID       string `json:"id"`
 				Language string `json:"language"`
 			} `json:"track"`
+			// Prerequisites are the concepts a concept exercise builds on, with their teaching material.
+			Prerequisites []struct {
+				Slug            string `json:"slug"`
+				IntroductionURL string `json:"introduction_url"`
+				AboutURL        string `json:"about_url"`
+			} `json:"prerequisites"`
 		} `json:"exercise"`
 		FileDownloadBaseURL string   `json:"file_download_base_url"`
 		Files               []string `json:"files"`
-		Iteration           struct {
+		// ExecutableFiles are the files that have to be executable, e.g. scripts.
+		ExecutableFiles []string `json:"executable_files"`
+		Iteration       struct {
 			SubmittedAt *string `json:"submitted_at"`
 		}
 	} `json:"solution"`
@@ -301,16 +1361,52 @@ func (dp downloadPayload) metadata() workspace.ExerciseMetadata {
 		URL:          dp.Solution.URL,
 		Handle:       dp.Solution.User.Handle,
 		IsRequester:  dp.Solution.User.IsRequester,
+		Type:         dp.Solution.Exercise.Type,
+		Difficulty:   dp.Solution.Exercise.Difficulty,
+		Topics:       dp.Solution.Exercise.Topics,
+		Blurb:        dp.Solution.Exercise.Blurb,
 	}
 }
 
+// conceptDoc is a document about a concept, and where it goes in the exercise.
+type conceptDoc struct {
+	path, url string
+}
+
+// conceptDocsDir is where the documents about an exercise's concepts go, relative to the exercise.
+var conceptDocsDir = filepath.Join(".docs", "concepts")
+
+// conceptDocs are the introduction and about documents of the exercise's prerequisite concepts.
+func (dp downloadPayload) conceptDocs() []conceptDoc {
+	var docs []conceptDoc
+	for _, concept := range dp.Solution.Exercise.Prerequisites {
+		if concept.Slug == "" || strings.ContainsAny(concept.Slug, `/\.`) {
+			continue
+		}
+		dir := filepath.Join(conceptDocsDir, concept.Slug)
+		if concept.IntroductionURL != "" {
+			docs = append(docs, conceptDoc{path: filepath.Join(dir, "introduction.md"), url: concept.IntroductionURL})
+		}
+		if concept.AboutURL != "" {
+			docs = append(docs, conceptDoc{path: filepath.Join(dir, "about.md"), url: concept.AboutURL})
+		}
+	}
+	return docs
+}
+
 func (dp downloadPayload) files() []solutionFile {
+	executable := make(map[string]bool, len(dp.Solution.ExecutableFiles))
+	for _, file := range dp.Solution.ExecutableFiles {
+		executable[file] = true
+	}
+
 	fx := make([]solutionFile, 0, len(dp.Solution.Files))
 	for _, file := range dp.Solution.Files {
 		f := solutionFile{
-			path:    file,
-			baseURL: dp.Solution.FileDownloadBaseURL,
-			slug:    dp.Solution.Exercise.ID,
+			path:       file,
+			baseURL:    dp.Solution.FileDownloadBaseURL,
+			slug:       dp.Solution.Exercise.ID,
+			executable: executable[file],
 		}
 		fx = append(fx, f)
 	}
@@ -319,6 +1415,7 @@ func (dp downloadPayload) files() []solutionFile {
 
 type solutionFile struct {
 	path, baseURL, slug string
+	executable          bool
 }
 
 func (sf solutionFile) url() (string, error) {
@@ -352,8 +1449,24 @@ func (sf solutionFile) relativePath() string {
 func setupDownloadFlags(flags *pflag.FlagSet) {
 	flags.StringP("uuid", "u", "", "the solution UUID")
 	flags.StringP("track", "t", "", "the track ID")
-	flags.StringP("exercise", "e", "", "the exercise slug")
+	flags.StringSliceP("exercise", "e", []string{}, "the exercise slug (comma-separated or repeated for several exercises)")
+	flags.Bool("all", false, "download every exercise in the track")
+	flags.Bool("all-unlocked", false, "download every exercise in the track that isn't locked")
 	flags.StringP("team", "T", "", "the team slug")
+	flags.BoolP("force", "F", false, "the same as --theirs")
+	flags.Bool(resolveTheirs, false, "overwrite files changed locally with the ones on the website, backing them up first")
+	flags.Bool(resolveOurs, false, "keep files changed locally instead of the ones on the website")
+	flags.BoolP(resolveInteractive, "i", false, "ask whether to keep or overwrite each file changed locally")
+	flags.Bool(resolveUpdate, false, "merge the website's changes into files changed locally, marking any conflicts")
+	flags.String("on-conflict", "", "what to do with files changed locally: backup, skip, overwrite or ask")
+	flags.Bool("docs-only", false, "refresh only the README, HELP, HINTS and .docs of an exercise you've downloaded")
+	flags.Int("iteration", 0, "restore the files of this iteration instead of the latest")
+	flags.String("into", "", "the directory in the exercise to restore the files of the --iteration to")
+	flags.Bool("undo", false, "revert the most recent download, restoring any files it overwrote")
+	flags.Bool("with-concepts", false, "also download the introductions and about documents of a concept exercise's prerequisite concepts")
+	flags.IntP("jobs", "j", defaultJobs(), "how many files to download at once")
+	flags.Bool("open", false, "open the exercise in your editor once it's downloaded")
+	flags.Bool("no-input", false, "never ask which exercise to download; fail if it isn't given")
 }
 
 func init() {
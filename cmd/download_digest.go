@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/exercism/cli/api"
+	"github.com/exercism/cli/debug"
+)
+
+// maxDigestAttempts is how many times a file that arrives damaged is fetched before giving up.
+const maxDigestAttempts = 3
+
+// digestMismatchError is the error for a file that doesn't match the digest the server sent with it.
+type digestMismatchError struct {
+	url, header string
+}
+
+func (e digestMismatchError) Error() string {
+	return fmt.Sprintf("%s was damaged on the way: it doesn't match its %s", e.url, e.header)
+}
+
+// fetchVerifiedFile fetches a file, and fetches it again if it arrives damaged.
+func fetchVerifiedFile(client *api.Client, url string) (contents []byte, modTime time.Time, err error) {
+	for attempt := 1; attempt <= maxDigestAttempts; attempt++ {
+		contents, modTime, err = fetchFile(client, url)
+		if _, damaged := err.(digestMismatchError); !damaged {
+			return contents, modTime, err
+		}
+		debug.Printf("%s (attempt %d of %d)\n", err, attempt, maxDigestAttempts)
+	}
+	return nil, modTime, err
+}
+
+// md5ETag matches the ETags that are the MD5 of the file, as object stores send them.
+var md5ETag = regexp.MustCompile(`^"([0-9a-fA-F]{32})"$`)
+
+// checkDigest verifies the contents of a response against the digests the server sent with it, if any:
+// Content-MD5, Digest, or else an ETag that is the MD5 of the file.
+// Responses that were decompressed on the way can't be checked, since the digests are of what was sent.
+func checkDigest(url string, res *http.Response, contents []byte) error {
+	if res.Uncompressed {
+		return nil
+	}
+	checked := false
+	if value := res.Header.Get("Content-MD5"); value != "" {
+		want, err := base64.StdEncoding.DecodeString(value)
+		if err == nil {
+			checked = true
+			if sum := md5.Sum(contents); !bytes.Equal(sum[:], want) {
+				return digestMismatchError{url: url, header: "Content-MD5"}
+			}
+		}
+	}
+	for _, digest := range strings.Split(res.Header.Get("Digest"), ",") {
+		parts := strings.SplitN(strings.TrimSpace(digest), "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		want, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			continue
+		}
+		var sum []byte
+		switch strings.ToLower(parts[0]) {
+		case "md5":
+			s := md5.Sum(contents)
+			sum = s[:]
+		case "sha-256":
+			s := sha256.Sum256(contents)
+			sum = s[:]
+		case "sha-512":
+			s := sha512.Sum512(contents)
+			sum = s[:]
+		default:
+			continue
+		}
+		checked = true
+		if !bytes.Equal(sum, want) {
+			return digestMismatchError{url: url, header: "Digest"}
+		}
+	}
+	if checked {
+		return nil
+	}
+	if m := md5ETag.FindStringSubmatch(res.Header.Get("ETag")); m != nil {
+		if sum := md5.Sum(contents); hex.EncodeToString(sum[:]) != strings.ToLower(m[1]) {
+			return digestMismatchError{url: url, header: "ETag"}
+		}
+	}
+	return nil
+}
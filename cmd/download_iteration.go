@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/exercism/cli/workspace"
+	"github.com/spf13/pflag"
+)
+
+// iterationFromFlags reads --iteration and --into.
+func (d *download) iterationFromFlags(flags *pflag.FlagSet, exercises int) error {
+	var err error
+	if d.iteration, err = flags.GetInt("iteration"); err != nil {
+		return err
+	}
+	if d.into, err = flags.GetString("into"); err != nil {
+		return err
+	}
+	if d.iteration < 0 {
+		return fmt.Errorf("--iteration must be at least 1, not %d", d.iteration)
+	}
+	if d.iteration == 0 {
+		if d.into != "" {
+			return errors.New("--into is where the files of an --iteration go, so it needs one")
+		}
+		return nil
+	}
+	if exercises > 1 {
+		return errors.New("--iteration restores the files of one exercise at a time")
+	}
+	if d.resolution == resolveUpdate {
+		return errors.New("--update can't be used with --iteration; the files of an iteration aren't merged")
+	}
+	if d.into != "" {
+		into := filepath.Clean(d.into)
+		if filepath.IsAbs(into) || into == "." || into == ".." || strings.HasPrefix(into, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("--into must be a directory inside the exercise directory, not %s", d.into)
+		}
+		d.into = into
+	}
+	return nil
+}
+
+// writeIteration writes the files of a past iteration to the exercise directory, or to the --into directory in it.
+// Files that differ from the iteration's are treated as changed locally.
+// The checksums and originals of the files that were downloaded are left as they are,
+// so that the restored files count as changed locally.
+func (d *download) writeIteration(files []downloadedFile) (string, error) {
+	metadata := d.payload.metadata()
+	ws := workspace.Workspace{Dir: d.workspace, ConceptDir: d.conceptDir}
+	dir := ws.ExerciseFor(&metadata).MetadataDir()
+	d.convertLineEndings(files)
+
+	op := downloadOperation{
+		At:       time.Now(),
+		Track:    metadata.Track,
+		Exercise: metadata.ExerciseSlug,
+		Dir:      dir,
+	}
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		op.CreatedDir = true
+	}
+
+	var conflicts []downloadedFile
+	for i := range files {
+		files[i].path = filepath.Join(d.into, files[i].path)
+		existing, err := ioutil.ReadFile(workspace.LongPath(filepath.Join(dir, files[i].path)))
+		if os.IsNotExist(err) {
+			op.Created = append(op.Created, files[i].path)
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if !bytes.Equal(existing, files[i].contents) {
+			conflicts = append(conflicts, downloadedFile{path: files[i].path, contents: existing})
+		}
+	}
+
+	resolved, err := d.resolveConflicts(dir, conflicts, files)
+	if err != nil {
+		return "", err
+	}
+	// No originals are written, so there are none to back up.
+	if err := d.backUp(dir, nil, nil, resolved, &op); err != nil {
+		return "", err
+	}
+
+	for _, file := range files {
+		if resolved.keep[file.path] {
+			continue
+		}
+		path := filepath.Join(dir, file.path)
+		if err := os.MkdirAll(workspace.LongPath(filepath.Dir(path)), os.FileMode(0755)); err != nil {
+			return "", fmt.Errorf("unable to create the directory for %s: %s", path, err)
+		}
+		if err := d.writeDownloadedFile(path, file.contents, file.executable); err != nil {
+			return "", fmt.Errorf("unable to write %s: %s", path, err)
+		}
+		setModTime(path, file.modTime)
+	}
+	// An exercise that hadn't been downloaded yet is set up like one that had.
+	if _, err := os.Stat(workspace.NewExerciseFromDir(dir).MetadataFilepath()); os.IsNotExist(err) {
+		if err := metadata.Write(dir); err != nil {
+			return "", err
+		}
+	}
+
+	if d.stateDir != "" {
+		if err := recordOperation(d.stateDir, op); err != nil {
+			fmt.Fprintf(Err, "Warning: unable to record the download, so it can't be undone: %s\n", err)
+		}
+	}
+	fmt.Fprintf(Err, "Restored the files of iteration %d of %s.\n", d.iteration, metadata.ExerciseSlug)
+	return filepath.Join(dir, d.into), nil
+}
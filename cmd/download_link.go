@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	netURL "net/url"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// solutionUUID matches the UUIDs of solutions, with or without hyphens.
+var solutionUUID = regexp.MustCompile(`^(?i:[0-9a-f]{32}|[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})$`)
+
+// downloadLink is what a link from the website says to download.
+type downloadLink struct {
+	track, slug, uuid string
+}
+
+// parseDownloadLink reads the link to an exercise or a solution on the website, such as
+// https://exercism.org/tracks/go/exercises/hamming, or the UUID of a solution.
+func parseDownloadLink(arg string) (*downloadLink, error) {
+	if solutionUUID.MatchString(arg) {
+		return &downloadLink{uuid: arg}, nil
+	}
+	u, err := netURL.Parse(arg)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("%s is neither a link to an exercise on the website nor the UUID of a solution", arg)
+	}
+
+	link := &downloadLink{}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		switch segments[i] {
+		case "tracks":
+			link.track = segments[i+1]
+		case "exercises":
+			link.slug = segments[i+1]
+		case "solutions":
+			if !solutionUUID.MatchString(segments[i+1]) {
+				return nil, fmt.Errorf("the link to %s doesn't say which solution it is; pass the solution's --uuid instead", arg)
+			}
+			return &downloadLink{uuid: segments[i+1]}, nil
+		}
+	}
+	if link.track == "" || link.slug == "" {
+		return nil, fmt.Errorf("%s isn't a link to an exercise, such as https://exercism.org/tracks/go/exercises/hamming", arg)
+	}
+	return link, nil
+}
+
+// applyDownloadLink sets the flags that the link stands for.
+// Flags that were given as well have to agree with it.
+func applyDownloadLink(flags *pflag.FlagSet, args []string) error {
+	if len(args) > 1 {
+		return errors.New("download takes one link to an exercise at a time")
+	}
+	link, err := parseDownloadLink(args[0])
+	if err != nil {
+		return err
+	}
+	set := func(name, value string) error {
+		if value == "" {
+			return nil
+		}
+		if given := flags.Lookup(name); given != nil && given.Changed {
+			if current := strings.Trim(given.Value.String(), "[]"); current != value {
+				return fmt.Errorf("the link is to %s, but --%s is %s", value, name, current)
+			}
+			return nil
+		}
+		return flags.Set(name, value)
+	}
+	if err := set("uuid", link.uuid); err != nil {
+		return err
+	}
+	if err := set("track", link.track); err != nil {
+		return err
+	}
+	return set("exercise", link.slug)
+}
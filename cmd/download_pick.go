@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/exercism/cli/config"
+	"github.com/spf13/pflag"
+)
+
+// wantsPicker tells whether a download that doesn't say what to download should offer a choice,
+// rather than fail: when there's someone to answer, and --no-input wasn't given.
+func wantsPicker(flags *pflag.FlagSet) (bool, error) {
+	noInput, err := flags.GetBool("no-input")
+	if err != nil {
+		return false, err
+	}
+	return !noInput && isInteractive(), nil
+}
+
+// pickDownload asks which track, if --track wasn't given, and then which of its exercises to download,
+// and sets the flags accordingly.
+func pickDownload(cfg config.Config, flags *pflag.FlagSet) error {
+	track, err := flags.GetString("track")
+	if err != nil {
+		return err
+	}
+	if track == "" {
+		if track, err = pickTrack(cfg); err != nil {
+			return err
+		}
+	}
+
+	c, err := loadExercisesCatalog(cfg, track)
+	if err != nil {
+		return err
+	}
+	var items []pickerItem
+	for _, exercise := range c.Exercises {
+		if normalizeStatus(exercise.Status) == statusLocked {
+			continue
+		}
+		label := exercise.Slug
+		if g, ok := statusGlyphs[normalizeStatus(exercise.Status)]; ok {
+			label = fmt.Sprintf("%s %s", g, exercise.Slug)
+		}
+		if exercise.Type != "" {
+			label = fmt.Sprintf("%s (%s)", label, exercise.Type)
+		}
+		items = append(items, pickerItem{value: exercise.Slug, label: label})
+	}
+	if len(items) == 0 {
+		return fmt.Errorf("there are no unlocked exercises in the %s track to download", track)
+	}
+	slug, err := pick(fmt.Sprintf("Which exercise of %s?", track), items)
+	if err != nil {
+		return err
+	}
+	if err := flags.Set("track", track); err != nil {
+		return err
+	}
+	return flags.Set("exercise", slug)
+}
+
+// pickTrack asks which of the tracks in the workspace to download from, or else of the tracks joined,
+// or for the track's slug if there are none yet.
+func pickTrack(cfg config.Config) (string, error) {
+	usrCfg := cfg.UserViperConfig
+	ws, err := openWorkspace(usrCfg)
+	if err != nil {
+		return "", err
+	}
+	var tracks []string
+	if _, err := os.Stat(ws.Dir); err == nil {
+		history, err := newPracticeHistory(ws)
+		if err != nil {
+			return "", err
+		}
+		tracks = history.tracks
+	}
+	if len(tracks) == 0 {
+		// Without the list, the track can still be typed in.
+		joined, _ := fetchTracks(usrCfg.GetString("token"), usrCfg.GetString("apibaseurl"))
+		for _, track := range joined {
+			if track.Joined {
+				tracks = append(tracks, track.Slug)
+			}
+		}
+	}
+	if len(tracks) == 0 {
+		track, err := prompt("Which track? ")
+		if err != nil || track == "" {
+			return "", errors.New("need a --track to download from")
+		}
+		return track, nil
+	}
+
+	items := make([]pickerItem, len(tracks))
+	for i, track := range tracks {
+		items[i] = pickerItem{value: track, label: track}
+	}
+	return pick("Which track?", items)
+}
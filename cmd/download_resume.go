@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/exercism/cli/debug"
+	"github.com/exercism/cli/workspace"
+)
+
+// partialDownloadsDir is where the files of downloads are kept while they are being fetched,
+// relative to the state directory.
+const partialDownloadsDir = "downloads"
+
+// prefetchedMaxAge is how long the files that were fetched ahead of time are used for,
+// after which the track may well have changed them.
+const prefetchedMaxAge = 24 * time.Hour
+
+// partialDownload keeps the files of a download as they are fetched,
+// so that a download that was interrupted can be resumed without fetching them again.
+// A nil partialDownload keeps nothing.
+type partialDownload struct {
+	dir string
+	mu  sync.Mutex
+
+	SolutionID  string `json:"solution_id"`
+	SubmittedAt string `json:"submitted_at,omitempty"`
+	// PrefetchedAt is when the files started being fetched ahead of time, rather than for a download.
+	PrefetchedAt time.Time `json:"prefetched_at,omitempty"`
+	// Files are the files fetched so far, by their path relative to the exercise directory.
+	Files map[string]partialFile `json:"files"`
+}
+
+// partialFile is what is needed to tell that a file that was fetched was kept whole.
+type partialFile struct {
+	Size     int    `json:"size"`
+	Checksum string `json:"checksum"`
+	// ModTime is when the file was last changed on the website, if it said.
+	ModTime time.Time `json:"mod_time,omitempty"`
+}
+
+// openPartialDownload picks up what was fetched of the solution by a download that didn't finish.
+// The files are fetched again if the solution has been submitted since,
+// or if they were fetched ahead of time too long ago.
+// It returns nil if there's no state directory to keep the files in.
+func openPartialDownload(stateDir string, payload *downloadPayload) *partialDownload {
+	if stateDir == "" || payload == nil || payload.Solution.ID == "" {
+		return nil
+	}
+	var submittedAt string
+	if payload.Solution.Iteration.SubmittedAt != nil {
+		submittedAt = *payload.Solution.Iteration.SubmittedAt
+	}
+	p := &partialDownload{
+		dir:         filepath.Join(stateDir, partialDownloadsDir, filepath.Base(payload.Solution.ID)),
+		SolutionID:  payload.Solution.ID,
+		SubmittedAt: submittedAt,
+		Files:       map[string]partialFile{},
+	}
+
+	b, err := ioutil.ReadFile(p.manifestPath())
+	if err != nil {
+		return p
+	}
+	var previous partialDownload
+	err = json.Unmarshal(b, &previous)
+	stale := !previous.PrefetchedAt.IsZero() && time.Since(previous.PrefetchedAt) > prefetchedMaxAge
+	if err != nil || previous.SolutionID != p.SolutionID || previous.SubmittedAt != p.SubmittedAt || stale {
+		debug.Printf("Discarding what was fetched of %s before\n", p.SolutionID)
+		_ = os.RemoveAll(p.dir)
+		return p
+	}
+	if previous.Files != nil {
+		p.Files = previous.Files
+	}
+	p.PrefetchedAt = previous.PrefetchedAt
+	return p
+}
+
+func (p *partialDownload) manifestPath() string {
+	return filepath.Join(p.dir, "manifest.json")
+}
+
+func (p *partialDownload) filePath(path string) string {
+	return workspace.LongPath(filepath.Join(p.dir, "files", path))
+}
+
+// read returns the file, and when it was last changed on the website, if it was fetched whole before.
+// Otherwise the contents are nil.
+func (p *partialDownload) read(path string) ([]byte, time.Time) {
+	if p == nil {
+		return nil, time.Time{}
+	}
+	p.mu.Lock()
+	kept, ok := p.Files[path]
+	p.mu.Unlock()
+	if !ok {
+		return nil, time.Time{}
+	}
+	contents, err := ioutil.ReadFile(p.filePath(path))
+	if err != nil || len(contents) != kept.Size || workspace.Checksum(contents) != kept.Checksum {
+		return nil, time.Time{}
+	}
+	return contents, kept.ModTime
+}
+
+// keep stores a file that was fetched, so that it isn't fetched again if the download is interrupted.
+func (p *partialDownload) keep(path string, contents []byte, modTime time.Time) error {
+	if p == nil {
+		return nil
+	}
+	target := p.filePath(path)
+	if err := os.MkdirAll(filepath.Dir(target), os.FileMode(0755)); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(target, contents, os.FileMode(0644)); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Files[path] = partialFile{Size: len(contents), Checksum: workspace.Checksum(contents), ModTime: modTime}
+	b, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(p.manifestPath(), b, os.FileMode(0644))
+}
+
+// remove discards the files once the download is done.
+func (p *partialDownload) remove() error {
+	if p == nil {
+		return nil
+	}
+	return os.RemoveAll(p.dir)
+}
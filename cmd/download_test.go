@@ -1,6 +1,11 @@
 package cmd
 
 import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -8,9 +13,15 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/exercism/cli/api"
 	"github.com/exercism/cli/config"
 	"github.com/exercism/cli/workspace"
 	"github.com/spf13/pflag"
@@ -111,6 +122,12 @@ func TestSolutionFile(t *testing.T) {
 			expectedPath: fmt.Sprintf("%[1]cbogus-exercise-12345%[1]cnumeric.txt", os.PathSeparator),
 			expectedURL:  "http://www.example.com//bogus-exercise-12345/numeric.txt",
 		},
+		{
+			name:         "filename with characters other than ASCII",
+			file:         "übung/ファイル.txt",
+			expectedPath: fmt.Sprintf("übung%cファイル.txt", os.PathSeparator),
+			expectedURL:  "http://www.example.com/%C3%BCbung/%E3%83%95%E3%82%A1%E3%82%A4%E3%83%AB.txt",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -206,6 +223,150 @@ func TestDownload(t *testing.T) {
 		assert.Equal(t, "bogus-track", metadata.Track)
 		assert.Equal(t, "bogus-exercise", metadata.ExerciseSlug)
 		assert.Equal(t, tc.requester, metadata.IsRequester)
+		assert.Equal(t, "practice", metadata.Type)
+		assert.Equal(t, "easy", metadata.Difficulty)
+		assert.Equal(t, []string{"strings", "loops"}, metadata.Topics)
+		assert.Equal(t, "A bogus exercise.", metadata.Blurb)
+	}
+}
+
+func TestPrintExerciseSummary(t *testing.T) {
+	co := newCapturedOutput()
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	printExerciseSummary(workspace.ExerciseMetadata{
+		ExerciseSlug: "bogus-exercise",
+		Type:         "practice",
+		Difficulty:   "easy",
+		Topics:       []string{"strings", "loops"},
+		Blurb:        "A bogus exercise.",
+	})
+	out := Err.(*bytes.Buffer).String()
+	assert.Contains(t, out, "bogus-exercise (practice, easy)")
+	assert.Contains(t, out, "A bogus exercise.")
+	assert.Contains(t, out, "Topics: strings, loops")
+
+	Err.(*bytes.Buffer).Reset()
+	printExerciseSummary(workspace.ExerciseMetadata{ExerciseSlug: "bogus-exercise"})
+	assert.Equal(t, "", Err.(*bytes.Buffer).String())
+}
+
+func TestDownloadMultipleExercises(t *testing.T) {
+	co := newCapturedOutput()
+	co.override()
+	defer co.reset()
+
+	tmpDir, err := ioutil.TempDir("", "download-many")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	mux.HandleFunc("/file-1.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "this is file 1")
+	})
+	mux.HandleFunc("/solutions/latest", func(w http.ResponseWriter, r *http.Request) {
+		slug := r.FormValue("exercise_id")
+		if slug == "no-such-exercise" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": {"type": "exercise_not_found", "message": "exercise not found"}}`)
+			return
+		}
+		fmt.Fprintf(w, `{"solution": {"id": "%[1]s-id", "user": {"handle": "alice", "is_requester": true}, "exercise": {"id": "%[1]s", "track": {"id": "%[2]s"}}, "file_download_base_url": "%[3]s/", "files": ["file-1.txt"]}}`, slug, r.FormValue("track_id"), ts.URL)
+	})
+
+	v := viper.New()
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	v.Set("token", "abc123")
+	cfg := config.Config{
+		UserViperConfig: v,
+	}
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupDownloadFlags(flags)
+	flags.Set("track", "bogus-track")
+	flags.Set("exercise", "two-fer,leap")
+	flags.Set("exercise", "no-such-exercise")
+	flags.Set("exercise", "leap")
+
+	err = runDownload(cfg, flags, []string{})
+	if assert.Error(t, err) {
+		assert.Regexp(t, "failed to download 1 of 3 exercises: no-such-exercise", err.Error())
+	}
+
+	for _, slug := range []string{"two-fer", "leap"} {
+		b, err := ioutil.ReadFile(filepath.Join(tmpDir, "bogus-track", slug, "file-1.txt"))
+		assert.NoError(t, err)
+		assert.Equal(t, "this is file 1", string(b))
+	}
+}
+
+func TestDownloadAllUnlocked(t *testing.T) {
+	co := newCapturedOutput()
+	stderr := &bytes.Buffer{}
+	co.newErr = stderr
+	co.override()
+	defer co.reset()
+
+	tmpDir, err := ioutil.TempDir("", "download-all")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	mux.HandleFunc("/tracks/bogus-track/exercises", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"exercises": [{"slug": "hello-world", "status": "completed"}, {"slug": "leap", "status": "available"}, {"slug": "clock", "status": "locked"}]}`)
+	})
+	mux.HandleFunc("/file-1.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "this is file 1")
+	})
+	var mu sync.Mutex
+	var requested []string
+	mux.HandleFunc("/solutions/latest", func(w http.ResponseWriter, r *http.Request) {
+		slug := r.FormValue("exercise_id")
+		mu.Lock()
+		requested = append(requested, slug)
+		mu.Unlock()
+		fmt.Fprintf(w, `{"solution": {"id": "%[1]s-id", "user": {"handle": "alice", "is_requester": true}, "exercise": {"id": "%[1]s", "track": {"id": "%[2]s"}}, "file_download_base_url": "%[3]s/", "files": ["file-1.txt"]}}`, slug, r.FormValue("track_id"), ts.URL)
+	})
+
+	v := viper.New()
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	v.Set("token", "abc123")
+	cfg := config.Config{
+		UserViperConfig: v,
+	}
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupDownloadFlags(flags)
+	flags.Set("track", "bogus-track")
+	flags.Set("all-unlocked", "true")
+
+	err = runDownload(cfg, flags, []string{})
+	assert.NoError(t, err)
+	sort.Strings(requested)
+	assert.Equal(t, []string{"hello-world", "leap"}, requested)
+	assert.Regexp(t, "Leaving out 1 locked exercise", stderr.String())
+
+	for _, slug := range []string{"hello-world", "leap"} {
+		_, err := os.Stat(filepath.Join(tmpDir, "bogus-track", slug, "file-1.txt"))
+		assert.NoError(t, err)
+	}
+
+	flags = pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupDownloadFlags(flags)
+	flags.Set("all", "true")
+	err = runDownload(cfg, flags, []string{})
+	if assert.Error(t, err) {
+		assert.Regexp(t, "need the --track", err.Error())
 	}
 }
 
@@ -319,6 +480,10 @@ const payloadTemplate = `
 			"id": "bogus-exercise",
 			"instructions_url": "http://example.com/bogus-exercise",
 			"auto_approve": false,
+			"type": "practice",
+			"difficulty": "easy",
+			"topics": ["strings", "loops"],
+			"blurb": "A bogus exercise.",
 			"track": {
 				"id": "bogus-track",
 				"language": "Bogus Language"
@@ -330,9 +495,714 @@ const payloadTemplate = `
 			"subdir/file-2.txt",
 			"file-3.txt"
 		],
+		"executable_files": ["subdir/file-2.txt"],
 		"iteration": {
 			"submitted_at": "2017-08-21t10:11:12.130z"
 		}
 	}
 }
 `
+
+func TestDownloadFileModes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows doesn't have executable permissions")
+	}
+	co := newCapturedOutput()
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	tmpDir, err := ioutil.TempDir("", "download-modes")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	ts := fakeDownloadServer("true", "")
+	defer ts.Close()
+
+	v := viper.New()
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	v.Set("token", "abc123")
+	cfg := config.Config{UserViperConfig: v}
+
+	download := func() error {
+		flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+		setupDownloadFlags(flags)
+		flags.Set("exercise", "bogus-exercise")
+		flags.Set("force", "true")
+		return runDownload(cfg, flags, []string{})
+	}
+	mode := func(name string) os.FileMode {
+		info, err := os.Stat(filepath.Join(tmpDir, "bogus-track", "bogus-exercise", name))
+		assert.NoError(t, err)
+		return info.Mode().Perm()
+	}
+	// The umask is whatever permissions a file that is created doesn't get.
+	probe := filepath.Join(tmpDir, "umask")
+	f, err := os.OpenFile(probe, os.O_CREATE|os.O_EXCL|os.O_WRONLY, os.FileMode(0777))
+	assert.NoError(t, err)
+	f.Close()
+	info, err := os.Stat(probe)
+	assert.NoError(t, err)
+	umask := os.FileMode(0777) &^ info.Mode().Perm()
+	assert.NoError(t, os.Remove(probe))
+
+	v.Set("download.file_mode", "0640")
+	assert.NoError(t, download())
+	assert.Equal(t, os.FileMode(0640)&^umask, mode("file-1.txt"))
+	assert.Equal(t, os.FileMode(0755)&^umask, mode(filepath.Join("subdir", "file-2.txt")))
+
+	// Files that are already there keep their permissions, but become executable if they have to be.
+	file1 := filepath.Join(tmpDir, "bogus-track", "bogus-exercise", "file-1.txt")
+	file2 := filepath.Join(tmpDir, "bogus-track", "bogus-exercise", "subdir", "file-2.txt")
+	assert.NoError(t, os.Chmod(file1, os.FileMode(0600)))
+	assert.NoError(t, os.Chmod(file2, os.FileMode(0600)))
+	assert.NoError(t, download())
+	assert.Equal(t, os.FileMode(0600), mode("file-1.txt"))
+	assert.Equal(t, os.FileMode(0711), mode(filepath.Join("subdir", "file-2.txt")))
+
+	v.Set("download.file_mode", "rw-r--r--")
+	err = download()
+	if assert.Error(t, err) {
+		assert.Regexp(t, "cannot understand download.file_mode", err.Error())
+	}
+}
+
+func TestDownloadConvertsLineEndings(t *testing.T) {
+	co := newCapturedOutput()
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	mux.HandleFunc("/solutions/latest", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, payloadTemplate, "true", "null", ts.URL+"/")
+	})
+	mux.HandleFunc("/file-1.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "line 1\nline 2\n")
+	})
+	mux.HandleFunc("/subdir/file-2.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "this is file 2")
+	})
+	mux.HandleFunc("/file-3.txt", func(w http.ResponseWriter, r *http.Request) {})
+
+	tmpDir, err := ioutil.TempDir("", "download-line-endings")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	v := viper.New()
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	v.Set("token", "abc123")
+	v.Set("line_endings", "crlf")
+	cfg := config.Config{UserViperConfig: v}
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupDownloadFlags(flags)
+	flags.Set("exercise", "bogus-exercise")
+	assert.NoError(t, runDownload(cfg, flags, []string{}))
+
+	b, err := ioutil.ReadFile(filepath.Join(tmpDir, "bogus-track", "bogus-exercise", "file-1.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "line 1\r\nline 2\r\n", string(b))
+}
+
+func TestDownloadWithConcepts(t *testing.T) {
+	co := newCapturedOutput()
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	mux.HandleFunc("/solutions/latest", func(w http.ResponseWriter, r *http.Request) {
+		payload := fmt.Sprintf(payloadTemplate, "true", "null", ts.URL+"/")
+		prerequisites := fmt.Sprintf(`"type": "concept",
+			"prerequisites": [
+				{"slug": "strings", "introduction_url": "%[1]s/concepts/strings/introduction.md", "about_url": "%[1]s/concepts/strings/about.md"},
+				{"slug": "loops", "introduction_url": "%[1]s/concepts/loops/introduction.md", "about_url": "%[1]s/concepts/loops/missing.md"}
+			],`, ts.URL)
+		fmt.Fprint(w, strings.Replace(payload, `"type": "practice",`, prerequisites, 1))
+	})
+	mux.HandleFunc("/file-1.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "this is file 1")
+	})
+	mux.HandleFunc("/subdir/file-2.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "this is file 2")
+	})
+	mux.HandleFunc("/file-3.txt", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/concepts/strings/introduction.md", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "# Introduction to strings")
+	})
+	mux.HandleFunc("/concepts/strings/about.md", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "# About strings")
+	})
+	mux.HandleFunc("/concepts/loops/introduction.md", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "# Introduction to loops")
+	})
+
+	tmpDir, err := ioutil.TempDir("", "download-concepts")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	v := viper.New()
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	v.Set("token", "abc123")
+	cfg := config.Config{UserViperConfig: v}
+	docsDir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise", ".docs", "concepts")
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupDownloadFlags(flags)
+	flags.Set("exercise", "bogus-exercise")
+	assert.NoError(t, runDownload(cfg, flags, []string{}))
+	_, err = os.Stat(docsDir)
+	assert.True(t, os.IsNotExist(err), "concepts are only downloaded with --with-concepts")
+
+	flags = pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupDownloadFlags(flags)
+	flags.Set("exercise", "bogus-exercise")
+	flags.Set("with-concepts", "true")
+	assert.NoError(t, runDownload(cfg, flags, []string{}))
+
+	for path, expected := range map[string]string{
+		filepath.Join("strings", "introduction.md"): "# Introduction to strings",
+		filepath.Join("strings", "about.md"):        "# About strings",
+		filepath.Join("loops", "introduction.md"):   "# Introduction to loops",
+	} {
+		b, err := ioutil.ReadFile(filepath.Join(docsDir, path))
+		assert.NoError(t, err, path)
+		assert.Equal(t, expected, string(b), path)
+	}
+	_, err = os.Stat(filepath.Join(docsDir, "loops", "about.md"))
+	assert.True(t, os.IsNotExist(err))
+	assert.Regexp(t, "Warning: skipping .*about.md, it isn't on the website", Err.(*bytes.Buffer).String())
+}
+
+func TestDownloadFailsOnMissingSolutionFile(t *testing.T) {
+	co := newCapturedOutput()
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	mux.HandleFunc("/solutions/latest", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, payloadTemplate, "true", "null", ts.URL+"/")
+	})
+	mux.HandleFunc("/file-1.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "this is file 1")
+	})
+	mux.HandleFunc("/subdir/file-2.txt", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"error": {"type": "forbidden", "message": "the link has expired"}}`)
+	})
+
+	tmpDir, err := ioutil.TempDir("", "download-missing-file")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	v := viper.New()
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	v.Set("token", "abc123")
+	cfg := config.Config{UserViperConfig: v}
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupDownloadFlags(flags)
+	flags.Set("exercise", "bogus-exercise")
+	err = runDownload(cfg, flags, []string{})
+	if assert.Error(t, err) {
+		assert.Regexp(t, "the link has expired", err.Error())
+	}
+	_, err = os.Stat(filepath.Join(tmpDir, "bogus-track", "bogus-exercise"))
+	assert.True(t, os.IsNotExist(err), "nothing is written with files missing")
+}
+
+func TestDownloadToConceptDir(t *testing.T) {
+	co := newCapturedOutput()
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	exerciseType := "concept"
+	mux.HandleFunc("/solutions/latest", func(w http.ResponseWriter, r *http.Request) {
+		payload := fmt.Sprintf(payloadTemplate, "true", "null", ts.URL+"/")
+		fmt.Fprint(w, strings.Replace(payload, `"type": "practice",`, fmt.Sprintf(`"type": %q,`, exerciseType), 1))
+	})
+	mux.HandleFunc("/file-1.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "this is file 1")
+	})
+	mux.HandleFunc("/subdir/file-2.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "this is file 2")
+	})
+	mux.HandleFunc("/file-3.txt", func(w http.ResponseWriter, r *http.Request) {})
+
+	tmpDir, err := ioutil.TempDir("", "download-concept-dir")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	v := viper.New()
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	v.Set("token", "abc123")
+	v.Set("concept_dir", "concepts")
+	cfg := config.Config{UserViperConfig: v}
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupDownloadFlags(flags)
+	flags.Set("exercise", "bogus-exercise")
+	assert.NoError(t, runDownload(cfg, flags, []string{}))
+	_, err = os.Stat(filepath.Join(tmpDir, "concepts", "bogus-track", "bogus-exercise", "file-1.txt"))
+	assert.NoError(t, err)
+
+	exerciseType = "practice"
+	flags = pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupDownloadFlags(flags)
+	flags.Set("exercise", "bogus-exercise")
+	assert.NoError(t, runDownload(cfg, flags, []string{}))
+	_, err = os.Stat(filepath.Join(tmpDir, "bogus-track", "bogus-exercise", "file-1.txt"))
+	assert.NoError(t, err)
+
+	v.Set("concept_dir", "../elsewhere")
+	flags = pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupDownloadFlags(flags)
+	flags.Set("exercise", "bogus-exercise")
+	err = runDownload(cfg, flags, []string{})
+	if assert.Error(t, err) {
+		assert.Regexp(t, "concept_dir", err.Error())
+	}
+}
+
+func TestDownloadResumesInterruptedDownload(t *testing.T) {
+	co := newCapturedOutput()
+	stderr := &bytes.Buffer{}
+	co.newErr = stderr
+	co.override()
+	defer co.reset()
+
+	tmpDir, err := ioutil.TempDir("", "download-resume")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+	stateDir := filepath.Join(tmpDir, "state")
+
+	var mu sync.Mutex
+	var fetched []string
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	mux.HandleFunc("/solutions/latest", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, payloadTemplate, "true", "null", ts.URL+"/")
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		fetched = append(fetched, r.URL.Path)
+		mu.Unlock()
+		w.Header().Set("Last-Modified", "Tue, 15 Nov 1994 08:12:31 GMT")
+		fmt.Fprintf(w, "fetched %s", r.URL.Path)
+	})
+
+	// A download that was interrupted after fetching the first file.
+	payload := &downloadPayload{}
+	payload.Solution.ID = "bogus-id"
+	submittedAt := "2017-08-21t10:11:12.130z"
+	payload.Solution.Iteration.SubmittedAt = &submittedAt
+	partial := openPartialDownload(stateDir, payload)
+	keptModTime := time.Date(2017, 8, 20, 9, 10, 11, 0, time.UTC)
+	assert.NoError(t, partial.keep("file-1.txt", []byte("kept file 1"), keptModTime))
+
+	v := viper.New()
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	v.Set("token", "abc123")
+	cfg := config.Config{
+		UserViperConfig: v,
+		StateDir:        stateDir,
+	}
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupDownloadFlags(flags)
+	flags.Set("exercise", "bogus-exercise")
+
+	err = runDownload(cfg, flags, []string{})
+	assert.NoError(t, err)
+
+	sort.Strings(fetched)
+	assert.Equal(t, []string{"/file-3.txt", "/subdir/file-2.txt"}, fetched)
+	assert.Regexp(t, "1 file\\(s\\) had already been fetched", stderr.String())
+
+	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
+	b, err := ioutil.ReadFile(filepath.Join(dir, "file-1.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "kept file 1", string(b))
+	b, err = ioutil.ReadFile(filepath.Join(dir, "subdir", "file-2.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "fetched /subdir/file-2.txt", string(b))
+
+	// Files are dated when they were last changed on the website, whether they were kept or fetched.
+	info, err := os.Stat(filepath.Join(dir, "file-1.txt"))
+	assert.NoError(t, err)
+	assert.True(t, keptModTime.Equal(info.ModTime()), info.ModTime().String())
+	info, err = os.Stat(filepath.Join(dir, "subdir", "file-2.txt"))
+	assert.NoError(t, err)
+	assert.True(t, time.Date(1994, 11, 15, 8, 12, 31, 0, time.UTC).Equal(info.ModTime()), info.ModTime().String())
+
+	_, err = os.Stat(filepath.Join(stateDir, partialDownloadsDir, "bogus-id"))
+	assert.True(t, os.IsNotExist(err), "It should remove the files kept while downloading.")
+}
+
+func TestPartialDownloadDiscardsDamagedFiles(t *testing.T) {
+	stateDir, err := ioutil.TempDir("", "partial-download")
+	defer os.RemoveAll(stateDir)
+	assert.NoError(t, err)
+
+	payload := &downloadPayload{}
+	payload.Solution.ID = "bogus-id"
+	partial := openPartialDownload(stateDir, payload)
+	assert.NoError(t, partial.keep("file-1.txt", []byte("file 1"), time.Time{}))
+	kept, _ := openPartialDownload(stateDir, payload).read("file-1.txt")
+	assert.Equal(t, "file 1", string(kept))
+
+	// A file that was cut short is fetched again.
+	err = ioutil.WriteFile(partial.filePath("file-1.txt"), []byte("fil"), os.FileMode(0644))
+	assert.NoError(t, err)
+	kept, _ = openPartialDownload(stateDir, payload).read("file-1.txt")
+	assert.Nil(t, kept)
+
+	// So is everything, once the solution has been submitted again.
+	assert.NoError(t, partial.keep("file-1.txt", []byte("file 1"), time.Time{}))
+	submittedAt := "2017-08-21t10:11:12.130z"
+	payload.Solution.Iteration.SubmittedAt = &submittedAt
+	kept, _ = openPartialDownload(stateDir, payload).read("file-1.txt")
+	assert.Nil(t, kept)
+
+	// And files that were fetched ahead of time too long ago.
+	partial = openPartialDownload(stateDir, payload)
+	partial.PrefetchedAt = time.Now().Add(-2 * prefetchedMaxAge)
+	assert.NoError(t, partial.keep("file-1.txt", []byte("file 1"), time.Time{}))
+	kept, _ = openPartialDownload(stateDir, payload).read("file-1.txt")
+	assert.Nil(t, kept)
+
+	assert.Nil(t, openPartialDownload("", payload))
+}
+
+func TestDownloadIteration(t *testing.T) {
+	co := newCapturedOutput()
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	tmpDir, err := ioutil.TempDir("", "download-iteration")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	mux.HandleFunc("/solutions/latest", func(w http.ResponseWriter, r *http.Request) {
+		baseURL := ts.URL + "/latest/"
+		if iteration := r.FormValue("iteration"); iteration != "" {
+			baseURL = ts.URL + "/iteration-" + iteration + "/"
+		}
+		fmt.Fprintf(w, payloadTemplate, "true", "null", baseURL)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "file-3.txt") {
+			return
+		}
+		fmt.Fprintf(w, "%s", strings.TrimPrefix(r.URL.Path, "/"))
+	})
+
+	v := viper.New()
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	v.Set("token", "abc123")
+	cfg := config.Config{
+		UserViperConfig: v,
+		StateDir:        filepath.Join(tmpDir, "state"),
+	}
+	run := func(flagValues map[string]string) error {
+		flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+		setupDownloadFlags(flags)
+		flags.Set("exercise", "bogus-exercise")
+		for name, value := range flagValues {
+			flags.Set(name, value)
+		}
+		return runDownload(cfg, flags, []string{})
+	}
+	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
+	read := func(path string) string {
+		b, err := ioutil.ReadFile(filepath.Join(dir, path))
+		assert.NoError(t, err)
+		return string(b)
+	}
+
+	assert.NoError(t, run(nil))
+	assert.Equal(t, "latest/file-1.txt", read("file-1.txt"))
+
+	assert.NoError(t, run(map[string]string{"iteration": "3", "into": "v3"}))
+	assert.Equal(t, "iteration-3/file-1.txt", read(filepath.Join("v3", "file-1.txt")))
+	assert.Equal(t, "iteration-3/subdir/file-2.txt", read(filepath.Join("v3", "subdir", "file-2.txt")))
+	assert.Equal(t, "latest/file-1.txt", read("file-1.txt"))
+
+	// The files in the exercise directory differ from the iteration's.
+	err = run(map[string]string{"iteration": "2"})
+	if assert.Error(t, err) {
+		assert.Regexp(t, "would overwrite", err.Error())
+	}
+	assert.NoError(t, run(map[string]string{"iteration": "2", "theirs": "true"}))
+	assert.Equal(t, "iteration-2/file-1.txt", read("file-1.txt"))
+
+	// The restored files count as changed locally.
+	err = run(nil)
+	if assert.Error(t, err) {
+		assert.Regexp(t, "would overwrite", err.Error())
+	}
+
+	for message, flagValues := range map[string]map[string]string{
+		"at least 1":          {"iteration": "-1"},
+		"needs one":           {"into": "v3"},
+		"inside the exercise": {"iteration": "3", "into": "../v3"},
+		"aren't merged":       {"iteration": "3", "update": "true"},
+	} {
+		err := run(flagValues)
+		if assert.Error(t, err) {
+			assert.Regexp(t, message, err.Error())
+		}
+	}
+}
+
+func TestParseDownloadLink(t *testing.T) {
+	testCases := []struct {
+		arg      string
+		expected *downloadLink
+		err      string
+	}{
+		{arg: "https://exercism.org/tracks/go/exercises/hamming", expected: &downloadLink{track: "go", slug: "hamming"}},
+		{arg: "https://exercism.org/tracks/go/exercises/hamming/iterations?idx=2", expected: &downloadLink{track: "go", slug: "hamming"}},
+		{arg: "https://exercism.io/my/solutions/0123456789abcdef0123456789abcdef", expected: &downloadLink{uuid: "0123456789abcdef0123456789abcdef"}},
+		{arg: "01234567-89ab-cdef-0123-456789abcdef", expected: &downloadLink{uuid: "01234567-89ab-cdef-0123-456789abcdef"}},
+		{arg: "https://exercism.org/tracks/go/exercises/hamming/solutions/alice", err: "doesn't say which solution"},
+		{arg: "https://exercism.org/tracks/go", err: "isn't a link to an exercise"},
+		{arg: "hamming", err: "neither a link"},
+	}
+
+	for _, tc := range testCases {
+		link, err := parseDownloadLink(tc.arg)
+		if tc.err != "" {
+			if assert.Error(t, err, tc.arg) {
+				assert.Regexp(t, tc.err, err.Error())
+			}
+			continue
+		}
+		assert.NoError(t, err, tc.arg)
+		assert.Equal(t, tc.expected, link, tc.arg)
+	}
+}
+
+func TestDownloadFromLink(t *testing.T) {
+	co := newCapturedOutput()
+	co.override()
+	defer co.reset()
+
+	tmpDir, err := ioutil.TempDir("", "download-link")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	ts := fakeDownloadServer("true", "")
+	defer ts.Close()
+
+	v := viper.New()
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	v.Set("token", "abc123")
+	cfg := config.Config{
+		UserViperConfig: v,
+	}
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupDownloadFlags(flags)
+	flags.Set("track", "bogus-track")
+	err = runDownload(cfg, flags, []string{"https://exercism.org/tracks/bogus-track/exercises/bogus-exercise"})
+	assert.NoError(t, err)
+	assertDownloadedCorrectFiles(t, tmpDir)
+
+	flags = pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupDownloadFlags(flags)
+	flags.Set("track", "go")
+	err = runDownload(cfg, flags, []string{"https://exercism.org/tracks/bogus-track/exercises/bogus-exercise"})
+	if assert.Error(t, err) {
+		assert.Regexp(t, "the link is to bogus-track, but --track is go", err.Error())
+	}
+}
+
+func TestDownloadOpen(t *testing.T) {
+	co := newCapturedOutput()
+	co.override()
+	defer co.reset()
+
+	tmpDir, err := ioutil.TempDir("", "download-open")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	ts := fakeDownloadServer("true", "")
+	defer ts.Close()
+
+	v := viper.New()
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	v.Set("token", "abc123")
+	cfg := config.Config{
+		UserViperConfig: v,
+	}
+
+	var opened string
+	defer func(f func(*viper.Viper, string) error) { openInEditor = f }(openInEditor)
+	openInEditor = func(_ *viper.Viper, dir string) error {
+		opened = dir
+		return nil
+	}
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupDownloadFlags(flags)
+	flags.Set("exercise", "bogus-exercise")
+	flags.Set("open", "true")
+	assert.NoError(t, runDownload(cfg, flags, []string{}))
+	assert.Equal(t, filepath.Join(tmpDir, "bogus-track", "bogus-exercise"), opened)
+
+	flags = pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupDownloadFlags(flags)
+	flags.Set("track", "bogus-track")
+	flags.Set("exercise", "bogus-exercise,leap")
+	flags.Set("open", "true")
+	err = runDownload(cfg, flags, []string{})
+	if assert.Error(t, err) {
+		assert.Regexp(t, "--open opens one exercise at a time", err.Error())
+	}
+}
+
+func TestDownloadDocsOnly(t *testing.T) {
+	co := newCapturedOutput()
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	tmpDir, err := ioutil.TempDir("", "download-docs")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	version := "1"
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	mux.HandleFunc("/solutions/latest", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"solution": {"id": "bogus-id", "user": {"handle": "alice", "is_requester": true}, "exercise": {"id": "bogus-exercise", "track": {"id": "bogus-track"}}, "file_download_base_url": "%s/", "files": ["README.md", ".docs/instructions.md", "leap.go", "leap_test.go"]}}`, ts.URL)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s version %s", r.URL.Path, version)
+	})
+
+	v := viper.New()
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	v.Set("token", "abc123")
+	cfg := config.Config{
+		UserViperConfig: v,
+	}
+	run := func(docsOnly bool) error {
+		flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+		setupDownloadFlags(flags)
+		flags.Set("exercise", "bogus-exercise")
+		if docsOnly {
+			flags.Set("docs-only", "true")
+		}
+		return runDownload(cfg, flags, []string{})
+	}
+	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
+	read := func(path string) string {
+		b, err := ioutil.ReadFile(filepath.Join(dir, path))
+		assert.NoError(t, err)
+		return string(b)
+	}
+
+	err = run(true)
+	if assert.Error(t, err) {
+		assert.Regexp(t, "hasn't been downloaded", err.Error())
+	}
+
+	assert.NoError(t, run(false))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "leap.go"), []byte("my solution"), os.FileMode(0644)))
+
+	version = "2"
+	assert.NoError(t, run(true))
+	assert.Equal(t, "/README.md version 2", read("README.md"))
+	assert.Equal(t, "/.docs/instructions.md version 2", read(filepath.Join(".docs", "instructions.md")))
+	assert.Equal(t, "my solution", read("leap.go"))
+	assert.Equal(t, "/leap_test.go version 1", read("leap_test.go"))
+}
+
+func TestCheckDigest(t *testing.T) {
+	contents := []byte("this is file 1")
+	md5Sum := md5.Sum(contents)
+	sha := sha256.Sum256(contents)
+
+	testCases := []struct {
+		header, value string
+		ok            bool
+	}{
+		{"Content-MD5", base64.StdEncoding.EncodeToString(md5Sum[:]), true},
+		{"Content-MD5", base64.StdEncoding.EncodeToString([]byte("0123456789abcdef")), false},
+		{"Digest", "sha-256=" + base64.StdEncoding.EncodeToString(sha[:]), true},
+		{"Digest", "unknown=abc, sha-256=" + base64.StdEncoding.EncodeToString(md5Sum[:]), false},
+		{"ETag", `"` + hex.EncodeToString(md5Sum[:]) + `"`, true},
+		{"ETag", `"0123456789abcdef0123456789abcdef"`, false},
+		{"ETag", `W/"0123456789abcdef0123456789abcdef"`, true},
+		{"ETag", `"5f1d7e3c-1a"`, true},
+	}
+	for _, tc := range testCases {
+		res := &http.Response{Header: http.Header{}}
+		res.Header.Set(tc.header, tc.value)
+		err := checkDigest("http://example.com/file-1.txt", res, contents)
+		if tc.ok {
+			assert.NoError(t, err, tc.header+": "+tc.value)
+		} else if assert.Error(t, err, tc.header+": "+tc.value) {
+			assert.Regexp(t, "damaged on the way", err.Error())
+		}
+	}
+}
+
+func TestFetchVerifiedFileRetriesDamagedFiles(t *testing.T) {
+	contents := []byte("this is file 1")
+	sum := md5.Sum(contents)
+	var mu sync.Mutex
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts++
+		damaged := attempts == 1
+		mu.Unlock()
+		w.Header().Set("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+		if damaged {
+			fmt.Fprint(w, "this is file !")
+			return
+		}
+		w.Write(contents)
+	}))
+	defer ts.Close()
+
+	client, err := api.NewClient("abc123", ts.URL)
+	assert.NoError(t, err)
+	b, _, err := fetchVerifiedFile(client, ts.URL+"/file-1.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, contents, b)
+	assert.Equal(t, 2, attempts)
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// The formats failures can be reported in, chosen with --error-format
+// or the error_format setting in the user config.
+const (
+	errorFormatText = "text"
+	errorFormatJSON = "json"
+)
+
+// errorFormatSetting is the error_format setting in the user config, used unless --error-format is given.
+var errorFormatSetting string
+
+// errorFormat is the format in effect for reporting failures, given the command's flags.
+func errorFormat(flags *pflag.FlagSet) string {
+	if flag := flags.Lookup("error-format"); flag != nil && flag.Changed {
+		return flag.Value.String()
+	}
+	return errorFormatSetting
+}
+
+// errorReport describes a failure for programs that wrap the CLI, such as editor plugins,
+// so they don't have to pick apart the message.
+type errorReport struct {
+	// Code identifies the failure, e.g. "missing_token" or "http_500".
+	Code string `json:"code"`
+	// Category is one of "api", "network", "config", "usage" or "general".
+	Category string `json:"category"`
+	Message  string `json:"message"`
+	// Remediation is a command that might fix the problem.
+	Remediation string `json:"remediation,omitempty"`
+	// RequestID identifies the request in the API's logs.
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// usageError is a command called with flags that it doesn't take, or with bad values.
+type usageError struct {
+	command string
+	err     error
+}
+
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+// flagError marks flag errors as usage errors, after adding suggestions for mistyped flags.
+func flagError(cmd *cobra.Command, err error) error {
+	return &usageError{command: cmd.CommandPath(), err: suggestFlagError(cmd, err)}
+}
+
+func newErrorReport(err error) errorReport {
+	report := errorReport{
+		Code:     "error",
+		Category: "general",
+		Message:  strings.TrimSpace(err.Error()),
+	}
+
+	var (
+		apiErr     *apiError
+		configErr  *userConfigError
+		usageErr   *usageError
+		timeoutErr *commandTimeoutError
+		netErr     net.Error
+	)
+	switch {
+	case errors.As(err, &timeoutErr):
+		report.Code = "timeout"
+	case errors.As(err, &apiErr):
+		report.Category = "api"
+		report.Code = apiErr.Type
+		if report.Code == "" {
+			report.Code = fmt.Sprintf("http_%d", apiErr.StatusCode)
+		}
+		report.RequestID = apiErr.RequestID
+		switch apiErr.StatusCode {
+		case http.StatusUnauthorized:
+			report.Remediation = fmt.Sprintf("%s configure --token=YOUR_TOKEN", BinaryName)
+		case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			report.Remediation = fmt.Sprintf("%s troubleshoot", BinaryName)
+		}
+	case errors.As(err, &configErr):
+		report.Category = "config"
+		report.Code = configErr.code
+		report.Remediation = configErr.remediation
+	case errors.As(err, &usageErr):
+		report.Category = "usage"
+		report.Code = "invalid_flag"
+		report.Remediation = fmt.Sprintf("%s --help", usageErr.command)
+	case errors.As(err, &netErr):
+		report.Category = "network"
+		report.Code = "unreachable"
+		if netErr.Timeout() {
+			report.Code = "timeout"
+		}
+		report.Remediation = fmt.Sprintf("%s troubleshoot", BinaryName)
+	}
+	return report
+}
+
+// reportError writes the error that a command failed with in the given format.
+// Anything other than JSON is reported as text.
+func reportError(w io.Writer, err error, format string) {
+	if format != errorFormatJSON {
+		fmt.Fprintf(w, "Error: %s\n", err)
+		return
+	}
+	b, jsonErr := json.Marshal(struct {
+		Error errorReport `json:"error"`
+	}{newErrorReport(err)})
+	if jsonErr != nil {
+		fmt.Fprintf(w, "Error: %s\n", err)
+		return
+	}
+	fmt.Fprintf(w, "%s\n", b)
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestNewErrorReport(t *testing.T) {
+	cmd := &cobra.Command{Use: "download"}
+
+	testCases := []struct {
+		desc     string
+		err      error
+		expected errorReport
+	}{
+		{
+			desc: "API error",
+			err:  &apiError{StatusCode: http.StatusNotFound, Type: "solution_not_found", Message: "Solution not found", RequestID: "abc-123"},
+			expected: errorReport{
+				Code:      "solution_not_found",
+				Category:  "api",
+				Message:   "Solution not found (request ID: abc-123)",
+				RequestID: "abc-123",
+			},
+		},
+		{
+			desc: "server error",
+			err:  &apiError{StatusCode: http.StatusInternalServerError, Status: "500 Internal Server Error"},
+			expected: errorReport{
+				Code:        "http_500",
+				Category:    "api",
+				Message:     "unexpected API response: 500 Internal Server Error",
+				Remediation: BinaryName + " troubleshoot",
+			},
+		},
+		{
+			desc: "unauthorized",
+			err:  &apiError{StatusCode: http.StatusUnauthorized, Type: "invalid_token", Message: "Invalid token"},
+			expected: errorReport{
+				Code:        "invalid_token",
+				Category:    "api",
+				Message:     "Invalid token",
+				Remediation: BinaryName + " configure --token=YOUR_TOKEN",
+			},
+		},
+		{
+			desc: "config error",
+			err:  validateUserConfig(viper.New()),
+			expected: errorReport{
+				Code:        "not_configured",
+				Category:    "config",
+				Message:     newErrorReport(validateUserConfig(viper.New())).Message,
+				Remediation: BinaryName + " configure --token=YOUR_TOKEN",
+			},
+		},
+		{
+			desc: "flag error",
+			err:  flagError(cmd, errors.New("unknown flag: --banana")),
+			expected: errorReport{
+				Code:        "invalid_flag",
+				Category:    "usage",
+				Message:     "unknown flag: --banana",
+				Remediation: "download --help",
+			},
+		},
+		{
+			desc: "network timeout",
+			err:  &net.OpError{Op: "read", Err: timeoutError{}},
+			expected: errorReport{
+				Code:        "timeout",
+				Category:    "network",
+				Message:     "read: i/o timeout",
+				Remediation: BinaryName + " troubleshoot",
+			},
+		},
+		{
+			desc: "command took too long",
+			err:  &commandTimeoutError{limit: 30 * time.Second},
+			expected: errorReport{
+				Code:     "timeout",
+				Category: "general",
+				Message:  "gave up after 30s, as set by --timeout",
+			},
+		},
+		{
+			desc: "anything else",
+			err:  errors.New("no such file"),
+			expected: errorReport{
+				Code:     "error",
+				Category: "general",
+				Message:  "no such file",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, newErrorReport(tc.err), tc.desc)
+	}
+}
+
+func TestReportError(t *testing.T) {
+	err := &apiError{StatusCode: http.StatusBadGateway, Status: "502 Bad Gateway", RequestID: "abc-123"}
+
+	var text bytes.Buffer
+	reportError(&text, err, errorFormatText)
+	assert.Equal(t, "Error: unexpected API response: 502 Bad Gateway (request ID: abc-123)\n", text.String())
+
+	var out bytes.Buffer
+	reportError(&out, err, errorFormatJSON)
+	var payload struct {
+		Error errorReport `json:"error"`
+	}
+	if assert.NoError(t, json.Unmarshal(out.Bytes(), &payload)) {
+		assert.Equal(t, "http_502", payload.Error.Code)
+		assert.Equal(t, "api", payload.Error.Category)
+		assert.Equal(t, "abc-123", payload.Error.RequestID)
+	}
+}
@@ -0,0 +1,329 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/exercism/cli/config"
+	"github.com/exercism/cli/workspace"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// exercisesCmd lists the exercises in a track.
+var exercisesCmd = &cobra.Command{
+	Use:        "exercises",
+	Aliases:    []string{"ls"},
+	SuggestFor: []string{"list", "catalog"},
+	Short:      "List the exercises in a track.",
+	Long: `List the exercises in a track, with their type, difficulty and topics.
+
+The listing can be narrowed down with filters, which are applied by the CLI
+so that they work the same whether or not the website is reachable:
+
+    exercism exercises --track=go --status=available --difficulty=easy --topic=strings
+
+Pass --topic more than once to only list exercises covering all of the topics.
+The in_progress status can also be given as started.
+
+Concept exercises teach a concept and unlock the exercises that build on it;
+practice exercises are for practicing what has been learned. List only one
+kind with --type=concept or --type=practice.
+
+Use --sort to order the exercises by name, difficulty or status instead of
+the order in which the track presents them.
+
+The catalog is cached after each successful request. If the website can't be
+reached, the cached catalog is listed instead, and if there is none, the
+exercises you have downloaded into your workspace.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.NewConfig()
+
+		v := viper.New()
+		v.AddConfigPath(cfg.Dir)
+		v.SetConfigName("user")
+		v.SetConfigType("json")
+		// Ignore error. If the file doesn't exist, that is fine.
+		_ = v.ReadInConfig()
+		config.BindUserEnv(v)
+		cfg.UserViperConfig = v
+
+		return runExercises(cfg, cmd.Flags(), args)
+	},
+}
+
+// exerciseStatuses are the values accepted by --status.
+var exerciseStatuses = []string{
+	statusLocked,
+	statusAvailable,
+	statusInProgress,
+	statusCompleted,
+	statusPublished,
+	statusDownloaded,
+}
+
+// difficultyRanks orders the difficulties from easiest to hardest.
+var difficultyRanks = map[string]int{
+	"easy":   1,
+	"medium": 2,
+	"hard":   3,
+}
+
+// statusRanks orders the statuses from the work in progress to the work not yet started.
+var statusRanks = map[string]int{
+	statusInProgress: 1,
+	statusDownloaded: 2,
+	statusAvailable:  3,
+	statusCompleted:  4,
+	statusPublished:  5,
+	statusLocked:     6,
+}
+
+// exerciseFilter narrows down a catalog.
+type exerciseFilter struct {
+	typ        string
+	status     string
+	difficulty string
+	topics     []string
+}
+
+func (f exerciseFilter) matches(exercise catalogExercise) bool {
+	if f.typ != "" && !strings.EqualFold(exercise.Type, f.typ) {
+		return false
+	}
+	if f.status != "" && normalizeStatus(exercise.Status) != f.status {
+		return false
+	}
+	if f.difficulty != "" && !strings.EqualFold(exercise.Difficulty, f.difficulty) {
+		return false
+	}
+	for _, topic := range f.topics {
+		if !hasTopic(exercise, topic) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasTopic(exercise catalogExercise, topic string) bool {
+	for _, t := range exercise.Topics {
+		if strings.EqualFold(t, topic) {
+			return true
+		}
+	}
+	return false
+}
+
+// statusAliases are other names for statuses, such as the ones the website uses.
+var statusAliases = map[string]string{
+	"started": statusInProgress,
+}
+
+// normalizeStatus accepts statuses spelled with dashes, e.g. in-progress, and their aliases.
+func normalizeStatus(status string) string {
+	status = strings.Replace(strings.ToLower(status), "-", "_", -1)
+	if alias, ok := statusAliases[status]; ok {
+		return alias
+	}
+	return status
+}
+
+func runExercises(cfg config.Config, flags *pflag.FlagSet, args []string) error {
+	usrCfg := cfg.UserViperConfig
+	if err := validateUserConfig(usrCfg); err != nil {
+		return err
+	}
+
+	track, err := flags.GetString("track")
+	if err != nil {
+		return err
+	}
+	if track == "" {
+		return errors.New("need a --track to list the exercises of")
+	}
+
+	filter, err := newExerciseFilter(flags)
+	if err != nil {
+		return err
+	}
+
+	sortBy, err := flags.GetString("sort")
+	if err != nil {
+		return err
+	}
+	if sortBy != "" && sortBy != "name" && sortBy != "difficulty" && sortBy != "status" {
+		return fmt.Errorf("cannot sort by '%s', use one of: name, difficulty, status", sortBy)
+	}
+
+	c, err := loadExercisesCatalog(cfg, track)
+	if err != nil {
+		return err
+	}
+
+	exercises := []catalogExercise{}
+	for _, exercise := range c.Exercises {
+		if filter.matches(exercise) {
+			exercises = append(exercises, exercise)
+		}
+	}
+	sortExercises(exercises, sortBy)
+
+	if len(exercises) == 0 {
+		fmt.Fprintln(Err, "No exercises match.")
+		return nil
+	}
+	printExercises(exercises)
+	return nil
+}
+
+func newExerciseFilter(flags *pflag.FlagSet) (exerciseFilter, error) {
+	typ, err := flags.GetString("type")
+	if err != nil {
+		return exerciseFilter{}, err
+	}
+	status, err := flags.GetString("status")
+	if err != nil {
+		return exerciseFilter{}, err
+	}
+	difficulty, err := flags.GetString("difficulty")
+	if err != nil {
+		return exerciseFilter{}, err
+	}
+	topics, err := flags.GetStringSlice("topic")
+	if err != nil {
+		return exerciseFilter{}, err
+	}
+
+	typ = strings.ToLower(typ)
+	if typ != "" && typ != workspace.ExerciseTypeConcept && typ != workspace.ExerciseTypePractice {
+		return exerciseFilter{}, fmt.Errorf("unknown type '%s', use one of: %s, %s", typ, workspace.ExerciseTypeConcept, workspace.ExerciseTypePractice)
+	}
+
+	status = normalizeStatus(status)
+	if status != "" {
+		known := false
+		for _, s := range exerciseStatuses {
+			if s == status {
+				known = true
+			}
+		}
+		if !known {
+			return exerciseFilter{}, fmt.Errorf("unknown status '%s', use one of: %s", status, strings.Join(exerciseStatuses, ", "))
+		}
+	}
+	return exerciseFilter{typ: typ, status: status, difficulty: difficulty, topics: topics}, nil
+}
+
+// loadExercisesCatalog fetches the catalog for a track, falling back to the
+// cached catalog, and then to the exercises in the workspace, if the API can't be reached.
+func loadExercisesCatalog(cfg config.Config, track string) (*catalog, error) {
+	usrCfg := cfg.UserViperConfig
+	path := catalogPath(cfg.StateDir, track)
+
+	c, err := fetchCatalog(usrCfg.GetString("token"), usrCfg.GetString("apibaseurl"), track)
+	if err == nil {
+		if cfg.StateDir != "" {
+			// A failure to cache the catalog shouldn't prevent listing it.
+			_ = c.save(path)
+		}
+		return c, nil
+	}
+
+	if cfg.StateDir != "" {
+		if cached, cerr := loadCatalog(path); cerr == nil && cached != nil {
+			fmt.Fprintf(Err, "Unable to fetch the catalog (%s).\nShowing the catalog cached at %s.\n\n", err, cached.FetchedAt.Format("2006-01-02 15:04"))
+			return cached, nil
+		}
+	}
+
+	ws, werr := openWorkspace(usrCfg)
+	if werr != nil {
+		return nil, err
+	}
+	local, lerr := localCatalog(ws, track)
+	if lerr != nil || len(local.Exercises) == 0 {
+		return nil, err
+	}
+	fmt.Fprintf(Err, "Unable to fetch the catalog (%s).\nShowing the exercises downloaded to your workspace.\n\n", err)
+	return local, nil
+}
+
+// sortExercises orders the exercises, keeping the track's order for ties.
+func sortExercises(exercises []catalogExercise, sortBy string) {
+	switch sortBy {
+	case "name":
+		sort.SliceStable(exercises, func(i, j int) bool {
+			return exercises[i].Slug < exercises[j].Slug
+		})
+	case "difficulty":
+		sort.SliceStable(exercises, func(i, j int) bool {
+			return rank(difficultyRanks, strings.ToLower(exercises[i].Difficulty)) < rank(difficultyRanks, strings.ToLower(exercises[j].Difficulty))
+		})
+	case "status":
+		sort.SliceStable(exercises, func(i, j int) bool {
+			return rank(statusRanks, normalizeStatus(exercises[i].Status)) < rank(statusRanks, normalizeStatus(exercises[j].Status))
+		})
+	}
+}
+
+// rank looks up the position of a value, placing unknown values last.
+func rank(ranks map[string]int, value string) int {
+	if r, ok := ranks[value]; ok {
+		return r
+	}
+	return len(ranks) + 1
+}
+
+func printExercises(exercises []catalogExercise) {
+	w := tabwriter.NewWriter(Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "EXERCISE\tTYPE\tDIFFICULTY\tSTATUS\tTOPICS\tDESCRIPTION")
+	for _, exercise := range exercises {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			exercise.Slug,
+			orDash(exercise.Type),
+			orDash(exercise.Difficulty),
+			statusLabel(exercise.Status),
+			orDash(strings.Join(exercise.Topics, ", ")),
+			exercise.Blurb,
+		)
+	}
+	w.Flush()
+}
+
+// statusLabel shows the status of an exercise with a glyph to pick it out at a glance.
+func statusLabel(status string) string {
+	if status == "" {
+		return "-"
+	}
+	label := strings.Replace(status, "_", " ", -1)
+	if g, ok := statusGlyphs[status]; ok {
+		return fmt.Sprintf("%s %s", g, label)
+	}
+	return label
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func setupExercisesFlags(flags *pflag.FlagSet) {
+	flags.StringP("track", "t", "", "the track to list the exercises of")
+	flags.String("type", "", "only list exercises of this type (concept, practice)")
+	flags.StringP("status", "s", "", fmt.Sprintf("only list exercises with this status (%s)", strings.Join(exerciseStatuses, ", ")))
+	flags.StringP("difficulty", "d", "", "only list exercises of this difficulty (easy, medium, hard)")
+	flags.StringSlice("topic", []string{}, "only list exercises covering this topic")
+	flags.String("sort", "", "order the exercises by name, difficulty or status")
+}
+
+func init() {
+	RootCmd.AddCommand(exercisesCmd)
+	setupExercisesFlags(exercisesCmd.Flags())
+}
@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/exercism/cli/config"
+	"github.com/exercism/cli/workspace"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+const catalogPayload = `
+{
+	"exercises": [
+		{"slug": "hello-world", "type": "practice", "difficulty": "easy", "topics": ["strings"], "status": "completed"},
+		{"slug": "two-fer", "type": "practice", "difficulty": "easy", "topics": ["strings", "optional-values"], "status": "available"},
+		{"slug": "clock", "type": "practice", "difficulty": "medium", "topics": ["time", "strings"], "status": "in_progress"},
+		{"slug": "strings", "type": "concept", "difficulty": "easy", "topics": ["strings"], "status": "available"},
+		{"slug": "zipper", "type": "practice", "difficulty": "hard", "topics": ["trees"], "status": "locked"},
+		{"slug": "anagram", "type": "practice", "difficulty": "medium", "topics": ["strings"], "status": "available"}
+	]
+}
+`
+
+func fakeCatalogServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tracks/go/exercises", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, catalogPayload)
+	})
+	return httptest.NewServer(mux)
+}
+
+func listedExercises(out string) []string {
+	var slugs []string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n")[1:] {
+		slugs = append(slugs, strings.Fields(line)[0])
+	}
+	return slugs
+}
+
+func TestExercisesRequiresTrack(t *testing.T) {
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", "/home/alice/exercism")
+	v.Set("apibaseurl", "http://example.com")
+	cfg := config.Config{UserViperConfig: v}
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupExercisesFlags(flags)
+
+	err := runExercises(cfg, flags, []string{})
+	if assert.Error(t, err) {
+		assert.Regexp(t, "need a --track", err.Error())
+	}
+}
+
+func TestExercisesFilterAndSort(t *testing.T) {
+	ts := fakeCatalogServer()
+	defer ts.Close()
+
+	testCases := []struct {
+		desc     string
+		flags    map[string]string
+		expected []string
+	}{
+		{
+			desc:     "in track order without filters",
+			flags:    map[string]string{},
+			expected: []string{"hello-world", "two-fer", "clock", "strings", "zipper", "anagram"},
+		},
+		{
+			desc:     "filtered by status",
+			flags:    map[string]string{"status": "available"},
+			expected: []string{"two-fer", "strings", "anagram"},
+		},
+		{
+			desc:     "filtered by a status with dashes",
+			flags:    map[string]string{"status": "in-progress"},
+			expected: []string{"clock"},
+		},
+		{
+			desc:     "filtered by a status the website's way",
+			flags:    map[string]string{"status": "started"},
+			expected: []string{"clock"},
+		},
+		{
+			desc:     "filtered by status, difficulty and topic",
+			flags:    map[string]string{"status": "available", "difficulty": "easy", "topic": "strings"},
+			expected: []string{"two-fer", "strings"},
+		},
+		{
+			desc:     "filtered by type",
+			flags:    map[string]string{"type": "concept"},
+			expected: []string{"strings"},
+		},
+		{
+			desc:     "filtered by type and status",
+			flags:    map[string]string{"type": "Practice", "status": "available"},
+			expected: []string{"two-fer", "anagram"},
+		},
+		{
+			desc:     "filtered by several topics",
+			flags:    map[string]string{"topic": "strings,time"},
+			expected: []string{"clock"},
+		},
+		{
+			desc:     "sorted by difficulty",
+			flags:    map[string]string{"sort": "difficulty"},
+			expected: []string{"hello-world", "two-fer", "strings", "clock", "anagram", "zipper"},
+		},
+		{
+			desc:     "sorted by name",
+			flags:    map[string]string{"status": "available", "sort": "name"},
+			expected: []string{"anagram", "strings", "two-fer"},
+		},
+		{
+			desc:     "sorted by status",
+			flags:    map[string]string{"difficulty": "medium", "sort": "status"},
+			expected: []string{"clock", "anagram"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			co := newCapturedOutput()
+			co.newOut = &bytes.Buffer{}
+			co.override()
+			defer co.reset()
+
+			stateDir, err := ioutil.TempDir("", "exercises-state")
+			assert.NoError(t, err)
+			defer os.RemoveAll(stateDir)
+
+			v := viper.New()
+			v.Set("token", "abc123")
+			v.Set("workspace", "/home/alice/exercism")
+			v.Set("apibaseurl", ts.URL)
+			cfg := config.Config{UserViperConfig: v, StateDir: stateDir}
+
+			flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+			setupExercisesFlags(flags)
+			flags.Set("track", "go")
+			for name, value := range tc.flags {
+				flags.Set(name, value)
+			}
+
+			err = runExercises(cfg, flags, []string{})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, listedExercises(Out.(*bytes.Buffer).String()))
+		})
+	}
+}
+
+func TestExercisesInvalidFlags(t *testing.T) {
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", "/home/alice/exercism")
+	v.Set("apibaseurl", "http://example.com")
+	cfg := config.Config{UserViperConfig: v}
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupExercisesFlags(flags)
+	flags.Set("track", "go")
+	flags.Set("status", "bogus")
+	err := runExercises(cfg, flags, []string{})
+	if assert.Error(t, err) {
+		assert.Regexp(t, "unknown status 'bogus'", err.Error())
+	}
+
+	flags = pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupExercisesFlags(flags)
+	flags.Set("track", "go")
+	flags.Set("type", "bogus")
+	err = runExercises(cfg, flags, []string{})
+	if assert.Error(t, err) {
+		assert.Regexp(t, "unknown type 'bogus'", err.Error())
+	}
+
+	flags = pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupExercisesFlags(flags)
+	flags.Set("track", "go")
+	flags.Set("sort", "bogus")
+	err = runExercises(cfg, flags, []string{})
+	if assert.Error(t, err) {
+		assert.Regexp(t, "cannot sort by 'bogus'", err.Error())
+	}
+}
+
+func TestExercisesOffline(t *testing.T) {
+	co := newCapturedOutput()
+	co.newOut = &bytes.Buffer{}
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	stateDir, err := ioutil.TempDir("", "exercises-state")
+	assert.NoError(t, err)
+	defer os.RemoveAll(stateDir)
+
+	wsDir, err := ioutil.TempDir("", "exercises-workspace")
+	assert.NoError(t, err)
+	defer os.RemoveAll(wsDir)
+
+	metadata := workspace.ExerciseMetadata{
+		Track:        "go",
+		ExerciseSlug: "bob",
+		Difficulty:   "easy",
+		Topics:       []string{"strings"},
+	}
+	dir := filepath.Join(wsDir, "go", "bob")
+	assert.NoError(t, os.MkdirAll(dir, os.FileMode(0755)))
+	assert.NoError(t, metadata.Write(dir))
+
+	ts := fakeCatalogServer()
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", wsDir)
+	v.Set("apibaseurl", ts.URL)
+	cfg := config.Config{UserViperConfig: v, StateDir: stateDir}
+
+	run := func() error {
+		flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+		setupExercisesFlags(flags)
+		flags.Set("track", "go")
+		flags.Set("status", "available")
+		return runExercises(cfg, flags, []string{})
+	}
+
+	// Populate the cache, then go offline.
+	assert.NoError(t, run())
+	ts.Close()
+
+	Out.(*bytes.Buffer).Reset()
+	assert.NoError(t, run())
+	assert.Equal(t, []string{"two-fer", "strings", "anagram"}, listedExercises(Out.(*bytes.Buffer).String()))
+	assert.Regexp(t, "Showing the catalog cached at", Err.(*bytes.Buffer).String())
+
+	// Without a cache, list what has been downloaded.
+	assert.NoError(t, os.RemoveAll(filepath.Join(stateDir, "catalog")))
+	Out.(*bytes.Buffer).Reset()
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupExercisesFlags(flags)
+	flags.Set("track", "go")
+	assert.NoError(t, runExercises(cfg, flags, []string{}))
+	assert.Equal(t, []string{"bob"}, listedExercises(Out.(*bytes.Buffer).String()))
+	assert.Regexp(t, "downloaded to your workspace", Err.(*bytes.Buffer).String())
+}
@@ -0,0 +1,306 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"html"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/exercism/cli/api"
+	"github.com/exercism/cli/config"
+	"github.com/exercism/cli/workspace"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// feedbackCmd shows the analyzer's comments on the latest iteration of an exercise.
+var feedbackCmd = &cobra.Command{
+	Use:   "feedback [PATH]",
+	Short: "Show the analyzer's feedback on your latest iteration.",
+	Long: `Show the comments the track's analyzer made on your latest iteration
+of an exercise.
+
+Pass the path to the exercise, or run the command from within it.
+
+With --annotate, each comment is printed as file:line: message, the way
+compilers report errors, so that editors can jump to the code it is about:
+
+    exercism feedback --annotate > feedback.txt
+    vim -q feedback.txt
+
+Comments that quote your code are put at the first line that contains the
+quote. Other comments are put at the exercise directory.
+`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.NewConfig()
+
+		v := viper.New()
+		v.AddConfigPath(cfg.Dir)
+		v.SetConfigName("user")
+		v.SetConfigType("json")
+		// Ignore error. If the file doesn't exist, that is fine.
+		_ = v.ReadInConfig()
+		config.BindUserEnv(v)
+		cfg.UserViperConfig = v
+
+		return runFeedback(cfg, cmd.Flags(), args)
+	},
+}
+
+func runFeedback(cfg config.Config, flags *pflag.FlagSet, args []string) error {
+	usrCfg := cfg.UserViperConfig
+	if err := validateUserConfig(usrCfg); err != nil {
+		return err
+	}
+	annotate, err := flags.GetBool("annotate")
+	if err != nil {
+		return err
+	}
+
+	dir, metadata, err := exerciseAt(usrCfg, args)
+	if err != nil {
+		return err
+	}
+
+	// The local history knows which files went into the iteration, if it was submitted from here.
+	iterationID := "latest"
+	var files []string
+	if record := latestSubmission(cfg.StateDir, metadata.ID); record != nil {
+		files = record.Files
+		if record.IterationID != "" {
+			iterationID = record.IterationID
+		}
+	}
+	if len(files) == 0 {
+		ignored, err := ignorePatterns(usrCfg, metadata.Track, dir)
+		if err != nil {
+			return err
+		}
+		if files, err = exerciseFiles(dir, ignored); err != nil {
+			return err
+		}
+	}
+
+	client, err := api.NewClient(usrCfg.GetString("token"), usrCfg.GetString("apibaseurl"))
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/solutions/%s/iterations/%s", usrCfg.GetString("apibaseurl"), metadata.ID, iterationID)
+	it, err := fetchIteration(client, url)
+	if err != nil {
+		return err
+	}
+	if !it.isProcessed() {
+		fmt.Fprintf(Err, "Your iteration is still being processed. Try again in a moment, or submit with --wait next time.\n")
+		return nil
+	}
+	if it.AnalyzerFeedback == nil || len(it.AnalyzerFeedback.Comments) == 0 {
+		fmt.Fprintln(Err, "The analyzer has no comments on your iteration.")
+		return nil
+	}
+
+	if annotate {
+		printAnnotations(it.AnalyzerFeedback, dir, files)
+		return nil
+	}
+	printFeedback(it.AnalyzerFeedback)
+	return nil
+}
+
+// exerciseAt finds the exercise that the path given in the args, or the current directory, is in.
+func exerciseAt(usrCfg *viper.Viper, args []string) (string, *workspace.ExerciseMetadata, error) {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", nil, err
+	}
+	ws, err := openWorkspace(usrCfg)
+	if err != nil {
+		return "", nil, err
+	}
+	dir, err := ws.ExerciseDir(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("%s isn't in an exercise downloaded to your workspace", path)
+	}
+	metadata, err := workspace.NewExerciseMetadata(dir)
+	if err != nil {
+		return "", nil, err
+	}
+	return dir, metadata, nil
+}
+
+// latestSubmission is the most recent submission of the solution in the local history, if any.
+func latestSubmission(stateDir, solutionID string) *submissionRecord {
+	if stateDir == "" {
+		return nil
+	}
+	records, err := readSubmissionHistory(stateDir)
+	if err != nil {
+		return nil
+	}
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].SolutionID == solutionID {
+			return &records[i]
+		}
+	}
+	return nil
+}
+
+// exerciseFiles lists the files in the exercise that would be submitted, as paths from the exercise directory.
+// Hidden files and directories, such as the metadata and the docs, are left out, and so are editor backups.
+func exerciseFiles(dir string, ignored workspace.IgnorePatterns) ([]string, error) {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(info.Name(), ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if ignored.Match(rel) == "" && !workspace.IsJunkFile(rel) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+func printFeedback(feedback *analyzerFeedback) {
+	if summary := htmlToText(feedback.Summary); summary != "" {
+		fmt.Fprintf(Out, "%s\n\n", summary)
+	}
+	for _, comment := range feedback.Comments {
+		fmt.Fprintf(Out, "  - %s: %s\n", comment.Type, htmlToText(comment.HTML))
+	}
+}
+
+// printAnnotations prints the comments as file:line: message.
+// The paths are relative to the current directory, so that editors find the files.
+func printAnnotations(feedback *analyzerFeedback, dir string, files []string) {
+	if summary := htmlToText(feedback.Summary); summary != "" {
+		fmt.Fprintf(Err, "%s\n", summary)
+	}
+	contents := make(map[string][]string, len(files))
+	for _, file := range files {
+		lines, err := readLines(filepath.Join(dir, filepath.FromSlash(file)))
+		if err == nil {
+			contents[file] = lines
+		}
+	}
+
+	for _, comment := range feedback.Comments {
+		message := fmt.Sprintf("%s: %s", comment.Type, htmlToText(comment.HTML))
+		file, line := locateComment(comment, files, contents)
+		if file == "" {
+			fmt.Fprintf(Out, "%s: %s\n", relativeToWorkingDir(dir), message)
+			continue
+		}
+		fmt.Fprintf(Out, "%s:%d: %s\n", relativeToWorkingDir(filepath.Join(dir, filepath.FromSlash(file))), line, message)
+	}
+}
+
+var (
+	// codePattern finds the code quoted in a comment.
+	codePattern = regexp.MustCompile(`(?s)<code[^>]*>(.*?)</code>`)
+	// tagPattern finds the tags in a comment, to be removed from the text.
+	tagPattern = regexp.MustCompile(`<[^>]*>`)
+	// blockTagPattern finds the tags that separate text, which become spaces.
+	blockTagPattern = regexp.MustCompile(`(?i)</?(p|br|div|pre|ul|ol|li|h[1-6]|blockquote)\b[^>]*>`)
+)
+
+// minQuoteLength is the length a quote needs to have to be looked for in the files.
+// Shorter quotes, like a single name, are too likely to be found in the wrong place.
+const minQuoteLength = 3
+
+// locateComment finds the file and line of the first bit of code quoted in the comment.
+// It returns a blank file if the comment doesn't quote code that is in the files.
+func locateComment(comment analyzerComment, files []string, contents map[string][]string) (string, int) {
+	for _, match := range codePattern.FindAllStringSubmatch(comment.HTML, -1) {
+		quote := firstLine(html.UnescapeString(tagPattern.ReplaceAllString(match[1], "")))
+		if len(quote) < minQuoteLength {
+			continue
+		}
+		for _, file := range files {
+			for i, line := range contents[file] {
+				if strings.Contains(line, quote) {
+					return file, i + 1
+				}
+			}
+		}
+	}
+	return "", 0
+}
+
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// htmlToText turns a comment into a single line of plain text.
+func htmlToText(s string) string {
+	s = tagPattern.ReplaceAllString(blockTagPattern.ReplaceAllString(s, " "), "")
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
+
+func readLines(path string) ([]string, error) {
+	b, err := ioutil.ReadFile(workspace.LongPath(path))
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(b)+1)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+// relativeToWorkingDir shortens the path to one relative to the current directory, if it is in it.
+func relativeToWorkingDir(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(wd, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
+
+func setupFeedbackFlags(flags *pflag.FlagSet) {
+	flags.Bool("annotate", false, "print the comments as file:line: message")
+}
+
+func init() {
+	RootCmd.AddCommand(feedbackCmd)
+	setupFeedbackFlags(feedbackCmd.Flags())
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/exercism/cli/config"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+const feedbackPayload = `{"iteration": {"status": "actionable_automated_feedback", "tests_status": "passed",
+	"analyzer_feedback": {
+		"summary": "<p>Nice work!</p>",
+		"comments": [
+			{"type": "actionable", "html": "<p>Consider <code>strings.Builder</code> instead of <code>result += word</code>.</p>"},
+			{"type": "informative", "html": "<p>Go has &quot;multiple return values&quot;.</p>"},
+			{"type": "essential", "html": "<p>Don't use <code>panic(err)</code> in a library.</p>"}
+		]
+	}}}`
+
+func TestFeedbackAnnotate(t *testing.T) {
+	var requested string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path
+		fmt.Fprint(w, feedbackPayload)
+	}))
+	defer ts.Close()
+
+	tmpDir, err := ioutil.TempDir("", "feedback")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	stateDir := filepath.Join(tmpDir, "state")
+
+	dir := filepath.Join(tmpDir, "go", "bob")
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), os.FileMode(0755)))
+	writeFakeMetadata(t, dir, "go", "bob")
+	solution := "package bob\n\nfunc Hey(words []string) string {\n\tresult := \"\"\n\tfor _, word := range words {\n\t\tresult += word\n\t}\n\treturn result\n}\n"
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "bob.go"), []byte(solution), os.FileMode(0644)))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "sub", "helper.go"), []byte("package sub\n\nfunc must(err error) {\n\tpanic(err)\n}\n"), os.FileMode(0644)))
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	cfg := config.Config{UserViperConfig: v, StateDir: stateDir}
+
+	run := func(args ...string) string {
+		co := newCapturedOutput()
+		co.newOut = &bytes.Buffer{}
+		co.newErr = &bytes.Buffer{}
+		co.override()
+		defer co.reset()
+
+		flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+		setupFeedbackFlags(flags)
+		flags.Set("annotate", "true")
+		assert.NoError(t, runFeedback(cfg, flags, args))
+		return co.newOut.(*bytes.Buffer).String()
+	}
+
+	// Without a local history, the latest iteration is asked for and all the files are searched.
+	out := run(filepath.Join(dir, "sub"))
+	assert.Equal(t, "/solutions/bogus-solution-uuid/iterations/latest", requested)
+	expected := fmt.Sprintf("%s:6: actionable: Consider strings.Builder instead of result += word.\n", filepath.Join(dir, "bob.go")) +
+		fmt.Sprintf("%s: informative: Go has \"multiple return values\".\n", dir) +
+		fmt.Sprintf("%s:4: essential: Don't use panic(err) in a library.\n", filepath.Join(dir, "sub", "helper.go"))
+	assert.Equal(t, expected, out)
+
+	// The local history says which iteration and files to look at.
+	assert.NoError(t, appendSubmissionRecord(stateDir, submissionRecord{
+		SubmittedAt: time.Now(),
+		SolutionID:  "bogus-solution-uuid",
+		IterationID: "42",
+		Files:       []string{"bob.go"},
+	}))
+	out = run(dir)
+	assert.Equal(t, "/solutions/bogus-solution-uuid/iterations/42", requested)
+	assert.Contains(t, out, fmt.Sprintf("%s: essential: Don't use panic(err) in a library.\n", dir))
+}
+
+func TestFeedbackOutsideExercise(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "feedback-outside")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", "http://example.com")
+	cfg := config.Config{UserViperConfig: v}
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupFeedbackFlags(flags)
+	err = runFeedback(cfg, flags, []string{tmpDir})
+	if assert.Error(t, err) {
+		assert.Regexp(t, "isn't in an exercise", err.Error())
+	}
+}
+
+func TestHTMLToText(t *testing.T) {
+	assert.Equal(t, "Use a for loop. It's faster.",
+		htmlToText("<p>Use a <code>for</code> loop.</p>\n<p>It&#39;s <em>faster</em>.</p>"))
+}
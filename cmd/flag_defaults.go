@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// flagDefaultsKey is the section of the user config that holds each command's preferred flags, e.g.
+//
+//	"commands": {"download": {"force": true}, "goal": {"status": {"nudge": true}}}
+const flagDefaultsKey = "commands"
+
+// exclusiveFlags are the flags of a command that can't be given together, by the command's key in the config.
+// A default for one of them is only applied if none of the others is given.
+var exclusiveFlags = map[string][][]string{
+	"download": {{"force", resolveTheirs, resolveOurs, resolveInteractive, resolveUpdate, "on-conflict"}},
+	"submit":   {{"manifest", "stdin"}},
+}
+
+// applyFlagDefaults makes the flags configured for the command its defaults.
+// They aren't marked as given, and flags that are given in the arguments win over the config.
+func applyFlagDefaults(root *cobra.Command, v *viper.Viper, args []string) {
+	if v == nil {
+		return
+	}
+	cmd, _, err := root.Find(args)
+	if err != nil || cmd == nil || cmd == root {
+		return
+	}
+
+	var names []string
+	for c := cmd; c != root && c != nil; c = c.Parent() {
+		names = append([]string{c.Name()}, names...)
+	}
+	key := strings.Join(append([]string{flagDefaultsKey}, names...), ".")
+	defaults := v.GetStringMap(key)
+	if len(defaults) == 0 {
+		return
+	}
+
+	// The arguments after a -- aren't flags.
+	end := len(args)
+	for i, arg := range args {
+		if arg == "--" {
+			end = i
+			break
+		}
+	}
+
+	keys := make([]string, 0, len(defaults))
+	for name := range defaults {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+
+	lookup := func(name string) *pflag.Flag {
+		if flag := cmd.Flags().Lookup(name); flag != nil {
+			return flag
+		}
+		return cmd.InheritedFlags().Lookup(name)
+	}
+	for _, name := range keys {
+		flag := lookup(name)
+		if flag == nil {
+			fmt.Fprintf(Err, "Warning: ignoring %s.%s in your config, '%s' has no --%s flag.\n", key, name, strings.Join(names, " "), name)
+			continue
+		}
+		if flagGiven(flag, args[:end]) || exclusiveFlagGiven(strings.Join(names, "."), name, lookup, args[:end]) {
+			continue
+		}
+		value := flagDefaultValue(defaults[name])
+		if err := flag.Value.Set(value); err != nil {
+			fmt.Fprintf(Err, "Warning: ignoring %s.%s in your config: %s\n", key, name, err)
+			continue
+		}
+		flag.DefValue = value
+	}
+}
+
+// exclusiveFlagGiven tells whether a flag that can't be given together with the named one appears in the arguments.
+func exclusiveFlagGiven(command, name string, lookup func(string) *pflag.Flag, args []string) bool {
+	for _, group := range exclusiveFlags[command] {
+		var inGroup bool
+		for _, other := range group {
+			if other == name {
+				inGroup = true
+			}
+		}
+		if !inGroup {
+			continue
+		}
+		for _, other := range group {
+			if flag := lookup(other); other != name && flag != nil && flagGiven(flag, args) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// flagGiven tells whether the flag appears in the arguments, by its name or its shorthand.
+func flagGiven(flag *pflag.Flag, args []string) bool {
+	for _, arg := range args {
+		if arg == "--"+flag.Name || strings.HasPrefix(arg, "--"+flag.Name+"=") {
+			return true
+		}
+		if flag.Shorthand != "" && !strings.HasPrefix(arg, "--") && strings.HasPrefix(arg, "-"+flag.Shorthand) {
+			return true
+		}
+	}
+	return false
+}
+
+// flagDefaultValue formats a value from the config the way it would be typed.
+// Lists become comma-separated values.
+func flagDefaultValue(value interface{}) string {
+	if values, ok := value.([]interface{}); ok {
+		parts := make([]string, len(values))
+		for i, v := range values {
+			parts[i] = fmt.Sprint(v)
+		}
+		return strings.Join(parts, ",")
+	}
+	return fmt.Sprint(value)
+}
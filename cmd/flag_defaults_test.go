@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyFlagDefaults(t *testing.T) {
+	co := newCapturedOutput()
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	newRoot := func() (*cobra.Command, *cobra.Command, *cobra.Command) {
+		root := &cobra.Command{Use: "exercism"}
+		root.PersistentFlags().Bool("verbose", false, "")
+		download := &cobra.Command{Use: "download", Run: func(*cobra.Command, []string) {}}
+		setupDownloadFlags(download.Flags())
+		goal := &cobra.Command{Use: "goal"}
+		status := &cobra.Command{Use: "status", Run: func(*cobra.Command, []string) {}}
+		setupGoalStatusFlags(status.Flags())
+		goal.AddCommand(status)
+		root.AddCommand(download, goal)
+		return root, download, status
+	}
+
+	v := viper.New()
+	v.Set("commands", map[string]interface{}{
+		"download": map[string]interface{}{
+			"force":    true,
+			"exercise": []interface{}{"leap", "bob"},
+			"verbose":  true,
+			"bogus":    1,
+		},
+		"goal": map[string]interface{}{
+			"status": map[string]interface{}{"nudge": true},
+		},
+	})
+
+	// The configured flags become defaults, rather than flags that were given.
+	root, download, _ := newRoot()
+	args := []string{"download", "--track=go"}
+	applyFlagDefaults(root, v, args)
+	assert.NoError(t, download.ParseFlags(args[1:]))
+	exercises, _ := download.Flags().GetStringSlice("exercise")
+	assert.Equal(t, []string{"leap", "bob"}, exercises)
+	force, _ := download.Flags().GetBool("force")
+	assert.True(t, force)
+	assert.False(t, download.Flags().Changed("force"))
+	verbose, _ := root.PersistentFlags().GetBool("verbose")
+	assert.True(t, verbose)
+	assert.Regexp(t, "ignoring commands.download.bogus", Err.(*bytes.Buffer).String())
+
+	// Flags on the command line win, whether given by name or shorthand.
+	root, download, _ = newRoot()
+	args = []string{"download", "-e", "clock", "--force=false", "--", "--verbose"}
+	applyFlagDefaults(root, v, args)
+	assert.NoError(t, download.ParseFlags(args[1:]))
+	exercises, _ = download.Flags().GetStringSlice("exercise")
+	assert.Equal(t, []string{"clock"}, exercises)
+	force, _ = download.Flags().GetBool("force")
+	assert.False(t, force)
+
+	// A default isn't applied when a flag it can't be combined with is given.
+	root, download, _ = newRoot()
+	args = []string{"download", "--ours"}
+	applyFlagDefaults(root, v, args)
+	assert.NoError(t, download.ParseFlags(args[1:]))
+	resolution, _, err := conflictResolution(download.Flags())
+	assert.NoError(t, err)
+	assert.Equal(t, resolveOurs, resolution)
+
+	root, _, status := newRoot()
+	applyFlagDefaults(root, v, []string{"goal", "status"})
+	nudge, _ := status.Flags().GetBool("nudge")
+	assert.True(t, nudge)
+
+	// Commands without defaults are left alone.
+	root, download, _ = newRoot()
+	applyFlagDefaults(root, viper.New(), []string{"download", "--track=go"})
+	force, _ = download.Flags().GetBool("force")
+	assert.False(t, force)
+}
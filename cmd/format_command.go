@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+
+	"github.com/spf13/viper"
+)
+
+// trackFormatters are the commands that format solution files in place in each track.
+// The paths of the files are added to the end of the command.
+var trackFormatters = map[string][]string{
+	"c":          {"clang-format", "-i"},
+	"cpp":        {"clang-format", "-i"},
+	"crystal":    {"crystal", "tool", "format"},
+	"dart":       {"dart", "format"},
+	"elixir":     {"mix", "format"},
+	"elm":        {"elm-format", "--yes"},
+	"gleam":      {"gleam", "format"},
+	"go":         {"gofmt", "-w"},
+	"haskell":    {"ormolu", "--mode", "inplace"},
+	"javascript": {"prettier", "--write"},
+	"kotlin":     {"ktlint", "-F"},
+	"python":     {"black", "--quiet"},
+	"ruby":       {"rubocop", "--autocorrect", "--format", "quiet"},
+	"rust":       {"rustfmt"},
+	"swift":      {"swift-format", "--in-place"},
+	"typescript": {"prettier", "--write"},
+	"zig":        {"zig", "fmt"},
+}
+
+// formatterCommand returns the command that formats solution files in the track.
+// It is taken from format.<track> in the user config, or else from the built-in formatters.
+func formatterCommand(usrCfg *viper.Viper, track string) ([]string, error) {
+	key := fmt.Sprintf("format.%s", track)
+	if usrCfg != nil && usrCfg.IsSet(key) {
+		if args := usrCfg.GetStringSlice(key); len(args) > 0 {
+			return args, nil
+		}
+	}
+	args, ok := trackFormatters[track]
+	if !ok {
+		return nil, fmt.Errorf("there's no known formatter for the %s track; set one with format.%s in your user config", track, track)
+	}
+	return args, nil
+}
+
+// runFormatter formats the files in place, from the exercise directory, writing what the formatter prints to w.
+func runFormatter(dir string, args, files []string, w io.Writer) error {
+	cmd := exec.Command(args[0], append(append([]string{}, args[1:]...), files...)...)
+	cmd.Dir = dir
+	cmd.Stdout = w
+	cmd.Stderr = w
+	return cmd.Run()
+}
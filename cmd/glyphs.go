@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+)
+
+// plainASCII restricts the output to plain ASCII, for terminals and locales
+// that can't show other characters. It is set by the --ascii flag or the ascii setting
+// in the user config, and assumed when the locale isn't UTF-8.
+var plainASCII bool
+
+// glyph is a symbol used in the output, along with what to show instead in plain ASCII.
+type glyph struct {
+	unicode, ascii string
+}
+
+func (g glyph) String() string {
+	if plainASCII {
+		return g.ascii
+	}
+	return g.unicode
+}
+
+// The glyphs used in the output. Output should never contain other non-ASCII characters.
+var (
+	glyphCompleted  = glyph{"✓", "+"}
+	glyphInProgress = glyph{"◐", "~"}
+	glyphAvailable  = glyph{"○", "o"}
+	glyphLocked     = glyph{"⊘", "x"}
+	glyphFailed     = glyph{"✗", "!"}
+	glyphBarFull    = glyph{"█", "#"}
+	glyphBarEmpty   = glyph{"░", "-"}
+
+	glyphTreeBranch   = glyph{"├── ", "|-- "}
+	glyphTreeLast     = glyph{"└── ", "`-- "}
+	glyphTreeVertical = glyph{"│   ", "|   "}
+	glyphTreeSpace    = glyph{"    ", "    "}
+)
+
+// statusGlyphs mark the status of an exercise.
+var statusGlyphs = map[string]glyph{
+	statusLocked:     glyphLocked,
+	statusAvailable:  glyphAvailable,
+	statusInProgress: glyphInProgress,
+	statusCompleted:  glyphCompleted,
+	statusPublished:  glyphCompleted,
+	statusDownloaded: glyphInProgress,
+}
+
+// localeIsUTF8 tells whether the locale in the environment allows for characters other than ASCII.
+// If no locale is set, it is assumed that it does.
+func localeIsUTF8() bool {
+	for _, key := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := os.Getenv(key); value != "" {
+			value = strings.ToLower(value)
+			return strings.Contains(value, "utf-8") || strings.Contains(value, "utf8")
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlyphs(t *testing.T) {
+	defer func() { plainASCII = false }()
+
+	plainASCII = false
+	assert.Equal(t, "✓ completed", statusLabel(statusCompleted))
+	assert.Equal(t, "◐ in progress", statusLabel(statusInProgress))
+
+	plainASCII = true
+	assert.Equal(t, "+ completed", statusLabel(statusCompleted))
+	assert.Equal(t, "~ in progress", statusLabel(statusInProgress))
+	assert.Equal(t, "-", statusLabel(""))
+
+	co := newCapturedOutput()
+	co.newOut = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+	printExercises([]catalogExercise{
+		{Slug: "hello-world", Status: statusCompleted},
+		{Slug: "clock", Status: statusInProgress, Topics: []string{"time"}},
+		{Slug: "zipper", Status: statusLocked},
+	})
+	for _, r := range Out.(*bytes.Buffer).String() {
+		if r > 127 {
+			t.Fatalf("expected plain ASCII, got %q", r)
+		}
+	}
+}
+
+func TestLocaleIsUTF8(t *testing.T) {
+	keys := []string{"LC_ALL", "LC_CTYPE", "LANG"}
+	original := map[string]string{}
+	for _, key := range keys {
+		original[key] = os.Getenv(key)
+		os.Unsetenv(key)
+	}
+	defer func() {
+		for key, value := range original {
+			os.Setenv(key, value)
+		}
+	}()
+
+	assert.True(t, localeIsUTF8())
+
+	os.Setenv("LANG", "en_US.UTF-8")
+	assert.True(t, localeIsUTF8())
+
+	os.Setenv("LC_ALL", "C")
+	assert.False(t, localeIsUTF8())
+
+	os.Setenv("LC_ALL", "de_DE.utf8")
+	assert.True(t, localeIsUTF8())
+}
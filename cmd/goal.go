@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/exercism/cli/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Periods that a practice goal can be set for.
+const (
+	periodDay   = "day"
+	periodWeek  = "week"
+	periodMonth = "month"
+)
+
+// goalCmd manages a practice goal.
+var goalCmd = &cobra.Command{
+	Use:   "goal",
+	Short: "Set a practice goal and track your progress.",
+	Long: `Set a goal for how often you want to submit solutions, and see how you're doing.
+
+    exercism goal set 3/week
+    exercism goal status
+
+Goals can be set per day, week or month. Weeks start on Monday.
+Progress is counted from the submissions made with this CLI on this computer.
+
+To be nudged when you're falling behind, add this to your shell startup file:
+
+    exercism goal status --nudge
+`,
+}
+
+var goalSetCmd = &cobra.Command{
+	Use:   "set COUNT/PERIOD",
+	Short: "Set a practice goal, e.g. 3/week.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runGoalSet(config.NewConfig(), args)
+	},
+}
+
+var goalStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the progress towards your practice goal.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runGoalStatus(config.NewConfig(), cmd.Flags())
+	},
+}
+
+var goalClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove your practice goal.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runGoalClear(config.NewConfig())
+	},
+}
+
+// goal is a number of submissions to make in each period.
+type goal struct {
+	Count  int       `json:"count"`
+	Period string    `json:"period"`
+	SetAt  time.Time `json:"set_at"`
+}
+
+// parseGoal reads a goal written as COUNT/PERIOD, e.g. 3/week.
+func parseGoal(s string) (goal, error) {
+	parts := strings.Split(strings.TrimSpace(s), "/")
+	if len(parts) != 2 {
+		return goal{}, fmt.Errorf("cannot understand the goal '%s', write it as COUNT/PERIOD, e.g. 3/week", s)
+	}
+	count, err := strconv.Atoi(parts[0])
+	if err != nil || count < 1 {
+		return goal{}, fmt.Errorf("the number of submissions in '%s' must be a positive number", s)
+	}
+	period := strings.ToLower(parts[1])
+	switch period {
+	case periodDay, periodWeek, periodMonth:
+	default:
+		return goal{}, fmt.Errorf("the period in '%s' must be one of: day, week, month", s)
+	}
+	return goal{Count: count, Period: period}, nil
+}
+
+// current describes the period that is under way, e.g. "this week".
+func (g goal) current() string {
+	if g.Period == periodDay {
+		return "today"
+	}
+	return "this " + g.Period
+}
+
+func (g goal) String() string {
+	return fmt.Sprintf("%d/%s", g.Count, g.Period)
+}
+
+// bounds returns the start and end of the period that includes the given time.
+func (g goal) bounds(now time.Time) (time.Time, time.Time) {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch g.Period {
+	case periodDay:
+		return day, day.AddDate(0, 0, 1)
+	case periodMonth:
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(0, 1, 0)
+	}
+	// Weeks start on Monday.
+	start := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	return start, start.AddDate(0, 0, 7)
+}
+
+// goalProgress is how far along a goal is in the current period.
+type goalProgress struct {
+	Goal  goal
+	Done  int
+	Start time.Time
+	End   time.Time
+	// Expected is how many submissions should have been made by now,
+	// if they were spread evenly over the period.
+	Expected int
+}
+
+func newGoalProgress(g goal, records []submissionRecord, now time.Time) goalProgress {
+	start, end := g.bounds(now)
+	progress := goalProgress{Goal: g, Start: start, End: end}
+	for _, record := range records {
+		if !record.SubmittedAt.Before(start) && record.SubmittedAt.Before(end) {
+			progress.Done++
+		}
+	}
+	elapsed := float64(now.Sub(start)) / float64(end.Sub(start))
+	progress.Expected = int(elapsed * float64(g.Count))
+	return progress
+}
+
+// IsMet tells whether the goal has been reached for the period.
+func (p goalProgress) IsMet() bool {
+	return p.Done >= p.Goal.Count
+}
+
+// IsSlipping tells whether the submissions are falling behind the pace needed to meet the goal.
+func (p goalProgress) IsSlipping() bool {
+	return !p.IsMet() && p.Done < p.Expected
+}
+
+func (p goalProgress) String() string {
+	const width = 20
+	filled := width
+	if !p.IsMet() {
+		filled = width * p.Done / p.Goal.Count
+	}
+	bar := strings.Repeat(glyphBarFull.String(), filled) + strings.Repeat(glyphBarEmpty.String(), width-filled)
+
+	status := "on track"
+	if p.IsMet() {
+		status = "goal met"
+	} else if p.IsSlipping() {
+		status = "falling behind"
+	}
+	return fmt.Sprintf("[%s] %d of %d submissions %s (%s)", bar, p.Done, p.Goal.Count, p.Goal.current(), status)
+}
+
+// goalPath is where the practice goal is kept.
+func goalPath(stateDir string) string {
+	return filepath.Join(stateDir, "goal.json")
+}
+
+// loadGoal reads the practice goal. It returns nil if no goal has been set.
+func loadGoal(stateDir string) (*goal, error) {
+	b, err := ioutil.ReadFile(goalPath(stateDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var g goal
+	if err := json.Unmarshal(b, &g); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func (g goal) save(stateDir string) error {
+	b, err := json.Marshal(g)
+	if err != nil {
+		return err
+	}
+	path := goalPath(stateDir)
+	if err := os.MkdirAll(filepath.Dir(path), os.FileMode(0755)); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, os.FileMode(0644))
+}
+
+func runGoalSet(cfg config.Config, args []string) error {
+	g, err := parseGoal(args[0])
+	if err != nil {
+		return err
+	}
+	g.SetAt = time.Now()
+	if err := g.save(cfg.StateDir); err != nil {
+		return err
+	}
+	fmt.Fprintf(Err, "\nYour goal is %d submissions per %s.\nCheck your progress with '%s goal status'.\n\n", g.Count, g.Period, BinaryName)
+	return nil
+}
+
+func runGoalStatus(cfg config.Config, flags *pflag.FlagSet) error {
+	nudge, err := flags.GetBool("nudge")
+	if err != nil {
+		return err
+	}
+
+	g, err := loadGoal(cfg.StateDir)
+	if err != nil {
+		return err
+	}
+	if g == nil {
+		if nudge {
+			return nil
+		}
+		return fmt.Errorf("you haven't set a goal yet, try '%s goal set 3/week'", BinaryName)
+	}
+
+	records, err := readSubmissionHistory(cfg.StateDir)
+	if err != nil {
+		return err
+	}
+	progress := newGoalProgress(*g, records, time.Now())
+
+	if nudge {
+		if progress.IsSlipping() {
+			fmt.Fprintf(Err, "Exercism: %d of %d submissions %s. Time to practice!\n", progress.Done, progress.Goal.Count, progress.Goal.current())
+		}
+		return nil
+	}
+
+	fmt.Fprintf(Out, "%s\n", progress)
+	if !progress.IsMet() {
+		fmt.Fprintf(Out, "%d to go before %s.\n", g.Count-progress.Done, progress.End.Format("Mon Jan 2"))
+	}
+	return nil
+}
+
+func runGoalClear(cfg config.Config) error {
+	err := os.Remove(goalPath(cfg.StateDir))
+	if os.IsNotExist(err) {
+		return errors.New("you haven't set a goal")
+	}
+	return err
+}
+
+func setupGoalStatusFlags(flags *pflag.FlagSet) {
+	flags.Bool("nudge", false, "only print a reminder, and only when falling behind")
+}
+
+func init() {
+	RootCmd.AddCommand(goalCmd)
+	goalCmd.AddCommand(goalSetCmd, goalStatusCmd, goalClearCmd)
+	setupGoalStatusFlags(goalStatusCmd.Flags())
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/exercism/cli/config"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGoal(t *testing.T) {
+	g, err := parseGoal("3/week")
+	assert.NoError(t, err)
+	assert.Equal(t, goal{Count: 3, Period: periodWeek}, g)
+
+	g, err = parseGoal(" 1/Day ")
+	assert.NoError(t, err)
+	assert.Equal(t, goal{Count: 1, Period: periodDay}, g)
+
+	for _, s := range []string{"3", "three/week", "0/week", "3/fortnight", "3/week/day"} {
+		_, err := parseGoal(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestGoalBounds(t *testing.T) {
+	// A Thursday.
+	now := time.Date(2026, time.October, 15, 18, 30, 0, 0, time.UTC)
+
+	start, end := goal{Period: periodWeek}.bounds(now)
+	assert.Equal(t, time.Date(2026, time.October, 12, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC), end)
+
+	start, end = goal{Period: periodDay}.bounds(now)
+	assert.Equal(t, time.Date(2026, time.October, 15, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC), end)
+
+	start, end = goal{Period: periodMonth}.bounds(now)
+	assert.Equal(t, time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC), end)
+
+	// Sunday is the last day of the week.
+	start, _ = goal{Period: periodWeek}.bounds(time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2026, time.October, 12, 0, 0, 0, 0, time.UTC), start)
+}
+
+func TestGoalProgress(t *testing.T) {
+	// Thursday evening, more than half way through the week.
+	now := time.Date(2026, time.October, 15, 18, 30, 0, 0, time.UTC)
+	g := goal{Count: 3, Period: periodWeek}
+
+	records := []submissionRecord{
+		{SubmittedAt: time.Date(2026, time.October, 11, 23, 0, 0, 0, time.UTC)},
+		{SubmittedAt: time.Date(2026, time.October, 13, 9, 0, 0, 0, time.UTC)},
+	}
+	progress := newGoalProgress(g, records, now)
+	assert.Equal(t, 1, progress.Done)
+	assert.Equal(t, 1, progress.Expected)
+	assert.False(t, progress.IsSlipping())
+	assert.Regexp(t, `1 of 3 submissions this week \(on track\)`, progress.String())
+
+	progress = newGoalProgress(g, records[:1], now)
+	assert.True(t, progress.IsSlipping())
+	assert.Regexp(t, "falling behind", progress.String())
+
+	records = append(records,
+		submissionRecord{SubmittedAt: time.Date(2026, time.October, 14, 9, 0, 0, 0, time.UTC)},
+		submissionRecord{SubmittedAt: time.Date(2026, time.October, 15, 9, 0, 0, 0, time.UTC)},
+	)
+	progress = newGoalProgress(g, records, now)
+	assert.True(t, progress.IsMet())
+	assert.Regexp(t, `\[█{20}\] 3 of 3 .*goal met`, progress.String())
+
+	plainASCII = true
+	defer func() { plainASCII = false }()
+	assert.Regexp(t, `\[#{20}\] 3 of 3 .*goal met`, progress.String())
+}
+
+func TestGoalCommands(t *testing.T) {
+	co := newCapturedOutput()
+	co.newOut = &bytes.Buffer{}
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	stateDir, err := ioutil.TempDir("", "goal")
+	assert.NoError(t, err)
+	defer os.RemoveAll(stateDir)
+	cfg := config.Config{StateDir: stateDir}
+
+	statusFlags := func(nudge bool) *pflag.FlagSet {
+		flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+		setupGoalStatusFlags(flags)
+		if nudge {
+			flags.Set("nudge", "true")
+		}
+		return flags
+	}
+
+	err = runGoalStatus(cfg, statusFlags(false))
+	if assert.Error(t, err) {
+		assert.Regexp(t, "haven't set a goal", err.Error())
+	}
+	assert.NoError(t, runGoalStatus(cfg, statusFlags(true)))
+
+	assert.Error(t, runGoalSet(cfg, []string{"lots"}))
+	assert.NoError(t, runGoalSet(cfg, []string{"2/day"}))
+
+	g, err := loadGoal(stateDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "2/day", g.String())
+
+	assert.NoError(t, appendSubmissionRecord(stateDir, submissionRecord{SubmittedAt: time.Now()}))
+	assert.NoError(t, runGoalStatus(cfg, statusFlags(false)))
+	assert.Regexp(t, "1 of 2 submissions today", Out.(*bytes.Buffer).String())
+
+	assert.NoError(t, runGoalClear(cfg))
+	assert.Error(t, runGoalClear(cfg))
+}
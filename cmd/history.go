@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/exercism/cli/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// historyCmd shows the local log of submissions.
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the solutions you've submitted.",
+	Long: `Show the solutions submitted with this CLI on this computer, newest first.
+
+Every submission is recorded in a log in the CLI's state directory, with the
+files that were submitted, a hash of their contents and the iteration it created.
+
+    exercism history --exercise=clock
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runHistory(config.NewConfig(), cmd.Flags())
+	},
+}
+
+func runHistory(cfg config.Config, flags *pflag.FlagSet) error {
+	exercise, err := flags.GetString("exercise")
+	if err != nil {
+		return err
+	}
+	track, err := flags.GetString("track")
+	if err != nil {
+		return err
+	}
+	limit, err := flags.GetInt("limit")
+	if err != nil {
+		return err
+	}
+	asJSON, err := flags.GetBool("json")
+	if err != nil {
+		return err
+	}
+
+	records, err := readSubmissionHistory(cfg.StateDir)
+	if err != nil {
+		return err
+	}
+
+	matches := []submissionRecord{}
+	for i := len(records) - 1; i >= 0; i-- {
+		record := records[i]
+		if exercise != "" && record.Exercise != exercise {
+			continue
+		}
+		if track != "" && record.Track != track {
+			continue
+		}
+		matches = append(matches, record)
+		if limit > 0 && len(matches) == limit {
+			break
+		}
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(Out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(matches)
+	}
+
+	if len(matches) == 0 {
+		fmt.Fprintln(Err, "No submissions found.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SUBMITTED\tEXERCISE\tITERATION\tHASH\tFILES")
+	for _, record := range matches {
+		fmt.Fprintf(w, "%s\t%s/%s\t%s\t%s\t%s\n",
+			record.SubmittedAt.Local().Format("2006-01-02 15:04"),
+			record.Track,
+			record.Exercise,
+			orDash(record.IterationID),
+			shortHash(record.PayloadHash),
+			strings.Join(record.Files, ", "),
+		)
+	}
+	return w.Flush()
+}
+
+// shortHash abbreviates a payload hash for display.
+func shortHash(hash string) string {
+	hash = strings.TrimPrefix(hash, "sha256:")
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return orDash(hash)
+}
+
+func setupHistoryFlags(flags *pflag.FlagSet) {
+	flags.StringP("exercise", "e", "", "only show submissions of this exercise")
+	flags.StringP("track", "t", "", "only show submissions to this track")
+	flags.IntP("limit", "n", 0, "show at most this many submissions")
+	flags.Bool("json", false, "print the submissions as JSON")
+}
+
+func init() {
+	RootCmd.AddCommand(historyCmd)
+	setupHistoryFlags(historyCmd.Flags())
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/exercism/cli/api"
+)
+
+// iterationPollInterval is how long to wait between asking whether an iteration has been processed.
+var iterationPollInterval = 2 * time.Second
+
+// defaultWaitTimeout is how long submit --wait waits for an iteration to be processed.
+const defaultWaitTimeout = 3 * time.Minute
+
+// iteration is the processing status of a submitted iteration, as reported by the API.
+type iteration struct {
+	// Status is e.g. testing or analyzing while the iteration is being processed,
+	// and tells what kind of feedback there is once it is done.
+	Status string `json:"status"`
+	// TestsStatus is e.g. queued, passed, failed or errored.
+	TestsStatus string `json:"tests_status"`
+
+	NumEssentialAutomatedComments     int `json:"num_essential_automated_comments"`
+	NumActionableAutomatedComments    int `json:"num_actionable_automated_comments"`
+	NumNonActionableAutomatedComments int `json:"num_non_actionable_automated_comments"`
+
+	RepresenterFeedback json.RawMessage   `json:"representer_feedback,omitempty"`
+	AnalyzerFeedback    *analyzerFeedback `json:"analyzer_feedback,omitempty"`
+}
+
+// analyzerFeedback is what the track's analyzer had to say about an iteration.
+type analyzerFeedback struct {
+	Summary  string            `json:"summary,omitempty"`
+	Comments []analyzerComment `json:"comments"`
+}
+
+// analyzerComment is a comment from the analyzer, rendered as HTML.
+type analyzerComment struct {
+	// Type is essential, actionable, informative or celebratory.
+	Type string `json:"type"`
+	HTML string `json:"html"`
+}
+
+// iterationProcessingStatuses are the statuses of an iteration that isn't done being processed.
+var iterationProcessingStatuses = map[string]bool{
+	"":          true,
+	"untested":  true,
+	"testing":   true,
+	"analyzing": true,
+}
+
+func (it *iteration) isProcessed() bool {
+	return !iterationProcessingStatuses[it.Status]
+}
+
+func (it *iteration) analyzerComments() int {
+	return it.NumEssentialAutomatedComments + it.NumActionableAutomatedComments + it.NumNonActionableAutomatedComments
+}
+
+func (it *iteration) hasRepresenterFeedback() bool {
+	feedback := strings.TrimSpace(string(it.RepresenterFeedback))
+	return feedback != "" && feedback != "null"
+}
+
+// fetchIteration requests the processing status of an iteration from the API.
+func fetchIteration(client *api.Client, url string) (*iteration, error) {
+	req, err := client.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, decodedAPIError(res)
+	}
+
+	var payload struct {
+		Iteration iteration `json:"iteration"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("unable to parse API response - %s", err)
+	}
+	return &payload.Iteration, nil
+}
+
+// waitForIteration polls the iteration until it has been processed or the time is up.
+// The last status seen is returned either way.
+func waitForIteration(client *api.Client, url string, timeout time.Duration) (*iteration, error) {
+	deadline := time.Now().Add(timeout)
+	spin := newSpinner(Err)
+	defer spin.stop()
+
+	for {
+		it, err := fetchIteration(client, url)
+		if err != nil {
+			return nil, err
+		}
+		if it.isProcessed() || !time.Now().Before(deadline) {
+			return it, nil
+		}
+		wait := iterationPollInterval
+		if left := time.Until(deadline); left < wait {
+			wait = left
+		}
+		spin.wait(wait, fmt.Sprintf("Waiting for your iteration to be processed (%s)", describeIterationStatus(it.Status)))
+	}
+}
+
+func describeIterationStatus(status string) string {
+	if status == "" || status == "untested" {
+		return "queued"
+	}
+	return strings.Replace(status, "_", " ", -1)
+}
+
+// printIterationOutcome shows what came of processing the iteration.
+func printIterationOutcome(it *iteration, timeout time.Duration) {
+	if !it.isProcessed() {
+		fmt.Fprintf(Err, "\n    Your iteration is still being processed after %s.\n    The results will be on the website.\n\n", timeout)
+		return
+	}
+
+	tests := "Tests: " + describeIterationStatus(it.TestsStatus)
+	switch it.TestsStatus {
+	case "passed":
+		tests = fmt.Sprintf("%s Tests passed", glyphCompleted)
+	case "failed", "errored", "exceptioned":
+		tests = fmt.Sprintf("%s Tests %s", glyphFailed, it.TestsStatus)
+	case "", "not_queued":
+		tests = "Tests: not run"
+	}
+	fmt.Fprintf(Out, "    %s\n", tests)
+
+	switch n := it.analyzerComments(); n {
+	case 0:
+		fmt.Fprintln(Out, "    No comments from the analyzer")
+	case 1:
+		fmt.Fprintln(Out, "    1 comment from the analyzer")
+	default:
+		fmt.Fprintf(Out, "    %d comments from the analyzer\n", n)
+	}
+	if it.hasRepresenterFeedback() {
+		fmt.Fprintln(Out, "    Feedback from a mentor on solutions like yours")
+	}
+	fmt.Fprintln(Out)
+}
+
+// spinnerInterval is how often the spinner moves.
+const spinnerInterval = 100 * time.Millisecond
+
+// spinnerFrames are the frames the spinner goes through.
+var spinnerFrames = []glyph{{"◐", "|"}, {"◓", "/"}, {"◑", "-"}, {"◒", `\`}}
+
+// spinner shows that something is going on while waiting.
+// At a terminal it spins on a line of its own; elsewhere, such as in a log,
+// the message is written once each time it changes.
+type spinner struct {
+	w        io.Writer
+	terminal bool
+	frame    int
+	message  string
+}
+
+func newSpinner(w io.Writer) *spinner {
+	f, ok := w.(*os.File)
+	return &spinner{w: w, terminal: ok && isTerminal(f)}
+}
+
+// wait shows the message for the given time.
+func (s *spinner) wait(d time.Duration, message string) {
+	if !s.terminal {
+		if message != s.message {
+			fmt.Fprintf(s.w, "%s...\n", message)
+		}
+		s.message = message
+		time.Sleep(d)
+		return
+	}
+
+	s.message = message
+	for end := time.Now().Add(d); time.Now().Before(end); {
+		fmt.Fprintf(s.w, "\r%s %s ", spinnerFrames[s.frame%len(spinnerFrames)], s.message)
+		s.frame++
+		time.Sleep(spinnerInterval)
+	}
+}
+
+// stop clears the spinner's line.
+func (s *spinner) stop() {
+	if s.terminal && s.message != "" {
+		fmt.Fprintf(s.w, "\r%s\r", strings.Repeat(" ", len([]rune(s.message))+3))
+	}
+}
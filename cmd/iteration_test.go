@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/exercism/cli/api"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitForIteration(t *testing.T) {
+	oldInterval := iterationPollInterval
+	iterationPollInterval = time.Millisecond
+	defer func() { iterationPollInterval = oldInterval }()
+
+	co := newCapturedOutput()
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	statuses := []string{"untested", "testing", "testing", "analyzing"}
+	var polls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/solutions/bogus-solution-uuid/iterations/42", r.URL.Path)
+		if polls < len(statuses) {
+			fmt.Fprintf(w, `{"iteration": {"id": 42, "status": %q, "tests_status": "queued"}}`, statuses[polls])
+			polls++
+			return
+		}
+		fmt.Fprint(w, `{"iteration": {"id": 42, "status": "actionable_automated_feedback", "tests_status": "passed",
+			"num_actionable_automated_comments": 2, "num_non_actionable_automated_comments": 1,
+			"representer_feedback": {"html": "<p>Nice</p>"}}}`)
+	}))
+	defer ts.Close()
+
+	client, err := api.NewClient("abc123", ts.URL)
+	assert.NoError(t, err)
+
+	it, err := waitForIteration(client, ts.URL+"/solutions/bogus-solution-uuid/iterations/42", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, it.isProcessed())
+	assert.Equal(t, 3, it.analyzerComments())
+	assert.True(t, it.hasRepresenterFeedback())
+
+	// Each status is shown once, rather than once for each time it was asked for.
+	assert.Equal(t, "Waiting for your iteration to be processed (queued)...\n"+
+		"Waiting for your iteration to be processed (testing)...\n"+
+		"Waiting for your iteration to be processed (analyzing)...\n", co.newErr.(*bytes.Buffer).String())
+}
+
+func TestWaitForIterationTimesOut(t *testing.T) {
+	oldInterval := iterationPollInterval
+	iterationPollInterval = time.Millisecond
+	defer func() { iterationPollInterval = oldInterval }()
+
+	co := newCapturedOutput()
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"iteration": {"id": 42, "status": "testing"}}`)
+	}))
+	defer ts.Close()
+
+	client, err := api.NewClient("abc123", ts.URL)
+	assert.NoError(t, err)
+
+	it, err := waitForIteration(client, ts.URL, 10*time.Millisecond)
+	assert.NoError(t, err)
+	assert.False(t, it.isProcessed())
+
+	printIterationOutcome(it, 10*time.Millisecond)
+	assert.Contains(t, co.newErr.(*bytes.Buffer).String(), "still being processed after 10ms")
+}
+
+func TestPrintIterationOutcome(t *testing.T) {
+	oldASCII := plainASCII
+	plainASCII = true
+	defer func() { plainASCII = oldASCII }()
+
+	testCases := []struct {
+		desc     string
+		it       iteration
+		expected string
+	}{
+		{
+			desc:     "tests passed without comments",
+			it:       iteration{Status: "no_automated_feedback", TestsStatus: "passed", RepresenterFeedback: []byte("null")},
+			expected: "    + Tests passed\n    No comments from the analyzer\n\n",
+		},
+		{
+			desc:     "tests failed with a comment",
+			it:       iteration{Status: "tests_failed", TestsStatus: "failed", NumEssentialAutomatedComments: 1},
+			expected: "    ! Tests failed\n    1 comment from the analyzer\n\n",
+		},
+		{
+			desc:     "no tests with representer feedback",
+			it:       iteration{Status: "celebratory_automated_feedback", TestsStatus: "not_queued", RepresenterFeedback: []byte(`{"html": "<p>Nice</p>"}`)},
+			expected: "    Tests: not run\n    No comments from the analyzer\n    Feedback from a mentor on solutions like yours\n\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		co := newCapturedOutput()
+		co.newOut = &bytes.Buffer{}
+		co.override()
+
+		printIterationOutcome(&tc.it, time.Minute)
+		co.reset()
+
+		assert.Equal(t, tc.expected, co.newOut.(*bytes.Buffer).String(), tc.desc)
+	}
+}
+
+func TestWaitTimeout(t *testing.T) {
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupSubmitFlags(flags)
+	timeout, err := waitTimeout(flags)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), timeout)
+
+	flags.Set("wait", "true")
+	timeout, err = waitTimeout(flags)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultWaitTimeout, timeout)
+
+	flags.Set("wait-timeout", "30s")
+	timeout, err = waitTimeout(flags)
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, timeout)
+
+	flags.Set("wait-timeout", "0s")
+	_, err = waitTimeout(flags)
+	assert.Error(t, err)
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/spf13/pflag"
+)
+
+// defaultJobs is how many requests are made at once unless --jobs says otherwise.
+// Downloads spend most of their time waiting on the network, so it is more than the number of CPUs.
+func defaultJobs() int {
+	jobs := 2 * runtime.NumCPU()
+	if jobs > 16 {
+		jobs = 16
+	}
+	return jobs
+}
+
+// jobsFromFlags reads --jobs, if the command has it.
+func jobsFromFlags(flags *pflag.FlagSet) (int, error) {
+	if flags.Lookup("jobs") == nil {
+		return defaultJobs(), nil
+	}
+	jobs, err := flags.GetInt("jobs")
+	if err != nil {
+		return 0, err
+	}
+	if jobs < 1 {
+		return 0, fmt.Errorf("--jobs must be at least 1, not %d", jobs)
+	}
+	return jobs, nil
+}
+
+// jobLimit bounds how many jobs run at once.
+// It is shared by everything that a command does concurrently,
+// so that nesting jobs doesn't multiply the bound.
+type jobLimit chan struct{}
+
+func newJobLimit(jobs int) jobLimit {
+	if jobs < 1 {
+		jobs = 1
+	}
+	return make(jobLimit, jobs)
+}
+
+// do runs fn once there is room for it. A nil limit runs it straight away.
+func (l jobLimit) do(fn func() error) error {
+	if l == nil {
+		return fn()
+	}
+	l <- struct{}{}
+	defer func() { <-l }()
+	return fn()
+}
+
+// each calls fn for every index from 0 to n concurrently, and waits for them all.
+// The errors are returned by index. Only the work that fn passes to the limit is bounded.
+func each(n int, fn func(i int) error) []error {
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = fn(i)
+		}(i)
+	}
+	wg.Wait()
+	return errs
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobLimit(t *testing.T) {
+	limit := newJobLimit(2)
+
+	var mu sync.Mutex
+	running, most := 0, 0
+	errs := each(10, func(i int) error {
+		return limit.do(func() error {
+			mu.Lock()
+			running++
+			if running > most {
+				most = running
+			}
+			mu.Unlock()
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+			if i == 3 {
+				return errors.New("failed")
+			}
+			return nil
+		})
+	})
+
+	assert.True(t, most <= 2, "more than 2 jobs ran at once")
+	for i, err := range errs {
+		if i == 3 {
+			assert.Error(t, err)
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+}
+
+func TestJobsFromFlags(t *testing.T) {
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	jobs, err := jobsFromFlags(flags)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultJobs(), jobs)
+
+	setupDownloadFlags(flags)
+	flags.Set("jobs", "3")
+	jobs, err = jobsFromFlags(flags)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, jobs)
+
+	flags.Set("jobs", "0")
+	_, err = jobsFromFlags(flags)
+	if assert.Error(t, err) {
+		assert.Regexp(t, "at least 1", err.Error())
+	}
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// downloadOperation records what a download changed in the workspace, so that it can be undone.
+type downloadOperation struct {
+	At       time.Time `json:"at"`
+	Track    string    `json:"track"`
+	Exercise string    `json:"exercise"`
+	Dir      string    `json:"dir"`
+	// CreatedDir is set if the exercise directory didn't exist before the download.
+	CreatedDir bool `json:"created_dir"`
+	// Created are the files that didn't exist before the download, relative to Dir.
+	Created []string `json:"created,omitempty"`
+	// CreatedOriginals are the files whose originals didn't exist before the download, relative to Dir.
+	CreatedOriginals []string `json:"created_originals,omitempty"`
+	// Snapshot is the ID of the snapshot of the files the download overwrote.
+	Snapshot string `json:"snapshot,omitempty"`
+}
+
+// journalPath is where the operations that can be undone are kept.
+func journalPath(stateDir string) string {
+	return filepath.Join(stateDir, "journal.json")
+}
+
+// maxJournalLength is how many operations are remembered.
+const maxJournalLength = 50
+
+func readJournal(stateDir string) ([]downloadOperation, error) {
+	b, err := ioutil.ReadFile(journalPath(stateDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ops []downloadOperation
+	if err := json.Unmarshal(b, &ops); err != nil {
+		return nil, err
+	}
+	return ops, nil
+}
+
+func writeJournal(stateDir string, ops []downloadOperation) error {
+	if len(ops) > maxJournalLength {
+		ops = ops[len(ops)-maxJournalLength:]
+	}
+	b, err := json.Marshal(ops)
+	if err != nil {
+		return err
+	}
+	path := journalPath(stateDir)
+	if err := os.MkdirAll(filepath.Dir(path), os.FileMode(0755)); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, os.FileMode(0644))
+}
+
+// recordOperation adds an operation to the end of the journal.
+func recordOperation(stateDir string, op downloadOperation) error {
+	ops, err := readJournal(stateDir)
+	if err != nil {
+		// A corrupt journal shouldn't stand in the way of new operations.
+		ops = nil
+	}
+	return writeJournal(stateDir, append(ops, op))
+}
+
+// lastOperation returns the most recent operation in the journal.
+// It returns nil if there is nothing to undo.
+func lastOperation(stateDir string) (*downloadOperation, error) {
+	ops, err := readJournal(stateDir)
+	if err != nil || len(ops) == 0 {
+		return nil, err
+	}
+	return &ops[len(ops)-1], nil
+}
+
+// dropLastOperation removes the most recent operation from the journal, once it has been undone.
+func dropLastOperation(stateDir string) error {
+	ops, err := readJournal(stateDir)
+	if err != nil || len(ops) == 0 {
+		return err
+	}
+	return writeJournal(stateDir, ops[:len(ops)-1])
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/exercism/cli/workspace"
+)
+
+// junitTestSuites is the root of a JUnit XML report, in the form that CI systems read.
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// junitProblem is why a test case failed, or couldn't be run.
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// newJUnitReport reports the results as a test suite named after the exercise.
+// A run that errored without running any tests is reported as a test case with an error,
+// so that it isn't mistaken for an empty run.
+func newJUnitReport(metadata *workspace.ExerciseMetadata, results *testRunnerResults, start time.Time, elapsed time.Duration) junitTestSuites {
+	suite := junitTestSuite{
+		Name:      metadata.String(),
+		Time:      fmt.Sprintf("%.3f", elapsed.Seconds()),
+		Timestamp: start.UTC().Format("2006-01-02T15:04:05"),
+	}
+	tests := results.Tests
+	if len(tests) == 0 && results.Status == testRunnerError {
+		tests = []testRunnerTest{{Name: metadata.String(), Status: testRunnerError, Message: results.Message}}
+	}
+	for _, test := range tests {
+		c := junitTestCase{Name: test.Name, Classname: metadata.String()}
+		switch test.Status {
+		case testRunnerPass:
+			c.SystemOut = test.Output
+		case testRunnerError:
+			c.Error = &junitProblem{Message: test.Message, Text: test.Output}
+			suite.Errors++
+		default:
+			c.Failure = &junitProblem{Message: test.Message, Text: test.Output}
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, c)
+	}
+	suite.Tests = len(suite.Cases)
+	return junitTestSuites{Suites: []junitTestSuite{suite}}
+}
+
+// writeJUnitReport writes the results to the file as JUnit XML.
+func writeJUnitReport(path string, metadata *workspace.ExerciseMetadata, results *testRunnerResults, start time.Time, elapsed time.Duration) error {
+	b, err := xml.MarshalIndent(newJUnitReport(metadata, results, start, elapsed), "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append([]byte(xml.Header), append(b, '\n')...), os.FileMode(0644))
+}
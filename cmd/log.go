@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/exercism/cli/config"
+	"github.com/exercism/cli/workspace"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// logCmd shows the iterations submitted from an exercise directory.
+var logCmd = &cobra.Command{
+	Use:   "log [PATH]",
+	Short: "Show the iterations you've submitted of an exercise.",
+	Long: `Show the iterations submitted of an exercise from its directory, newest first.
+It works offline.
+
+Pass the path to the exercise, or run the command from within it.
+
+Every submission is recorded in the exercise's .exercism/iterations directory,
+with the checksums of the files, the number of the iteration and the API's
+response. Pass --json to see the records in full.
+`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.NewConfig()
+
+		v := viper.New()
+		v.AddConfigPath(cfg.Dir)
+		v.SetConfigName("user")
+		v.SetConfigType("json")
+		// Ignore error. If the file doesn't exist, that is fine.
+		_ = v.ReadInConfig()
+		config.BindUserEnv(v)
+		cfg.UserViperConfig = v
+
+		return runLog(cfg, cmd.Flags(), args)
+	},
+}
+
+func runLog(cfg config.Config, flags *pflag.FlagSet, args []string) error {
+	limit, err := flags.GetInt("limit")
+	if err != nil {
+		return err
+	}
+	asJSON, err := flags.GetBool("json")
+	if err != nil {
+		return err
+	}
+
+	dir, metadata, err := exerciseAt(cfg.UserViperConfig, args)
+	if err != nil {
+		return err
+	}
+	records, err := workspace.NewIterationRecords(dir)
+	if err != nil {
+		return err
+	}
+
+	// Newest first.
+	for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+		records[i], records[j] = records[j], records[i]
+	}
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+
+	if asJSON {
+		if records == nil {
+			records = []workspace.IterationRecord{}
+		}
+		enc := json.NewEncoder(Out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	}
+
+	if len(records) == 0 {
+		fmt.Fprintf(Err, "Nothing has been submitted of %s from this computer.\n", metadata)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ITERATION\tSUBMITTED\tFILES\tMESSAGE")
+	for _, record := range records {
+		iteration := ""
+		if record.Index > 0 {
+			iteration = strconv.Itoa(record.Index)
+		}
+		files := make([]string, 0, len(record.Files))
+		for path := range record.Files {
+			files = append(files, path)
+		}
+		sort.Strings(files)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+			orDash(iteration),
+			record.SubmittedAt.Local().Format("2006-01-02 15:04"),
+			strings.Join(files, ", "),
+			record.Message,
+		)
+	}
+	return w.Flush()
+}
+
+func setupLogFlags(flags *pflag.FlagSet) {
+	flags.IntP("limit", "n", 0, "show at most this many iterations")
+	flags.Bool("json", false, "print the records of the iterations as JSON")
+}
+
+func init() {
+	RootCmd.AddCommand(logCmd)
+	setupLogFlags(logCmd.Flags())
+}
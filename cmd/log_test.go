@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/exercism/cli/config"
+	"github.com/exercism/cli/workspace"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLog(t *testing.T) {
+	co := newCapturedOutput()
+	co.newOut = &bytes.Buffer{}
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	idx := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idx++
+		fmt.Fprintf(w, `{"iteration": {"id": "it-%d", "idx": %d}}`, idx, idx)
+	}))
+	defer ts.Close()
+
+	tmpDir, err := ioutil.TempDir("", "log")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
+	assert.NoError(t, os.MkdirAll(dir, os.FileMode(0755)))
+	writeFakeMetadata(t, dir, "bogus-track", "bogus-exercise")
+	file := filepath.Join(dir, "file.txt")
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	cfg := config.Config{
+		Persister:       config.InMemoryPersister{},
+		UserViperConfig: v,
+	}
+
+	logFlags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupLogFlags(logFlags)
+	assert.NoError(t, runLog(cfg, logFlags, []string{dir}))
+	assert.Regexp(t, "Nothing has been submitted", co.newErr.(*bytes.Buffer).String())
+
+	for _, contents := range []string{"First try.", "Second try."} {
+		assert.NoError(t, ioutil.WriteFile(file, []byte(contents), os.FileMode(0644)))
+		flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+		setupSubmitFlags(flags)
+		flags.Set("message", contents)
+		assert.NoError(t, runSubmit(cfg, flags, []string{file}))
+	}
+
+	records, err := workspace.NewIterationRecords(dir)
+	assert.NoError(t, err)
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, "it-2", records[1].IterationID)
+		assert.Equal(t, workspace.Checksum([]byte("Second try.")), records[1].Files["file.txt"])
+	}
+
+	co.newOut = &bytes.Buffer{}
+	co.override()
+	assert.NoError(t, runLog(cfg, logFlags, []string{dir}))
+	assert.Regexp(t, `(?s)ITERATION.*\n2 .*file.txt\s+Second try\.\n1 .*file.txt\s+First try\.\n$`, co.newOut.(*bytes.Buffer).String())
+}
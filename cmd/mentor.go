@@ -0,0 +1,475 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/exercism/cli/api"
+	"github.com/exercism/cli/config"
+	"github.com/exercism/cli/editor"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// Statuses of a mentoring discussion, as reported by the API.
+const (
+	discussionAwaitingStudent = "awaiting_student"
+	discussionAwaitingMentor  = "awaiting_mentor"
+)
+
+// mentorCmd groups the commands for taking part in mentoring discussions.
+var mentorCmd = &cobra.Command{
+	Use:   "mentor",
+	Short: "Take part in mentoring discussions.",
+	Long: `Take part in the mentoring discussions about your solutions.
+
+    exercism mentor reply
+    exercism mentor reply --message "Thanks, I've pushed a new iteration."
+    exercism mentor export --discussion=ID > discussion.md
+`,
+}
+
+var mentorReplyCmd = &cobra.Command{
+	Use:   "reply [PATH]",
+	Short: "Reply to your mentor.",
+	Long: `Reply to the mentoring discussion about an exercise.
+
+Pass the path to the exercise, or run the command from within it.
+
+Unless the reply is given with --message, it is written in your editor.
+The editor is found the same way as for open --editor, and has to wait
+until the file is closed, e.g. "code --wait". Lines starting with '#'
+are left out, and an empty reply is not posted.
+
+Once the reply is posted, the discussion is waiting for your mentor again.
+`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.NewConfig()
+
+		v := viper.New()
+		v.AddConfigPath(cfg.Dir)
+		v.SetConfigName("user")
+		v.SetConfigType("json")
+		// Ignore error. If the file doesn't exist, that is fine.
+		_ = v.ReadInConfig()
+		config.BindUserEnv(v)
+		cfg.UserViperConfig = v
+
+		return runMentorReply(cfg, cmd.Flags(), args)
+	},
+}
+
+var mentorExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export a mentoring discussion.",
+	Long: `Export a whole mentoring discussion as a single document, to keep it
+or to share it.
+
+The document has the code of each iteration that was discussed, followed by
+the posts about it, with the time each was written. It is in Markdown,
+or in JSON with --format=json. The discussion ID is the last part of the
+discussion's URL on the website.
+
+    exercism mentor export --discussion=ID > discussion.md
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.NewConfig()
+
+		v := viper.New()
+		v.AddConfigPath(cfg.Dir)
+		v.SetConfigName("user")
+		v.SetConfigType("json")
+		// Ignore error. If the file doesn't exist, that is fine.
+		_ = v.ReadInConfig()
+		config.BindUserEnv(v)
+		cfg.UserViperConfig = v
+
+		return runMentorExport(cfg, cmd.Flags())
+	},
+}
+
+// discussion is a mentoring discussion about a solution.
+type discussion struct {
+	UUID   string `json:"uuid"`
+	Status string `json:"status"`
+	Mentor struct {
+		Handle string `json:"handle"`
+	} `json:"mentor"`
+	Links struct {
+		Self string `json:"self"`
+	} `json:"links"`
+}
+
+func (d discussion) isActive() bool {
+	return d.Status == discussionAwaitingStudent || d.Status == discussionAwaitingMentor
+}
+
+// composeEditor opens the file to write a message in. It's swapped out in tests.
+var composeEditor = func(usrCfg *viper.Viper, path string) error {
+	template, err := editor.Resolve(usrCfg.GetString("editor.command"))
+	if err != nil {
+		return err
+	}
+	return editor.Open(template, path, 0)
+}
+
+const replyTemplate = `
+
+# Write your reply to %s above.
+# Lines starting with '#' are left out. An empty reply is not posted.
+`
+
+func runMentorReply(cfg config.Config, flags *pflag.FlagSet, args []string) error {
+	usrCfg := cfg.UserViperConfig
+	if err := validateUserConfig(usrCfg); err != nil {
+		return err
+	}
+	_, metadata, err := exerciseAt(usrCfg, args)
+	if err != nil {
+		return err
+	}
+
+	client, err := api.NewClient(usrCfg.GetString("token"), usrCfg.GetString("apibaseurl"))
+	if err != nil {
+		return err
+	}
+	d, err := activeDiscussion(client, usrCfg.GetString("apibaseurl"), metadata.ID)
+	if err != nil {
+		return err
+	}
+	if d == nil {
+		return fmt.Errorf("there is no mentoring discussion going on about %s in %s", metadata.ExerciseSlug, metadata.Track)
+	}
+
+	message, err := flags.GetString("message")
+	if err != nil {
+		return err
+	}
+	if !flags.Changed("message") {
+		mentor := "your mentor"
+		if d.Mentor.Handle != "" {
+			mentor = d.Mentor.Handle
+		}
+		if message, err = composeMessage(usrCfg, fmt.Sprintf(replyTemplate, mentor)); err != nil {
+			return err
+		}
+	}
+	if strings.TrimSpace(message) == "" {
+		return errors.New("the reply is empty, so it wasn't posted")
+	}
+
+	if err := postDiscussionReply(client, usrCfg.GetString("apibaseurl"), d.UUID, message); err != nil {
+		return err
+	}
+	fmt.Fprintln(Err, "Your reply was posted, and the discussion is waiting for your mentor.")
+	if d.Links.Self != "" {
+		fmt.Fprintln(Out, d.Links.Self)
+	}
+	return nil
+}
+
+// activeDiscussion finds the mentoring discussion going on about a solution, if there is one.
+// A discussion waiting for the student comes before one waiting for the mentor.
+func activeDiscussion(client *api.Client, baseURL, solutionID string) (*discussion, error) {
+	url := fmt.Sprintf("%s/solutions/%s/mentor_discussions", baseURL, solutionID)
+	req, err := client.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, decodedAPIError(res)
+	}
+
+	var payload struct {
+		Discussions []discussion `json:"discussions"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("unable to parse API response - %s", err)
+	}
+
+	var active *discussion
+	for i, d := range payload.Discussions {
+		if !d.isActive() {
+			continue
+		}
+		if active == nil || (d.Status == discussionAwaitingStudent && active.Status != discussionAwaitingStudent) {
+			active = &payload.Discussions[i]
+		}
+	}
+	return active, nil
+}
+
+// postDiscussionReply adds the message to the discussion, and hands the discussion back to the mentor.
+func postDiscussionReply(client *api.Client, baseURL, uuid, message string) error {
+	url := fmt.Sprintf("%s/mentoring/discussions/%s/posts", baseURL, uuid)
+	if err := sendDiscussionJSON(client, "POST", url, map[string]string{"content": message}); err != nil {
+		return err
+	}
+	url = fmt.Sprintf("%s/mentoring/discussions/%s", baseURL, uuid)
+	return sendDiscussionJSON(client, "PATCH", url, map[string]string{"status": discussionAwaitingMentor})
+}
+
+func sendDiscussionJSON(client *api.Client, method, url string, payload interface{}) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := client.NewRequest(method, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return decodedAPIError(res)
+	}
+	return nil
+}
+
+// composeMessage has a message written in the editor, starting from the template.
+// Lines starting with '#' are left out, and the message is trimmed.
+func composeMessage(usrCfg *viper.Viper, template string) (string, error) {
+	dir, err := ioutil.TempDir("", "exercism-message")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "MESSAGE.md")
+	if err := ioutil.WriteFile(path, []byte(template), os.FileMode(0600)); err != nil {
+		return "", err
+	}
+	if err := composeEditor(usrCfg, path); err != nil {
+		return "", err
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return stripCommentLines(string(b)), nil
+}
+
+func stripCommentLines(s string) string {
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	for scanner.Scan() {
+		if line := strings.TrimRight(scanner.Text(), "\r"); !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// discussionTranscript is everything that was said in a mentoring discussion.
+type discussionTranscript struct {
+	UUID     string `json:"uuid"`
+	Status   string `json:"status"`
+	Track    string `json:"track"`
+	Exercise string `json:"exercise"`
+	Student  struct {
+		Handle string `json:"handle"`
+	} `json:"student"`
+	Mentor struct {
+		Handle string `json:"handle"`
+	} `json:"mentor"`
+	CreatedAt  time.Time             `json:"created_at"`
+	FinishedAt *time.Time            `json:"finished_at,omitempty"`
+	Iterations []discussionIteration `json:"iterations"`
+	Posts      []discussionPost      `json:"posts"`
+}
+
+// discussionIteration is a version of the code that was discussed.
+type discussionIteration struct {
+	Idx         int              `json:"idx"`
+	SubmittedAt time.Time        `json:"submitted_at"`
+	Files       []discussionFile `json:"files"`
+}
+
+type discussionFile struct {
+	Filename string `json:"filename"`
+	Content  string `json:"content"`
+}
+
+// discussionPost is a post in a discussion, about one of the iterations.
+type discussionPost struct {
+	AuthorHandle    string    `json:"author_handle"`
+	ByStudent       bool      `json:"by_student"`
+	IterationIdx    int       `json:"iteration_idx"`
+	ContentMarkdown string    `json:"content_markdown"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func runMentorExport(cfg config.Config, flags *pflag.FlagSet) error {
+	usrCfg := cfg.UserViperConfig
+	if err := validateUserConfig(usrCfg); err != nil {
+		return err
+	}
+	id, err := flags.GetString("discussion")
+	if err != nil {
+		return err
+	}
+	if id == "" {
+		return errors.New("give the ID of the discussion to export with --discussion")
+	}
+	format, err := flags.GetString("format")
+	if err != nil {
+		return err
+	}
+	if format != "markdown" && format != "json" {
+		return fmt.Errorf("cannot export in '%s', use one of: markdown, json", format)
+	}
+
+	client, err := api.NewClient(usrCfg.GetString("token"), usrCfg.GetString("apibaseurl"))
+	if err != nil {
+		return err
+	}
+	transcript, err := fetchDiscussionTranscript(client, usrCfg.GetString("apibaseurl"), id)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		b, err := json.MarshalIndent(transcript, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(Out, "%s\n", b)
+		return nil
+	}
+	writeTranscriptMarkdown(Out, transcript)
+	return nil
+}
+
+// fetchDiscussionTranscript requests a discussion with all its iterations and posts from the API.
+func fetchDiscussionTranscript(client *api.Client, baseURL, id string) (*discussionTranscript, error) {
+	url := fmt.Sprintf("%s/mentoring/discussions/%s", baseURL, id)
+	req, err := client.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, decodedAPIError(res)
+	}
+
+	var payload struct {
+		Discussion discussionTranscript `json:"discussion"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("unable to parse API response - %s", err)
+	}
+	t := &payload.Discussion
+	sort.SliceStable(t.Iterations, func(i, j int) bool { return t.Iterations[i].Idx < t.Iterations[j].Idx })
+	sort.SliceStable(t.Posts, func(i, j int) bool { return t.Posts[i].CreatedAt.Before(t.Posts[j].CreatedAt) })
+	return t, nil
+}
+
+// transcriptTimeFormat is how times are written in a Markdown transcript.
+const transcriptTimeFormat = "2006-01-02 15:04 MST"
+
+// writeTranscriptMarkdown writes the code of each iteration followed by the posts about it.
+// Posts that aren't about an iteration in the discussion come at the end.
+func writeTranscriptMarkdown(w io.Writer, t *discussionTranscript) {
+	fmt.Fprintf(w, "# Mentoring discussion: %s in %s\n\n", t.Exercise, t.Track)
+	fmt.Fprintf(w, "- Student: %s\n", t.Student.Handle)
+	fmt.Fprintf(w, "- Mentor: %s\n", t.Mentor.Handle)
+	fmt.Fprintf(w, "- Started: %s\n", t.CreatedAt.UTC().Format(transcriptTimeFormat))
+	if t.FinishedAt != nil {
+		fmt.Fprintf(w, "- Finished: %s\n", t.FinishedAt.UTC().Format(transcriptTimeFormat))
+	}
+
+	discussed := map[int]bool{}
+	for _, it := range t.Iterations {
+		discussed[it.Idx] = true
+		fmt.Fprintf(w, "\n## Iteration %d\n\n", it.Idx)
+		fmt.Fprintf(w, "Submitted %s\n", it.SubmittedAt.UTC().Format(transcriptTimeFormat))
+		for _, file := range it.Files {
+			fence := codeFence(file.Content)
+			fmt.Fprintf(w, "\n### %s\n\n%s%s\n%s", file.Filename, fence, strings.TrimPrefix(filepath.Ext(file.Filename), "."), file.Content)
+			if !strings.HasSuffix(file.Content, "\n") {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintln(w, fence)
+		}
+		for _, post := range t.Posts {
+			if post.IterationIdx == it.Idx {
+				writePostMarkdown(w, post)
+			}
+		}
+	}
+
+	var rest []discussionPost
+	for _, post := range t.Posts {
+		if !discussed[post.IterationIdx] {
+			rest = append(rest, post)
+		}
+	}
+	if len(rest) > 0 {
+		fmt.Fprint(w, "\n## Other posts\n")
+		for _, post := range rest {
+			writePostMarkdown(w, post)
+		}
+	}
+}
+
+func writePostMarkdown(w io.Writer, post discussionPost) {
+	role := "mentor"
+	if post.ByStudent {
+		role = "student"
+	}
+	fmt.Fprintf(w, "\n**%s** (%s), %s:\n\n%s\n", post.AuthorHandle, role, post.CreatedAt.UTC().Format(transcriptTimeFormat), strings.TrimSpace(post.ContentMarkdown))
+}
+
+// codeFence is a fence that is longer than any run of backticks in the code.
+func codeFence(code string) string {
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return fence
+}
+
+func setupMentorExportFlags(flags *pflag.FlagSet) {
+	flags.String("discussion", "", "the ID of the discussion to export")
+	flags.String("format", "markdown", "the format of the export: markdown or json")
+}
+
+func setupMentorReplyFlags(flags *pflag.FlagSet) {
+	flags.StringP("message", "m", "", "the reply, instead of writing it in your editor")
+}
+
+func init() {
+	RootCmd.AddCommand(mentorCmd)
+	mentorCmd.AddCommand(mentorReplyCmd)
+	setupMentorReplyFlags(mentorReplyCmd.Flags())
+	mentorCmd.AddCommand(mentorExportCmd)
+	setupMentorExportFlags(mentorExportCmd.Flags())
+}
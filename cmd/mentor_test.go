@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/exercism/cli/config"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+const discussionsPayload = `{"discussions": [
+	{"uuid": "finished-uuid", "status": "finished", "mentor": {"handle": "old"}},
+	{"uuid": "active-uuid", "status": "awaiting_student", "mentor": {"handle": "ana"}, "links": {"self": "https://exercism.org/discussions/active-uuid"}}
+]}`
+
+func fakeDiscussionServer(t *testing.T, requests map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			fmt.Fprint(w, discussionsPayload)
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		requests[fmt.Sprintf("%s %s", r.Method, r.URL.Path)] = string(body)
+		fmt.Fprint(w, "{}")
+	}))
+}
+
+func TestMentorReply(t *testing.T) {
+	co := newCapturedOutput()
+	co.override()
+	defer co.reset()
+
+	requests := map[string]string{}
+	ts := fakeDiscussionServer(t, requests)
+	defer ts.Close()
+
+	tmpDir, err := ioutil.TempDir("", "mentor-reply")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	dir := filepath.Join(tmpDir, "go", "bob")
+	assert.NoError(t, os.MkdirAll(dir, os.FileMode(0755)))
+	writeFakeMetadata(t, dir, "go", "bob")
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	cfg := config.Config{UserViperConfig: v}
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupMentorReplyFlags(flags)
+	flags.Set("message", "Thanks, I've used a map now.")
+	assert.NoError(t, runMentorReply(cfg, flags, []string{dir}))
+
+	var post map[string]string
+	assert.NoError(t, json.Unmarshal([]byte(requests["POST /mentoring/discussions/active-uuid/posts"]), &post))
+	assert.Equal(t, "Thanks, I've used a map now.", post["content"])
+	var patch map[string]string
+	assert.NoError(t, json.Unmarshal([]byte(requests["PATCH /mentoring/discussions/active-uuid"]), &patch))
+	assert.Equal(t, "awaiting_mentor", patch["status"])
+}
+
+func TestMentorReplyInEditor(t *testing.T) {
+	co := newCapturedOutput()
+	co.override()
+	defer co.reset()
+
+	requests := map[string]string{}
+	ts := fakeDiscussionServer(t, requests)
+	defer ts.Close()
+
+	tmpDir, err := ioutil.TempDir("", "mentor-reply-editor")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	dir := filepath.Join(tmpDir, "go", "bob")
+	assert.NoError(t, os.MkdirAll(dir, os.FileMode(0755)))
+	writeFakeMetadata(t, dir, "go", "bob")
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	cfg := config.Config{UserViperConfig: v}
+
+	var template string
+	defer func(f func(*viper.Viper, string) error) { composeEditor = f }(composeEditor)
+	composeEditor = func(_ *viper.Viper, path string) error {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		template = string(b)
+		return ioutil.WriteFile(path, append([]byte("Is this better?\n"), b...), os.FileMode(0600))
+	}
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupMentorReplyFlags(flags)
+	assert.NoError(t, runMentorReply(cfg, flags, []string{dir}))
+	assert.Contains(t, template, "# Write your reply to ana above.")
+	assert.Contains(t, requests["POST /mentoring/discussions/active-uuid/posts"], `"content":"Is this better?"`)
+
+	// An empty reply isn't posted.
+	delete(requests, "POST /mentoring/discussions/active-uuid/posts")
+	composeEditor = func(*viper.Viper, string) error { return nil }
+	err = runMentorReply(cfg, flags, []string{dir})
+	if assert.Error(t, err) {
+		assert.Regexp(t, "reply is empty", err.Error())
+	}
+	assert.Empty(t, requests["POST /mentoring/discussions/active-uuid/posts"])
+}
+
+func TestMentorReplyWithoutDiscussion(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"discussions": [{"uuid": "finished-uuid", "status": "mentor_finished"}]}`)
+	}))
+	defer ts.Close()
+
+	tmpDir, err := ioutil.TempDir("", "mentor-reply-none")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	dir := filepath.Join(tmpDir, "go", "bob")
+	assert.NoError(t, os.MkdirAll(dir, os.FileMode(0755)))
+	writeFakeMetadata(t, dir, "go", "bob")
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	cfg := config.Config{UserViperConfig: v}
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupMentorReplyFlags(flags)
+	flags.Set("message", "Hello?")
+	err = runMentorReply(cfg, flags, []string{dir})
+	if assert.Error(t, err) {
+		assert.Regexp(t, "no mentoring discussion going on about bob in go", err.Error())
+	}
+}
+
+func TestStripCommentLines(t *testing.T) {
+	assert.Equal(t, "First line\n\nSecond line", stripCommentLines("\nFirst line\r\n\n# a comment\nSecond line\n\n# another\n"))
+}
+
+const transcriptPayload = `{"discussion": {
+	"uuid": "abc", "status": "finished", "track": "go", "exercise": "bob",
+	"student": {"handle": "sam"}, "mentor": {"handle": "ana"},
+	"created_at": "2026-01-02T10:00:00Z", "finished_at": "2026-01-05T12:30:00Z",
+	"iterations": [
+		{"idx": 2, "submitted_at": "2026-01-04T09:00:00Z", "files": [{"filename": "bob.go", "content": "package bob\n\n// v2\n"}]},
+		{"idx": 1, "submitted_at": "2026-01-02T09:00:00Z", "files": [{"filename": "README.md", "content": "` + "```go\\nx\\n```" + `"}]}
+	],
+	"posts": [
+		{"author_handle": "sam", "by_student": true, "iteration_idx": 2, "content_markdown": "Better?", "created_at": "2026-01-04T10:00:00Z"},
+		{"author_handle": "ana", "iteration_idx": 1, "content_markdown": "Try a switch.\n", "created_at": "2026-01-03T10:00:00Z"},
+		{"author_handle": "ana", "iteration_idx": 3, "content_markdown": "Bye!", "created_at": "2026-01-05T10:00:00Z"}
+	]
+}}`
+
+func TestMentorExport(t *testing.T) {
+	var requested string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path
+		fmt.Fprint(w, transcriptPayload)
+	}))
+	defer ts.Close()
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", "/home/username")
+	v.Set("apibaseurl", ts.URL)
+	cfg := config.Config{UserViperConfig: v}
+
+	run := func(format string) string {
+		co := newCapturedOutput()
+		co.newOut = &bytes.Buffer{}
+		co.override()
+		defer co.reset()
+
+		flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+		setupMentorExportFlags(flags)
+		flags.Set("discussion", "abc")
+		flags.Set("format", format)
+		assert.NoError(t, runMentorExport(cfg, flags))
+		return co.newOut.(*bytes.Buffer).String()
+	}
+
+	expected := "# Mentoring discussion: bob in go\n\n" +
+		"- Student: sam\n- Mentor: ana\n- Started: 2026-01-02 10:00 UTC\n- Finished: 2026-01-05 12:30 UTC\n" +
+		"\n## Iteration 1\n\nSubmitted 2026-01-02 09:00 UTC\n" +
+		"\n### README.md\n\n````md\n```go\nx\n```\n````\n" +
+		"\n**ana** (mentor), 2026-01-03 10:00 UTC:\n\nTry a switch.\n" +
+		"\n## Iteration 2\n\nSubmitted 2026-01-04 09:00 UTC\n" +
+		"\n### bob.go\n\n```go\npackage bob\n\n// v2\n```\n" +
+		"\n**sam** (student), 2026-01-04 10:00 UTC:\n\nBetter?\n" +
+		"\n## Other posts\n" +
+		"\n**ana** (mentor), 2026-01-05 10:00 UTC:\n\nBye!\n"
+	assert.Equal(t, expected, run("markdown"))
+	assert.Equal(t, "/mentoring/discussions/abc", requested)
+
+	var transcript discussionTranscript
+	assert.NoError(t, json.Unmarshal([]byte(run("json")), &transcript))
+	assert.Equal(t, "sam", transcript.Student.Handle)
+	assert.Len(t, transcript.Iterations, 2)
+	assert.Equal(t, 1, transcript.Iterations[0].Idx)
+	assert.Equal(t, "Try a switch.\n", transcript.Posts[0].ContentMarkdown)
+}
+
+func TestMentorExportValidatesFlags(t *testing.T) {
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", "/home/username")
+	v.Set("apibaseurl", "http://example.com")
+	cfg := config.Config{UserViperConfig: v}
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupMentorExportFlags(flags)
+	err := runMentorExport(cfg, flags)
+	if assert.Error(t, err) {
+		assert.Regexp(t, "--discussion", err.Error())
+	}
+
+	flags.Set("discussion", "abc")
+	flags.Set("format", "pdf")
+	err = runMentorExport(cfg, flags)
+	if assert.Error(t, err) {
+		assert.Regexp(t, "cannot export in 'pdf'", err.Error())
+	}
+}
@@ -0,0 +1,395 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/exercism/cli/api"
+	"github.com/exercism/cli/config"
+	"github.com/exercism/cli/workspace"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// staleAfter is how long after practicing a topic it counts as
+// not practiced at all when suggesting the next exercise.
+const staleAfter = 30 * 24 * time.Hour
+
+// nextRand picks the suggested exercise. Tests replace it to be deterministic.
+var nextRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+// nextCmd suggests an exercise to work on.
+var nextCmd = &cobra.Command{
+	Use:     "next",
+	Aliases: []string{"n"},
+	Short:   "Suggest an exercise to practice next.",
+	Long: `Suggest an exercise to practice next, and offer to download it.
+
+The exercise is picked at random from the unlocked exercises that you haven't
+completed, favouring those with topics that you haven't practiced recently.
+What you've practiced is worked out from the exercises in your workspace.
+
+Without --track, exercises are suggested from the tracks in your workspace.
+
+    exercism next --track=go --difficulty=medium
+
+Pass --syllabus to do the exercise the website recommends instead: the next
+concept exercise of the track's syllabus, or once there's none left, the next
+unlocked practice exercise. It's downloaded in one step, and its path printed.
+If the website can't recommend one, it's worked out from the syllabus:
+
+    exercism next --track=go --syllabus
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.NewConfig()
+
+		v := viper.New()
+		v.AddConfigPath(cfg.Dir)
+		v.SetConfigName("user")
+		v.SetConfigType("json")
+		// Ignore error. If the file doesn't exist, that is fine.
+		_ = v.ReadInConfig()
+		config.BindUserEnv(v)
+		cfg.UserViperConfig = v
+
+		return runNext(cfg, cmd.Flags(), args)
+	},
+}
+
+// practiceHistory is what has been practiced locally.
+type practiceHistory struct {
+	// topics maps a topic to when it was last practiced.
+	topics map[string]time.Time
+	// dirs maps track/exercise to where it was downloaded.
+	dirs map[string]string
+	// tracks are the tracks found in the workspace.
+	tracks []string
+}
+
+// newPracticeHistory reads the practice history from the exercises in the workspace.
+// An exercise counts as practiced when it was last submitted or, failing that, downloaded.
+func newPracticeHistory(ws workspace.Workspace) (practiceHistory, error) {
+	history := practiceHistory{
+		topics: map[string]time.Time{},
+		dirs:   map[string]string{},
+	}
+
+	exercises, err := ws.Exercises()
+	if err != nil {
+		return history, err
+	}
+
+	seenTracks := map[string]bool{}
+	for _, exercise := range exercises {
+		metadata, err := workspace.NewExerciseMetadata(exercise.MetadataDir())
+		if err != nil {
+			return history, err
+		}
+
+		if !seenTracks[metadata.Track] {
+			seenTracks[metadata.Track] = true
+			history.tracks = append(history.tracks, metadata.Track)
+		}
+		history.dirs[metadata.Track+"/"+metadata.ExerciseSlug] = exercise.MetadataDir()
+
+		var practicedAt time.Time
+		if metadata.SubmittedAt != nil {
+			practicedAt = *metadata.SubmittedAt
+		} else if info, err := os.Stat(exercise.MetadataFilepath()); err == nil {
+			practicedAt = info.ModTime()
+		}
+		for _, topic := range metadata.Topics {
+			topic = strings.ToLower(topic)
+			if practicedAt.After(history.topics[topic]) {
+				history.topics[topic] = practicedAt
+			}
+		}
+	}
+	sort.Strings(history.tracks)
+	return history, nil
+}
+
+// weight is how strongly an exercise is favoured, based on how long ago
+// its topics were last practiced.
+func (history practiceHistory) weight(exercise catalogExercise, now time.Time) float64 {
+	if len(exercise.Topics) == 0 {
+		return 1
+	}
+	var total float64
+	for _, topic := range exercise.Topics {
+		since := staleAfter
+		if practicedAt, ok := history.topics[strings.ToLower(topic)]; ok && now.Sub(practicedAt) < staleAfter {
+			since = now.Sub(practicedAt)
+		}
+		total += since.Hours() / 24
+	}
+	return 1 + total/float64(len(exercise.Topics))
+}
+
+// suggestion is an exercise to practice next.
+type suggestion struct {
+	track    string
+	exercise catalogExercise
+}
+
+// pickSuggestion picks a weighted random exercise from the candidates.
+func pickSuggestion(candidates []suggestion, history practiceHistory, now time.Time, r *rand.Rand) suggestion {
+	weights := make([]float64, len(candidates))
+	var total float64
+	for i, candidate := range candidates {
+		weights[i] = history.weight(candidate.exercise, now)
+		total += weights[i]
+	}
+	target := r.Float64() * total
+	for i, w := range weights {
+		if target < w {
+			return candidates[i]
+		}
+		target -= w
+	}
+	return candidates[len(candidates)-1]
+}
+
+// isUnfinished tells whether an exercise is unlocked and hasn't been completed.
+func isUnfinished(exercise catalogExercise) bool {
+	switch normalizeStatus(exercise.Status) {
+	case statusLocked, statusCompleted, statusPublished:
+		return false
+	}
+	return true
+}
+
+func runNext(cfg config.Config, flags *pflag.FlagSet, args []string) error {
+	usrCfg := cfg.UserViperConfig
+	if err := validateUserConfig(usrCfg); err != nil {
+		return err
+	}
+
+	ws, err := openWorkspace(usrCfg)
+	if err != nil {
+		return err
+	}
+	history := practiceHistory{topics: map[string]time.Time{}, dirs: map[string]string{}}
+	if _, err := os.Stat(ws.Dir); err == nil {
+		if history, err = newPracticeHistory(ws); err != nil {
+			return err
+		}
+	}
+
+	track, err := flags.GetString("track")
+	if err != nil {
+		return err
+	}
+	tracks := history.tracks
+	if track != "" {
+		tracks = []string{track}
+	}
+	if len(tracks) == 0 {
+		return errors.New("need a --track to suggest an exercise from")
+	}
+
+	difficulty, err := flags.GetString("difficulty")
+	if err != nil {
+		return err
+	}
+	filter := exerciseFilter{difficulty: difficulty}
+
+	bySyllabus, err := flags.GetBool("syllabus")
+	if err != nil {
+		return err
+	}
+	if bySyllabus {
+		if track == "" {
+			return errors.New("need a --track to follow the syllabus of")
+		}
+		next, err := syllabusSuggestion(cfg, track, filter)
+		if err != nil {
+			return err
+		}
+		if next == nil {
+			fmt.Fprintf(Err, "There's nothing left to do in the %s track's syllabus.\n", track)
+			return nil
+		}
+		// The exercise is downloaded in one step, without asking.
+		return offerSuggestion(cfg, *next, history, false)
+	}
+
+	var candidates []suggestion
+	for _, track := range tracks {
+		c, err := loadExercisesCatalog(cfg, track)
+		if err != nil {
+			return err
+		}
+		for _, exercise := range c.Exercises {
+			if isUnfinished(exercise) && filter.matches(exercise) {
+				candidates = append(candidates, suggestion{track: track, exercise: exercise})
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		fmt.Fprintln(Err, "There are no unlocked exercises left to suggest.")
+		return nil
+	}
+
+	yes, err := flags.GetBool("yes")
+	if err != nil {
+		return err
+	}
+	return offerSuggestion(cfg, pickSuggestion(candidates, history, time.Now(), nextRand), history, !yes)
+}
+
+// errNoRecommendations means that the API doesn't recommend exercises.
+var errNoRecommendations = errors.New("the API doesn't recommend exercises")
+
+// syllabusSuggestion is the exercise the API recommends doing next in the track.
+// It's nil when everything has been done.
+// If the API doesn't recommend exercises, it's worked out from the track's syllabus instead.
+func syllabusSuggestion(cfg config.Config, track string, filter exerciseFilter) (*suggestion, error) {
+	usrCfg := cfg.UserViperConfig
+	exercise, err := fetchRecommendation(usrCfg.GetString("token"), usrCfg.GetString("apibaseurl"), track, filter.difficulty)
+	if err == errNoRecommendations {
+		return localSyllabusSuggestion(cfg, track, filter)
+	}
+	if err != nil || exercise == nil {
+		return nil, err
+	}
+	return &suggestion{track: track, exercise: *exercise}, nil
+}
+
+// fetchRecommendation asks the API for the exercise to do next in the track:
+// the next concept exercise, or else the next unlocked practice exercise.
+// It's nil when everything has been done.
+func fetchRecommendation(token, baseURL, track, difficulty string) (*catalogExercise, error) {
+	client, err := api.NewClient(token, baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/tracks/%s/exercises/next", baseURL, track)
+	req, err := client.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if difficulty != "" {
+		q := req.URL.Query()
+		q.Add("difficulty", difficulty)
+		req.URL.RawQuery = q.Encode()
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return nil, errNoRecommendations
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, decodedAPIError(res)
+	}
+
+	var payload struct {
+		Exercise *catalogExercise `json:"exercise"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("unable to parse API response - %s", err)
+	}
+	return payload.Exercise, nil
+}
+
+// localSyllabusSuggestion is the next exercise in the order of the track's syllabus:
+// the first unfinished concept exercise of the first concept that's unlocked but not completed,
+// or else the first unfinished practice exercise. It's nil when everything has been done.
+func localSyllabusSuggestion(cfg config.Config, track string, filter exerciseFilter) (*suggestion, error) {
+	usrCfg := cfg.UserViperConfig
+	c, err := loadExercisesCatalog(cfg, track)
+	if err != nil {
+		return nil, err
+	}
+	s, err := fetchSyllabus(usrCfg.GetString("token"), usrCfg.GetString("apibaseurl"), track)
+	if err != nil {
+		return nil, err
+	}
+
+	exercises := make(map[string]catalogExercise, len(c.Exercises))
+	for _, exercise := range c.Exercises {
+		exercises[exercise.Slug] = exercise
+	}
+	for _, concept := range s.Concepts {
+		switch normalizeStatus(concept.Status) {
+		case statusLocked, statusCompleted:
+			continue
+		}
+		for _, slug := range concept.Exercises {
+			if exercise, ok := exercises[slug]; ok && isUnfinished(exercise) && filter.matches(exercise) {
+				return &suggestion{track: track, exercise: exercise}, nil
+			}
+		}
+	}
+	for _, exercise := range c.Exercises {
+		if exercise.Type != "concept" && isUnfinished(exercise) && filter.matches(exercise) {
+			return &suggestion{track: track, exercise: exercise}, nil
+		}
+	}
+	return nil, nil
+}
+
+// offerSuggestion shows the suggested exercise and downloads it, asking first if ask is set,
+// or says where it is if it's been downloaded already.
+func offerSuggestion(cfg config.Config, next suggestion, history practiceHistory, ask bool) error {
+	printSuggestion(next)
+
+	if dir, ok := history.dirs[next.track+"/"+next.exercise.Slug]; ok {
+		fmt.Fprintf(Err, "\nYou've already downloaded it to\n")
+		fmt.Fprintf(Out, "%s\n", dir)
+		return nil
+	}
+
+	if ask && !confirm("\nDownload it now? [Y/n] ") {
+		fmt.Fprintf(Err, "\nTo download it later, run\n\n    %s download --track=%s --exercise=%s\n\n", BinaryName, next.track, next.exercise.Slug)
+		return nil
+	}
+
+	downloadFlags := pflag.NewFlagSet("download", pflag.ContinueOnError)
+	setupDownloadFlags(downloadFlags)
+	downloadFlags.Set("track", next.track)
+	downloadFlags.Set("exercise", next.exercise.Slug)
+	return runDownload(cfg, downloadFlags, []string{})
+}
+
+func printSuggestion(next suggestion) {
+	details := (&workspace.ExerciseMetadata{Type: next.exercise.Type, Difficulty: next.exercise.Difficulty}).Details()
+	fmt.Fprintf(Err, "\nHow about %s in %s", next.exercise.Slug, next.track)
+	if details != "" {
+		fmt.Fprintf(Err, " (%s)", details)
+	}
+	fmt.Fprintln(Err, "?")
+	if next.exercise.Blurb != "" {
+		fmt.Fprintf(Err, "    %s\n", next.exercise.Blurb)
+	}
+	if len(next.exercise.Topics) > 0 {
+		fmt.Fprintf(Err, "    Topics: %s\n", strings.Join(next.exercise.Topics, ", "))
+	}
+}
+
+func setupNextFlags(flags *pflag.FlagSet) {
+	flags.StringP("track", "t", "", "the track to suggest an exercise from")
+	flags.StringP("difficulty", "d", "", "only suggest exercises of this difficulty (easy, medium, hard)")
+	flags.BoolP("yes", "y", false, "download the suggested exercise without asking")
+	flags.Bool("syllabus", false, "download the exercise the website recommends next rather than one at random")
+}
+
+func init() {
+	RootCmd.AddCommand(nextCmd)
+	setupNextFlags(nextCmd.Flags())
+}
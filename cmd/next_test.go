@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/exercism/cli/config"
+	"github.com/exercism/cli/workspace"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPracticeHistoryWeight(t *testing.T) {
+	now := time.Now()
+	history := practiceHistory{
+		topics: map[string]time.Time{
+			"strings": now.Add(-24 * time.Hour),
+			"loops":   now.Add(-10 * 24 * time.Hour),
+			"time":    now.Add(-90 * 24 * time.Hour),
+		},
+	}
+
+	recent := history.weight(catalogExercise{Topics: []string{"strings"}}, now)
+	older := history.weight(catalogExercise{Topics: []string{"loops"}}, now)
+	stale := history.weight(catalogExercise{Topics: []string{"time"}}, now)
+	never := history.weight(catalogExercise{Topics: []string{"trees"}}, now)
+
+	assert.InDelta(t, 2, recent, 0.01)
+	assert.InDelta(t, 11, older, 0.01)
+	assert.InDelta(t, 31, stale, 0.01)
+	assert.Equal(t, stale, never)
+	assert.Equal(t, float64(1), history.weight(catalogExercise{}, now))
+}
+
+func TestPickSuggestionFavoursUnpracticedTopics(t *testing.T) {
+	now := time.Now()
+	history := practiceHistory{
+		topics: map[string]time.Time{"strings": now},
+	}
+	candidates := []suggestion{
+		{track: "go", exercise: catalogExercise{Slug: "two-fer", Topics: []string{"strings"}}},
+		{track: "go", exercise: catalogExercise{Slug: "zipper", Topics: []string{"trees"}}},
+	}
+
+	r := rand.New(rand.NewSource(1))
+	picks := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		picks[pickSuggestion(candidates, history, now, r).exercise.Slug]++
+	}
+	assert.True(t, picks["zipper"] > 900, "expected zipper to be favoured, got %v", picks)
+}
+
+func TestNext(t *testing.T) {
+	ts := fakeCatalogServer()
+	defer ts.Close()
+
+	oldIn := In
+	defer func() { In = oldIn }()
+
+	wsDir, err := ioutil.TempDir("", "next-workspace")
+	assert.NoError(t, err)
+	defer os.RemoveAll(wsDir)
+
+	stateDir, err := ioutil.TempDir("", "next-state")
+	assert.NoError(t, err)
+	defer os.RemoveAll(stateDir)
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", wsDir)
+	v.Set("apibaseurl", ts.URL)
+	cfg := config.Config{UserViperConfig: v, StateDir: stateDir}
+
+	run := func(answer string) (string, string, error) {
+		co := newCapturedOutput()
+		co.newOut = &bytes.Buffer{}
+		co.newErr = &bytes.Buffer{}
+		co.override()
+		defer co.reset()
+
+		In = strings.NewReader(answer)
+		flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+		setupNextFlags(flags)
+		flags.Set("track", "go")
+		flags.Set("difficulty", "medium")
+		err := runNext(cfg, flags, []string{})
+		return Out.(*bytes.Buffer).String(), Err.(*bytes.Buffer).String(), err
+	}
+
+	// Of the medium exercises, clock is in progress and anagram is available.
+	_, stderr, err := run("n\n")
+	assert.NoError(t, err)
+	assert.Regexp(t, `How about (clock|anagram) in go \(practice, medium\)\?`, stderr)
+	assert.Regexp(t, "download --track=go --exercise=(clock|anagram)", stderr)
+
+	for _, slug := range []string{"clock", "anagram"} {
+		dir := filepath.Join(wsDir, "go", slug)
+		assert.NoError(t, os.MkdirAll(dir, os.FileMode(0755)))
+		metadata := workspace.ExerciseMetadata{Track: "go", ExerciseSlug: slug}
+		assert.NoError(t, metadata.Write(dir))
+	}
+
+	stdout, stderr, err := run("")
+	assert.NoError(t, err)
+	assert.Regexp(t, "already downloaded", stderr)
+	assert.Regexp(t, filepath.Join(wsDir, "go"), stdout)
+}
+
+func TestNextWithoutTrack(t *testing.T) {
+	wsDir, err := ioutil.TempDir("", "next-workspace")
+	assert.NoError(t, err)
+	defer os.RemoveAll(wsDir)
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", wsDir)
+	v.Set("apibaseurl", "http://example.com")
+	cfg := config.Config{UserViperConfig: v}
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupNextFlags(flags)
+	err = runNext(cfg, flags, []string{})
+	if assert.Error(t, err) {
+		assert.Regexp(t, "need a --track", err.Error())
+	}
+}
+
+func TestNextBySyllabus(t *testing.T) {
+	concepts := `{"concepts": [
+		{"slug": "basics", "status": "completed", "exercises": ["lasagna"]},
+		{"slug": "strings", "status": "in_progress", "prerequisites": ["basics"], "exercises": ["party-robot"]},
+		{"slug": "numbers", "status": "available", "prerequisites": ["basics"], "exercises": ["cars-assemble"]}
+	]}`
+	exercises := `{"exercises": [
+		{"slug": "lasagna", "type": "concept", "status": "completed"},
+		{"slug": "party-robot", "type": "concept", "status": "in_progress"},
+		{"slug": "cars-assemble", "type": "concept", "status": "available"},
+		{"slug": "hello-world", "type": "practice", "status": "completed"},
+		{"slug": "leap", "type": "practice", "status": "available"}
+	]}`
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tracks/go/concepts", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, concepts)
+	})
+	mux.HandleFunc("/tracks/go/exercises", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, exercises)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	wsDir, err := ioutil.TempDir("", "next-workspace")
+	assert.NoError(t, err)
+	defer os.RemoveAll(wsDir)
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", wsDir)
+	v.Set("apibaseurl", ts.URL)
+	cfg := config.Config{UserViperConfig: v}
+
+	next, err := syllabusSuggestion(cfg, "go", exerciseFilter{})
+	assert.NoError(t, err)
+	if assert.NotNil(t, next) {
+		assert.Equal(t, "party-robot", next.exercise.Slug)
+	}
+
+	// Once the concepts are done, it's on to the practice exercises.
+	concepts = `{"concepts": [{"slug": "basics", "status": "completed", "exercises": ["lasagna"]}]}`
+	next, err = syllabusSuggestion(cfg, "go", exerciseFilter{})
+	assert.NoError(t, err)
+	if assert.NotNil(t, next) {
+		assert.Equal(t, "leap", next.exercise.Slug)
+	}
+
+	exercises = `{"exercises": [{"slug": "lasagna", "type": "concept", "status": "completed"}]}`
+	next, err = syllabusSuggestion(cfg, "go", exerciseFilter{})
+	assert.NoError(t, err)
+	assert.Nil(t, next)
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupNextFlags(flags)
+	flags.Set("syllabus", "true")
+	err = runNext(cfg, flags, []string{})
+	if assert.Error(t, err) {
+		assert.Regexp(t, "need a --track", err.Error())
+	}
+}
+
+func TestNextRecommendedByAPI(t *testing.T) {
+	co := newCapturedOutput()
+	co.newOut = &bytes.Buffer{}
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	oldIn := In
+	defer func() { In = oldIn }()
+	// Nothing is read from the input, since it doesn't ask.
+	In = strings.NewReader("n\n")
+
+	var difficulty string
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	mux.HandleFunc("/tracks/bogus-track/exercises/next", func(w http.ResponseWriter, r *http.Request) {
+		difficulty = r.URL.Query().Get("difficulty")
+		fmt.Fprint(w, `{"exercise": {"slug": "bogus-exercise", "type": "practice", "difficulty": "easy"}}`)
+	})
+	mux.HandleFunc("/solutions/latest", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "bogus-exercise", r.FormValue("exercise_id"))
+		fmt.Fprintf(w, payloadTemplate, "true", "null", ts.URL+"/")
+	})
+	for _, path := range []string{"/file-1.txt", "/subdir/file-2.txt", "/file-3.txt"} {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "this is a file")
+		})
+	}
+
+	wsDir, err := ioutil.TempDir("", "next-workspace")
+	assert.NoError(t, err)
+	defer os.RemoveAll(wsDir)
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", wsDir)
+	v.Set("apibaseurl", ts.URL)
+	cfg := config.Config{UserViperConfig: v}
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupNextFlags(flags)
+	flags.Set("track", "bogus-track")
+	flags.Set("difficulty", "easy")
+	flags.Set("syllabus", "true")
+	assert.NoError(t, runNext(cfg, flags, []string{}))
+
+	assert.Equal(t, "easy", difficulty)
+	assert.Regexp(t, "How about bogus-exercise in bogus-track", Err.(*bytes.Buffer).String())
+	assert.NotRegexp(t, "Download it now", Err.(*bytes.Buffer).String())
+	dir := filepath.Join(wsDir, "bogus-track", "bogus-exercise")
+	assert.Regexp(t, regexp.QuoteMeta(dir), Out.(*bytes.Buffer).String())
+	_, err = os.Stat(filepath.Join(dir, "file-1.txt"))
+	assert.NoError(t, err)
+
+	// It's nil when there's nothing left to do.
+	mux.HandleFunc("/tracks/other-track/exercises/next", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"exercise": null}`)
+	})
+	next, err := syllabusSuggestion(cfg, "other-track", exerciseFilter{})
+	assert.NoError(t, err)
+	assert.Nil(t, next)
+}
@@ -2,8 +2,12 @@ package cmd
 
 import (
 	"github.com/exercism/cli/browser"
+	"github.com/exercism/cli/config"
+	"github.com/exercism/cli/editor"
 	"github.com/exercism/cli/workspace"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
 )
 
 // openCmd opens the designated exercise in the browser.
@@ -14,17 +18,63 @@ var openCmd = &cobra.Command{
 	Long: `Open the specified exercise to the solution page on the Exercism website.
 
 Pass the path to the directory that contains the solution you want to see on the website.
+
+With --editor the exercise is opened in your editor instead. The editor is
+taken from the editor.command setting in your user config, then $VISUAL or
+$EDITOR, and otherwise the first known editor that is installed
+(VS Code, Sublime Text, JetBrains IDEs, Neovim, Vim, Emacs, nano).
+
+The editor.command setting is a template, for example:
+
+    code -g {path}:{line}
 	`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		metadata, err := workspace.NewExerciseMetadata(args[0])
+		cfg := config.NewConfig()
+
+		v := viper.New()
+		v.AddConfigPath(cfg.Dir)
+		v.SetConfigName("user")
+		v.SetConfigType("json")
+		// Ignore error. If the file doesn't exist, that is fine.
+		_ = v.ReadInConfig()
+		config.BindUserEnv(v)
+		cfg.UserViperConfig = v
+
+		return runOpen(cfg, cmd.Flags(), args)
+	},
+}
+
+func runOpen(cfg config.Config, flags *pflag.FlagSet, args []string) error {
+	inEditor, err := flags.GetBool("editor")
+	if err != nil {
+		return err
+	}
+	if inEditor {
+		line, err := flags.GetInt("line")
 		if err != nil {
 			return err
 		}
-		return browser.Open(metadata.URL)
-	},
+		template, err := editor.Resolve(cfg.UserViperConfig.GetString("editor.command"))
+		if err != nil {
+			return err
+		}
+		return editor.Open(template, args[0], line)
+	}
+
+	metadata, err := workspace.NewExerciseMetadata(args[0])
+	if err != nil {
+		return err
+	}
+	return browser.Open(metadata.URL)
+}
+
+func setupOpenFlags(flags *pflag.FlagSet) {
+	flags.BoolP("editor", "e", false, "open the exercise directory or file in your editor")
+	flags.IntP("line", "l", 0, "the line to open the file at, when using --editor")
 }
 
 func init() {
 	RootCmd.AddCommand(openCmd)
+	setupOpenFlags(openCmd.Flags())
 }
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/exercism/cli/config"
+	"github.com/exercism/cli/workspace"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// outdatedCmd lists the exercises that the tracks have changed since they were downloaded.
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "List the exercises the tracks have changed since you downloaded them.",
+	Long: `List the exercises in your workspace that the tracks have changed since you
+downloaded them, such as their tests or instructions, with the files that changed.
+
+Each exercise is fetched again and compared with the files as they were when
+they were downloaded, so the changes you have made yourself don't count.
+Pass --track to only check the exercises of one track.
+
+Bring an exercise up to date with 'exercism update PATH'.
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.NewConfig()
+
+		v := viper.New()
+		v.AddConfigPath(cfg.Dir)
+		v.SetConfigName("user")
+		v.SetConfigType("json")
+		// Ignore error. If the file doesn't exist, that is fine.
+		_ = v.ReadInConfig()
+		config.BindUserEnv(v)
+		cfg.UserViperConfig = v
+
+		return runOutdated(cfg, cmd.Flags())
+	},
+}
+
+// outdatedExercise is an exercise with the files the track has changed since it was downloaded.
+type outdatedExercise struct {
+	metadata *workspace.ExerciseMetadata
+	changed  []string
+}
+
+func runOutdated(cfg config.Config, flags *pflag.FlagSet) error {
+	usrCfg := cfg.UserViperConfig
+	if err := validateUserConfig(usrCfg); err != nil {
+		return err
+	}
+	track, err := flags.GetString("track")
+	if err != nil {
+		return err
+	}
+	jobs, err := jobsFromFlags(flags)
+	if err != nil {
+		return err
+	}
+
+	ws, err := openWorkspace(usrCfg)
+	if err != nil {
+		return err
+	}
+	all, err := ws.Exercises()
+	if err != nil {
+		return err
+	}
+	var exercises []workspace.Exercise
+	for _, exercise := range all {
+		if track == "" || exercise.Track == track {
+			exercises = append(exercises, exercise)
+		}
+	}
+	if len(exercises) == 0 {
+		if track != "" {
+			fmt.Fprintf(Err, "There are no exercises of the %s track in your workspace.\n", track)
+		} else {
+			fmt.Fprintln(Err, "There are no exercises in your workspace.")
+		}
+		return nil
+	}
+
+	fmt.Fprintf(Err, "Checking %d exercise(s)...\n", len(exercises))
+	limit := newJobLimit(jobs)
+	results := make([]outdatedExercise, len(exercises))
+	errs := each(len(exercises), func(i int) error {
+		dir := exercises[i].MetadataDir()
+		metadata, err := workspace.NewExerciseMetadata(dir)
+		if err != nil {
+			return err
+		}
+		results[i].metadata = metadata
+		changed, err := checkOutdated(cfg, dir, metadata, limit)
+		if err != nil {
+			return err
+		}
+		results[i].changed = changed
+		return nil
+	})
+
+	var outdated []outdatedExercise
+	var failed []string
+	for i, result := range results {
+		if errs[i] != nil {
+			fmt.Fprintf(Err, "%s Unable to check %s: %s\n", glyphFailed, exercises[i].MetadataDir(), errs[i])
+			failed = append(failed, exercises[i].MetadataDir())
+			continue
+		}
+		if len(result.changed) > 0 {
+			outdated = append(outdated, result)
+		}
+	}
+
+	if len(outdated) == 0 {
+		fmt.Fprintln(Err, "All your exercises are up to date.")
+	} else {
+		sort.SliceStable(outdated, func(i, j int) bool {
+			if outdated[i].metadata.Track != outdated[j].metadata.Track {
+				return outdated[i].metadata.Track < outdated[j].metadata.Track
+			}
+			return outdated[i].metadata.ExerciseSlug < outdated[j].metadata.ExerciseSlug
+		})
+		w := tabwriter.NewWriter(Out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TRACK\tEXERCISE\tCHANGED\tPATH")
+		for _, exercise := range outdated {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+				exercise.metadata.Track,
+				exercise.metadata.ExerciseSlug,
+				strings.Join(exercise.changed, ", "),
+				exercise.metadata.Dir,
+			)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprintf(Err, "\n%d exercise(s) are outdated. Bring one up to date with '%s update PATH'.\n", len(outdated), BinaryName)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("unable to check %d of %d exercises", len(failed), len(exercises))
+	}
+	return nil
+}
+
+// checkOutdated fetches the exercise in the given directory again
+// and returns the files that have changed since it was downloaded.
+func checkOutdated(cfg config.Config, dir string, metadata *workspace.ExerciseMetadata, limit jobLimit) ([]string, error) {
+	d, err := newDownloadForExercise(cfg, metadata)
+	if err != nil {
+		return nil, err
+	}
+	d.limit = limit
+	// Nothing is written, so there's nothing to resume.
+	d.stateDir = ""
+	if err := limit.do(d.requestPayload); err != nil {
+		return nil, err
+	}
+	files, err := d.fetchFiles()
+	if err != nil {
+		return nil, err
+	}
+	d.convertLineEndings(files)
+	return changedUpstream(dir, files)
+}
+
+// changedUpstream returns the files that differ from the ones that were downloaded to the exercise directory.
+// It goes by the checksums of the files as they were downloaded, or else by their originals,
+// or else, for exercises downloaded before either was kept, by the files themselves.
+func changedUpstream(dir string, files []downloadedFile) ([]string, error) {
+	checksums, err := workspace.NewChecksums(dir)
+	if err != nil {
+		checksums = workspace.Checksums{}
+	}
+	var changed []string
+	for _, file := range files {
+		if _, ok := checksums[filepath.ToSlash(file.path)]; ok {
+			if checksums.IsModified(file.path, file.contents) {
+				changed = append(changed, filepath.ToSlash(file.path))
+			}
+			continue
+		}
+		downloaded, err := workspace.ReadOriginal(dir, file.path)
+		if os.IsNotExist(err) {
+			downloaded, err = ioutil.ReadFile(workspace.LongPath(filepath.Join(dir, file.path)))
+		}
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if err != nil || !bytes.Equal(downloaded, file.contents) {
+			changed = append(changed, filepath.ToSlash(file.path))
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+func setupOutdatedFlags(flags *pflag.FlagSet) {
+	flags.StringP("track", "t", "", "only check the exercises of this track")
+	flags.IntP("jobs", "j", defaultJobs(), "how many exercises to check at once")
+}
+
+func init() {
+	RootCmd.AddCommand(outdatedCmd)
+	setupOutdatedFlags(outdatedCmd.Flags())
+}
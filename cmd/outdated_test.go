@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/exercism/cli/config"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutdated(t *testing.T) {
+	co := newCapturedOutput()
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	co.newOut, co.newErr = stdout, stderr
+	co.override()
+	defer co.reset()
+
+	tmpDir, err := ioutil.TempDir("", "outdated")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	versions := map[string]string{"leap": "1", "clock": "1"}
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	mux.HandleFunc("/solutions/latest", func(w http.ResponseWriter, r *http.Request) {
+		slug := r.FormValue("exercise_id")
+		fmt.Fprintf(w, `{"solution": {"id": "%[1]s-id", "user": {"handle": "alice", "is_requester": true}, "exercise": {"id": "%[1]s", "track": {"id": "%[2]s"}}, "file_download_base_url": "%[3]s/%[1]s/", "files": ["solution.txt", "test.txt"]}}`, slug, r.FormValue("track_id"), ts.URL)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		slug := filepath.Base(filepath.Dir(r.URL.Path))
+		if filepath.Base(r.URL.Path) == "test.txt" {
+			fmt.Fprintf(w, "tests version %s", versions[slug])
+			return
+		}
+		fmt.Fprint(w, "the stub")
+	})
+
+	v := viper.New()
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	v.Set("token", "abc123")
+	cfg := config.Config{
+		UserViperConfig: v,
+	}
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupDownloadFlags(flags)
+	flags.Set("track", "bogus-track")
+	flags.Set("exercise", "leap,clock")
+	assert.NoError(t, runDownload(cfg, flags, []string{}))
+	stdout.Reset()
+	stderr.Reset()
+
+	// Changes made locally don't count.
+	path := filepath.Join(tmpDir, "bogus-track", "clock", "solution.txt")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("my solution"), os.FileMode(0644)))
+
+	flags = pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupOutdatedFlags(flags)
+	assert.NoError(t, runOutdated(cfg, flags))
+	assert.Equal(t, "", stdout.String())
+	assert.Regexp(t, "All your exercises are up to date", stderr.String())
+
+	versions["leap"] = "2"
+	assert.NoError(t, runOutdated(cfg, flags))
+	assert.Regexp(t, `bogus-track\s+leap\s+test.txt\s+`, stdout.String())
+	assert.NotRegexp(t, "clock", stdout.String())
+
+	stdout.Reset()
+	flags.Set("track", "other-track")
+	assert.NoError(t, runOutdated(cfg, flags))
+	assert.Equal(t, "", stdout.String())
+	assert.Regexp(t, "no exercises of the other-track track", stderr.String())
+}
+
+func TestChangedUpstream(t *testing.T) {
+	dir, err := ioutil.TempDir("", "changed-upstream")
+	defer os.RemoveAll(dir)
+	assert.NoError(t, err)
+
+	// Downloaded before checksums or originals were kept.
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "same.txt"), []byte("same"), os.FileMode(0644)))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "differs.txt"), []byte("old"), os.FileMode(0644)))
+
+	changed, err := changedUpstream(dir, []downloadedFile{
+		{path: "same.txt", contents: []byte("same")},
+		{path: "differs.txt", contents: []byte("new")},
+		{path: "added.txt", contents: []byte("new")},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"added.txt", "differs.txt"}, changed)
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// errNothingPicked is returned when the choice is abandoned.
+var errNothingPicked = errors.New("nothing was picked")
+
+// pickerItem is one of the choices offered by a picker.
+type pickerItem struct {
+	// value is what picking the item gives.
+	value string
+	// label is how the item is shown, and what is searched.
+	label string
+}
+
+// pick offers a list of items to choose from. The answer is either the number of an item,
+// or some text that narrows the list down to the items it fuzzily matches, until one is left.
+// An empty answer takes the first of the items listed.
+func pick(title string, items []pickerItem) (string, error) {
+	if len(items) == 0 {
+		return "", errNothingPicked
+	}
+	shown := items
+	for {
+		fmt.Fprintf(Err, "\n%s\n\n", title)
+		for i, item := range shown {
+			fmt.Fprintf(Err, "  %2d  %s\n", i+1, item.label)
+		}
+		answer, err := prompt("\nType to search, or pick a number: ")
+		if err != nil {
+			return "", errNothingPicked
+		}
+		if answer == "" {
+			return shown[0].value, nil
+		}
+		if n, err := strconv.Atoi(answer); err == nil {
+			if n >= 1 && n <= len(shown) {
+				return shown[n-1].value, nil
+			}
+			fmt.Fprintf(Err, "There's no %d in the list.\n", n)
+			continue
+		}
+
+		matches := fuzzyFilter(answer, items)
+		switch len(matches) {
+		case 0:
+			fmt.Fprintf(Err, "Nothing matches %q.\n", answer)
+			shown = items
+		case 1:
+			return matches[0].value, nil
+		default:
+			shown = matches
+		}
+	}
+}
+
+// fuzzyFilter returns the items whose labels contain the letters of the query in order,
+// best matches first.
+func fuzzyFilter(query string, items []pickerItem) []pickerItem {
+	type match struct {
+		item  pickerItem
+		score int
+	}
+	var matches []match
+	for _, item := range items {
+		if score, ok := fuzzyScore(query, item.label); ok {
+			matches = append(matches, match{item, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score < matches[j].score
+	})
+	filtered := make([]pickerItem, len(matches))
+	for i, m := range matches {
+		filtered[i] = m.item
+	}
+	return filtered
+}
+
+// fuzzyScore tells whether the letters of the query appear in the text in order, ignoring case,
+// and how loosely: the fewer letters skipped between them, and the earlier they start, the lower the score.
+func fuzzyScore(query, text string) (int, bool) {
+	query = strings.ToLower(strings.Replace(query, " ", "", -1))
+	text = strings.ToLower(text)
+	if query == "" {
+		return 0, true
+	}
+
+	// Text that contains the query as it is beats any scattered match.
+	if i := strings.Index(text, query); i >= 0 {
+		return utf8.RuneCountInString(text[:i]), true
+	}
+	score, start, skipped := len(text), -1, 0
+	q := []rune(query)
+	for i, r := range []rune(text) {
+		if len(q) == 0 {
+			break
+		}
+		if r != q[0] {
+			if start >= 0 {
+				skipped++
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+		q = q[1:]
+	}
+	if len(q) > 0 {
+		return 0, false
+	}
+	return score + start + skipped, true
+}
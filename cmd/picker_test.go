@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/exercism/cli/config"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuzzyFilter(t *testing.T) {
+	items := []pickerItem{
+		{value: "linked-list", label: "linked-list"},
+		{value: "simple-linked-list", label: "simple-linked-list"},
+		{value: "leap", label: "leap"},
+		{value: "list-ops", label: "list-ops"},
+	}
+	values := func(items []pickerItem) []string {
+		var v []string
+		for _, item := range items {
+			v = append(v, item.value)
+		}
+		return v
+	}
+
+	assert.Equal(t, []string{"linked-list", "simple-linked-list"}, values(fuzzyFilter("linked", items)))
+	// Scattered letters match too, after the ones that match as they are.
+	assert.Equal(t, []string{"list-ops", "linked-list", "simple-linked-list"}, values(fuzzyFilter("lst", items)))
+	assert.Equal(t, []string{"leap", "list-ops"}, values(fuzzyFilter("LP", items)))
+	assert.Empty(t, fuzzyFilter("xyz", items))
+}
+
+func TestPick(t *testing.T) {
+	co := newCapturedOutput()
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+	oldIn := In
+	defer func() { In = oldIn }()
+
+	items := []pickerItem{
+		{value: "two-fer", label: "two-fer"},
+		{value: "leap", label: "leap"},
+		{value: "linked-list", label: "linked-list"},
+	}
+	testCases := []struct {
+		desc, answers, expected string
+	}{
+		{"by number", "2\n", "leap"},
+		{"by the only match", "tw\n", "two-fer"},
+		{"by narrowing down, then the number", "l\n2\n", "linked-list"},
+		{"after a number out of range", "7\n1\n", "two-fer"},
+		{"after nothing matched", "xyz\nleap\n", "leap"},
+		{"the first, by default", "\n", "two-fer"},
+	}
+	for _, tc := range testCases {
+		In = strings.NewReader(tc.answers)
+		picked, err := pick("Which exercise?", items)
+		assert.NoError(t, err, tc.desc)
+		assert.Equal(t, tc.expected, picked, tc.desc)
+	}
+
+	In = strings.NewReader("")
+	_, err := pick("Which exercise?", items)
+	assert.Equal(t, errNothingPicked, err)
+}
+
+func TestDownloadPicksExercise(t *testing.T) {
+	co := newCapturedOutput()
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+	oldIn := In
+	oldInteractive := isInteractive
+	defer func() {
+		In = oldIn
+		isInteractive = oldInteractive
+	}()
+	isInteractive = func() bool { return true }
+
+	tmpDir, err := ioutil.TempDir("", "download-pick")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	mux.HandleFunc("/tracks/go/exercises", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"exercises": [{"slug": "hello-world", "status": "completed"}, {"slug": "zipper", "status": "locked"}, {"slug": "leap", "status": "available"}]}`)
+	})
+	var requested string
+	mux.HandleFunc("/solutions/latest", func(w http.ResponseWriter, r *http.Request) {
+		requested = r.FormValue("track_id") + "/" + r.FormValue("exercise_id")
+		fmt.Fprintf(w, `{"solution": {"id": "bogus-id", "user": {"handle": "alice", "is_requester": true}, "exercise": {"id": "leap", "track": {"id": "go"}}, "file_download_base_url": "%s/", "files": []}}`, ts.URL)
+	})
+
+	v := viper.New()
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	v.Set("token", "abc123")
+	cfg := config.Config{UserViperConfig: v}
+
+	// There are no tracks in the workspace to choose from, so the track is asked for.
+	In = strings.NewReader("go\nlea\n")
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupDownloadFlags(flags)
+	assert.NoError(t, runDownload(cfg, flags, []string{}))
+	assert.Equal(t, "go/leap", requested)
+	assert.NotRegexp(t, "zipper", Err.(*bytes.Buffer).String())
+	_, err = os.Stat(filepath.Join(tmpDir, "go", "leap"))
+	assert.NoError(t, err)
+
+	flags = pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupDownloadFlags(flags)
+	flags.Set("no-input", "true")
+	err = runDownload(cfg, flags, []string{})
+	if assert.Error(t, err) {
+		assert.Regexp(t, "need an --exercise name or a solution --uuid", err.Error())
+	}
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/exercism/cli/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// defaultPrefetchCount is how many of the next exercises are fetched ahead of time.
+const defaultPrefetchCount = 2
+
+// prefetchCmd fetches the files of the next exercises of a track ahead of time.
+// It's what submit --prefetch runs in the background, so it isn't listed.
+var prefetchCmd = &cobra.Command{
+	Use:    "prefetch",
+	Short:  "Fetch the next exercises of a track ahead of time.",
+	Hidden: true,
+	Long: `Fetch the files of the next unlocked exercises of a track that you haven't
+downloaded yet, without writing them to the workspace. Downloading one of
+them then takes the files that were fetched instead of fetching them again.
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.NewConfig()
+
+		v := viper.New()
+		v.AddConfigPath(cfg.Dir)
+		v.SetConfigName("user")
+		v.SetConfigType("json")
+		// Ignore error. If the file doesn't exist, that is fine.
+		_ = v.ReadInConfig()
+		config.BindUserEnv(v)
+		cfg.UserViperConfig = v
+
+		return runPrefetch(cfg, cmd.Flags())
+	},
+}
+
+func runPrefetch(cfg config.Config, flags *pflag.FlagSet) error {
+	usrCfg := cfg.UserViperConfig
+	if err := validateUserConfig(usrCfg); err != nil {
+		return err
+	}
+	if cfg.StateDir == "" {
+		return errors.New("there's nowhere to keep the fetched files")
+	}
+	track, err := flags.GetString("track")
+	if err != nil {
+		return err
+	}
+	if track == "" {
+		return errors.New("need a --track to fetch the next exercises of")
+	}
+	skip, err := flags.GetString("skip")
+	if err != nil {
+		return err
+	}
+	count, err := flags.GetInt("count")
+	if err != nil {
+		return err
+	}
+
+	slugs, err := nextExercises(cfg, track, skip, count)
+	if err != nil || len(slugs) == 0 {
+		return err
+	}
+
+	downloadFlags := pflag.NewFlagSet("download", pflag.ContinueOnError)
+	setupDownloadFlags(downloadFlags)
+	downloadFlags.Set("track", track)
+	base, err := newDownloadForSlugs(downloadFlags, usrCfg, slugs)
+	if err != nil {
+		return err
+	}
+	base.stateDir = cfg.StateDir
+	base.prefetching = true
+
+	errs := each(len(slugs), func(i int) error {
+		d := *base
+		d.slug = slugs[i]
+		if err := d.limit.do(d.requestPayload); err != nil {
+			return err
+		}
+		_, err := d.fetchFiles()
+		return err
+	})
+	for i, slug := range slugs {
+		if errs[i] != nil {
+			fmt.Fprintf(Err, "%s Unable to fetch %s: %s\n", glyphFailed, slug, errs[i])
+			continue
+		}
+		fmt.Fprintf(Err, "%s Fetched %s\n", glyphCompleted, slug)
+	}
+	return nil
+}
+
+// nextExercises are the first unlocked exercises of the track, in the order of the track,
+// that haven't been completed or downloaded.
+func nextExercises(cfg config.Config, track, skip string, count int) ([]string, error) {
+	ws, err := openWorkspace(cfg.UserViperConfig)
+	if err != nil {
+		return nil, err
+	}
+	history := practiceHistory{topics: map[string]time.Time{}, dirs: map[string]string{}}
+	if _, err := os.Stat(ws.Dir); err == nil {
+		if history, err = newPracticeHistory(ws); err != nil {
+			return nil, err
+		}
+	}
+	c, err := loadExercisesCatalog(cfg, track)
+	if err != nil {
+		return nil, err
+	}
+
+	var slugs []string
+	for _, exercise := range c.Exercises {
+		if len(slugs) == count {
+			break
+		}
+		if exercise.Slug == skip || !isUnfinished(exercise) {
+			continue
+		}
+		if _, ok := history.dirs[track+"/"+exercise.Slug]; ok {
+			continue
+		}
+		slugs = append(slugs, exercise.Slug)
+	}
+	return slugs, nil
+}
+
+// startPrefetch fetches the next exercises of the track in a process of its own,
+// which carries on once the command that started it is done. It's swapped out in tests.
+var startPrefetch = func(track, skip string) error {
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(self, prefetchArgs(track, skip)...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// Nothing waits for it.
+	return cmd.Process.Release()
+}
+
+// prefetchArgs are the arguments that startPrefetch runs the CLI with.
+// The prefetch uses the same config as the command that started it.
+func prefetchArgs(track, skip string) []string {
+	args := []string{"prefetch", "--track", track, "--skip", skip, "--count", strconv.Itoa(defaultPrefetchCount)}
+	if config.DirOverride != "" {
+		args = append(args, "--config-dir", config.DirOverride)
+	}
+	return args
+}
+
+func setupPrefetchFlags(flags *pflag.FlagSet) {
+	flags.StringP("track", "t", "", "the track ID")
+	flags.String("skip", "", "an exercise not to fetch, such as the one just submitted")
+	flags.Int("count", defaultPrefetchCount, "how many exercises to fetch")
+}
+
+func init() {
+	RootCmd.AddCommand(prefetchCmd)
+	setupPrefetchFlags(prefetchCmd.Flags())
+}
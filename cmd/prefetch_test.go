@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/exercism/cli/config"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefetch(t *testing.T) {
+	co := newCapturedOutput()
+	stderr := &bytes.Buffer{}
+	co.newErr = stderr
+	co.override()
+	defer co.reset()
+
+	tmpDir, err := ioutil.TempDir("", "prefetch")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	mux.HandleFunc("/tracks/bogus-track/exercises", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"exercises": [{"slug": "hello-world", "status": "completed"}, {"slug": "two-fer", "status": "available"}, {"slug": "leap", "status": "available"}, {"slug": "bob", "status": "available"}, {"slug": "clock", "status": "available"}]}`)
+	})
+	var mu sync.Mutex
+	var fetched []string
+	mux.HandleFunc("/solutions/latest", func(w http.ResponseWriter, r *http.Request) {
+		slug := r.FormValue("exercise_id")
+		fmt.Fprintf(w, `{"solution": {"id": "%[1]s-id", "user": {"handle": "alice", "is_requester": true}, "exercise": {"id": "%[1]s", "track": {"id": "bogus-track"}}, "file_download_base_url": "%[2]s/%[1]s/", "files": ["README.md"]}}`, slug, ts.URL)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		fetched = append(fetched, r.URL.Path)
+		mu.Unlock()
+		fmt.Fprintf(w, "fetched %s", r.URL.Path)
+	})
+
+	v := viper.New()
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	v.Set("token", "abc123")
+	cfg := config.Config{
+		UserViperConfig: v,
+		StateDir:        filepath.Join(tmpDir, "state"),
+	}
+	assert.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "bogus-track", "leap"), os.FileMode(0755)))
+	writeFakeMetadata(t, filepath.Join(tmpDir, "bogus-track", "leap"), "bogus-track", "leap")
+
+	// The exercise just submitted, and the ones completed or downloaded already, are passed over.
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupPrefetchFlags(flags)
+	flags.Set("track", "bogus-track")
+	flags.Set("skip", "two-fer")
+	assert.NoError(t, runPrefetch(cfg, flags))
+	sort.Strings(fetched)
+	assert.Equal(t, []string{"/bob/README.md", "/clock/README.md"}, fetched)
+
+	_, err = os.Stat(filepath.Join(tmpDir, "bogus-track", "bob"))
+	assert.True(t, os.IsNotExist(err), "It shouldn't write anything to the workspace.")
+
+	// Downloading a prefetched exercise takes the files that were fetched.
+	fetched = nil
+	stderr.Reset()
+	flags = pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupDownloadFlags(flags)
+	flags.Set("track", "bogus-track")
+	flags.Set("exercise", "bob")
+	assert.NoError(t, runDownload(cfg, flags, []string{}))
+	assert.Empty(t, fetched)
+	assert.Regexp(t, "Using the 1 file\\(s\\) of bob that were fetched ahead of time", stderr.String())
+
+	b, err := ioutil.ReadFile(filepath.Join(tmpDir, "bogus-track", "bob", "README.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, "fetched /bob/README.md", string(b))
+}
+
+func TestPrefetchArgs(t *testing.T) {
+	defer func() { config.DirOverride = "" }()
+
+	config.DirOverride = ""
+	assert.Equal(t, []string{"prefetch", "--track", "go", "--skip", "leap", "--count", "2"}, prefetchArgs("go", "leap"))
+
+	config.DirOverride = "/tmp/sandbox"
+	assert.Equal(t, []string{"prefetch", "--track", "go", "--skip", "leap", "--count", "2", "--config-dir", "/tmp/sandbox"}, prefetchArgs("go", "leap"))
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/exercism/cli/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// progressCmd summarises how far the user has got in each track.
+var progressCmd = &cobra.Command{
+	Use:   "progress",
+	Short: "Show how much of each of your tracks you've completed.",
+	Long: `Show how much of each of the tracks you've joined you've completed, as a
+percentage of its exercises, with a bar to compare them at a glance.
+
+Pass --json to get the numbers in a machine-readable format.
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.NewConfig()
+
+		v := viper.New()
+		v.AddConfigPath(cfg.Dir)
+		v.SetConfigName("user")
+		v.SetConfigType("json")
+		// Ignore error. If the file doesn't exist, that is fine.
+		_ = v.ReadInConfig()
+		config.BindUserEnv(v)
+		cfg.UserViperConfig = v
+
+		return runProgress(cfg, cmd.Flags())
+	},
+}
+
+// trackProgress is how much of a track has been completed.
+type trackProgress struct {
+	Track     string `json:"track"`
+	Completed int    `json:"completed"`
+	Total     int    `json:"total"`
+	Percent   int    `json:"percent"`
+}
+
+func newTrackProgress(track trackSummary) trackProgress {
+	p := trackProgress{Track: track.Slug, Completed: track.NumCompletedExercises, Total: track.NumExercises}
+	if p.Total > 0 {
+		p.Percent = 100 * p.Completed / p.Total
+	}
+	return p
+}
+
+// bar shows the share completed, width characters wide.
+func (p trackProgress) bar(width int) string {
+	filled := width * p.Percent / 100
+	return strings.Repeat(glyphBarFull.String(), filled) + strings.Repeat(glyphBarEmpty.String(), width-filled)
+}
+
+func runProgress(cfg config.Config, flags *pflag.FlagSet) error {
+	usrCfg := cfg.UserViperConfig
+	if err := validateUserConfig(usrCfg); err != nil {
+		return err
+	}
+	asJSON, err := flags.GetBool("json")
+	if err != nil {
+		return err
+	}
+
+	tracks, err := fetchTracks(usrCfg.GetString("token"), usrCfg.GetString("apibaseurl"))
+	if err != nil {
+		return err
+	}
+	progress := []trackProgress{}
+	for _, track := range tracks {
+		if track.Joined {
+			progress = append(progress, newTrackProgress(track))
+		}
+	}
+	if asJSON {
+		return json.NewEncoder(Out).Encode(progress)
+	}
+	if len(progress) == 0 {
+		fmt.Fprintln(Err, "You haven't joined any tracks. Join one on the website.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(Out, 0, 0, 2, ' ', 0)
+	for _, p := range progress {
+		fmt.Fprintf(w, "%s\t[%s] %3d%%\t%d/%d\n", p.Track, p.bar(20), p.Percent, p.Completed, p.Total)
+	}
+	w.Flush()
+	return nil
+}
+
+func setupProgressFlags(flags *pflag.FlagSet) {
+	flags.Bool("json", false, "print the progress as JSON")
+}
+
+func init() {
+	RootCmd.AddCommand(progressCmd)
+	setupProgressFlags(progressCmd.Flags())
+}
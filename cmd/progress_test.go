@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/exercism/cli/config"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgress(t *testing.T) {
+	defer func() { plainASCII = false }()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tracks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, tracksPayload)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", "/home/alice/exercism")
+	v.Set("apibaseurl", ts.URL)
+	cfg := config.Config{UserViperConfig: v}
+
+	run := func(args ...string) string {
+		co := newCapturedOutput()
+		co.newOut = &bytes.Buffer{}
+		co.override()
+		defer co.reset()
+
+		flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+		setupProgressFlags(flags)
+		assert.NoError(t, flags.Parse(args))
+		assert.NoError(t, runProgress(cfg, flags))
+		return Out.(*bytes.Buffer).String()
+	}
+
+	plainASCII = true
+	expected := "go      [#-------------------]   8%  12/141\n" +
+		"python  [####################] 100%  137/137\n"
+	assert.Equal(t, expected, run())
+
+	var progress []trackProgress
+	assert.NoError(t, json.Unmarshal([]byte(run("--json")), &progress))
+	assert.Equal(t, []trackProgress{
+		{Track: "go", Completed: 12, Total: 141, Percent: 8},
+		{Track: "python", Completed: 137, Total: 137, Percent: 100},
+	}, progress)
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// inReader buffers In, so that answers to successive prompts aren't lost to the buffer.
+var (
+	inReader *bufio.Reader
+	inSource io.Reader
+)
+
+// prompt asks a question and returns the answer, trimmed and in lower case.
+// It returns io.EOF if there is no one to answer.
+func prompt(question string) (string, error) {
+	if inReader == nil || inSource != In {
+		inReader = bufio.NewReader(In)
+		inSource = In
+	}
+	fmt.Fprint(Err, question)
+	answer, err := inReader.ReadString('\n')
+	if err != nil && answer == "" {
+		return "", err
+	}
+	return strings.ToLower(strings.TrimSpace(answer)), nil
+}
+
+// promptSecret asks for something that shouldn't be shown, such as a token, and returns the answer trimmed.
+// At a terminal what is typed isn't echoed. Otherwise the answer is read from In as it is,
+// so that it can be piped in.
+func promptSecret(question string) (string, error) {
+	if inReader == nil || inSource != In {
+		inReader = bufio.NewReader(In)
+		inSource = In
+	}
+	if f, ok := In.(*os.File); ok && isInteractive() {
+		fmt.Fprint(Err, question)
+		if err := setEcho(f, false); err == nil {
+			defer func() {
+				setEcho(f, true)
+				// The newline that was typed wasn't echoed either.
+				fmt.Fprintln(Err)
+			}()
+		}
+	}
+	answer, err := inReader.ReadString('\n')
+	if err != nil && answer == "" {
+		return "", err
+	}
+	return strings.TrimSpace(answer), nil
+}
+
+// confirm asks a yes or no question, defaulting to yes.
+func confirm(question string) bool {
+	answer, err := prompt(question)
+	if err != nil {
+		return false
+	}
+	switch answer {
+	case "", "y", "yes":
+		return true
+	}
+	return false
+}
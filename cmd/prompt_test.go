@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrompt(t *testing.T) {
+	co := newCapturedOutput()
+	co.override()
+	defer co.reset()
+
+	oldIn := In
+	defer func() { In = oldIn }()
+
+	In = strings.NewReader("First\n  second  \nthird")
+	for _, expected := range []string{"first", "second", "third"} {
+		answer, err := prompt("? ")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, answer)
+	}
+	_, err := prompt("? ")
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestConfirm(t *testing.T) {
+	co := newCapturedOutput()
+	co.override()
+	defer co.reset()
+
+	oldIn := In
+	defer func() { In = oldIn }()
+
+	for answer, expected := range map[string]bool{"\n": true, "y\n": true, "YES\n": true, "n\n": false, "nope\n": false, "": false} {
+		In = strings.NewReader(answer)
+		assert.Equal(t, expected, confirm("? "), "answer %q", answer)
+	}
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/exercism/cli/config"
+	"github.com/spf13/viper"
+)
+
+const (
+	// retryKey is the user config setting that is set to retryAlways
+	// when the user asks to always retry instead of being asked each time.
+	retryKey    = "retry"
+	retryAlways = "always"
+
+	// maxAutomaticRetries limits how often an operation is retried without asking,
+	// so that an outage doesn't keep the command going forever.
+	maxAutomaticRetries = 3
+)
+
+// isInteractive tells whether there is someone at a terminal to answer prompts.
+var isInteractive = func() bool {
+	f, ok := In.(*os.File)
+	return ok && isTerminal(f)
+}
+
+// isRetryable tells whether an error might go away if the operation is tried again:
+// the network failing, the API being down, or too many requests.
+// Running out of the time given by --timeout isn't worth retrying.
+func isRetryable(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError || apiErr.StatusCode == http.StatusTooManyRequests
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// withRetry runs the operation, and offers to run it again when it fails in a way that might be temporary.
+// It only asks when someone is there to answer; otherwise the error is returned as it is.
+// Answering "always" saves the choice in the user config, after which it retries without asking.
+func withRetry(cfg config.Config, op func() error) error {
+	var automatic int
+	for {
+		err := op()
+		if err == nil || !isRetryable(err) || !isInteractive() {
+			return err
+		}
+		fmt.Fprintf(Err, "%s\n", err)
+
+		if cfg.UserViperConfig != nil && cfg.UserViperConfig.GetString(retryKey) == retryAlways {
+			if automatic >= maxAutomaticRetries {
+				return err
+			}
+			automatic++
+			fmt.Fprintf(Err, "Retrying (%d of %d)...\n", automatic, maxAutomaticRetries)
+			continue
+		}
+
+		answer, perr := prompt("Retry? [Y/n/always] ")
+		if perr != nil {
+			return err
+		}
+		switch answer {
+		case "", "y", "yes":
+		case "a", "always":
+			rememberAlwaysRetry(cfg)
+		default:
+			return err
+		}
+	}
+}
+
+// rememberAlwaysRetry saves the choice to always retry in the user config.
+// Failing to save it isn't worth failing the command over.
+func rememberAlwaysRetry(cfg config.Config) {
+	if cfg.UserViperConfig == nil {
+		return
+	}
+	cfg.UserViperConfig.Set(retryKey, retryAlways)
+	if cfg.Persister == nil {
+		return
+	}
+	// The config in use has the values from the environment as well, which aren't to be saved.
+	v := viper.New()
+	v.AddConfigPath(cfg.Dir)
+	v.SetConfigName("user")
+	v.SetConfigType("json")
+	// Ignore error. If the file doesn't exist, that is fine.
+	_ = v.ReadInConfig()
+	v.Set(retryKey, retryAlways)
+	if err := cfg.Persister.Save(v, "user"); err != nil {
+		fmt.Fprintf(Err, "Warning: unable to save the choice to always retry: %s\n", err)
+		return
+	}
+	fmt.Fprintf(Err, "Failed operations will be retried without asking. Set %q to another value in your config to be asked again.\n", retryKey)
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/exercism/cli/config"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRetryable(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		err      error
+		expected bool
+	}{
+		{"server error", &apiError{StatusCode: http.StatusBadGateway}, true},
+		{"too many requests", &apiError{StatusCode: http.StatusTooManyRequests}, true},
+		{"not found", &apiError{StatusCode: http.StatusNotFound}, false},
+		{"network error", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{"other error", errors.New("no such file"), false},
+		{"out of time", &url.Error{Op: "Get", URL: "http://example.com", Err: context.DeadlineExceeded}, false},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, isRetryable(tc.err), tc.desc)
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	oldIn := In
+	oldInteractive := isInteractive
+	defer func() {
+		In = oldIn
+		isInteractive = oldInteractive
+	}()
+
+	failing := func(times int, err error) (func() error, *int) {
+		var calls int
+		return func() error {
+			calls++
+			if calls <= times {
+				return err
+			}
+			return nil
+		}, &calls
+	}
+	unavailable := &apiError{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable"}
+
+	testCases := []struct {
+		desc          string
+		interactive   bool
+		answers       string
+		setting       string
+		failures      int
+		err           error
+		expectedCalls int
+		expectedErr   error
+		expectedSave  string
+	}{
+		{
+			desc:          "retries when the user agrees",
+			interactive:   true,
+			answers:       "\ny\n",
+			failures:      2,
+			err:           unavailable,
+			expectedCalls: 3,
+		},
+		{
+			desc:          "gives up when the user declines",
+			interactive:   true,
+			answers:       "n\n",
+			failures:      2,
+			err:           unavailable,
+			expectedCalls: 1,
+			expectedErr:   unavailable,
+		},
+		{
+			desc:          "doesn't ask when no one is there",
+			answers:       "y\n",
+			failures:      1,
+			err:           unavailable,
+			expectedCalls: 1,
+			expectedErr:   unavailable,
+		},
+		{
+			desc:          "doesn't ask about errors that won't go away",
+			interactive:   true,
+			answers:       "y\n",
+			failures:      1,
+			err:           errors.New("no such file"),
+			expectedCalls: 1,
+			expectedErr:   errors.New("no such file"),
+		},
+		{
+			desc:          "remembers to always retry",
+			interactive:   true,
+			answers:       "always\n",
+			failures:      2,
+			err:           unavailable,
+			expectedCalls: 3,
+			expectedSave:  retryAlways,
+		},
+		{
+			desc:          "retries without asking when set to always",
+			interactive:   true,
+			setting:       retryAlways,
+			failures:      2,
+			err:           unavailable,
+			expectedCalls: 3,
+			expectedSave:  retryAlways,
+		},
+		{
+			desc:          "stops retrying without asking eventually",
+			interactive:   true,
+			setting:       retryAlways,
+			failures:      10,
+			err:           unavailable,
+			expectedCalls: maxAutomaticRetries + 1,
+			expectedErr:   unavailable,
+			expectedSave:  retryAlways,
+		},
+	}
+
+	for _, tc := range testCases {
+		co := newCapturedOutput()
+		co.newErr = &bytes.Buffer{}
+		co.override()
+
+		In = strings.NewReader(tc.answers)
+		interactive := tc.interactive
+		isInteractive = func() bool { return interactive }
+
+		v := viper.New()
+		if tc.setting != "" {
+			v.Set(retryKey, tc.setting)
+		}
+		cfg := config.Config{UserViperConfig: v, Persister: config.InMemoryPersister{}}
+
+		op, calls := failing(tc.failures, tc.err)
+		err := withRetry(cfg, op)
+		co.reset()
+
+		assert.Equal(t, tc.expectedErr, err, tc.desc)
+		assert.Equal(t, tc.expectedCalls, *calls, tc.desc)
+		assert.Equal(t, tc.expectedSave, v.GetString(retryKey), tc.desc)
+	}
+}
@@ -4,12 +4,13 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"strings"
 
 	"github.com/exercism/cli/api"
-	"github.com/exercism/cli/cli"
 	"github.com/exercism/cli/config"
 	"github.com/exercism/cli/debug"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // RootCmd represents the base command when called without any subcommands.
@@ -20,34 +21,106 @@ var RootCmd = &cobra.Command{
 
 Download exercises and submit your solutions.`,
 	SilenceUsage: true,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.NewConfig()
+
+		v := viper.New()
+		v.AddConfigPath(cfg.Dir)
+		v.SetConfigName("user")
+		v.SetConfigType("json")
+		// Ignore error. If the file doesn't exist, that is fine.
+		_ = v.ReadInConfig()
+		config.BindUserEnv(v)
+
+		if isFirstRun(v) {
+			fmt.Fprint(Err, getStartedMessage(v))
+			return nil
+		}
+		return cmd.Help()
+	},
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		if verbose, _ := cmd.Flags().GetBool("verbose"); verbose {
 			debug.Verbose = verbose
 		}
 		if unmask, _ := cmd.Flags().GetBool("unmask-token"); unmask {
 			debug.UnmaskAPIKey = unmask
 		}
-		if timeout, _ := cmd.Flags().GetInt("timeout"); timeout > 0 {
-			cli.TimeoutInSeconds = timeout
-			api.TimeoutInSeconds = timeout
+		if ascii, _ := cmd.Flags().GetBool("ascii"); ascii {
+			plainASCII = ascii
 		}
+		if dir, _ := cmd.Flags().GetString("config-dir"); dir != "" {
+			config.DirOverride = config.Resolve(dir, config.NewConfig().Home)
+		}
+		value, _ := cmd.Flags().GetString("timeout")
+		timeout, err := parseTimeout(value)
+		if err != nil {
+			return &usageError{command: cmd.CommandPath(), err: err}
+		}
+		if timeout > 0 {
+			limitCommand(timeout, errorFormat(cmd.Flags()))
+		}
+		return nil
 	},
 }
 
 // Execute adds all child commands to the root command.
 func Execute() {
+	// The config is read before the flags are parsed, so look for an alternate one first.
+	if dir := configDirFromArgs(os.Args[1:]); dir != "" {
+		config.DirOverride = config.Resolve(dir, config.NewConfig().Home)
+	}
+	cfg := config.NewConfig()
+	v := viper.New()
+	v.AddConfigPath(cfg.Dir)
+	v.SetConfigName("user")
+	v.SetConfigType("json")
+	// Ignore error. If the file doesn't exist, that is fine.
+	_ = v.ReadInConfig()
+	config.BindUserEnv(v)
+	plainASCII = v.GetBool("ascii") || !localeIsUTF8()
+	errorFormatSetting = v.GetString("error_format")
+
+	args := applyDeprecations(RootCmd, os.Args[1:])
+	applyFlagDefaults(RootCmd, v, args)
+	RootCmd.SetArgs(args)
+	// Errors are reported here rather than by cobra, so that they can be reported as JSON.
+	RootCmd.SilenceErrors = true
 	if err := RootCmd.Execute(); err != nil {
+		reportError(Err, err, errorFormat(RootCmd.PersistentFlags()))
 		os.Exit(-1)
 	}
 }
 
+// configDirFromArgs finds the value of --config-dir in the arguments, if it's there.
+func configDirFromArgs(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if strings.HasPrefix(arg, "--config-dir=") {
+			return strings.TrimPrefix(arg, "--config-dir=")
+		}
+		if arg == "--config-dir" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
 func init() {
 	BinaryName = os.Args[0]
+	RootCmd.Use = BinaryName
 	config.SetDefaultDirName(BinaryName)
 	Out = os.Stdout
 	Err = os.Stderr
+	In = os.Stdin
 	api.UserAgent = fmt.Sprintf("github.com/exercism/cli v%s (%s/%s)", Version, runtime.GOOS, runtime.GOARCH)
+	RootCmd.SetFlagErrorFunc(flagError)
 	RootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
-	RootCmd.PersistentFlags().IntP("timeout", "", 0, "override the default HTTP timeout (seconds)")
+	RootCmd.PersistentFlags().StringP("timeout", "", "", "give up if the command takes longer than this, e.g. 30s or 2m")
 	RootCmd.PersistentFlags().BoolP("unmask-token", "", false, "will unmask the API during a request/response dump")
+	RootCmd.PersistentFlags().BoolP("silence-deprecations", "", false, "don't print notes about deprecated commands and flags")
+	RootCmd.PersistentFlags().BoolP("ascii", "", false, "only use plain ASCII characters in the output")
+	RootCmd.PersistentFlags().StringP("config-dir", "", "", "use the config in this directory instead of the default one")
+	RootCmd.PersistentFlags().StringP("error-format", "", errorFormatText, "how to report failures: text or json")
 }
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/exercism/cli/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigDirFromArgs(t *testing.T) {
+	testCases := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"download", "--exercise=bob"}, ""},
+		{[]string{"--config-dir=/tmp/a", "download"}, "/tmp/a"},
+		{[]string{"download", "--config-dir", "/tmp/b", "--exercise=bob"}, "/tmp/b"},
+		{[]string{"download", "--config-dir"}, ""},
+		{[]string{"submit", "--", "--config-dir=/tmp/c"}, ""},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, configDirFromArgs(tc.args), "%v", tc.args)
+	}
+}
+
+func TestUserEnvOverridesConfig(t *testing.T) {
+	co := newCapturedOutput()
+	co.newOut = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	tmpDir, err := ioutil.TempDir("", "user-env")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	// The user config points somewhere else entirely.
+	cfgDir := filepath.Join(tmpDir, "config")
+	assert.NoError(t, os.MkdirAll(cfgDir, os.FileMode(0755)))
+	userCfg := `{"token": "file-token", "apibaseurl": "http://127.0.0.1:1", "workspace": "` + filepath.ToSlash(filepath.Join(tmpDir, "file-workspace")) + `"}`
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(cfgDir, "user.json"), []byte(userCfg), os.FileMode(0644)))
+	defer func() { config.DirOverride = "" }()
+
+	workspaceDir := filepath.Join(tmpDir, "env-workspace")
+	dir := filepath.Join(workspaceDir, "bogus-track", "bogus-exercise")
+	assert.NoError(t, os.MkdirAll(dir, os.FileMode(0755)))
+	writeFakeMetadata(t, dir, "bogus-track", "bogus-exercise")
+	file := filepath.Join(dir, "file.txt")
+	assert.NoError(t, ioutil.WriteFile(file, []byte("This is a file."), os.FileMode(0644)))
+
+	env := map[string]string{
+		"EXERCISM_TOKEN":        "env-token",
+		"EXERCISM_API_BASE_URL": ts.URL,
+		"EXERCISM_WORKSPACE":    workspaceDir,
+	}
+	for name, value := range env {
+		defer os.Setenv(name, os.Getenv(name))
+		os.Setenv(name, value)
+	}
+
+	testCases := []struct {
+		args    []string
+		request string
+	}{
+		{[]string{"tracks"}, "GET /tracks"},
+		{[]string{"exercises", "--track", "bogus-track"}, "GET /tracks/bogus-track/exercises"},
+		{[]string{"search", "bogus"}, "GET /exercises"},
+		{[]string{"progress"}, "GET /tracks"},
+		{[]string{"status"}, "GET /tracks"},
+		{[]string{"syllabus", "--track", "bogus-track"}, "GET /tracks/bogus-track/concepts"},
+		{[]string{"data", "export"}, "GET /profile"},
+		{[]string{"download", "--exercise", "bogus-exercise", "--track", "bogus-track"}, "GET /solutions/latest"},
+		{[]string{"submit", file}, "PATCH /solutions/bogus-solution-uuid"},
+		{[]string{"doctor"}, "GET /ping"},
+	}
+	silenced := RootCmd.SilenceErrors
+	RootCmd.SilenceErrors = true
+	defer func() { RootCmd.SilenceErrors = silenced }()
+	for _, tc := range testCases {
+		requests = nil
+		RootCmd.SetArgs(append(tc.args, "--config-dir", cfgDir))
+		// Only the first request matters, the fake API doesn't answer any of them.
+		_ = RootCmd.Execute()
+		if assert.NotEmpty(t, requests, "%v", tc.args) {
+			assert.Equal(t, tc.request+" Bearer env-token", requests[0], "%v", tc.args)
+		}
+	}
+
+	co.newOut.(*bytes.Buffer).Reset()
+	RootCmd.SetArgs([]string{"workspace", "--config-dir", cfgDir})
+	assert.NoError(t, RootCmd.Execute())
+	assert.Equal(t, workspaceDir+"\n", co.newOut.(*bytes.Buffer).String())
+}
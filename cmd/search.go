@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/exercism/cli/api"
+	"github.com/exercism/cli/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// searchCmd finds exercises by keyword.
+var searchCmd = &cobra.Command{
+	Use:        "search QUERY",
+	SuggestFor: []string{"find"},
+	Short:      "Search for exercises across the tracks.",
+	Long: `Search for exercises whose name, description or topics match the query,
+across all the tracks or in the one given with --track:
+
+    exercism search "linked list" --track=go
+
+Download one of them with 'exercism download --track=<track> --exercise=<exercise>'.
+`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.NewConfig()
+
+		v := viper.New()
+		v.AddConfigPath(cfg.Dir)
+		v.SetConfigName("user")
+		v.SetConfigType("json")
+		// Ignore error. If the file doesn't exist, that is fine.
+		_ = v.ReadInConfig()
+		config.BindUserEnv(v)
+		cfg.UserViperConfig = v
+
+		return runSearch(cfg, cmd.Flags(), args)
+	},
+}
+
+// searchResult is an exercise that matches a search.
+type searchResult struct {
+	Track string `json:"track"`
+	catalogExercise
+}
+
+func runSearch(cfg config.Config, flags *pflag.FlagSet, args []string) error {
+	usrCfg := cfg.UserViperConfig
+	if err := validateUserConfig(usrCfg); err != nil {
+		return err
+	}
+	query := strings.TrimSpace(strings.Join(args, " "))
+	if query == "" {
+		return errors.New("need something to search for")
+	}
+	track, err := flags.GetString("track")
+	if err != nil {
+		return err
+	}
+
+	results, err := fetchSearchResults(usrCfg.GetString("token"), usrCfg.GetString("apibaseurl"), query, track)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		fmt.Fprintf(Err, "No exercises match %q.\n", query)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TRACK\tEXERCISE\tDIFFICULTY\tDESCRIPTION")
+	for _, result := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", result.Track, result.Slug, orDash(result.Difficulty), result.Blurb)
+	}
+	w.Flush()
+	return nil
+}
+
+// fetchSearchResults asks the API for the exercises that match the query, in the track if one is given.
+func fetchSearchResults(token, baseURL, query, track string) ([]searchResult, error) {
+	client, err := api.NewClient(token, baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := client.NewRequest("GET", fmt.Sprintf("%s/exercises", baseURL), nil)
+	if err != nil {
+		return nil, err
+	}
+	q := req.URL.Query()
+	q.Add("q", query)
+	if track != "" {
+		q.Add("track_id", track)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, decodedAPIError(res)
+	}
+
+	var payload struct {
+		Exercises []searchResult `json:"exercises"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("unable to parse API response - %s", err)
+	}
+	return payload.Exercises, nil
+}
+
+func setupSearchFlags(flags *pflag.FlagSet) {
+	flags.StringP("track", "t", "", "only search the exercises of this track")
+}
+
+func init() {
+	RootCmd.AddCommand(searchCmd)
+	setupSearchFlags(searchCmd.Flags())
+}
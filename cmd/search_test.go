@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/exercism/cli/config"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearch(t *testing.T) {
+	co := newCapturedOutput()
+	co.newOut = &bytes.Buffer{}
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	var query string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/exercises", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		if r.FormValue("q") == "zebra" {
+			fmt.Fprint(w, `{"exercises": []}`)
+			return
+		}
+		fmt.Fprint(w, `{"exercises": [
+			{"track": "go", "slug": "linked-list", "difficulty": "hard", "blurb": "Implement a doubly linked list."},
+			{"track": "go", "slug": "simple-linked-list", "blurb": "Write a simple linked list implementation."}
+		]}`)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", "/home/alice/exercism")
+	v.Set("apibaseurl", ts.URL)
+	cfg := config.Config{UserViperConfig: v}
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupSearchFlags(flags)
+	flags.Set("track", "go")
+	assert.NoError(t, runSearch(cfg, flags, []string{"linked", "list"}))
+	assert.Equal(t, "q=linked+list&track_id=go", query)
+
+	out := Out.(*bytes.Buffer).String()
+	assert.Regexp(t, `go +linked-list +hard +Implement a doubly linked list\.`, out)
+	assert.Regexp(t, `go +simple-linked-list +- +Write a simple linked list implementation\.`, out)
+
+	flags = pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupSearchFlags(flags)
+	assert.NoError(t, runSearch(cfg, flags, []string{"zebra"}))
+	assert.Equal(t, "q=zebra", query)
+	assert.Regexp(t, `No exercises match "zebra"`, Err.(*bytes.Buffer).String())
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/exercism/cli/api"
+	"github.com/exercism/cli/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// statusCmd gives an overview of where the user is on the website.
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show your progress in the tracks you've joined.",
+	Long: `Show how far you've got in each of the tracks you've joined, how many
+mentoring discussions you have going on, and how many notifications you
+haven't read, without having to go to the website.
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.NewConfig()
+
+		v := viper.New()
+		v.AddConfigPath(cfg.Dir)
+		v.SetConfigName("user")
+		v.SetConfigType("json")
+		// Ignore error. If the file doesn't exist, that is fine.
+		_ = v.ReadInConfig()
+		config.BindUserEnv(v)
+		cfg.UserViperConfig = v
+
+		return runStatus(cfg)
+	},
+}
+
+func runStatus(cfg config.Config) error {
+	usrCfg := cfg.UserViperConfig
+	if err := validateUserConfig(usrCfg); err != nil {
+		return err
+	}
+	token, baseURL := usrCfg.GetString("token"), usrCfg.GetString("apibaseurl")
+
+	tracks, err := fetchTracks(token, baseURL)
+	if err != nil {
+		return err
+	}
+	var joined []trackSummary
+	for _, track := range tracks {
+		if track.Joined {
+			joined = append(joined, track)
+		}
+	}
+	if len(joined) == 0 {
+		fmt.Fprintln(Err, "You haven't joined any tracks. Join one on the website.")
+	} else {
+		w := tabwriter.NewWriter(Out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TRACK\tCOMPLETED\tIN PROGRESS")
+		for _, track := range joined {
+			fmt.Fprintf(w, "%s\t%d/%d\t%d\n", track.Slug, track.NumCompletedExercises, track.NumExercises, track.NumInProgressExercises)
+		}
+		w.Flush()
+	}
+
+	client, err := api.NewClient(token, baseURL)
+	if err != nil {
+		return err
+	}
+	// The tracks are what matters most, so the rest is left out rather than failing.
+	fmt.Fprintln(Out)
+	var discussions struct {
+		Discussions []discussion `json:"discussions"`
+	}
+	if err := fetchJSON(client, fmt.Sprintf("%s/mentoring/discussions", baseURL), &discussions); err != nil {
+		fmt.Fprintf(Err, "Unable to check your mentoring discussions: %s\n", err)
+	} else {
+		var active, waiting int
+		for _, d := range discussions.Discussions {
+			if !d.isActive() {
+				continue
+			}
+			active++
+			if d.Status == discussionAwaitingStudent {
+				waiting++
+			}
+		}
+		fmt.Fprintf(Out, "Mentoring:      %d open discussion(s), %d waiting for your reply\n", active, waiting)
+	}
+
+	var notifications struct {
+		Meta struct {
+			UnreadCount int `json:"unread_count"`
+		} `json:"meta"`
+	}
+	if err := fetchJSON(client, fmt.Sprintf("%s/notifications?status=unread", baseURL), &notifications); err != nil {
+		fmt.Fprintf(Err, "Unable to check your notifications: %s\n", err)
+	} else {
+		fmt.Fprintf(Out, "Notifications:  %d unread\n", notifications.Meta.UnreadCount)
+	}
+	return nil
+}
+
+func init() {
+	RootCmd.AddCommand(statusCmd)
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/exercism/cli/config"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatus(t *testing.T) {
+	co := newCapturedOutput()
+	co.newOut = &bytes.Buffer{}
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tracks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tracks": [
+			{"slug": "go", "is_joined": true, "num_exercises": 141, "num_completed_exercises": 12, "num_in_progress_exercises": 2},
+			{"slug": "rust", "is_joined": false, "num_exercises": 98}
+		]}`)
+	})
+	mux.HandleFunc("/mentoring/discussions", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"discussions": [{"uuid": "a", "status": "%s"}, {"uuid": "b", "status": "%s"}, {"uuid": "c", "status": "finished"}]}`,
+			discussionAwaitingStudent, discussionAwaitingMentor)
+	})
+	var notificationsQuery string
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		notificationsQuery = r.URL.RawQuery
+		fmt.Fprint(w, `{"notifications": [], "meta": {"unread_count": 3}}`)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", "/home/alice/exercism")
+	v.Set("apibaseurl", ts.URL)
+	cfg := config.Config{UserViperConfig: v}
+
+	assert.NoError(t, runStatus(cfg))
+	out := Out.(*bytes.Buffer).String()
+	assert.Regexp(t, `go +12/141 +2`, out)
+	assert.NotRegexp(t, "rust", out)
+	assert.Regexp(t, `Mentoring: +2 open discussion\(s\), 1 waiting for your reply`, out)
+	assert.Regexp(t, `Notifications: +3 unread`, out)
+	assert.Equal(t, "status=unread", notificationsQuery)
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// submissionRecord is an entry in the local log of submissions.
+type submissionRecord struct {
+	SubmittedAt time.Time `json:"submitted_at"`
+	Track       string    `json:"track"`
+	Exercise    string    `json:"exercise"`
+	SolutionID  string    `json:"solution_id"`
+	IterationID string    `json:"iteration_id,omitempty"`
+	Files       []string  `json:"files"`
+	PayloadHash string    `json:"payload_hash"`
+}
+
+// submissionHistoryPath is where the log of submissions is kept.
+func submissionHistoryPath(stateDir string) string {
+	return filepath.Join(stateDir, "history.jsonl")
+}
+
+// appendSubmissionRecord adds a submission to the end of the log.
+// The log is only ever appended to, one JSON record per line.
+func appendSubmissionRecord(stateDir string, record submissionRecord) error {
+	path := submissionHistoryPath(stateDir)
+	if err := os.MkdirAll(filepath.Dir(path), os.FileMode(0755)); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, os.FileMode(0644))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	b, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(b, '\n'))
+	return err
+}
+
+// readSubmissionHistory reads the log of submissions, oldest first.
+// Lines that can't be parsed, e.g. after an interrupted write, are skipped.
+func readSubmissionHistory(stateDir string) ([]submissionRecord, error) {
+	f, err := os.Open(submissionHistoryPath(stateDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []submissionRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record submissionRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// payloadHash fingerprints the files of a submission as they are submitted,
+// independently of the order they were given in, so that identical submissions can be spotted.
+func payloadHash(files []submittedFileSize) string {
+	sorted := make([]submittedFileSize, len(files))
+	copy(sorted, files)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].path < sorted[j].path
+	})
+
+	h := sha256.New()
+	for _, file := range sorted {
+		fmt.Fprintf(h, "%s\x00", file.path)
+		h.Write(file.sum[:])
+	}
+	return fmt.Sprintf("sha256:%x", h.Sum(nil))
+}
+
+// parseIterationID picks the ID of the new iteration out of the API's
+// response to a submission. It is blank if the API didn't say.
+func parseIterationID(body []byte) string {
+	var payload struct {
+		Iteration struct {
+			// The ID may be a number or a string.
+			ID json.RawMessage `json:"id"`
+		} `json:"iteration"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return ""
+	}
+	return strings.Trim(string(payload.Iteration.ID), `"`)
+}
+
+// parseIterationIndex picks the number of the new iteration out of the API's
+// response to a submission. It is zero if the API didn't say.
+func parseIterationIndex(body []byte) int {
+	var payload struct {
+		Iteration struct {
+			Idx int `json:"idx"`
+		} `json:"iteration"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return 0
+	}
+	return payload.Iteration.Idx
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/exercism/cli/config"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubmissionHistory(t *testing.T) {
+	stateDir, err := ioutil.TempDir("", "submission-history")
+	assert.NoError(t, err)
+	defer os.RemoveAll(stateDir)
+
+	records, err := readSubmissionHistory(stateDir)
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+
+	first := submissionRecord{SubmittedAt: time.Now().Add(-time.Hour).UTC(), Track: "go", Exercise: "clock", SolutionID: "abc"}
+	second := submissionRecord{SubmittedAt: time.Now().UTC(), Track: "go", Exercise: "bob", SolutionID: "def"}
+	assert.NoError(t, appendSubmissionRecord(stateDir, first))
+
+	// A partially written line doesn't spoil the rest of the history.
+	f, err := os.OpenFile(submissionHistoryPath(stateDir), os.O_APPEND|os.O_WRONLY, 0644)
+	assert.NoError(t, err)
+	fmt.Fprint(f, "{\"submitted_at\n")
+	f.Close()
+
+	assert.NoError(t, appendSubmissionRecord(stateDir, second))
+
+	records, err = readSubmissionHistory(stateDir)
+	assert.NoError(t, err)
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, "clock", records[0].Exercise)
+		assert.True(t, first.SubmittedAt.Equal(records[0].SubmittedAt))
+		assert.Equal(t, "bob", records[1].Exercise)
+	}
+}
+
+func TestSubmitRecordsHistory(t *testing.T) {
+	co := newCapturedOutput()
+	co.override()
+	defer co.reset()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"iteration": {"id": 42}}`)
+	}))
+	defer ts.Close()
+
+	tmpDir, err := ioutil.TempDir("", "submit-history")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
+	os.MkdirAll(dir, os.FileMode(0755))
+	writeFakeMetadata(t, dir, "bogus-track", "bogus-exercise")
+	file := filepath.Join(dir, "file.txt")
+	assert.NoError(t, ioutil.WriteFile(file, []byte("hello"), os.FileMode(0644)))
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	cfg := config.Config{
+		Persister:       config.InMemoryPersister{},
+		UserViperConfig: v,
+		StateDir:        filepath.Join(tmpDir, "state"),
+	}
+
+	err = runSubmit(cfg, pflag.NewFlagSet("fake", pflag.PanicOnError), []string{file})
+	assert.NoError(t, err)
+
+	records, err := readSubmissionHistory(cfg.StateDir)
+	assert.NoError(t, err)
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "bogus-track", records[0].Track)
+		assert.Equal(t, "bogus-exercise", records[0].Exercise)
+		assert.Equal(t, "bogus-solution-uuid", records[0].SolutionID)
+		assert.Equal(t, "42", records[0].IterationID)
+		assert.Equal(t, []string{"file.txt"}, records[0].Files)
+		assert.Regexp(t, "^sha256:[0-9a-f]{64}$", records[0].PayloadHash)
+	}
+}
+
+func TestPayloadHash(t *testing.T) {
+	file := func(path, contents string) submittedFileSize {
+		return submittedFileSize{path: path, size: int64(len(contents)), sum: sha256.Sum256([]byte(contents))}
+	}
+	a := file("a.txt", "hello")
+	b := file("b.txt", "world")
+
+	ab := payloadHash([]submittedFileSize{a, b})
+	assert.Regexp(t, "^sha256:[0-9a-f]{64}$", ab)
+	assert.Equal(t, ab, payloadHash([]submittedFileSize{b, a}))
+	assert.NotEqual(t, ab, payloadHash([]submittedFileSize{a, file("b.txt", "there")}))
+	assert.NotEqual(t, ab, payloadHash([]submittedFileSize{a, file("c.txt", "world")}))
+}
+
+func TestParseIterationID(t *testing.T) {
+	assert.Equal(t, "42", parseIterationID([]byte(`{"iteration": {"id": 42}}`)))
+	assert.Equal(t, "abc", parseIterationID([]byte(`{"iteration": {"id": "abc"}}`)))
+	assert.Equal(t, "", parseIterationID([]byte(`{}`)))
+	assert.Equal(t, "", parseIterationID([]byte(`not json`)))
+}
+
+func TestHistory(t *testing.T) {
+	co := newCapturedOutput()
+	co.newOut = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	stateDir, err := ioutil.TempDir("", "history")
+	assert.NoError(t, err)
+	defer os.RemoveAll(stateDir)
+	cfg := config.Config{StateDir: stateDir}
+
+	now := time.Now()
+	for i, exercise := range []string{"clock", "bob", "clock"} {
+		record := submissionRecord{
+			SubmittedAt: now.Add(time.Duration(i) * time.Minute),
+			Track:       "go",
+			Exercise:    exercise,
+			IterationID: fmt.Sprintf("%d", i+1),
+			Files:       []string{exercise + ".go"},
+			PayloadHash: "sha256:0123456789abcdef",
+		}
+		assert.NoError(t, appendSubmissionRecord(stateDir, record))
+	}
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupHistoryFlags(flags)
+	flags.Set("exercise", "clock")
+	assert.NoError(t, runHistory(cfg, flags))
+
+	lines := strings.Split(strings.TrimSpace(Out.(*bytes.Buffer).String()), "\n")
+	if assert.Len(t, lines, 3) {
+		assert.Regexp(t, `go/clock\s+3\s+0123456789ab\s+clock.go`, lines[1])
+		assert.Regexp(t, `go/clock\s+1\s+`, lines[2])
+	}
+
+	Out.(*bytes.Buffer).Reset()
+	flags = pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupHistoryFlags(flags)
+	flags.Set("limit", "1")
+	flags.Set("json", "true")
+	assert.NoError(t, runHistory(cfg, flags))
+
+	var records []submissionRecord
+	assert.NoError(t, json.Unmarshal(Out.(*bytes.Buffer).Bytes(), &records))
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "3", records[0].IterationID)
+	}
+}
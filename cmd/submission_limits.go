@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/exercism/cli/api"
+	"github.com/spf13/viper"
+)
+
+// defaultMaxFileSize is the size, in bytes, that the API has always needed files to be smaller than.
+// Files that are smaller are submitted without asking the API what its limit is now.
+const defaultMaxFileSize int64 = 65535
+
+// defaultMaxPayloadSize is the most, in bytes, that the API has always accepted in one submission,
+// counting all the files along with the multipart encoding.
+// Submissions that are smaller are sent without asking the API what its limit is now.
+const defaultMaxPayloadSize int64 = 1024 * 1024
+
+// submissionLimitsTTL is how long the limits the API gave are used before asking again.
+const submissionLimitsTTL = 24 * time.Hour
+
+// submissionLimits are how large the API accepts submissions to be.
+type submissionLimits struct {
+	MaxFileSize    int64     `json:"max_file_size"`
+	MaxPayloadSize int64     `json:"max_payload_size"`
+	FetchedAt      time.Time `json:"fetched_at"`
+}
+
+// submissionLimitsPath is where the limits the API gave are cached.
+func submissionLimitsPath(stateDir string) string {
+	return filepath.Join(stateDir, "submission_limits.json")
+}
+
+// maxFileSize is the size, in bytes, that the API needs files to be smaller than.
+// If the API can't say, the limit it has always had is assumed.
+func maxFileSize(usrCfg *viper.Viper, stateDir string) int64 {
+	limits := currentSubmissionLimits(usrCfg, stateDir)
+	if limits == nil || limits.MaxFileSize <= 0 {
+		return defaultMaxFileSize
+	}
+	return limits.MaxFileSize
+}
+
+// maxPayloadSize is the most, in bytes, that the API accepts in one submission.
+// If the API can't say, the limit it has always had is assumed.
+func maxPayloadSize(usrCfg *viper.Viper, stateDir string) int64 {
+	limits := currentSubmissionLimits(usrCfg, stateDir)
+	if limits == nil || limits.MaxPayloadSize <= 0 {
+		return defaultMaxPayloadSize
+	}
+	return limits.MaxPayloadSize
+}
+
+// currentSubmissionLimits are taken from the cache while it is fresh, or else asked of the API and cached.
+// It returns nil if the API can't say.
+func currentSubmissionLimits(usrCfg *viper.Viper, stateDir string) *submissionLimits {
+	var path string
+	if stateDir != "" {
+		path = submissionLimitsPath(stateDir)
+		if limits, err := loadSubmissionLimits(path); err == nil && limits != nil && time.Since(limits.FetchedAt) < submissionLimitsTTL {
+			return limits
+		}
+	}
+	limits, err := fetchSubmissionLimits(usrCfg.GetString("token"), usrCfg.GetString("apibaseurl"))
+	if err != nil {
+		return nil
+	}
+	if path != "" {
+		// A failure to cache the limits shouldn't prevent submitting.
+		_ = limits.save(path)
+	}
+	return limits
+}
+
+// fetchSubmissionLimits asks the API how large it accepts submissions to be.
+func fetchSubmissionLimits(token, baseURL string) (*submissionLimits, error) {
+	client, err := api.NewClient(token, baseURL)
+	if err != nil {
+		return nil, err
+	}
+	req, err := client.NewRequest("GET", fmt.Sprintf("%s/submissions/limits", baseURL), nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, decodedAPIError(res)
+	}
+	var payload struct {
+		Limits struct {
+			MaxFileSize    int64 `json:"max_file_size"`
+			MaxPayloadSize int64 `json:"max_payload_size"`
+		} `json:"limits"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("unable to parse API response - %s", err)
+	}
+	return &submissionLimits{
+		MaxFileSize:    payload.Limits.MaxFileSize,
+		MaxPayloadSize: payload.Limits.MaxPayloadSize,
+		FetchedAt:      time.Now(),
+	}, nil
+}
+
+// loadSubmissionLimits reads the cached limits.
+// It returns nil if they have not been cached.
+func loadSubmissionLimits(path string) (*submissionLimits, error) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var limits submissionLimits
+	if err := json.Unmarshal(b, &limits); err != nil {
+		return nil, err
+	}
+	return &limits, nil
+}
+
+func (l *submissionLimits) save(path string) error {
+	b, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.FileMode(0755)); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, os.FileMode(0644))
+}
@@ -2,31 +2,164 @@ package cmd
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"mime/multipart"
+	"net/textproto"
 	"os"
+	"os/exec"
+	"path"
 	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/exercism/cli/api"
+	"github.com/exercism/cli/browser"
 	"github.com/exercism/cli/config"
+	"github.com/exercism/cli/debug"
 	"github.com/exercism/cli/workspace"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"golang.org/x/text/unicode/norm"
 )
 
 // submitCmd lets people upload a solution to the website.
 var submitCmd = &cobra.Command{
-	Use:     "submit FILE1 [FILE2 ...]",
-	Aliases: []string{"s"},
-	Short:   "Submit your solution to an exercise.",
+	Use:        "submit [FILE_OR_DIR ...]",
+	Aliases:    []string{"s"},
+	SuggestFor: []string{"upload", "push", "send"},
+	Short:      "Submit your solution to an exercise.",
 	Long: `Submit your solution to an Exercism exercise.
 
     Call the command with the list of files you want to submit.
+    Patterns such as *.go are expanded, even where the shell doesn't.
+
+    Pass the exercise directory instead, or call the command without
+    arguments from anywhere within it, to submit the solution files in it.
+    They are the files listed as the solution in the track's
+    .exercism/config.json. For exercises downloaded without that list,
+    all the files are submitted except the tests and the files that come
+    with the exercise to build it, such as package.json or Cargo.toml.
+
+    Files are submitted with the line endings they have, except on Windows,
+    where they are submitted with LF line endings. Set line_endings in your
+    user config to lf, native or crlf to submit them with LF line endings
+    everywhere, or with CRLF line endings. Your files are left as they are.
+    Pass --verbose to see which files were converted. Binary files, such as
+    images, are never converted, and are submitted with their content type.
+
+    Pass --normalize-eol, or set submit.normalize_eol to true in your user
+    config, to submit the files with LF line endings and without a UTF-8 byte
+    order mark whatever the other settings say, which analyzers expect.
+
+    Files that aren't plain UTF-8, e.g. because they start with a byte order
+    mark, get a warning. Set submit.encoding to transcode in your user config
+    to submit them converted to UTF-8 instead, or to ignore to say nothing.
+
+    Editor integrations and scripts can submit a solution that isn't saved
+    to the workspace by piping it in, from within the exercise directory or
+    with the directory as the argument:
+
+        cat solution.go | exercism submit --stdin --filename=solution.go
+
+    Build output and dependencies, such as target/ on the Rust track or
+    node_modules/ on the JavaScript track, are never submitted. Replace the
+    patterns for a track with a list under ignore.<track> in your user config.
+    To leave out more files, such as coverage reports, list them in a
+    .exercismignore file in the exercise directory, or in your workspace
+    for every exercise. It has the syntax of .gitignore:
+
+        bin/
+        coverage/
+        *.log
+        !keep.log
+
+    Files left behind by editors, the operating system or version control,
+    such as .DS_Store, *.swp, *~ or .git/, are skipped as well.
+
+    Submit extra files on purpose, such as helper modules or test data,
+    with --include. They are submitted even if they match an ignore pattern,
+    and the path each one is submitted as is shown:
+
+        exercism submit leap.py --include=helpers/dates.py
+
+    To leave out a header that your files must have, such as a license
+    banner, set submit.strip_headers in your user config to a list of
+    regular expressions. The first one that matches the start of a file is
+    removed from what is submitted, along with the blank lines after it:
+
+        "submit": {"strip_headers": ["(?s)/\\*.*?Copyright.*?\\*/"]}
+
+    Solutions can be published for anyone to see, so files that look like
+    they contain passwords, keys or tokens, and .env files, aren't submitted.
+    If they are false alarms, submit again with --allow-secrets.
+
+    Add a note about what changed in the iteration with --message.
+    It is shown with the iteration on the website:
+
+        exercism submit --message "Refactored to use channels"
+
+    Pass --open to open the solution on the website once it's submitted.
+    Set submit.open to true in your user config to always do so, and pass
+    --open=false to leave it closed for once.
+
+    Pass --prefetch to fetch the next few exercises of the track in the
+    background once the solution is submitted, so that downloading the next
+    one doesn't have to wait for them. Set submit.prefetch to true in your
+    user config to always do so.
+
+    Submitting the same files as last time for an exercise is refused,
+    since it makes an identical iteration. Pass --force to submit them anyway.
+
+    A solution that is in more than one directory isn't submitted either, in
+    case it's the wrong copy. Pass --allow-copies to submit it anyway.
+
+    Pass --format to format the solution files in place with the track's
+    formatter before submitting them, such as gofmt, rustfmt or prettier.
+    Set submit.format to true in your user config to always do so, and
+    choose the formatter for a track with format.<track>:
+
+        "format": {"python": "ruff format"}
+
+    Pass --require-tests-pass to run the exercise's tests with the track's
+    usual tooling first, such as go test or cargo test, and only submit if
+    they pass. Set submit.require_tests_pass to true in your user config to
+    always do so. Pass --allow-failing-tests to submit anyway when they fail.
+
+    Pass --attach-test-results to run the tests first and submit their output
+    and exit code along with the files, so that the website can show them
+    while the online test runner is busy. Set submit.attach_test_results to
+    true in your user config to always do so.
+
+    To submit several exercises in one go, for example after working offline,
+    list them in a manifest and pass it with --manifest. The paths of the
+    exercises are relative to the manifest, and the paths of the files to
+    their exercise. An exercise without files has its solution files submitted:
+
+        {"exercises": [
+          {"path": "go/leap", "files": ["leap.go"]},
+          {"path": "rust/clock"}
+        ]}
+
+    If a mentor is looking at your solution, pass --discussion to post the
+    new iteration to the mentoring discussion, with its --message if it has
+    one, so that your mentor is told about it, as the website does.
+
+    Pass --dry-run to see the exercise and the files that would be
+    submitted, with their sizes as submitted, without submitting them.
+
+    Pass --wait to stay until the tests and the analysis of your iteration
+    are done, and see whether the tests passed and how much feedback there
+    is. It gives up after three minutes, or the time given with --wait-timeout.
 `,
-	Args: cobra.MinimumNArgs(1),
+	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg := config.NewConfig()
 
@@ -36,6 +169,7 @@ var submitCmd = &cobra.Command{
 		usrCfg.SetConfigType("json")
 		// Ignore error. If the file doesn't exist, that is fine.
 		_ = usrCfg.ReadInConfig()
+		config.BindUserEnv(usrCfg)
 		cfg.UserViperConfig = usrCfg
 
 		v := viper.New()
@@ -53,230 +187,1301 @@ func runSubmit(cfg config.Config, flags *pflag.FlagSet, args []string) error {
 	if err := validateUserConfig(cfg.UserViperConfig); err != nil {
 		return err
 	}
+	if manifest, _ := flags.GetString("manifest"); manifest != "" {
+		if len(args) > 0 {
+			return errors.New("with --manifest, the exercises and files to submit are listed in the manifest")
+		}
+		if stdin, _ := flags.GetBool("stdin"); stdin {
+			return errors.New("--manifest and --stdin can't be used together")
+		}
+		return runSubmitManifest(cfg, flags, manifest)
+	}
+	return submitExercise(cfg, flags, args)
+}
+
+// submitManifestEntry is an exercise to submit, as listed in a manifest.
+type submitManifestEntry struct {
+	// Path is the exercise directory, relative to the manifest if it isn't absolute.
+	Path string `json:"path"`
+	// Files are the files to submit, relative to the exercise directory.
+	// The solution files in the directory are submitted if there are none.
+	Files []string `json:"files"`
+}
+
+// runSubmitManifest submits each of the exercises listed in the manifest in turn,
+// and reports how each went. A failure doesn't stop the others from being submitted.
+func runSubmitManifest(cfg config.Config, flags *pflag.FlagSet, manifest string) error {
+	b, err := ioutil.ReadFile(manifest)
+	if err != nil {
+		return err
+	}
+	var payload struct {
+		Exercises []submitManifestEntry `json:"exercises"`
+	}
+	if err := json.Unmarshal(b, &payload); err != nil {
+		return fmt.Errorf("unable to read the manifest %s - %s", manifest, err)
+	}
+	if len(payload.Exercises) == 0 {
+		return fmt.Errorf("the manifest %s doesn't list any exercises", manifest)
+	}
+	base := filepath.Dir(manifest)
+
+	results := make([]error, len(payload.Exercises))
+	for i, entry := range payload.Exercises {
+		dir := filepath.FromSlash(entry.Path)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(base, dir)
+		}
+		args := []string{dir}
+		if len(entry.Files) > 0 {
+			args = nil
+			for _, file := range entry.Files {
+				args = append(args, filepath.Join(dir, filepath.FromSlash(file)))
+			}
+		}
+		fmt.Fprintf(Err, "Submitting %s (%d of %d)\n", entry.Path, i+1, len(payload.Exercises))
+		results[i] = submitExercise(cfg, flags, args)
+	}
 
-	ctx := newSubmitCmdContext(cfg.UserViperConfig, flags)
+	var failed int
+	fmt.Fprintf(Err, "\nSubmitted %d exercise(s):\n\n", len(payload.Exercises))
+	for i, entry := range payload.Exercises {
+		if results[i] == nil {
+			fmt.Fprintf(Err, "    %s %s\n", glyphCompleted, entry.Path)
+			continue
+		}
+		failed++
+		reason := strings.SplitN(strings.TrimSpace(results[i].Error()), "\n", 2)[0]
+		fmt.Fprintf(Err, "    %s %s: %s\n", glyphFailed, entry.Path, reason)
+	}
+	fmt.Fprintln(Err)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d exercises failed to submit", failed, len(payload.Exercises))
+	}
+	return nil
+}
+
+// submitExercise submits the files given in the args, which all belong to one exercise.
+func submitExercise(cfg config.Config, flags *pflag.FlagSet, args []string) error {
+	ctx := newSubmitCmdContext(cfg, flags)
+
+	includes, err := includedFiles(flags)
+	if err != nil {
+		return err
+	}
+	wait, err := waitTimeout(flags)
+	if err != nil {
+		return err
+	}
+	if ctx.message, err = iterationMessage(flags); err != nil {
+		return err
+	}
+	ctx.normalizeEOL = submitSetting(cfg.UserViperConfig, flags, "normalize-eol")
+
+	var (
+		exercise  workspace.Exercise
+		documents []workspace.Document
+	)
+	if stdin, _ := flags.GetBool("stdin"); stdin {
+		var tmpDir string
+		exercise, documents, tmpDir, err = ctx.stdinDocuments(args, flags)
+		if tmpDir != "" {
+			defer os.RemoveAll(tmpDir)
+		}
+	} else {
+		exercise, documents, err = ctx.fileDocuments(args, includes)
+	}
+	if err != nil {
+		return err
+	}
 
-	if err := ctx.validator.filesExistAndNotADir(args); err != nil {
+	if err = ctx.validator.submissionNotEmpty(documents); err != nil {
 		return err
 	}
 
-	submitPaths, err := ctx.evaluatedSymlinks(args)
+	if !allowSecrets(flags) {
+		if err = ctx.validator.noSecrets(documents); err != nil {
+			return err
+		}
+	}
+
+	metadata, err := ctx.metadata(exercise)
 	if err != nil {
 		return err
 	}
 
-	submitPaths = ctx.removeDuplicatePaths(submitPaths)
+	if err := ctx.validator.metadataMatchesExercise(metadata, exercise); err != nil {
+		return err
+	}
+
+	if err := ctx.validator.isRequestor(metadata); err != nil {
+		return err
+	}
+
+	// Each check is overridden by its own flag, so that getting past one doesn't get past the others.
+	if allowCopies, _ := flags.GetBool("allow-copies"); !allowCopies {
+		if err := ctx.validator.notCopied(metadata, exercise); err != nil {
+			return err
+		}
+	}
+
+	if dryRun, _ := flags.GetBool("dry-run"); dryRun {
+		return ctx.printDryRun(metadata, documents)
+	}
+
+	if submitSetting(cfg.UserViperConfig, flags, "format") {
+		if err := ctx.format(exercise, documents); err != nil {
+			return err
+		}
+	}
+
+	if force, _ := flags.GetBool("force"); !force {
+		if err := ctx.notIdenticalToLastSubmission(metadata, documents); err != nil {
+			return err
+		}
+	}
+	requireTestsPass := submitSetting(cfg.UserViperConfig, flags, "require-tests-pass")
+	attachTestResults := submitSetting(cfg.UserViperConfig, flags, "attach-test-results")
+	if requireTestsPass || attachTestResults {
+		results, err := ctx.runTests(exercise)
+		if err != nil {
+			return err
+		}
+		if requireTestsPass {
+			allowFailing, _ := flags.GetBool("allow-failing-tests")
+			if err := results.passOrAllowFailing(allowFailing); err != nil {
+				return err
+			}
+		}
+		if attachTestResults {
+			ctx.testResults = results
+		}
+	}
+
+	var mentoring *discussion
+	if toDiscussion, _ := flags.GetBool("discussion"); toDiscussion {
+		if mentoring, err = ctx.activeDiscussion(metadata); err != nil {
+			return err
+		}
+	}
 
-	if err = ctx.validator.filesBelongToSameExercise(submitPaths); err != nil {
+	var response []byte
+	err = withRetry(cfg, func() error {
+		response, err = ctx.submit(metadata, documents)
 		return err
+	})
+	if err != nil {
+		return err
+	}
+	iterationID := parseIterationID(response)
+
+	ctx.recordSubmission(metadata, documents, iterationID)
+	ctx.recordIteration(exercise, documents, response)
+	ctx.printResult(metadata)
+	if mentoring != nil {
+		ctx.postToDiscussion(mentoring, parseIterationIndex(response))
+	}
+	if submitSetting(cfg.UserViperConfig, flags, "open") {
+		if err := openBrowser(metadata.URL); err != nil {
+			fmt.Fprintf(Err, "Could not open %s in your browser: %s\n\n", metadata.URL, err)
+		}
+	}
+	if submitSetting(cfg.UserViperConfig, flags, "prefetch") {
+		if err := startPrefetch(metadata.Track, metadata.ExerciseSlug); err != nil {
+			fmt.Fprintf(Err, "Warning: unable to fetch the next exercises of %s ahead of time: %s\n\n", metadata.Track, err)
+		}
+	}
+	if wait > 0 {
+		ctx.waitForIteration(metadata, iterationID, wait)
+	}
+	ctx.printGoalProgress()
+	return nil
+}
+
+type submitCmdContext struct {
+	usrCfg    *viper.Viper
+	stateDir  string
+	flags     *pflag.FlagSet
+	validator submitValidator
+	// included are the files given with --include, which are submitted even if they match an ignore pattern.
+	included map[string]bool
+	// message is the note given with --message, which is shown with the iteration on the website.
+	message string
+	// normalizeEOL submits the files with LF line endings and without a UTF-8 byte order mark,
+	// whatever the line_endings and submit.encoding settings say.
+	normalizeEOL bool
+	// testResults are the outcome of running the tests locally, which --attach-test-results submits.
+	testResults *localTestResults
+	// submittedHash fingerprints the files as they were last sent, for the local history.
+	submittedHash string
+}
+
+// openBrowser opens a URL in the browser. It is a variable so that tests don't launch one.
+var openBrowser = browser.Open
+
+// submitSetting tells whether an opt-in flag is on, such as --open, or else its setting
+// in the user config, such as submit.open.
+func submitSetting(usrCfg *viper.Viper, flags *pflag.FlagSet, name string) bool {
+	if flags.Changed(name) {
+		on, _ := flags.GetBool(name)
+		return on
+	}
+	return usrCfg.GetBool("submit." + strings.Replace(name, "-", "_", -1))
+}
+
+// includedFiles returns the extra files to submit that were given with --include.
+func includedFiles(flags *pflag.FlagSet) ([]string, error) {
+	if flags.Lookup("include") == nil {
+		return nil, nil
+	}
+	return flags.GetStringSlice("include")
+}
+
+// iterationMessage is the note to submit along with the files, given with --message.
+func iterationMessage(flags *pflag.FlagSet) (string, error) {
+	if flags.Lookup("message") == nil {
+		return "", nil
+	}
+	message, err := flags.GetString("message")
+	if err != nil {
+		return "", err
+	}
+	message = strings.TrimSpace(message)
+	if flags.Changed("message") && message == "" {
+		return "", errors.New("the message given with --message is empty")
+	}
+	return message, nil
+}
+
+// allowSecrets tells whether files that look like they contain credentials may be submitted anyway.
+func allowSecrets(flags *pflag.FlagSet) bool {
+	if flags.Lookup("allow-secrets") == nil {
+		return false
+	}
+	allow, _ := flags.GetBool("allow-secrets")
+	return allow
+}
+
+// waitTimeout is how long to wait for the new iteration to be processed, or zero to not wait.
+func waitTimeout(flags *pflag.FlagSet) (time.Duration, error) {
+	if flags.Lookup("wait") == nil {
+		return 0, nil
+	}
+	wait, err := flags.GetBool("wait")
+	if err != nil || !wait {
+		return 0, err
+	}
+	timeout, err := flags.GetDuration("wait-timeout")
+	if err != nil {
+		return 0, err
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("--wait-timeout has to be more than zero, not %s", timeout)
+	}
+	return timeout, nil
+}
+
+func newSubmitCmdContext(cfg config.Config, flags *pflag.FlagSet) *submitCmdContext {
+	return &submitCmdContext{
+		usrCfg:    cfg.UserViperConfig,
+		stateDir:  cfg.StateDir,
+		flags:     flags,
+		validator: submitValidator{usrCfg: cfg.UserViperConfig, stateDir: cfg.StateDir},
+	}
+}
+
+// expandDirectories replaces the directories in the paths with the solution files in them.
+func (s *submitCmdContext) expandDirectories(paths []string) ([]string, error) {
+	expanded := make([]string, 0, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil || !info.IsDir() {
+			// Missing files are reported along with the others.
+			expanded = append(expanded, path)
+			continue
+		}
+		files, err := s.solutionFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		expanded = append(expanded, files...)
+	}
+	return expanded, nil
+}
+
+// expandGlobs expands the arguments that are glob patterns, such as *.go, into the files they match.
+// Shells on Windows leave that to the program. An argument that is the name of a file,
+// or that matches nothing, is left as it is, so that a missing file is reported as such.
+func expandGlobs(paths []string) []string {
+	expanded := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if !strings.ContainsAny(path, "*?[") {
+			expanded = append(expanded, path)
+			continue
+		}
+		if _, err := os.Lstat(path); err == nil {
+			expanded = append(expanded, path)
+			continue
+		}
+		matches, err := filepath.Glob(path)
+		if err != nil || len(matches) == 0 {
+			expanded = append(expanded, path)
+			continue
+		}
+		expanded = append(expanded, matches...)
+	}
+	return expanded
+}
+
+// exerciseRoot finds the directory of the exercise that the path is in.
+func (s *submitCmdContext) exerciseRoot(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	if dir, err = workspace.EvalSymlinks(dir); err != nil {
+		return "", err
+	}
+	ws, err := workspace.New(s.usrCfg.GetString("workspace"))
+	if err != nil {
+		return "", err
+	}
+	root, err := ws.ExerciseDir(dir)
+	if err != nil {
+		msg := `
+
+    The directory you are submitting isn't an exercise in your workspace.
+
+        %s
+
+    Change into the exercise directory, or provide the path to the file(s) you wish to submit
+
+        %s submit FILENAME
+
+            `
+		return "", fmt.Errorf(msg, dir, BinaryName)
+	}
+	return root, nil
+}
+
+// solutionFiles finds the files to submit in a directory of an exercise.
+// The track's configuration of the exercise says which files make up the solution.
+// Exercises without it have their tests and build files left out instead.
+func (s *submitCmdContext) solutionFiles(dir string) ([]string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if dir, err = workspace.EvalSymlinks(dir); err != nil {
+		return nil, err
+	}
+	root, err := s.exerciseRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := filepath.Rel(root, dir)
+	if err != nil {
+		return nil, err
+	}
+	prefix = filepath.ToSlash(prefix)
+	inDir := func(file string) bool {
+		return prefix == "." || strings.HasPrefix(file, prefix+"/")
+	}
+
+	exerciseConfig, err := workspace.NewExerciseConfig(root)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var files []string
+	if exerciseConfig != nil && len(exerciseConfig.Files.Solution) > 0 {
+		for _, file := range exerciseConfig.Files.Solution {
+			file = path.Clean(file)
+			if !inDir(file) {
+				continue
+			}
+			abs := filepath.Join(root, filepath.FromSlash(file))
+			if _, err := os.Stat(abs); os.IsNotExist(err) {
+				msg := `
+
+    The solution file '%s' listed in .exercism/config.json cannot be found.
+
+        %s
+
+    Please provide the path to the file(s) you wish to submit
+
+        %s submit FILENAME
+
+            `
+				return nil, fmt.Errorf(msg, file, abs, BinaryName)
+			}
+			files = append(files, abs)
+		}
+	} else {
+		ignored, err := ignorePatterns(s.usrCfg, workspace.NewExerciseFromDir(root).Track, root)
+		if err != nil {
+			return nil, err
+		}
+		all, err := exerciseFiles(root, ignored)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range all {
+			if !inDir(file) || workspace.IsTestOrToolingFile(file) {
+				continue
+			}
+			if exerciseConfig != nil && exerciseConfig.IsSupportFile(file) {
+				continue
+			}
+			files = append(files, filepath.Join(root, filepath.FromSlash(file)))
+		}
+	}
+
+	if len(files) == 0 {
+		msg := `
+
+    No solution files found in the directory.
+
+        %s
+
+    Please provide the path to the file(s) you wish to submit
+
+        %s submit FILENAME
+
+            `
+		return nil, fmt.Errorf(msg, dir, BinaryName)
+	}
+	fmt.Fprintf(Err, "Submitting the solution files in %s:\n\n", dir)
+	for _, file := range files {
+		rel, _ := filepath.Rel(root, file)
+		fmt.Fprintf(Err, "    %s\n", filepath.ToSlash(rel))
+	}
+	fmt.Fprintln(Err)
+	return files, nil
+}
+
+// fileDocuments finds the exercise and builds the documents to submit from the files
+// and directories given in the args, along with the extra files given with --include.
+func (s *submitCmdContext) fileDocuments(args, includes []string) (workspace.Exercise, []workspace.Document, error) {
+	if len(args) == 0 {
+		// Submit the whole exercise, even from a directory within it.
+		root, err := s.exerciseRoot(".")
+		if err != nil {
+			return workspace.Exercise{}, nil, err
+		}
+		args = []string{root}
+	}
+	args = expandGlobs(args)
+	includes = expandGlobs(includes)
+	args, err := s.expandDirectories(args)
+	if err != nil {
+		return workspace.Exercise{}, nil, err
+	}
+
+	if err := s.validator.filesExistAndNotADir(append(args, includes...)); err != nil {
+		return workspace.Exercise{}, nil, err
 	}
 
-	exercise, err := ctx.exercise(submitPaths[0])
+	submitPaths, err := s.evaluatedSymlinks(args)
+	if err != nil {
+		return workspace.Exercise{}, nil, err
+	}
+	includePaths, err := s.evaluatedSymlinks(includes)
+	if err != nil {
+		return workspace.Exercise{}, nil, err
+	}
+	s.included = make(map[string]bool, len(includePaths))
+	for _, path := range includePaths {
+		s.included[path] = true
+	}
+
+	submitPaths = s.removeDuplicatePaths(append(submitPaths, includePaths...))
+
+	if err = s.validator.filesBelongToSameExercise(submitPaths); err != nil {
+		return workspace.Exercise{}, nil, err
+	}
+
+	exercise, err := s.exercise(submitPaths[0])
+	if err != nil {
+		return workspace.Exercise{}, nil, err
+	}
+
+	if err = s.migrateLegacyMetadata(exercise); err != nil {
+		return workspace.Exercise{}, nil, err
+	}
+
+	if err = s.validator.fileSizesWithinMax(submitPaths); err != nil {
+		return workspace.Exercise{}, nil, err
+	}
+
+	documents, err := s.documents(submitPaths, exercise)
+	if err != nil {
+		return workspace.Exercise{}, nil, err
+	}
+	return exercise, documents, nil
+}
+
+// stdinDocuments builds the document to submit from what is read from standard input,
+// as the file given with --filename in the exercise that the directory in the args,
+// or the current directory, is in. The contents are kept in a temporary directory,
+// which the caller removes.
+func (s *submitCmdContext) stdinDocuments(args []string, flags *pflag.FlagSet) (workspace.Exercise, []workspace.Document, string, error) {
+	filename, err := flags.GetString("filename")
+	if err != nil {
+		return workspace.Exercise{}, nil, "", err
+	}
+	relPath := path.Clean(filepath.ToSlash(filename))
+	if filename == "" || path.IsAbs(relPath) || filepath.IsAbs(filename) || relPath == ".." || strings.HasPrefix(relPath, "../") {
+		msg := `
+
+    Give the path within the exercise to submit the solution as with --filename
+
+        %s submit --stdin --filename=FILENAME
+
+        `
+		return workspace.Exercise{}, nil, "", fmt.Errorf(msg, BinaryName)
+	}
+	if len(args) > 1 {
+		return workspace.Exercise{}, nil, "", errors.New("with --stdin, give at most the directory of the exercise")
+	}
+	dir := "."
+	if len(args) == 1 {
+		dir = args[0]
+	}
+	root, err := s.exerciseRoot(dir)
+	if err != nil {
+		return workspace.Exercise{}, nil, "", err
+	}
+	exercise := workspace.NewExerciseFromDir(root)
+	if err = s.migrateLegacyMetadata(exercise); err != nil {
+		return workspace.Exercise{}, nil, "", err
+	}
+
+	contents, err := ioutil.ReadAll(In)
+	if err != nil {
+		return workspace.Exercise{}, nil, "", err
+	}
+	if len(contents) == 0 {
+		return workspace.Exercise{}, nil, "", errors.New("nothing was read from standard input to submit")
+	}
+
+	tmpDir, err := ioutil.TempDir("", "exercism-stdin")
+	if err != nil {
+		return workspace.Exercise{}, nil, "", err
+	}
+	file := filepath.Join(tmpDir, filepath.FromSlash(relPath))
+	if err := os.MkdirAll(filepath.Dir(file), os.FileMode(0755)); err != nil {
+		return workspace.Exercise{}, nil, tmpDir, err
+	}
+	if err := ioutil.WriteFile(file, contents, os.FileMode(0644)); err != nil {
+		return workspace.Exercise{}, nil, tmpDir, err
+	}
+	if err = s.validator.fileSizesWithinMax([]string{file}); err != nil {
+		return workspace.Exercise{}, nil, tmpDir, err
+	}
+	doc, err := workspace.NewDocument(tmpDir, file)
+	if err != nil {
+		return workspace.Exercise{}, nil, tmpDir, err
+	}
+	return exercise, []workspace.Document{doc}, tmpDir, nil
+}
+
+// evaluatedSymlinks returns the submit paths with evaluated symlinks.
+func (s *submitCmdContext) evaluatedSymlinks(submitPaths []string) ([]string, error) {
+	evalSymlinkSubmitPaths := make([]string, 0, len(submitPaths))
+	for _, path := range submitPaths {
+		var err error
+		path, err = filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+
+		src, err := workspace.EvalSymlinks(path)
+		if err != nil {
+			return nil, err
+		}
+		evalSymlinkSubmitPaths = append(evalSymlinkSubmitPaths, src)
+	}
+	return evalSymlinkSubmitPaths, nil
+}
+
+func (s *submitCmdContext) removeDuplicatePaths(submitPaths []string) []string {
+	seen := make(map[string]bool)
+	result := make([]string, 0, len(submitPaths))
+
+	for _, val := range submitPaths {
+		if _, ok := seen[val]; !ok {
+			seen[val] = true
+			result = append(result, val)
+		}
+	}
+
+	return result
+}
+
+// exercise creates an exercise using one of the submitted filepaths.
+// This assumes prior verification that submit paths belong to the same exercise.
+func (s *submitCmdContext) exercise(aSubmitPath string) (workspace.Exercise, error) {
+	ws, err := workspace.New(s.usrCfg.GetString("workspace"))
+	if err != nil {
+		return workspace.Exercise{}, err
+	}
+
+	dir, err := ws.ExerciseDir(aSubmitPath)
+	if err != nil {
+		return workspace.Exercise{}, err
+	}
+	return workspace.NewExerciseFromDir(dir), nil
+}
+
+func (s *submitCmdContext) migrateLegacyMetadata(exercise workspace.Exercise) error {
+	migrationStatus, err := exercise.MigrateLegacyMetadataFile()
 	if err != nil {
 		return err
 	}
+	if verbose, _ := s.flags.GetBool("verbose"); verbose {
+		fmt.Fprint(Err, migrationStatus.String())
+	}
+	return nil
+}
+
+// documents builds the documents that get submitted.
+// Empty files and files matching the track's ignore patterns are skipped, printing a warning.
+// Files left behind by editors and the like are skipped too, with a note.
+func (s *submitCmdContext) documents(submitPaths []string, exercise workspace.Exercise) ([]workspace.Document, error) {
+	ignored, err := ignorePatterns(s.usrCfg, exercise.Track, exercise.Filepath())
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]workspace.Document, 0, len(submitPaths))
+	var extras []workspace.Document
+	var junk []string
+	for _, file := range submitPaths {
+		// Don't submit empty files
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		if info.Size() == 0 {
+
+			msg := `
+
+    WARNING: Skipping empty file
+             %s
+
+        `
+			fmt.Fprintf(Err, msg, file)
+			continue
+		}
+		doc, err := workspace.NewDocument(exercise.Filepath(), file)
+		if err != nil {
+			return nil, err
+		}
+		if s.included[file] {
+			extras = append(extras, doc)
+			docs = append(docs, doc)
+			continue
+		}
+		if workspace.IsJunkFile(doc.Path()) {
+			junk = append(junk, doc.Path())
+			continue
+		}
+		if pattern := ignored.Match(doc.Path()); pattern != "" {
+			msg := `
+
+    WARNING: Skipping %s, which matches the ignore pattern '%s'
+             Change the patterns for the track with ignore.%s in your user config,
+             or in a .exercismignore file in the exercise or the workspace.
+
+        `
+			fmt.Fprintf(Err, msg, file, pattern, exercise.Track)
+			continue
+		}
+		docs = append(docs, doc)
+	}
+
+	if len(junk) > 0 {
+		fmt.Fprintf(Err, "Skipping %d editor or system file(s), which aren't part of the solution:\n\n", len(junk))
+		for _, path := range junk {
+			fmt.Fprintf(Err, "    %s\n", path)
+		}
+		fmt.Fprintln(Err)
+	}
+	if len(extras) > 0 {
+		fmt.Fprintf(Err, "Including %d extra file(s), which will be submitted as:\n\n", len(extras))
+		for _, doc := range extras {
+			fmt.Fprintf(Err, "    %s\n", norm.NFC.String(doc.Path()))
+		}
+		fmt.Fprintln(Err)
+	}
+	return docs, nil
+}
+
+func (s *submitCmdContext) metadata(exercise workspace.Exercise) (*workspace.ExerciseMetadata, error) {
+	metadata, err := workspace.NewExerciseMetadata(exercise.Filepath())
+	if err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// submit submits the documents to the Exercism API.
+// Large submissions are uploaded in chunks when the API supports it.
+func (s *submitCmdContext) submit(metadata *workspace.ExerciseMetadata, docs []workspace.Document) ([]byte, error) {
+	var statePath string
+	var state *uploadState
+	if s.stateDir != "" {
+		statePath = uploadStatePath(s.stateDir, metadata.ID)
+		// A corrupt state file just means we start the upload from scratch.
+		state, _ = loadUploadState(statePath)
+	}
+
+	// Measure the body first, so that it can be checked and its length given
+	// without keeping it all in memory.
+	var size byteCounter
+	measure := multipart.NewWriter(&size)
+	if state != nil {
+		// Reuse the boundary so that the body of a resumed upload is identical.
+		if err := measure.SetBoundary(state.Boundary); err != nil {
+			state = nil
+		}
+	}
+	sizes, err := s.writeBody(measure, docs, true)
+	if err != nil {
+		return nil, err
+	}
+	s.submittedHash = payloadHash(sizes)
+
+	client, err := api.NewClient(s.usrCfg.GetString("token"), s.usrCfg.GetString("apibaseurl"))
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/solutions/%s", s.usrCfg.GetString("apibaseurl"), metadata.ID)
+
+	if s.chunked(int64(size)) {
+		// A chunked upload is checksummed and sent in pieces, so it needs the whole body.
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		if err := writer.SetBoundary(measure.Boundary()); err != nil {
+			return nil, err
+		}
+		if _, err := s.writeBody(writer, docs, false); err != nil {
+			return nil, err
+		}
+		upload := &chunkedUpload{
+			client:    client,
+			url:       url,
+			statePath: statePath,
+			state:     state,
+		}
+		err := upload.run(body.Bytes(), writer.Boundary())
+		if err == nil {
+			return upload.response, nil
+		}
+		if err != errChunkedUploadUnsupported {
+			return nil, err
+		}
+	}
+
+	// Only a submission sent in one request is held to the limit of its size.
+	if err := s.validator.payloadWithinMax(int64(size), sizes); err != nil {
+		return nil, err
+	}
+	return s.stream(client, url, docs, measure.Boundary(), int64(size))
+}
+
+// chunked tells whether a submission of the given size is uploaded in chunks,
+// provided that the API supports it.
+func (s *submitCmdContext) chunked(size int64) bool {
+	return s.stateDir != "" && size > chunkedUploadThreshold
+}
+
+// stream sends the submission in one request, writing the body as it is sent.
+func (s *submitCmdContext) stream(client *api.Client, url string, docs []workspace.Document, boundary string, size int64) ([]byte, error) {
+	pr, pw := io.Pipe()
+	// Closing the reader stops the writer if the request ends before the body is sent.
+	defer pr.Close()
+
+	writer := multipart.NewWriter(pw)
+	if err := writer.SetBoundary(boundary); err != nil {
+		return nil, err
+	}
+	go func() {
+		_, err := s.writeBody(writer, docs, false)
+		pw.CloseWithError(err)
+	}()
+
+	req, err := client.NewRequest("PATCH", url, pr)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, decodedAPIError(resp)
+	}
+
+	bb := &bytes.Buffer{}
+	_, err = bb.ReadFrom(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return bb.Bytes(), nil
+}
+
+// byteCounter counts the bytes written to it, to measure a body without keeping it.
+type byteCounter int64
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	*c += byteCounter(len(p))
+	return len(p), nil
+}
+
+// ignorePatterns are the patterns of files not to submit from an exercise directory.
+// The built-in patterns for the track can be replaced with a list under ignore.<track> in the user config.
+// The .exercismignore files in the workspace and in the exercise directory add to them, in that order.
+func ignorePatterns(usrCfg *viper.Viper, track, dir string) (workspace.IgnorePatterns, error) {
+	patterns := workspace.DefaultIgnorePatterns(track)
+	key := fmt.Sprintf("ignore.%s", track)
+	if usrCfg != nil && usrCfg.IsSet(key) {
+		patterns = workspace.IgnorePatterns(usrCfg.GetStringSlice(key))
+	}
+
+	var ignoreFiles []string
+	if usrCfg != nil && usrCfg.GetString("workspace") != "" {
+		ignoreFiles = append(ignoreFiles, filepath.Join(usrCfg.GetString("workspace"), workspace.IgnoreFilename))
+	}
+	ignoreFiles = append(ignoreFiles, filepath.Join(dir, workspace.IgnoreFilename))
+	for _, filename := range ignoreFiles {
+		more, err := workspace.ReadIgnoreFile(filename)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, more...)
+	}
+	return patterns, nil
+}
+
+// writeBody writes the documents to the multipart body as they are submitted,
+// converted as configured, and returns the size of each.
+// The conversions are reported if asked, so that writing the body again doesn't repeat them.
+func (s *submitCmdContext) writeBody(writer *multipart.Writer, docs []workspace.Document, report bool) ([]submittedFileSize, error) {
+	lineEndings, err := workspace.NewLineEndings(s.usrCfg.GetString("line_endings"))
+	if err != nil {
+		return nil, err
+	}
+	ending := lineEndings.ForSubmission()
+	if s.normalizeEOL {
+		ending = workspace.LineEndingsLF.ForSubmission()
+	}
+	encodingPolicy, err := workspace.NewEncodingPolicy(s.usrCfg.GetString("submit.encoding"))
+	if err != nil {
+		return nil, err
+	}
+	headers, err := workspace.NewHeaderPatterns(s.usrCfg.GetStringSlice("submit.strip_headers"))
+	if err != nil {
+		return nil, err
+	}
+
+	if s.message != "" {
+		if err := writer.WriteField("message", s.message); err != nil {
+			return nil, err
+		}
+	}
+	if s.testResults != nil {
+		if err := writeTestResults(writer, s.testResults); err != nil {
+			return nil, err
+		}
+	}
+
+	sizes := make([]submittedFileSize, 0, len(docs))
+	for _, doc := range docs {
+		contents, err := ioutil.ReadFile(workspace.LongPath(doc.Filepath()))
+		if err != nil {
+			return nil, err
+		}
+		contentType := "application/octet-stream"
+		var transforms []string
+		if workspace.IsBinary(contents) {
+			// Binary files, such as images, are submitted byte for byte, with their type.
+			contentType = workspace.ContentType(contents)
+			if report {
+				debug.Printf("Submitting %s as it is, since it is a binary file (%s)\n", doc.Path(), contentType)
+			}
+		} else {
+			// The file on disk is left as it is; only what is submitted is converted.
+			var bom, stripped, converted bool
+			if s.normalizeEOL {
+				contents, bom = workspace.StripBOM(contents)
+				if bom {
+					transforms = append(transforms, "byte order mark removed")
+				}
+			}
+			transcoded := checkEncoding(doc.Path(), contents, encodingPolicy, report)
+			if !bytes.Equal(transcoded, contents) {
+				transforms = append(transforms, "converted to UTF-8")
+			}
+			contents, stripped = headers.Strip(transcoded)
+			if stripped {
+				transforms = append(transforms, "header removed")
+			}
+			contents, converted = workspace.ConvertLineEndings(contents, ending)
+			if converted {
+				transforms = append(transforms, fmt.Sprintf("line endings converted to %s", workspace.LineEndingName(ending)))
+			}
+			if report && len(transforms) > 0 {
+				debug.Printf("Submitting %s with its %s\n", doc.Path(), strings.Join(transforms, ", "))
+			}
+		}
 
-	if err = ctx.migrateLegacyMetadata(exercise); err != nil {
-		return err
+		part, err := createFormFile(writer, "files[]", doc.Path(), contentType)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = part.Write(contents); err != nil {
+			return nil, err
+		}
+		sizes = append(sizes, submittedFileSize{
+			path:       doc.Path(),
+			size:       int64(len(contents)),
+			transforms: transforms,
+			sum:        sha256.Sum256(contents),
+		})
 	}
-
-	if err = ctx.validator.fileSizesWithinMax(submitPaths); err != nil {
-		return err
+	if err := writer.Close(); err != nil {
+		return nil, err
 	}
+	return sizes, nil
+}
 
-	documents, err := ctx.documents(submitPaths, exercise)
+// writeTestResults adds the outcome of the local tests to a multipart body, as JSON.
+func writeTestResults(writer *multipart.Writer, results *localTestResults) error {
+	b, err := json.Marshal(results)
 	if err != nil {
 		return err
 	}
-
-	if err = ctx.validator.submissionNotEmpty(documents); err != nil {
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", `form-data; name="local_test_results"`)
+	h.Set("Content-Type", "application/json")
+	part, err := writer.CreatePart(h)
+	if err != nil {
 		return err
 	}
+	_, err = part.Write(b)
+	return err
+}
 
-	metadata, err := ctx.metadata(exercise)
+// printDryRun shows what would be submitted, with the size of each file as it would be submitted.
+func (s *submitCmdContext) printDryRun(metadata *workspace.ExerciseMetadata, docs []workspace.Document) error {
+	var size byteCounter
+	sizes, err := s.writeBody(multipart.NewWriter(&size), docs, true)
 	if err != nil {
 		return err
 	}
-
-	if err := ctx.validator.metadataMatchesExercise(metadata, exercise); err != nil {
-		return err
+	if !s.chunked(int64(size)) {
+		if err := s.validator.payloadWithinMax(int64(size), sizes); err != nil {
+			return err
+		}
 	}
 
-	if err := ctx.validator.isRequestor(metadata); err != nil {
-		return err
+	fmt.Fprintf(Out, "Exercise: %s\n", metadata)
+	fmt.Fprintf(Out, "Solution: %s\n", metadata.ID)
+	if s.message != "" {
+		fmt.Fprintf(Out, "Message: %s\n", s.message)
 	}
-
-	if err := ctx.submit(metadata, documents); err != nil {
-		return err
+	fmt.Fprintf(Out, "Files:\n")
+	w := tabwriter.NewWriter(Out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	for _, file := range sizes {
+		var transforms string
+		if len(file.transforms) > 0 {
+			transforms = fmt.Sprintf(" (%s)", strings.Join(file.transforms, ", "))
+		}
+		fmt.Fprintf(w, "    %s\t  %s%s\n", humanSize(file.size), norm.NFC.String(file.path), transforms)
 	}
-
-	ctx.printResult(metadata)
+	w.Flush()
+	fmt.Fprintf(Out, "Total: %s in %d file(s)\n", humanSize(int64(size)), len(sizes))
+	fmt.Fprintln(Err, "\nThis was a dry run, so nothing was submitted.")
 	return nil
 }
 
-type submitCmdContext struct {
-	usrCfg    *viper.Viper
-	flags     *pflag.FlagSet
-	validator submitValidator
+// createFormFile adds a file of the given content type to a multipart body, like
+// multipart.Writer.CreateFormFile, taking care that paths with characters other than ASCII arrive intact.
+// Paths are sent as UTF-8 in the filename parameter, as RFC 7578 calls for, normalized to NFC,
+// since e.g. macOS decomposes accented characters in file names.
+func createFormFile(w *multipart.Writer, fieldname, path, contentType string) (io.Writer, error) {
+	path = norm.NFC.String(path)
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, quoteEscaper.Replace(fieldname), quoteEscaper.Replace(path)))
+	h.Set("Content-Type", contentType)
+	return w.CreatePart(h)
 }
 
-func newSubmitCmdContext(usrCfg *viper.Viper, flags *pflag.FlagSet) *submitCmdContext {
-	return &submitCmdContext{
-		usrCfg:    usrCfg,
-		flags:     flags,
-		validator: submitValidator{usrCfg: usrCfg},
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// checkEncoding warns about files that many test runners can't read, or converts them to plain UTF-8,
+// depending on the policy. It returns the contents to submit.
+// Nothing is said unless report is set.
+func checkEncoding(path string, contents []byte, policy workspace.EncodingPolicy, report bool) []byte {
+	encoding := workspace.DetectEncoding(contents)
+	if encoding == workspace.EncodingUTF8 || encoding == workspace.EncodingBinary || policy == workspace.EncodingIgnore {
+		return contents
+	}
+	if policy == workspace.EncodingTranscode {
+		if !report {
+			return workspace.ToUTF8(contents)
+		}
+		debug.Printf("Converted %s from %s to UTF-8 for submission\n", path, encoding)
+		return workspace.ToUTF8(contents)
+	}
+	if !report {
+		return contents
 	}
+	fmt.Fprintf(Err, "Warning: %s is %s, which the test runners of some tracks can't read.\n", path, encoding)
+	fmt.Fprintf(Err, "         Set submit.encoding to transcode in your user config to submit it as plain UTF-8.\n")
+	return contents
 }
 
-// evaluatedSymlinks returns the submit paths with evaluated symlinks.
-func (s *submitCmdContext) evaluatedSymlinks(submitPaths []string) ([]string, error) {
-	evalSymlinkSubmitPaths := make([]string, 0, len(submitPaths))
-	for _, path := range submitPaths {
-		var err error
-		path, err = filepath.Abs(path)
-		if err != nil {
-			return nil, err
-		}
+// recordSubmission adds the submission to the local history.
+// The submission has already succeeded, so failing to record it is only a warning.
+func (s *submitCmdContext) recordSubmission(metadata *workspace.ExerciseMetadata, docs []workspace.Document, iterationID string) {
+	if s.stateDir == "" {
+		return
+	}
+	record := submissionRecord{
+		SubmittedAt: time.Now(),
+		Track:       metadata.Track,
+		Exercise:    metadata.ExerciseSlug,
+		SolutionID:  metadata.ID,
+		IterationID: iterationID,
+	}
+	for _, doc := range docs {
+		record.Files = append(record.Files, doc.Path())
+	}
+	record.PayloadHash = s.submittedHash
+	if err := appendSubmissionRecord(s.stateDir, record); err != nil {
+		fmt.Fprintf(Err, "Warning: unable to record the submission in the local history: %s\n", err)
+	}
+}
 
-		src, err := filepath.EvalSymlinks(path)
+// recordIteration keeps a record of the submission in the exercise directory, for exercism log.
+// The submission has already succeeded, so failing to record it is only a warning.
+func (s *submitCmdContext) recordIteration(exercise workspace.Exercise, docs []workspace.Document, response []byte) {
+	record := workspace.IterationRecord{
+		SubmittedAt: time.Now(),
+		IterationID: parseIterationID(response),
+		Index:       parseIterationIndex(response),
+		Message:     s.message,
+		Files:       workspace.Checksums{},
+	}
+	if json.Valid(response) {
+		record.Response = response
+	}
+	for _, doc := range docs {
+		contents, err := ioutil.ReadFile(workspace.LongPath(doc.Filepath()))
 		if err != nil {
-			return nil, err
+			fmt.Fprintf(Err, "Warning: unable to record the iteration in the exercise directory: %s\n", err)
+			return
 		}
-		evalSymlinkSubmitPaths = append(evalSymlinkSubmitPaths, src)
+		record.Files.Set(doc.Path(), contents)
+	}
+	if err := record.Write(exercise.Filepath()); err != nil {
+		fmt.Fprintf(Err, "Warning: unable to record the iteration in the exercise directory: %s\n", err)
 	}
-	return evalSymlinkSubmitPaths, nil
 }
 
-func (s *submitCmdContext) removeDuplicatePaths(submitPaths []string) []string {
-	seen := make(map[string]bool)
-	result := make([]string, 0, len(submitPaths))
-
-	for _, val := range submitPaths {
-		if _, ok := seen[val]; !ok {
-			seen[val] = true
-			result = append(result, val)
+// format formats the solution files in place with the track's formatter.
+// The extra files given with --include are left as they are.
+func (s *submitCmdContext) format(exercise workspace.Exercise, docs []workspace.Document) error {
+	args, err := formatterCommand(s.usrCfg, exercise.Track)
+	if err != nil {
+		return err
+	}
+	var files []string
+	for _, doc := range docs {
+		if !s.included[doc.Filepath()] {
+			files = append(files, doc.Filepath())
 		}
 	}
+	if len(files) == 0 {
+		return nil
+	}
+	debug.Printf("Formatting the solution with %s\n", strings.Join(args, " "))
+	if err := runFormatter(exercise.Filepath(), args, files, Err); err != nil {
+		msg := `
 
-	return result
-}
+    The solution couldn't be formatted with %s (%s),
+    so it wasn't submitted.
 
-// exercise creates an exercise using one of the submitted filepaths.
-// This assumes prior verification that submit paths belong to the same exercise.
-func (s *submitCmdContext) exercise(aSubmitPath string) (workspace.Exercise, error) {
-	ws, err := workspace.New(s.usrCfg.GetString("workspace"))
-	if err != nil {
-		return workspace.Exercise{}, err
-	}
+    Change the formatter with format.%s in your user config,
+    or submit without formatting
 
-	dir, err := ws.ExerciseDir(aSubmitPath)
-	if err != nil {
-		return workspace.Exercise{}, err
+        %s submit --format=false
+
+        `
+		return fmt.Errorf(msg, args[0], err, exercise.Track, BinaryName)
 	}
-	return workspace.NewExerciseFromDir(dir), nil
+	return nil
 }
 
-func (s *submitCmdContext) migrateLegacyMetadata(exercise workspace.Exercise) error {
-	migrationStatus, err := exercise.MigrateLegacyMetadataFile()
+// activeDiscussion finds the mentoring discussion going on about the solution,
+// which --discussion posts the new iteration to. It is an error if there is none,
+// so that the iteration isn't submitted without the mentor hearing of it.
+func (s *submitCmdContext) activeDiscussion(metadata *workspace.ExerciseMetadata) (*discussion, error) {
+	client, err := api.NewClient(s.usrCfg.GetString("token"), s.usrCfg.GetString("apibaseurl"))
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if verbose, _ := s.flags.GetBool("verbose"); verbose {
-		fmt.Fprintf(Err, migrationStatus.String())
+	d, err := activeDiscussion(client, s.usrCfg.GetString("apibaseurl"), metadata.ID)
+	if err != nil {
+		return nil, err
 	}
-	return nil
-}
+	if d == nil {
+		msg := `
 
-// documents builds the documents that get submitted.
-// Empty files are skipped, printing a warning.
-func (s *submitCmdContext) documents(submitPaths []string, exercise workspace.Exercise) ([]workspace.Document, error) {
-	docs := make([]workspace.Document, 0, len(submitPaths))
-	for _, file := range submitPaths {
-		// Don't submit empty files
-		info, err := os.Stat(file)
-		if err != nil {
-			return nil, err
-		}
-		if info.Size() == 0 {
+    There is no mentoring discussion going on about %s,
+    so the solution wasn't submitted.
 
-			msg := `
+    To submit it anyway, leave out --discussion
 
-    WARNING: Skipping empty file
-             %s
+        %s submit
 
         `
-			fmt.Fprintf(Err, msg, file)
-			continue
-		}
-		doc, err := workspace.NewDocument(exercise.Filepath(), file)
-		if err != nil {
-			return nil, err
-		}
-		docs = append(docs, doc)
+		return nil, fmt.Errorf(msg, metadata, BinaryName)
 	}
-	return docs, nil
+	return d, nil
 }
 
-func (s *submitCmdContext) metadata(exercise workspace.Exercise) (*workspace.ExerciseMetadata, error) {
-	metadata, err := workspace.NewExerciseMetadata(exercise.Filepath())
+// postToDiscussion tells the mentor about the new iteration, with its message if it has one,
+// and hands the discussion back to them, as the website does.
+// The solution has been submitted by then, so failing to post is only a warning.
+func (s *submitCmdContext) postToDiscussion(d *discussion, index int) {
+	content := s.message
+	if content == "" {
+		content = "I've submitted a new iteration."
+		if index > 0 {
+			content = fmt.Sprintf("I've submitted iteration %d.", index)
+		}
+	}
+	client, err := api.NewClient(s.usrCfg.GetString("token"), s.usrCfg.GetString("apibaseurl"))
+	if err == nil {
+		err = postDiscussionReply(client, s.usrCfg.GetString("apibaseurl"), d.UUID, content)
+	}
 	if err != nil {
-		return nil, err
+		fmt.Fprintf(Err, "Warning: unable to tell your mentor about the new iteration: %s\n", err)
+		fmt.Fprintf(Err, "         Reply in the discussion with: %s mentor reply\n\n", BinaryName)
+		return
 	}
-	return metadata, nil
+	fmt.Fprintf(Err, "    Your mentor has been told about the new iteration.\n\n")
 }
 
-// submit submits the documents to the Exercism API.
-func (s *submitCmdContext) submit(metadata *workspace.ExerciseMetadata, docs []workspace.Document) error {
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
+// maxTestOutput is the most of the output of the tests that is attached to a submission.
+// The end of the output, which says how the tests went, is kept.
+const maxTestOutput = 32 * 1024
 
-	for _, doc := range docs {
-		file, err := os.Open(doc.Filepath())
-		if err != nil {
-			return err
-		}
-		defer file.Close()
+// localTestResults are the outcome of running the tests of the exercise before submitting it.
+type localTestResults struct {
+	Command  string    `json:"command"`
+	ExitCode int       `json:"exit_code"`
+	Output   string    `json:"output"`
+	RanAt    time.Time `json:"ran_at"`
+}
 
-		part, err := writer.CreateFormFile("files[]", doc.Path())
-		if err != nil {
-			return err
-		}
-		_, err = io.Copy(part, file)
-		if err != nil {
-			return err
-		}
+// runTests runs the tests of the exercise, showing what they print as they run.
+// The error says the tests couldn't be run; failing tests are in the results.
+func (s *submitCmdContext) runTests(exercise workspace.Exercise) (*localTestResults, error) {
+	args, err := testCommand(s.usrCfg, exercise.Track, exercise.Slug, exercise.Filepath())
+	if err != nil {
+		return nil, err
 	}
-	if err := writer.Close(); err != nil {
-		return err
+	results := &localTestResults{Command: strings.Join(args, " "), RanAt: time.Now()}
+	fmt.Fprintf(Err, "Running the tests with %s\n\n", results.Command)
+	var output bytes.Buffer
+	w := io.MultiWriter(Err, &output)
+	err = runTestCommand(exercise.Filepath(), args, w, w)
+	fmt.Fprintln(Err)
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		results.ExitCode = exitErr.ExitCode()
+	} else if err != nil {
+		return nil, fmt.Errorf("the tests couldn't be run with %s - %s", results.Command, err)
+	}
+	results.Output = output.String()
+	if len(results.Output) > maxTestOutput {
+		results.Output = results.Output[len(results.Output)-maxTestOutput:]
 	}
+	return results, nil
+}
 
-	client, err := api.NewClient(s.usrCfg.GetString("token"), s.usrCfg.GetString("apibaseurl"))
-	if err != nil {
-		return err
+// passOrAllowFailing makes sure that nothing is submitted until the tests pass.
+// If failing tests are allowed, they are only a warning.
+func (r *localTestResults) passOrAllowFailing(allowFailing bool) error {
+	if r.ExitCode == 0 {
+		return nil
 	}
-	url := fmt.Sprintf("%s/solutions/%s", s.usrCfg.GetString("apibaseurl"), metadata.ID)
-	req, err := client.NewRequest("PATCH", url, body)
-	if err != nil {
-		return err
+	if allowFailing {
+		fmt.Fprintf(Err, "Warning: the tests failed (exit code %d), but --allow-failing-tests submits the solution anyway.\n\n", r.ExitCode)
+		return nil
 	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
+	msg := `
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
+    The tests failed (exit code %d), so the solution wasn't submitted.
 
-	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		return decodedAPIError(resp)
+    Fix them and submit again, or, to submit the solution anyway, pass --allow-failing-tests
+
+        %s submit --allow-failing-tests
+
+        `
+	return fmt.Errorf(msg, r.ExitCode, BinaryName)
+}
+
+// notIdenticalToLastSubmission checks the local history, so that the files of
+// the last iteration of the solution aren't submitted again by accident.
+func (s *submitCmdContext) notIdenticalToLastSubmission(metadata *workspace.ExerciseMetadata, docs []workspace.Document) error {
+	last := latestSubmission(s.stateDir, metadata.ID)
+	if last == nil || last.PayloadHash == "" {
+		return nil
 	}
+	// The files are compared as they would be submitted, converted as configured.
+	sizes, err := s.writeBody(multipart.NewWriter(ioutil.Discard), docs, false)
+	if err != nil || payloadHash(sizes) != last.PayloadHash {
+		return nil
+	}
+	msg := `
 
-	bb := &bytes.Buffer{}
-	_, err = bb.ReadFrom(resp.Body)
+    The files are the same as the ones you submitted for %s on %s,
+    so submitting them would make an identical iteration.
+
+    To submit them anyway, pass --force
+
+        %s submit --force
+
+        `
+	return fmt.Errorf(msg, metadata, last.SubmittedAt.Local().Format("2006-01-02 15:04"), BinaryName)
+}
+
+// printGoalProgress shows how the submission counts towards the practice goal, if there is one.
+func (s *submitCmdContext) printGoalProgress() {
+	if s.stateDir == "" {
+		return
+	}
+	g, err := loadGoal(s.stateDir)
+	if err != nil || g == nil {
+		return
+	}
+	records, err := readSubmissionHistory(s.stateDir)
 	if err != nil {
-		return err
+		return
 	}
-	return nil
+	fmt.Fprintf(Err, "    %s\n\n", newGoalProgress(*g, records, time.Now()))
 }
 
 func (s *submitCmdContext) printResult(metadata *workspace.ExerciseMetadata) {
@@ -286,16 +1491,44 @@ func (s *submitCmdContext) printResult(metadata *workspace.ExerciseMetadata) {
     %s
 `
 	suffix := "View it at:\n\n    "
-	if metadata.AutoApprove && metadata.Team == "" {
+	switch {
+	case metadata.Team != "":
+	case metadata.IsConcept():
+		suffix = "Complete the exercise to unlock the concepts and exercises that build on it at:\n"
+	case metadata.Type == workspace.ExerciseTypePractice:
+		suffix = "You can mark the exercise as complete at:\n"
+	case metadata.AutoApprove:
 		suffix = "You can complete the exercise and unlock the next core exercise at:\n"
 	}
 	fmt.Fprintf(Err, msg, suffix)
 	fmt.Fprintf(Out, "    %s\n\n", metadata.URL)
 }
 
+// waitForIteration shows the outcome of processing the new iteration once it is known.
+// The solution has been submitted by then, so failing to find out isn't an error.
+func (s *submitCmdContext) waitForIteration(metadata *workspace.ExerciseMetadata, iterationID string, timeout time.Duration) {
+	if iterationID == "" {
+		fmt.Fprintln(Err, "Unable to wait for the results: the website didn't say which iteration was created.")
+		return
+	}
+	client, err := api.NewClient(s.usrCfg.GetString("token"), s.usrCfg.GetString("apibaseurl"))
+	if err != nil {
+		fmt.Fprintf(Err, "Unable to wait for the results: %s\n", err)
+		return
+	}
+	url := fmt.Sprintf("%s/solutions/%s/iterations/%s", s.usrCfg.GetString("apibaseurl"), metadata.ID, iterationID)
+	it, err := waitForIteration(client, url, timeout)
+	if err != nil {
+		fmt.Fprintf(Err, "Unable to wait for the results: %s\n", err)
+		return
+	}
+	printIterationOutcome(it, timeout)
+}
+
 // submitValidator contains the validation rules for a submission.
 type submitValidator struct {
-	usrCfg *viper.Viper
+	usrCfg   *viper.Viper
+	stateDir string
 }
 
 // filesExistAndNotADir checks that each file exists and is not a directory.
@@ -368,25 +1601,94 @@ func (s submitValidator) filesBelongToSameExercise(submitPaths []string) error {
 	return nil
 }
 
-// fileSizesWithinMax checks that each file does not exceed the max allowed size.
+// fileSizesWithinMax checks that each file does not exceed the max allowed size,
+// listing the files that do, so that it's clear which to trim.
+// The API is only asked for its limit when a file is too large for the one it has always had.
 func (s submitValidator) fileSizesWithinMax(submitPaths []string) error {
+	sizes := make([]submittedFileSize, 0, len(submitPaths))
+	var largest int64
 	for _, file := range submitPaths {
 		info, err := os.Stat(file)
 		if err != nil {
 			return err
 		}
-		const maxFileSize int64 = 65535
-		if info.Size() >= maxFileSize {
-			msg := `
+		sizes = append(sizes, submittedFileSize{path: file, size: info.Size()})
+		if info.Size() > largest {
+			largest = info.Size()
+		}
+	}
+	if largest < defaultMaxFileSize {
+		return nil
+	}
+
+	max := maxFileSize(s.usrCfg, s.stateDir)
+	var breakdown bytes.Buffer
+	w := tabwriter.NewWriter(&breakdown, 0, 0, 2, ' ', tabwriter.AlignRight)
+	for _, file := range sizes {
+		if file.size >= max {
+			fmt.Fprintf(w, "        %s\t  %s\n", humanSize(file.size), file.path)
+		}
+	}
+	w.Flush()
+	if breakdown.Len() == 0 {
+		return nil
+	}
+
+	msg := `
 
-      The submitted file '%s' is larger than the max allowed file size of %d bytes.
+      These files are larger than the max allowed file size of %s:
+
+%s
       Please reduce the size of the file and try again.
 
          `
-			return fmt.Errorf(msg, file, maxFileSize)
-		}
+	return fmt.Errorf(msg, humanSize(max), breakdown.String())
+}
+
+// submittedFileSize is the size of a file as it is submitted.
+type submittedFileSize struct {
+	path string
+	size int64
+	// transforms are how the file was changed for submission, if at all.
+	transforms []string
+	// sum is the SHA-256 of the file as submitted.
+	sum [sha256.Size]byte
+}
+
+// payloadWithinMax checks the size of the whole submission, listing the files
+// from largest to smallest if it is too large, so that it's clear which to trim.
+// The API is only asked for its limit when the submission is too large for the one it has always had.
+func (s submitValidator) payloadWithinMax(payloadSize int64, sizes []submittedFileSize) error {
+	if payloadSize <= defaultMaxPayloadSize {
+		return nil
 	}
-	return nil
+	max := maxPayloadSize(s.usrCfg, s.stateDir)
+	if payloadSize <= max {
+		return nil
+	}
+	sorted := make([]submittedFileSize, len(sizes))
+	copy(sorted, sizes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].size > sorted[j].size
+	})
+
+	var breakdown bytes.Buffer
+	w := tabwriter.NewWriter(&breakdown, 0, 0, 2, ' ', tabwriter.AlignRight)
+	for _, file := range sorted {
+		fmt.Fprintf(w, "        %s\t  %s\n", humanSize(file.size), file.path)
+	}
+	w.Flush()
+
+	msg := `
+
+      The submission is %s, which is larger than the max allowed size of %s.
+      These are the files in it, largest first:
+
+%s
+      Please leave out or reduce the size of some of the files and try again.
+
+         `
+	return fmt.Errorf(msg, humanSize(payloadSize), humanSize(max), breakdown.String())
 }
 
 // submissionNotEmpty checks that there is at least one file to submit.
@@ -405,7 +1707,6 @@ func (s submitValidator) submissionNotEmpty(docs []workspace.Document) error {
 // metadataMatchesExercise checks that the metadata refers to the exercise being submitted.
 func (s submitValidator) metadataMatchesExercise(metadata *workspace.ExerciseMetadata, exercise workspace.Exercise) error {
 	if metadata.ExerciseSlug != exercise.Slug {
-		// TODO: error msg should suggest running future doctor command
 		msg := `
 
     The exercise directory does not match exercise slug in metadata:
@@ -413,13 +1714,50 @@ func (s submitValidator) metadataMatchesExercise(metadata *workspace.ExerciseMet
         expected '%[1]s' but got '%[2]s'
 
     Please rename the directory '%[1]s' to '%[2]s' and try again.
+    To check the rest of your workspace for problems like this, run
+
+        %[3]s doctor
 
         `
-		return fmt.Errorf(msg, exercise.Slug, metadata.ExerciseSlug)
+		return fmt.Errorf(msg, exercise.Slug, metadata.ExerciseSlug, BinaryName)
 	}
 	return nil
 }
 
+// noSecrets checks that none of the files look like they contain credentials,
+// since solutions can be published for anyone to see.
+// The report says where each one is, but never what it is.
+func (s submitValidator) noSecrets(docs []workspace.Document) error {
+	var findings []string
+	for _, doc := range docs {
+		if workspace.IsEnvFile(doc.Path()) {
+			findings = append(findings, fmt.Sprintf("        %s: environment file", doc.Path()))
+			continue
+		}
+		contents, err := ioutil.ReadFile(workspace.LongPath(doc.Filepath()))
+		if err != nil {
+			return err
+		}
+		for _, secret := range workspace.FindSecrets(contents) {
+			findings = append(findings, fmt.Sprintf("        %s:%d: %s", doc.Path(), secret.Line, secret.Kind))
+		}
+	}
+	if len(findings) == 0 {
+		return nil
+	}
+	msg := `
+
+    These files look like they contain passwords, keys or tokens:
+
+%s
+
+    Solutions can be published for anyone to see. Remove the secrets and
+    submit again, or if they aren't secrets, submit again with --allow-secrets.
+
+`
+	return fmt.Errorf(msg, strings.Join(findings, "\n"))
+}
+
 // isRequestor checks that the submission requestor is listed as the author in the metadata.
 func (s submitValidator) isRequestor(metadata *workspace.ExerciseMetadata) error {
 	if !metadata.IsRequester {
@@ -436,6 +1774,54 @@ func (s submitValidator) isRequestor(metadata *workspace.ExerciseMetadata) error
 	return nil
 }
 
+// notCopied checks that the exercise isn't in more than one directory, since only one
+// of them can have the solution that is on the website.
+func (s submitValidator) notCopied(metadata *workspace.ExerciseMetadata, exercise workspace.Exercise) error {
+	copies, err := workspace.CopiesOf(exercise.Filepath(), metadata.ID)
+	if err != nil || len(copies) == 0 {
+		return nil
+	}
+	msg := `
+
+    The solution is in more than one directory:
+
+        %s
+        %s
+
+    Keep the one you're working on and remove the others, or merge them.
+    To see which of them can be removed safely, run
+
+        %s doctor
+
+    To submit from this directory anyway, pass --allow-copies
+
+        `
+	return fmt.Errorf(msg, exercise.Filepath(), strings.Join(copies, "\n        "), BinaryName)
+}
+
+func setupSubmitFlags(flags *pflag.FlagSet) {
+	flags.StringSlice("include", []string{}, "an extra file to submit, such as a helper module or test data (repeatable)")
+	flags.Bool("allow-secrets", false, "submit files even if they look like they contain passwords, keys or tokens")
+	flags.StringP("message", "m", "", "a note about the iteration, shown with it on the website")
+	flags.Bool("open", false, "open the solution on the website once it's submitted")
+	flags.Bool("prefetch", false, "fetch the next exercises of the track in the background once it's submitted")
+	flags.BoolP("force", "F", false, "submit even if the files are the same as in the last iteration")
+	flags.Bool("allow-copies", false, "submit even if the solution is in more than one directory")
+	flags.Bool("allow-failing-tests", false, "submit even if the tests fail with --require-tests-pass")
+	flags.Bool("normalize-eol", false, "submit the files with LF line endings and without a UTF-8 byte order mark")
+	flags.Bool("format", false, "format the solution files with the track's formatter before submitting them")
+	flags.Bool("require-tests-pass", false, "run the tests first, and only submit if they pass")
+	flags.Bool("attach-test-results", false, "run the tests first, and submit their output and exit code with the files")
+	flags.Bool("stdin", false, "submit what is read from standard input, as the file given with --filename")
+	flags.String("filename", "", "the path within the exercise to submit standard input as, with --stdin")
+	flags.Bool("discussion", false, "post the new iteration to the mentoring discussion going on about the exercise")
+	flags.String("manifest", "", "submit each of the exercises listed in a JSON manifest")
+	flags.Bool("dry-run", false, "show what would be submitted without submitting it")
+	flags.Bool("wait", false, "wait for the tests and analysis of the iteration, and show the outcome")
+	flags.Duration("wait-timeout", defaultWaitTimeout, "how long to wait with --wait")
+}
+
 func init() {
 	RootCmd.AddCommand(submitCmd)
+	setupSubmitFlags(submitCmd.Flags())
 }
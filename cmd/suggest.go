@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// flagSuggestFor maps flag names people commonly reach for onto the ones we use.
+var flagSuggestFor = map[string]string{
+	"slug":     "exercise",
+	"problem":  "exercise",
+	"language": "track",
+	"lang":     "track",
+	"id":       "uuid",
+	"key":      "token",
+	"dir":      "workspace",
+	"url":      "api",
+}
+
+// closestMatches returns the candidates within maxDistance edits of the word,
+// closest first.
+func closestMatches(word string, candidates []string, maxDistance int) []string {
+	type match struct {
+		candidate string
+		distance  int
+	}
+	var matches []match
+	for _, candidate := range candidates {
+		d := levenshtein(strings.ToLower(word), strings.ToLower(candidate))
+		if d <= maxDistance {
+			matches = append(matches, match{candidate, d})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].distance < matches[j].distance
+	})
+
+	result := make([]string, len(matches))
+	for i, m := range matches {
+		result[i] = m.candidate
+	}
+	return result
+}
+
+// levenshtein is the number of single-character edits needed to turn a into b.
+func levenshtein(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	prev := make([]int, len(t)+1)
+	curr := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		curr[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			curr[j] = prev[j-1] + cost
+			if prev[j]+1 < curr[j] {
+				curr[j] = prev[j] + 1
+			}
+			if curr[j-1]+1 < curr[j] {
+				curr[j] = curr[j-1] + 1
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(t)]
+}
+
+// suggestFlagError adds "did you mean" suggestions to unknown flag errors.
+func suggestFlagError(cmd *cobra.Command, err error) error {
+	const prefix = "unknown flag: --"
+	if !strings.HasPrefix(err.Error(), prefix) {
+		return err
+	}
+	name := strings.TrimPrefix(err.Error(), prefix)
+	name = strings.SplitN(name, "=", 2)[0]
+
+	var names []string
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if !f.Hidden {
+			names = append(names, f.Name)
+		}
+	})
+
+	var suggestions []string
+	if alias, ok := flagSuggestFor[name]; ok && cmd.Flags().Lookup(alias) != nil {
+		suggestions = append(suggestions, alias)
+	}
+	for _, match := range closestMatches(name, names, 2) {
+		if len(suggestions) == 0 || suggestions[0] != match {
+			suggestions = append(suggestions, match)
+		}
+	}
+	if len(suggestions) == 0 {
+		return err
+	}
+
+	msg := fmt.Sprintf("%s\n\nDid you mean this?\n", err)
+	for _, s := range suggestions {
+		msg = fmt.Sprintf("%s\t--%s\n", msg, s)
+	}
+	return fmt.Errorf("%s", msg)
+}
+
+// maxSlugDistance is how far off a mistyped track or exercise slug can be and still be suggested.
+const maxSlugDistance = 3
+
+// slugSuggestionError is an error about a track or exercise that doesn't exist,
+// along with the ones that were probably meant.
+type slugSuggestionError struct {
+	err         error
+	suggestions []string
+}
+
+func (e *slugSuggestionError) Error() string {
+	msg := fmt.Sprintf("%s\n\nDid you mean this?\n", e.err)
+	for _, s := range e.suggestions {
+		msg = fmt.Sprintf("%s\t%s\n", msg, s)
+	}
+	return msg
+}
+
+func (e *slugSuggestionError) Unwrap() error {
+	return e.err
+}
+
+// suggestSlugs adds suggestions to the error the API gives when the exercise or track asked for isn't found.
+// If the track exists, the exercises in it are suggested, otherwise the tracks.
+// The error is returned as it is when there's nothing to suggest.
+func suggestSlugs(token, baseURL, track, slug string, err error) error {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || track == "" {
+		return err
+	}
+
+	var candidates []string
+	word := slug
+	c, cerr := fetchCatalog(token, baseURL, track)
+	switch {
+	case cerr == nil:
+		for _, exercise := range c.Exercises {
+			candidates = append(candidates, exercise.Slug)
+		}
+	case errors.As(cerr, &apiErr) && apiErr.StatusCode == http.StatusNotFound:
+		tracks, terr := fetchTracks(token, baseURL)
+		if terr != nil {
+			return err
+		}
+		for _, t := range tracks {
+			candidates = append(candidates, t.Slug)
+		}
+		word = track
+	default:
+		return err
+	}
+
+	suggestions := closestMatches(word, candidates, maxSlugDistance)
+	if len(suggestions) == 0 {
+		return err
+	}
+	if len(suggestions) > 3 {
+		suggestions = suggestions[:3]
+	}
+	return &slugSuggestionError{err: err, suggestions: suggestions}
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/exercism/cli/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevenshtein(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		distance int
+	}{
+		{"", "", 0},
+		{"submit", "submit", 0},
+		{"sumbit", "submit", 2},
+		{"dowload", "download", 1},
+		{"exercice", "exercise", 1},
+		{"", "track", 5},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.distance, levenshtein(tc.a, tc.b), tc.a+" -> "+tc.b)
+	}
+}
+
+func TestClosestMatches(t *testing.T) {
+	candidates := []string{"track", "team", "exercise", "uuid"}
+	assert.Equal(t, []string{"track"}, closestMatches("trak", candidates, 1))
+	assert.Equal(t, []string{"team", "track"}, closestMatches("tram", candidates, 2))
+	assert.Empty(t, closestMatches("banana", candidates, 2))
+}
+
+func TestSuggestFlagError(t *testing.T) {
+	cmd := &cobra.Command{}
+	setupDownloadFlags(cmd.Flags())
+
+	err := suggestFlagError(cmd, errors.New("unknown flag: --exercice"))
+	assert.Regexp(t, "Did you mean this\\?\n\t--exercise\n", err.Error())
+
+	err = suggestFlagError(cmd, errors.New("unknown flag: --language=go"))
+	assert.Regexp(t, "Did you mean this\\?\n\t--track\n", err.Error())
+
+	err = suggestFlagError(cmd, errors.New("unknown flag: --banana"))
+	assert.Equal(t, "unknown flag: --banana", err.Error())
+
+	err = suggestFlagError(cmd, errors.New("unknown shorthand flag: 'x' in -x"))
+	assert.Equal(t, "unknown shorthand flag: 'x' in -x", err.Error())
+}
+
+func TestDownloadSuggestsSlugs(t *testing.T) {
+	co := newCapturedOutput()
+	co.override()
+	defer co.reset()
+
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	mux.HandleFunc("/tracks/go/exercises", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"exercises": [{"slug": "leap"}, {"slug": "clock"}, {"slug": "hamming"}]}`)
+	})
+	mux.HandleFunc("/tracks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tracks": [{"slug": "go"}, {"slug": "rust"}, {"slug": "python"}]}`)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error": {"type": "not_found", "message": "Not found"}}`)
+	})
+
+	v := viper.New()
+	v.Set("workspace", "/home/alice/exercism")
+	v.Set("apibaseurl", ts.URL)
+	v.Set("token", "abc123")
+	cfg := config.Config{UserViperConfig: v}
+
+	download := func(track, exercise string) error {
+		flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+		setupDownloadFlags(flags)
+		flags.Set("track", track)
+		flags.Set("exercise", exercise)
+		flags.Set("no-input", "true")
+		return runDownload(cfg, flags, []string{})
+	}
+
+	err := download("go", "lepa")
+	if assert.Error(t, err) {
+		assert.Regexp(t, "Not found\n\nDid you mean this\\?\n\tleap\n$", err.Error())
+		var apiErr *apiError
+		assert.True(t, errors.As(err, &apiErr), "It should still be an API error.")
+	}
+
+	err = download("rsut", "leap")
+	if assert.Error(t, err) {
+		assert.Regexp(t, "Did you mean this\\?\n\trust\n$", err.Error())
+	}
+
+	err = download("go", "zebra-puzzle")
+	if assert.Error(t, err) {
+		assert.Equal(t, "Not found", err.Error())
+	}
+}
@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/exercism/cli/api"
+	"github.com/exercism/cli/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// syllabusCmd shows a track's concepts and how they build on each other.
+var syllabusCmd = &cobra.Command{
+	Use:        "syllabus",
+	SuggestFor: []string{"concepts", "tree"},
+	Short:      "Show the concepts taught in a track.",
+	Long: `Show the concepts taught in a track as a tree, each under the concept
+that has to be learned before it.
+
+Each concept is marked as completed, in progress, available or locked, and
+lists the concept exercises that teach it:
+
+    exercism syllabus --track=go
+
+A concept that builds on more than one other concept is shown under the
+last of them, and lists the others.
+
+Pass --json to get the concept map in a machine-readable format.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.NewConfig()
+
+		v := viper.New()
+		v.AddConfigPath(cfg.Dir)
+		v.SetConfigName("user")
+		v.SetConfigType("json")
+		// Ignore error. If the file doesn't exist, that is fine.
+		_ = v.ReadInConfig()
+		config.BindUserEnv(v)
+		cfg.UserViperConfig = v
+
+		return runSyllabus(cfg, cmd.Flags())
+	},
+}
+
+// syllabusConcept is a concept as listed in a track's concept map.
+type syllabusConcept struct {
+	Slug   string `json:"slug"`
+	Name   string `json:"name,omitempty"`
+	Status string `json:"status,omitempty"`
+	// Prerequisites are the slugs of the concepts to learn first.
+	Prerequisites []string `json:"prerequisites,omitempty"`
+	// Exercises are the slugs of the concept exercises that teach the concept.
+	Exercises []string `json:"exercises,omitempty"`
+}
+
+// syllabus is a track's concept map, in the order the track presents the concepts.
+type syllabus struct {
+	Track    string            `json:"track"`
+	Concepts []syllabusConcept `json:"concepts"`
+}
+
+// fetchSyllabus requests the concept map for a track from the API.
+func fetchSyllabus(token, baseURL, track string) (*syllabus, error) {
+	client, err := api.NewClient(token, baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/tracks/%s/concepts", baseURL, track)
+	req, err := client.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, decodedAPIError(res)
+	}
+
+	var payload struct {
+		Concepts []syllabusConcept `json:"concepts"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("unable to parse API response - %s", err)
+	}
+	return &syllabus{Track: track, Concepts: payload.Concepts}, nil
+}
+
+func runSyllabus(cfg config.Config, flags *pflag.FlagSet) error {
+	usrCfg := cfg.UserViperConfig
+	if err := validateUserConfig(usrCfg); err != nil {
+		return err
+	}
+
+	track, err := flags.GetString("track")
+	if err != nil {
+		return err
+	}
+	if track == "" {
+		return errors.New("need a --track to show the syllabus of")
+	}
+	asJSON, err := flags.GetBool("json")
+	if err != nil {
+		return err
+	}
+
+	s, err := fetchSyllabus(usrCfg.GetString("token"), usrCfg.GetString("apibaseurl"), track)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		return json.NewEncoder(Out).Encode(s)
+	}
+	if len(s.Concepts) == 0 {
+		fmt.Fprintf(Err, "The %s track doesn't have a syllabus.\n", track)
+		return nil
+	}
+	s.print()
+	return nil
+}
+
+// parent is the concept that a concept is shown under: the last of its prerequisites
+// in the track's order, since that's the one that unlocks it. Top-level concepts have none.
+func (s *syllabus) parent(concept syllabusConcept, positions map[string]int) string {
+	parent := ""
+	for _, slug := range concept.Prerequisites {
+		pos, ok := positions[slug]
+		if !ok || slug == concept.Slug {
+			continue
+		}
+		if parent == "" || pos > positions[parent] {
+			parent = slug
+		}
+	}
+	return parent
+}
+
+func (s *syllabus) print() {
+	positions := make(map[string]int, len(s.Concepts))
+	for i, concept := range s.Concepts {
+		positions[concept.Slug] = i
+	}
+	children := make(map[string][]syllabusConcept)
+	var roots []syllabusConcept
+	for _, concept := range s.Concepts {
+		if parent := s.parent(concept, positions); parent != "" {
+			children[parent] = append(children[parent], concept)
+		} else {
+			roots = append(roots, concept)
+		}
+	}
+
+	printed := make(map[string]bool, len(s.Concepts))
+	var walk func(concepts []syllabusConcept, indent string)
+	walk = func(concepts []syllabusConcept, indent string) {
+		for i, concept := range concepts {
+			if printed[concept.Slug] {
+				continue
+			}
+			printed[concept.Slug] = true
+
+			branch, next := glyphTreeBranch, glyphTreeVertical
+			if i == len(concepts)-1 {
+				branch, next = glyphTreeLast, glyphTreeSpace
+			}
+			fmt.Fprintf(Out, "%s%s%s\n", indent, branch, conceptLabel(concept, s.parent(concept, positions)))
+			walk(children[concept.Slug], indent+next.String())
+		}
+	}
+	walk(roots, "")
+
+	// Concepts whose prerequisites go round in circles have no way in from the top.
+	for _, concept := range s.Concepts {
+		if !printed[concept.Slug] {
+			walk([]syllabusConcept{concept}, "")
+		}
+	}
+
+	fmt.Fprintf(Err, "\n%s completed  %s in progress  %s available  %s locked\n",
+		glyphCompleted, glyphInProgress, glyphAvailable, glyphLocked)
+}
+
+// conceptLabel shows a concept with its status, the exercises that teach it,
+// and the prerequisites other than the one it's shown under.
+func conceptLabel(concept syllabusConcept, parent string) string {
+	name := concept.Name
+	if name == "" {
+		name = concept.Slug
+	}
+	label := name
+	if g, ok := statusGlyphs[concept.Status]; ok {
+		label = fmt.Sprintf("%s %s", g, name)
+	}
+
+	var details []string
+	if len(concept.Exercises) > 0 {
+		details = append(details, fmt.Sprintf("taught by %s", strings.Join(concept.Exercises, ", ")))
+	}
+	var also []string
+	for _, slug := range concept.Prerequisites {
+		if slug != parent {
+			also = append(also, slug)
+		}
+	}
+	if len(also) > 0 && parent != "" {
+		details = append(details, fmt.Sprintf("also needs %s", strings.Join(also, ", ")))
+	}
+	if len(details) > 0 {
+		label = fmt.Sprintf("%s (%s)", label, strings.Join(details, "; "))
+	}
+	return label
+}
+
+func setupSyllabusFlags(flags *pflag.FlagSet) {
+	flags.StringP("track", "t", "", "the track to show the syllabus of")
+	flags.Bool("json", false, "print the concept map as JSON")
+}
+
+func init() {
+	RootCmd.AddCommand(syllabusCmd)
+	setupSyllabusFlags(syllabusCmd.Flags())
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/exercism/cli/config"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+const syllabusPayload = `
+{
+	"concepts": [
+		{"slug": "basics", "name": "Basics", "status": "completed", "exercises": ["lasagna"]},
+		{"slug": "numbers", "name": "Numbers", "status": "completed", "prerequisites": ["basics"], "exercises": ["cars-assemble"]},
+		{"slug": "strings", "name": "Strings", "status": "in_progress", "prerequisites": ["basics"], "exercises": ["party-robot"]},
+		{"slug": "conditionals", "name": "Conditionals", "status": "available", "prerequisites": ["numbers"]},
+		{"slug": "string-formatting", "name": "String Formatting", "status": "locked", "prerequisites": ["numbers", "strings"], "exercises": ["welcome-to-tech-palace"]}
+	]
+}
+`
+
+func TestSyllabus(t *testing.T) {
+	defer func() { plainASCII = false }()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tracks/go/concepts", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, syllabusPayload)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", "/home/alice/exercism")
+	v.Set("apibaseurl", ts.URL)
+	cfg := config.Config{UserViperConfig: v}
+
+	run := func(args ...string) (string, error) {
+		co := newCapturedOutput()
+		co.newOut = &bytes.Buffer{}
+		co.override()
+		defer co.reset()
+
+		flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+		setupSyllabusFlags(flags)
+		flags.Parse(args)
+		err := runSyllabus(cfg, flags)
+		return Out.(*bytes.Buffer).String(), err
+	}
+
+	_, err := run()
+	if assert.Error(t, err) {
+		assert.Regexp(t, "need a --track", err.Error())
+	}
+
+	plainASCII = false
+	out, err := run("--track=go")
+	assert.NoError(t, err)
+	expected := `└── ✓ Basics (taught by lasagna)
+    ├── ✓ Numbers (taught by cars-assemble)
+    │   └── ○ Conditionals
+    └── ◐ Strings (taught by party-robot)
+        └── ⊘ String Formatting (taught by welcome-to-tech-palace; also needs numbers)
+`
+	assert.Equal(t, expected, out)
+
+	plainASCII = true
+	out, err = run("--track=go")
+	assert.NoError(t, err)
+	expected = "`-- + Basics (taught by lasagna)\n" +
+		"    |-- + Numbers (taught by cars-assemble)\n" +
+		"    |   `-- o Conditionals\n" +
+		"    `-- ~ Strings (taught by party-robot)\n" +
+		"        `-- x String Formatting (taught by welcome-to-tech-palace; also needs numbers)\n"
+	assert.Equal(t, expected, out)
+
+	out, err = run("--track=go", "--json")
+	assert.NoError(t, err)
+	var s syllabus
+	assert.NoError(t, json.Unmarshal([]byte(out), &s))
+	assert.Equal(t, "go", s.Track)
+	assert.Equal(t, 5, len(s.Concepts))
+	assert.Equal(t, []string{"numbers", "strings"}, s.Concepts[4].Prerequisites)
+}
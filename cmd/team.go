@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/exercism/cli/api"
+	"github.com/exercism/cli/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// teamCmd groups the commands for managing the members of a team.
+var teamCmd = &cobra.Command{
+	Use:   "team",
+	Short: "Manage the members of a team.",
+	Long: `Invite people to a team, list its members, or leave it.
+
+    exercism team invite --team=SLUG student@example.com
+    exercism team members --team=SLUG
+    exercism team leave --team=SLUG
+
+Only the team's admins can invite people. What else you can do depends on
+your role in the team, and the API says so when something isn't allowed.
+`,
+}
+
+var teamInviteCmd = &cobra.Command{
+	Use:   "invite EMAIL...",
+	Short: "Invite people to a team.",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTeamInvite(loadTeamConfig(), cmd.Flags(), args)
+	},
+}
+
+var teamMembersCmd = &cobra.Command{
+	Use:   "members",
+	Short: "List the members of a team.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTeamMembers(loadTeamConfig(), cmd.Flags())
+	},
+}
+
+var teamLeaveCmd = &cobra.Command{
+	Use:   "leave",
+	Short: "Leave a team.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTeamLeave(loadTeamConfig(), cmd.Flags())
+	},
+}
+
+func loadTeamConfig() config.Config {
+	cfg := config.NewConfig()
+
+	v := viper.New()
+	v.AddConfigPath(cfg.Dir)
+	v.SetConfigName("user")
+	v.SetConfigType("json")
+	// Ignore error. If the file doesn't exist, that is fine.
+	_ = v.ReadInConfig()
+	config.BindUserEnv(v)
+	cfg.UserViperConfig = v
+	return cfg
+}
+
+// teamMember is a member of a team, as listed by the API.
+type teamMember struct {
+	Handle   string    `json:"handle"`
+	Name     string    `json:"name"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+func runTeamInvite(cfg config.Config, flags *pflag.FlagSet, emails []string) error {
+	client, team, err := teamClient(cfg.UserViperConfig, flags)
+	if err != nil {
+		return err
+	}
+	for _, email := range emails {
+		if !strings.Contains(email, "@") {
+			return fmt.Errorf("'%s' doesn't look like an email address", email)
+		}
+	}
+
+	var failed int
+	for _, email := range emails {
+		url := fmt.Sprintf("%s/teams/%s/invitations", client.APIBaseURL, team)
+		if _, err := sendTeamRequest(client, "POST", url, map[string]string{"email": email}); err != nil {
+			fmt.Fprintf(Err, "%s Could not invite %s: %s\n", glyphFailed, email, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(Out, "%s Invited %s\n", glyphCompleted, email)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d invitations failed", failed, len(emails))
+	}
+	return nil
+}
+
+func runTeamMembers(cfg config.Config, flags *pflag.FlagSet) error {
+	client, team, err := teamClient(cfg.UserViperConfig, flags)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/teams/%s/memberships", client.APIBaseURL, team)
+	res, err := sendTeamRequest(client, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	var payload struct {
+		Memberships []teamMember `json:"memberships"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		return fmt.Errorf("unable to parse API response - %s", err)
+	}
+	if len(payload.Memberships) == 0 {
+		fmt.Fprintf(Err, "The team %s has no members.\n", team)
+		return nil
+	}
+	printTeamMembers(Out, payload.Memberships)
+	return nil
+}
+
+func printTeamMembers(w io.Writer, members []teamMember) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, m := range members {
+		joined := ""
+		if !m.JoinedAt.IsZero() {
+			joined = m.JoinedAt.Format("2006-01-02")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", m.Handle, m.Name, m.Role, joined)
+	}
+	tw.Flush()
+}
+
+func runTeamLeave(cfg config.Config, flags *pflag.FlagSet) error {
+	client, team, err := teamClient(cfg.UserViperConfig, flags)
+	if err != nil {
+		return err
+	}
+	yes, err := flags.GetBool("yes")
+	if err != nil {
+		return err
+	}
+	if !yes {
+		answer, err := prompt(fmt.Sprintf("Leave the team %s? [y/N] ", team))
+		if err != nil {
+			return errors.New("pass --yes to leave the team without being asked")
+		}
+		if answer != "y" && answer != "yes" {
+			return nil
+		}
+	}
+
+	url := fmt.Sprintf("%s/teams/%s/membership", client.APIBaseURL, team)
+	res, err := sendTeamRequest(client, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	fmt.Fprintf(Err, "You've left the team %s.\n", team)
+	return nil
+}
+
+// teamClient checks the config and the --team flag, and returns a client for the API.
+func teamClient(usrCfg *viper.Viper, flags *pflag.FlagSet) (*api.Client, string, error) {
+	if err := validateUserConfig(usrCfg); err != nil {
+		return nil, "", err
+	}
+	team, err := flags.GetString("team")
+	if err != nil {
+		return nil, "", err
+	}
+	if team == "" {
+		return nil, "", errors.New("give the slug of the team with --team")
+	}
+	client, err := api.NewClient(usrCfg.GetString("token"), usrCfg.GetString("apibaseurl"))
+	if err != nil {
+		return nil, "", err
+	}
+	return client, team, nil
+}
+
+// sendTeamRequest makes a request with an optional JSON payload, and fails unless it succeeds.
+// The caller closes the body of the response.
+func sendTeamRequest(client *api.Client, method, url string, payload interface{}) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := client.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		defer res.Body.Close()
+		return nil, decodedAPIError(res)
+	}
+	return res, nil
+}
+
+func setupTeamFlags(flags *pflag.FlagSet) {
+	flags.StringP("team", "T", "", "the team slug")
+}
+
+func setupTeamLeaveFlags(flags *pflag.FlagSet) {
+	flags.BoolP("yes", "y", false, "leave without being asked")
+}
+
+func init() {
+	RootCmd.AddCommand(teamCmd)
+	teamCmd.AddCommand(teamInviteCmd)
+	teamCmd.AddCommand(teamMembersCmd)
+	teamCmd.AddCommand(teamLeaveCmd)
+	setupTeamFlags(teamCmd.PersistentFlags())
+	setupTeamLeaveFlags(teamLeaveCmd.Flags())
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/exercism/cli/config"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func fakeTeamServer(t *testing.T, requests *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		*requests = append(*requests, strings.TrimSpace(fmt.Sprintf("%s %s %s", r.Method, r.URL.Path, body)))
+
+		switch {
+		case strings.Contains(string(body), "nobody@example.com"):
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprint(w, `{"error": {"type": "invalid_email", "message": "nobody has that email address"}}`)
+		case r.Method == "GET":
+			fmt.Fprint(w, `{"memberships": [
+				{"handle": "teacher", "name": "Ms Frizzle", "role": "admin", "joined_at": "2026-09-01T08:00:00Z"},
+				{"handle": "arnold", "role": "member", "joined_at": "2026-09-02T08:00:00Z"}
+			]}`)
+		default:
+			fmt.Fprint(w, "{}")
+		}
+	}))
+}
+
+func fakeUserConfig(apiBaseURL string) config.Config {
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", "/home/username")
+	v.Set("apibaseurl", apiBaseURL)
+	return config.Config{UserViperConfig: v}
+}
+
+func TestTeamInvite(t *testing.T) {
+	co := newCapturedOutput()
+	co.override()
+	defer co.reset()
+
+	var requests []string
+	ts := fakeTeamServer(t, &requests)
+	defer ts.Close()
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupTeamFlags(flags)
+	flags.Set("team", "class-4a")
+
+	err := runTeamInvite(fakeUserConfig(ts.URL), flags, []string{"pupil@example.com", "nobody@example.com"})
+	if assert.Error(t, err) {
+		assert.Regexp(t, "1 of 2 invitations failed", err.Error())
+	}
+	assert.Equal(t, []string{
+		`POST /teams/class-4a/invitations {"email":"pupil@example.com"}`,
+		`POST /teams/class-4a/invitations {"email":"nobody@example.com"}`,
+	}, requests)
+
+	// Nothing is sent if one of the addresses is wrong.
+	requests = nil
+	err = runTeamInvite(fakeUserConfig(ts.URL), flags, []string{"pupil@example.com", "pupil"})
+	if assert.Error(t, err) {
+		assert.Regexp(t, "'pupil' doesn't look like an email address", err.Error())
+	}
+	assert.Empty(t, requests)
+}
+
+func TestTeamMembers(t *testing.T) {
+	co := newCapturedOutput()
+	co.newOut = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	var requests []string
+	ts := fakeTeamServer(t, &requests)
+	defer ts.Close()
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupTeamFlags(flags)
+	flags.Set("team", "class-4a")
+
+	assert.NoError(t, runTeamMembers(fakeUserConfig(ts.URL), flags))
+	assert.Equal(t, []string{"GET /teams/class-4a/memberships"}, requests)
+	expected := "teacher  Ms Frizzle  admin   2026-09-01\n" +
+		"arnold               member  2026-09-02\n"
+	assert.Equal(t, expected, co.newOut.(*bytes.Buffer).String())
+}
+
+func TestTeamLeave(t *testing.T) {
+	co := newCapturedOutput()
+	co.override()
+	defer co.reset()
+	oldIn := In
+	defer func() { In = oldIn }()
+
+	var requests []string
+	ts := fakeTeamServer(t, &requests)
+	defer ts.Close()
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupTeamFlags(flags)
+	setupTeamLeaveFlags(flags)
+	flags.Set("team", "class-4a")
+
+	// Staying is the default.
+	In = strings.NewReader("\n")
+	assert.NoError(t, runTeamLeave(fakeUserConfig(ts.URL), flags))
+	assert.Empty(t, requests)
+
+	// With no one to ask, --yes is needed.
+	In = strings.NewReader("")
+	err := runTeamLeave(fakeUserConfig(ts.URL), flags)
+	if assert.Error(t, err) {
+		assert.Regexp(t, "--yes", err.Error())
+	}
+	assert.Empty(t, requests)
+
+	In = strings.NewReader("y\n")
+	assert.NoError(t, runTeamLeave(fakeUserConfig(ts.URL), flags))
+	assert.Equal(t, []string{"DELETE /teams/class-4a/membership"}, requests)
+}
+
+func TestTeamRequiresSlug(t *testing.T) {
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupTeamFlags(flags)
+	err := runTeamMembers(fakeUserConfig("http://example.com"), flags)
+	if assert.Error(t, err) {
+		assert.Regexp(t, "--team", err.Error())
+	}
+}
@@ -0,0 +1,31 @@
+//go:build !windows
+// +build !windows
+
+package cmd
+
+import (
+	"os"
+	"os/exec"
+)
+
+// isTerminal tells whether the file is a terminal, as opposed to e.g. /dev/null.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	stty := exec.Command("stty", "-g")
+	stty.Stdin = f
+	return stty.Run() == nil
+}
+
+// setEcho turns the echoing of typed characters in the terminal on or off.
+func setEcho(f *os.File, on bool) error {
+	mode := "-echo"
+	if on {
+		mode = "echo"
+	}
+	stty := exec.Command("stty", mode)
+	stty.Stdin = f
+	return stty.Run()
+}
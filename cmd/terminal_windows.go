@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"os"
+	"syscall"
+)
+
+// enableEchoInput is ENABLE_ECHO_INPUT in the console mode.
+const enableEchoInput = 0x0004
+
+var procSetConsoleMode = syscall.NewLazyDLL("kernel32.dll").NewProc("SetConsoleMode")
+
+// isTerminal tells whether the file is a console, as opposed to e.g. NUL.
+func isTerminal(f *os.File) bool {
+	var mode uint32
+	return syscall.GetConsoleMode(syscall.Handle(f.Fd()), &mode) == nil
+}
+
+// setEcho turns the echoing of typed characters in the console on or off.
+func setEcho(f *os.File, on bool) error {
+	handle := syscall.Handle(f.Fd())
+	var mode uint32
+	if err := syscall.GetConsoleMode(handle, &mode); err != nil {
+		return err
+	}
+	if on {
+		mode |= enableEchoInput
+	} else {
+		mode &^= enableEchoInput
+	}
+	if r, _, err := procSetConsoleMode.Call(uintptr(handle), uintptr(mode)); r == 0 {
+		return err
+	}
+	return nil
+}
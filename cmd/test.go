@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/exercism/cli/config"
+	"github.com/exercism/cli/workspace"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// testCmd runs the tests of an exercise.
+var testCmd = &cobra.Command{
+	Use:   "test [PATH] [-- ARGS...]",
+	Short: "Run the tests of an exercise.",
+	Long: `Run the tests of an exercise with the track's usual tooling,
+such as go test, cargo test or pytest.
+
+Pass the path to the exercise, or run the command from within it.
+The track is taken from the exercise's metadata.
+
+To run the tests of a track differently, or of a track that the CLI doesn't
+know how to test, set the command in your user config. In it, {dir}, {track}
+and {slug} stand for the exercise directory, the track and the exercise:
+
+    "tracks": {"rust": {"test_command": ["cargo", "test", "--manifest-path", "{dir}/Cargo.toml"]}}
+
+Arguments after -- are passed on to the test command:
+
+    exercism test -- -run TestLeapYear
+
+If the track's tooling isn't installed, pass --docker to run the tests with
+the track's test runner in Docker, exercism/TRACK-test-runner, the way the
+website runs them. The runner has no network access, so pull its image first:
+
+    docker pull exercism/go-test-runner
+
+To hand the results to CI or a classroom, write them as JUnit XML:
+
+    exercism test --report=junit --output results.xml
+
+With the track's tooling, the whole run is reported as a single test case;
+the test runner in Docker reports each test separately.
+`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.NewConfig()
+
+		v := viper.New()
+		v.AddConfigPath(cfg.Dir)
+		v.SetConfigName("user")
+		v.SetConfigType("json")
+		// Ignore error. If the file doesn't exist, that is fine.
+		_ = v.ReadInConfig()
+		config.BindUserEnv(v)
+		cfg.UserViperConfig = v
+
+		var extra []string
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			args, extra = args[:dash], args[dash:]
+		}
+		if len(args) > 1 {
+			return fmt.Errorf("give at most one exercise to test, not %d", len(args))
+		}
+		return runTest(cfg, cmd.Flags(), args, extra)
+	},
+}
+
+func runTest(cfg config.Config, flags *pflag.FlagSet, args, extra []string) error {
+	docker, err := flags.GetBool("docker")
+	if err != nil {
+		return err
+	}
+	report, err := flags.GetString("report")
+	if err != nil {
+		return err
+	}
+	output, err := flags.GetString("output")
+	if err != nil {
+		return err
+	}
+	if report != "" && report != "junit" {
+		return fmt.Errorf("unknown report format %q; the only format is junit", report)
+	}
+	if report != "" && output == "" {
+		return fmt.Errorf("give the file to write the report to with --output")
+	}
+	if docker && len(extra) > 0 {
+		return fmt.Errorf("arguments can't be passed on to the test runner with --docker")
+	}
+
+	dir, metadata, err := exerciseAt(cfg.UserViperConfig, args)
+	if err != nil {
+		return err
+	}
+	var results *testRunnerResults
+	start := time.Now()
+	if docker {
+		results, err = runDockerTests(dir, metadata)
+	} else {
+		results, err = runLocalTests(cfg.UserViperConfig, dir, metadata, extra)
+	}
+	if err != nil {
+		return err
+	}
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	if report != "" {
+		if err := writeJUnitReport(output, metadata, results, start, elapsed); err != nil {
+			return fmt.Errorf("unable to write the test report - %s", err)
+		}
+		fmt.Fprintf(Err, "\nWrote the test report to %s\n", output)
+	}
+
+	if results.Status != testRunnerPass {
+		if results.ExitCode != 0 {
+			fmt.Fprintf(Err, "\n%s Tests failed in %s (exit code %d)\n", glyphFailed, elapsed, results.ExitCode)
+		} else {
+			fmt.Fprintf(Err, "\n%s Tests failed in %s\n", glyphFailed, elapsed)
+		}
+		return fmt.Errorf("the tests of %s failed", metadata)
+	}
+	fmt.Fprintf(Err, "\n%s Tests passed in %s\n", glyphCompleted, elapsed)
+	return nil
+}
+
+// runLocalTests runs the tests with the track's tooling, showing what they print as they run.
+// The results have a single test for the whole run, with what the tests printed as its output.
+func runLocalTests(usrCfg *viper.Viper, dir string, metadata *workspace.ExerciseMetadata, extra []string) (*testRunnerResults, error) {
+	command, err := testCommand(usrCfg, metadata.Track, metadata.ExerciseSlug, dir)
+	if err != nil {
+		return nil, err
+	}
+	command = append(append([]string{}, command...), extra...)
+
+	fmt.Fprintf(Err, "Running the tests of %s with %s\n\n", metadata, strings.Join(command, " "))
+	var output bytes.Buffer
+	err = runTestCommand(dir, command, io.MultiWriter(Out, &output), io.MultiWriter(Err, &output))
+
+	test := testRunnerTest{Name: metadata.String(), Status: testRunnerPass, Output: output.String()}
+	results := &testRunnerResults{Status: testRunnerPass}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		test.Status = testRunnerFail
+		test.Message = fmt.Sprintf("%s exited with code %d", command[0], exitErr.ExitCode())
+		results.Status = testRunnerFail
+		results.ExitCode = exitErr.ExitCode()
+	} else if err != nil {
+		return nil, fmt.Errorf("the tests couldn't be run with %s - %s", command[0], err)
+	}
+	results.Tests = []testRunnerTest{test}
+	return results, nil
+}
+
+// testRunnerResults are the results.json that a track's test runner writes, as described in
+// https://exercism.org/docs/building/tooling/test-runners/interface.
+type testRunnerResults struct {
+	Status  string           `json:"status"`
+	Message string           `json:"message"`
+	Tests   []testRunnerTest `json:"tests"`
+	// ExitCode is that of the track's tooling, when the tests are run with it.
+	ExitCode int `json:"-"`
+}
+
+// testRunnerTest is the result of one of the tests in a test runner's results.
+type testRunnerTest struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Output  string `json:"output"`
+}
+
+// Statuses of a test run, and of each test in it.
+const (
+	testRunnerPass  = "pass"
+	testRunnerFail  = "fail"
+	testRunnerError = "error"
+)
+
+// dockerTestCommand is the command that runs the track's test runner on the exercise,
+// with the solution and the directory for the results mounted as the runner expects.
+func dockerTestCommand(track, slug, dir, outputDir string) []string {
+	return []string{
+		"docker", "run", "--rm", "--network", "none", "--read-only",
+		"--mount", fmt.Sprintf("type=bind,src=%s,dst=/solution", dir),
+		"--mount", fmt.Sprintf("type=bind,src=%s,dst=/output", outputDir),
+		"--mount", "type=tmpfs,dst=/tmp",
+		fmt.Sprintf("exercism/%s-test-runner", track),
+		slug, "/solution/", "/output/",
+	}
+}
+
+// runDockerTests runs the tests with the track's test runner in Docker, and shows its results.
+func runDockerTests(dir string, metadata *workspace.ExerciseMetadata) (*testRunnerResults, error) {
+	outputDir, err := ioutil.TempDir("", "exercism-test-runner")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(outputDir)
+
+	command := dockerTestCommand(metadata.Track, metadata.ExerciseSlug, dir, outputDir)
+	fmt.Fprintf(Err, "Running the tests of %s with the %s test runner in Docker\n\n", metadata, metadata.Track)
+	// What the runner prints is for debugging it; the results are in results.json.
+	if err := runTestCommand(dir, command, Err, Err); err != nil {
+		return nil, fmt.Errorf("the test runner couldn't be run with docker - %s", err)
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(outputDir, "results.json"))
+	if err != nil {
+		return nil, fmt.Errorf("the test runner didn't write its results - %s", err)
+	}
+	var results testRunnerResults
+	if err := json.Unmarshal(b, &results); err != nil {
+		return nil, fmt.Errorf("unable to parse the test runner's results - %s", err)
+	}
+	printTestRunnerResults(Out, results)
+	return &results, nil
+}
+
+// printTestRunnerResults shows each test with whether it passed, and why not if it didn't.
+func printTestRunnerResults(w io.Writer, results testRunnerResults) {
+	passed := 0
+	for _, test := range results.Tests {
+		if test.Status == testRunnerPass {
+			passed++
+			fmt.Fprintf(w, "%s %s\n", glyphCompleted, test.Name)
+			continue
+		}
+		fmt.Fprintf(w, "%s %s\n", glyphFailed, test.Name)
+		for _, text := range []string{test.Message, test.Output} {
+			if text = strings.TrimSpace(text); text != "" {
+				fmt.Fprintf(w, "    %s\n", strings.Replace(text, "\n", "\n    ", -1))
+			}
+		}
+	}
+	if results.Status == testRunnerError && strings.TrimSpace(results.Message) != "" {
+		fmt.Fprintf(w, "%s\n", strings.TrimSpace(results.Message))
+	}
+	if len(results.Tests) > 0 {
+		fmt.Fprintf(w, "\n%d of %d tests passed\n", passed, len(results.Tests))
+	}
+}
+
+func setupTestFlags(flags *pflag.FlagSet) {
+	flags.Bool("docker", false, "run the tests with the track's test runner in Docker")
+	flags.String("report", "", "write a report of the results in this format (junit)")
+	flags.StringP("output", "o", "", "the file to write the report to")
+}
+
+func init() {
+	RootCmd.AddCommand(testCmd)
+	setupTestFlags(testCmd.Flags())
+}
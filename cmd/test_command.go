@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// trackTestCommands are the commands that run the tests of an exercise in each track,
+// from the exercise directory, with the track's usual tooling.
+var trackTestCommands = map[string][]string{
+	"bash":       {"bats", "."},
+	"c":          {"make"},
+	"clojure":    {"lein", "test"},
+	"cpp":        {"make"},
+	"crystal":    {"crystal", "spec"},
+	"csharp":     {"dotnet", "test"},
+	"dart":       {"dart", "test"},
+	"elixir":     {"mix", "test"},
+	"elm":        {"elm-test"},
+	"erlang":     {"rebar3", "eunit"},
+	"fsharp":     {"dotnet", "test"},
+	"gleam":      {"gleam", "test"},
+	"go":         {"go", "test", "./..."},
+	"haskell":    {"stack", "test"},
+	"java":       {"gradle", "test"},
+	"javascript": {"npm", "test"},
+	"julia":      {"julia", "runtests.jl"},
+	"kotlin":     {"gradle", "test"},
+	"ocaml":      {"dune", "runtest"},
+	"python":     {"python3", "-m", "pytest"},
+	"ruby":       {"rake", "test"},
+	"rust":       {"cargo", "test"},
+	"scala":      {"sbt", "test"},
+	"swift":      {"swift", "test"},
+	"typescript": {"yarn", "test"},
+	"vbnet":      {"dotnet", "test"},
+	"zig":        {"zig", "build", "test"},
+}
+
+// testCommand returns the command that runs the tests of the exercise in the given directory.
+// It is taken from tracks.<track>.test_command in the user config, or else from the built-in commands.
+// In a configured command, {dir}, {track} and {slug} stand for the exercise directory,
+// the track and the exercise.
+func testCommand(usrCfg *viper.Viper, track, slug, dir string) ([]string, error) {
+	if command := configuredCommand(usrCfg, "test_command", track, slug, dir); command != nil {
+		return command, nil
+	}
+	args, ok := trackTestCommands[track]
+	if !ok {
+		return nil, fmt.Errorf("there's no known command to run the tests of the %s track; set one with tracks.%s.test_command in your user config", track, track)
+	}
+	return args, nil
+}
+
+// configuredCommand returns the command set with tracks.<track>.<name> in the user config,
+// with the placeholders replaced, or nil if none is set.
+func configuredCommand(usrCfg *viper.Viper, name, track, slug, dir string) []string {
+	key := fmt.Sprintf("tracks.%s.%s", track, name)
+	if usrCfg == nil || !usrCfg.IsSet(key) {
+		return nil
+	}
+	args := usrCfg.GetStringSlice(key)
+	if len(args) == 0 {
+		return nil
+	}
+	r := strings.NewReplacer("{dir}", dir, "{track}", track, "{slug}", slug)
+	command := make([]string, len(args))
+	for i, arg := range args {
+		command[i] = r.Replace(arg)
+	}
+	return command
+}
+
+// runTestCommand runs the tests in the exercise directory, writing what they print to stdout and stderr.
+// The error says the tests failed, or that they couldn't be run.
+func runTestCommand(dir string, args []string, stdout, stderr io.Writer) error {
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = dir
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/exercism/cli/workspace"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunTest(t *testing.T) {
+	co := newCapturedOutput()
+	co.newOut = &bytes.Buffer{}
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	// The go tool is at hand wherever these tests run.
+	trackTestCommands["bogus-track"] = []string{"go"}
+	defer delete(trackTestCommands, "bogus-track")
+
+	tmpDir, err := ioutil.TempDir("", "test-command")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
+	assert.NoError(t, os.MkdirAll(dir, os.FileMode(0755)))
+	writeFakeMetadata(t, dir, "bogus-track", "bogus-exercise")
+
+	cfg := fakeUserConfig("http://example.com")
+	cfg.UserViperConfig.Set("workspace", tmpDir)
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupTestFlags(flags)
+
+	assert.NoError(t, runTest(cfg, flags, []string{dir}, []string{"version"}))
+	assert.Regexp(t, "go version", co.newOut.(*bytes.Buffer).String())
+	assert.Regexp(t, "Tests passed", co.newErr.(*bytes.Buffer).String())
+
+	err = runTest(cfg, flags, []string{dir}, []string{"bogus-subcommand"})
+	if assert.Error(t, err) {
+		assert.Regexp(t, "the tests of .* failed", err.Error())
+	}
+	assert.Regexp(t, `Tests failed in .* \(exit code \d+\)`, co.newErr.(*bytes.Buffer).String())
+
+	delete(trackTestCommands, "bogus-track")
+	err = runTest(cfg, flags, []string{dir}, nil)
+	if assert.Error(t, err) {
+		assert.Regexp(t, "no known command", err.Error())
+	}
+}
+
+func TestDockerTestCommand(t *testing.T) {
+	command := dockerTestCommand("go", "leap", "/ws/go/leap", "/tmp/out")
+	assert.Equal(t, "docker", command[0])
+	assert.Contains(t, command, "--network")
+	assert.Contains(t, command, "type=bind,src=/ws/go/leap,dst=/solution")
+	assert.Contains(t, command, "type=bind,src=/tmp/out,dst=/output")
+	assert.Equal(t, []string{"exercism/go-test-runner", "leap", "/solution/", "/output/"}, command[len(command)-4:])
+}
+
+func TestPrintTestRunnerResults(t *testing.T) {
+	results := testRunnerResults{
+		Status: "fail",
+		Tests: []testRunnerTest{
+			{Name: "TestLeapYear", Status: "pass"},
+			{Name: "TestCentury", Status: "fail", Message: "expected true\ngot false", Output: "debugging"},
+		},
+	}
+	var out bytes.Buffer
+	printTestRunnerResults(&out, results)
+	expected := glyphCompleted.String() + ` TestLeapYear
+` + glyphFailed.String() + ` TestCentury
+    expected true
+    got false
+    debugging
+
+1 of 2 tests passed
+`
+	assert.Equal(t, expected, out.String())
+
+	out.Reset()
+	printTestRunnerResults(&out, testRunnerResults{Status: "error", Message: "leap.go:3: syntax error"})
+	assert.Equal(t, "leap.go:3: syntax error\n", out.String())
+}
+
+func TestRunTestJUnitReport(t *testing.T) {
+	co := newCapturedOutput()
+	co.newOut = &bytes.Buffer{}
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	trackTestCommands["bogus-track"] = []string{"go"}
+	defer delete(trackTestCommands, "bogus-track")
+
+	tmpDir, err := ioutil.TempDir("", "test-report")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
+	assert.NoError(t, os.MkdirAll(dir, os.FileMode(0755)))
+	writeFakeMetadata(t, dir, "bogus-track", "bogus-exercise")
+
+	cfg := fakeUserConfig("http://example.com")
+	cfg.UserViperConfig.Set("workspace", tmpDir)
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupTestFlags(flags)
+	report := filepath.Join(tmpDir, "results.xml")
+	flags.Set("report", "junit")
+	flags.Set("output", report)
+
+	err = runTest(cfg, flags, []string{dir}, []string{"bogus-subcommand"})
+	assert.Error(t, err)
+
+	b, err := ioutil.ReadFile(report)
+	assert.NoError(t, err)
+	xml := string(b)
+	assert.Regexp(t, `<testsuite name="bogus-track/bogus-exercise" tests="1" failures="1" errors="0"`, xml)
+	assert.Regexp(t, `<failure message="go exited with code \d+">`, xml)
+	assert.Regexp(t, "bogus-subcommand", xml)
+
+	flags.Set("report", "tap")
+	err = runTest(cfg, flags, []string{dir}, nil)
+	if assert.Error(t, err) {
+		assert.Regexp(t, "unknown report format", err.Error())
+	}
+}
+
+func TestNewJUnitReport(t *testing.T) {
+	metadata := &workspace.ExerciseMetadata{Track: "go", ExerciseSlug: "leap"}
+	results := &testRunnerResults{
+		Status: "fail",
+		Tests: []testRunnerTest{
+			{Name: "TestLeapYear", Status: "pass"},
+			{Name: "TestCentury", Status: "fail", Message: "expected true"},
+		},
+	}
+	report := newJUnitReport(metadata, results, time.Now(), 1500*time.Millisecond)
+	suite := report.Suites[0]
+	assert.Equal(t, "go/leap", suite.Name)
+	assert.Equal(t, 2, suite.Tests)
+	assert.Equal(t, 1, suite.Failures)
+	assert.Equal(t, "1.500", suite.Time)
+	assert.Nil(t, suite.Cases[0].Failure)
+	assert.Equal(t, "expected true", suite.Cases[1].Failure.Message)
+
+	report = newJUnitReport(metadata, &testRunnerResults{Status: "error", Message: "syntax error"}, time.Now(), 0)
+	suite = report.Suites[0]
+	assert.Equal(t, 1, suite.Errors)
+	assert.Equal(t, "syntax error", suite.Cases[0].Error.Message)
+}
+
+func TestTestCommandFromUserConfig(t *testing.T) {
+	cfg := fakeUserConfig("http://example.com")
+	command, err := testCommand(cfg.UserViperConfig, "rust", "leap", "/ws/rust/leap")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cargo", "test"}, command)
+
+	cfg.UserViperConfig.Set("tracks.rust.test_command", []string{"cargo", "test", "--manifest-path", "{dir}/Cargo.toml", "{track}-{slug}"})
+	command, err = testCommand(cfg.UserViperConfig, "rust", "leap", "/ws/rust/leap")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cargo", "test", "--manifest-path", "/ws/rust/leap/Cargo.toml", "rust-leap"}, command)
+
+	_, err = testCommand(cfg.UserViperConfig, "bogus-track", "leap", "/ws/bogus-track/leap")
+	if assert.Error(t, err) {
+		assert.Regexp(t, "tracks.bogus-track.test_command", err.Error())
+	}
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/exercism/cli/api"
+)
+
+// timeoutGracePeriod is how long a command that ran out of time gets to notice and clean up
+// after its requests are cancelled, before it is stopped regardless.
+const timeoutGracePeriod = 2 * time.Second
+
+// commandTimeoutError is a command taking longer than --timeout allows.
+type commandTimeoutError struct {
+	limit time.Duration
+}
+
+func (e *commandTimeoutError) Error() string {
+	return fmt.Sprintf("gave up after %s, as set by --timeout", e.limit)
+}
+
+// parseTimeout reads the --timeout flag: a duration such as 30s or 2m,
+// or a number of seconds as it used to be given. Zero means no limit.
+func parseTimeout(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, fmt.Errorf("invalid --timeout %q: it can't be negative", value)
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid --timeout %q: use a duration such as 30s or 2m", value)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("invalid --timeout %q: it can't be negative", value)
+	}
+	return d, nil
+}
+
+// limitCommand bounds the rest of the command to the given time.
+// Requests to the API are cancelled when the time is up. If the command is still running
+// after the grace period, the failure is reported and the process exits,
+// so that scripts calling the CLI can count on it not hanging.
+func limitCommand(limit time.Duration, errorFormat string) {
+	ctx, cancel := context.WithTimeout(context.Background(), limit)
+	api.Context = ctx
+
+	time.AfterFunc(limit+timeoutGracePeriod, func() {
+		cancel()
+		reportError(Err, &commandTimeoutError{limit: limit}, errorFormat)
+		os.Exit(-1)
+	})
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTimeout(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected time.Duration
+		valid    bool
+	}{
+		{"", 0, true},
+		{"0", 0, true},
+		{"30s", 30 * time.Second, true},
+		{"1m30s", 90 * time.Second, true},
+		{"45", 45 * time.Second, true},
+		{"-5", 0, false},
+		{"-5s", 0, false},
+		{"soon", 0, false},
+	}
+
+	for _, tc := range testCases {
+		timeout, err := parseTimeout(tc.value)
+		if tc.valid {
+			assert.NoError(t, err, tc.value)
+			assert.Equal(t, tc.expected, timeout, tc.value)
+		} else {
+			assert.Error(t, err, tc.value)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// toolchainProbe is a program a track needs, and how to tell which version of it is installed.
+type toolchainProbe struct {
+	// Command prints the version of the program.
+	Command []string
+	// MinVersion is the oldest version that the track's exercises work with, if it matters.
+	MinVersion string
+}
+
+// trackToolchains are the programs that each track needs to build and test its exercises.
+// The program that runs the tests is checked separately, from the track's test command.
+// Java 8 calls itself 1.8, so that is the version it is checked for.
+var trackToolchains = map[string][]toolchainProbe{
+	"bash":       {{Command: []string{"bash", "--version"}, MinVersion: "4.0"}},
+	"c":          {{Command: []string{"cc", "--version"}}},
+	"clojure":    {{Command: []string{"java", "-version"}, MinVersion: "1.8"}},
+	"cpp":        {{Command: []string{"c++", "--version"}}, {Command: []string{"cmake", "--version"}, MinVersion: "3.5"}},
+	"csharp":     {{Command: []string{"dotnet", "--version"}, MinVersion: "6.0"}},
+	"elixir":     {{Command: []string{"elixir", "-e", "IO.puts System.version"}, MinVersion: "1.10"}},
+	"erlang":     {{Command: []string{"erl", "-noshell", "-eval", "io:format(\"~s~n\", [erlang:system_info(otp_release)]), halt()."}, MinVersion: "23"}},
+	"fsharp":     {{Command: []string{"dotnet", "--version"}, MinVersion: "6.0"}},
+	"go":         {{Command: []string{"go", "version"}, MinVersion: "1.18"}},
+	"haskell":    {{Command: []string{"stack", "--version"}, MinVersion: "2.0"}},
+	"java":       {{Command: []string{"java", "-version"}, MinVersion: "11"}},
+	"javascript": {{Command: []string{"node", "--version"}, MinVersion: "16"}},
+	"kotlin":     {{Command: []string{"java", "-version"}, MinVersion: "11"}},
+	"python":     {{Command: []string{"python3", "--version"}, MinVersion: "3.7"}, {Command: []string{"python3", "-m", "pytest", "--version"}}},
+	"ruby":       {{Command: []string{"ruby", "--version"}, MinVersion: "2.7"}, {Command: []string{"ruby", "-e", "require 'minitest'; puts Minitest::VERSION"}}},
+	"rust":       {{Command: []string{"rustc", "--version"}, MinVersion: "1.56"}},
+	"scala":      {{Command: []string{"java", "-version"}, MinVersion: "1.8"}},
+	"swift":      {{Command: []string{"swift", "--version"}}},
+	"typescript": {{Command: []string{"node", "--version"}, MinVersion: "16"}},
+}
+
+// toolchainChecks check that the programs the track needs are installed, recent enough,
+// and that the program its tests are run with is there.
+func toolchainChecks(usrCfg *viper.Viper, track string) []*doctorCheck {
+	install := fmt.Sprintf("See https://exercism.org/docs/tracks/%s/installation", track)
+	var checks []*doctorCheck
+	for _, probe := range trackToolchains[track] {
+		checks = append(checks, checkToolchainProbe(track, probe, install))
+	}
+
+	check := &doctorCheck{Name: fmt.Sprintf("%s test command", track), Status: doctorPass}
+	command, err := testCommand(usrCfg, track, "", "")
+	if err != nil {
+		check.Status = doctorFail
+		check.Detail = err.Error()
+		return append(checks, check)
+	}
+	path, err := exec.LookPath(command[0])
+	if err != nil {
+		check.Status = doctorFail
+		check.Detail = fmt.Sprintf("%s isn't installed, so '%s' can't run the tests", command[0], strings.Join(command, " "))
+		check.Fixes = []string{install}
+		return append(checks, check)
+	}
+	check.Detail = fmt.Sprintf("%s (%s)", strings.Join(command, " "), path)
+	return append(checks, check)
+}
+
+// checkToolchainProbe runs the probe, and checks the version it prints.
+func checkToolchainProbe(track string, probe toolchainProbe, install string) *doctorCheck {
+	name := strings.Join(probe.Command, " ")
+	if len(probe.Command) > 2 {
+		name = strings.Join(probe.Command[:2], " ")
+	}
+	check := &doctorCheck{Name: fmt.Sprintf("%s: %s", track, name), Status: doctorPass}
+	if _, err := exec.LookPath(probe.Command[0]); err != nil {
+		check.Status = doctorFail
+		check.Detail = fmt.Sprintf("%s isn't installed", probe.Command[0])
+		check.Fixes = []string{install}
+		return check
+	}
+	// Some programs print their version to stderr.
+	output, err := exec.Command(probe.Command[0], probe.Command[1:]...).CombinedOutput()
+	if err != nil {
+		check.Status = doctorFail
+		detail := firstLine(string(output))
+		if detail == "" {
+			detail = err.Error()
+		}
+		check.Detail = fmt.Sprintf("'%s' failed: %s", strings.Join(probe.Command, " "), detail)
+		check.Fixes = []string{install}
+		return check
+	}
+	version := toolchainVersion.FindString(string(output))
+	check.Detail = firstLine(string(output))
+	if probe.MinVersion == "" {
+		return check
+	}
+	if version == "" {
+		check.Detail = fmt.Sprintf("%s (unable to tell whether it is %s or later)", check.Detail, probe.MinVersion)
+		return check
+	}
+	if compareVersions(version, probe.MinVersion) < 0 {
+		check.Status = doctorFail
+		check.Detail = fmt.Sprintf("version %s is installed, but the track needs %s or later", version, probe.MinVersion)
+		check.Fixes = []string{install}
+	}
+	return check
+}
+
+// toolchainVersion finds the first version number in what a program prints.
+var toolchainVersion = regexp.MustCompile(`\d+(\.\d+)*`)
+
+// compareVersions compares dotted version numbers, returning -1, 0 or 1.
+// Missing parts count as zero, so 1.18 is the same as 1.18.0.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
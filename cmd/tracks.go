@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/exercism/cli/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// tracksCmd lists the tracks.
+var tracksCmd = &cobra.Command{
+	Use:        "tracks",
+	SuggestFor: []string{"languages"},
+	Short:      "List the tracks.",
+	Long: `List all the tracks, whether you've joined them, how many of their
+exercises you've completed, and whether they have learning mode, in which
+concept exercises teach the language step by step.
+
+Pass --joined to list only the tracks you've joined, and --json to get the
+list in a machine-readable format:
+
+    exercism tracks --joined --json
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.NewConfig()
+
+		v := viper.New()
+		v.AddConfigPath(cfg.Dir)
+		v.SetConfigName("user")
+		v.SetConfigType("json")
+		// Ignore error. If the file doesn't exist, that is fine.
+		_ = v.ReadInConfig()
+		config.BindUserEnv(v)
+		cfg.UserViperConfig = v
+
+		return runTracks(cfg, cmd.Flags())
+	},
+}
+
+func runTracks(cfg config.Config, flags *pflag.FlagSet) error {
+	usrCfg := cfg.UserViperConfig
+	if err := validateUserConfig(usrCfg); err != nil {
+		return err
+	}
+	joinedOnly, err := flags.GetBool("joined")
+	if err != nil {
+		return err
+	}
+	asJSON, err := flags.GetBool("json")
+	if err != nil {
+		return err
+	}
+
+	tracks, err := fetchTracks(usrCfg.GetString("token"), usrCfg.GetString("apibaseurl"))
+	if err != nil {
+		return err
+	}
+	listed := []trackSummary{}
+	for _, track := range tracks {
+		if !joinedOnly || track.Joined {
+			listed = append(listed, track)
+		}
+	}
+	if asJSON {
+		return json.NewEncoder(Out).Encode(listed)
+	}
+	if len(listed) == 0 {
+		if joinedOnly {
+			fmt.Fprintln(Err, "You haven't joined any tracks. Join one on the website.")
+		} else {
+			fmt.Fprintln(Err, "There are no tracks.")
+		}
+		return nil
+	}
+	printTracks(listed)
+	return nil
+}
+
+func printTracks(tracks []trackSummary) {
+	w := tabwriter.NewWriter(Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TRACK\tTITLE\tJOINED\tCOMPLETED\tLEARNING MODE")
+	for _, track := range tracks {
+		joined, completed := "-", "-"
+		if track.Joined {
+			joined = glyphCompleted.String()
+			completed = fmt.Sprintf("%d/%d", track.NumCompletedExercises, track.NumExercises)
+		}
+		learningMode := "no"
+		if track.LearningMode {
+			learningMode = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", track.Slug, orDash(track.Title), joined, completed, learningMode)
+	}
+	w.Flush()
+}
+
+func setupTracksFlags(flags *pflag.FlagSet) {
+	flags.Bool("joined", false, "only list the tracks you've joined")
+	flags.Bool("json", false, "print the tracks as JSON")
+}
+
+func init() {
+	RootCmd.AddCommand(tracksCmd)
+	setupTracksFlags(tracksCmd.Flags())
+}
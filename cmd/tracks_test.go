@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/exercism/cli/config"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+const tracksPayload = `
+{
+	"tracks": [
+		{"slug": "go", "title": "Go", "is_joined": true, "learning_mode": true, "num_exercises": 141, "num_completed_exercises": 12},
+		{"slug": "rust", "title": "Rust", "is_joined": false, "learning_mode": false, "num_exercises": 98},
+		{"slug": "python", "title": "Python", "is_joined": true, "learning_mode": true, "num_exercises": 137, "num_completed_exercises": 137}
+	]
+}
+`
+
+func TestTracks(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tracks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, tracksPayload)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", "/home/alice/exercism")
+	v.Set("apibaseurl", ts.URL)
+	cfg := config.Config{UserViperConfig: v}
+
+	run := func(args ...string) string {
+		co := newCapturedOutput()
+		co.newOut = &bytes.Buffer{}
+		co.override()
+		defer co.reset()
+
+		flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+		setupTracksFlags(flags)
+		assert.NoError(t, flags.Parse(args))
+		assert.NoError(t, runTracks(cfg, flags))
+		return Out.(*bytes.Buffer).String()
+	}
+
+	out := run()
+	assert.Regexp(t, `go +Go +\S+ +12/141 +yes`, out)
+	assert.Regexp(t, `rust +Rust +- +- +no`, out)
+	assert.Regexp(t, `python +Python +\S+ +137/137 +yes`, out)
+
+	var tracks []trackSummary
+	assert.NoError(t, json.Unmarshal([]byte(run("--joined", "--json")), &tracks))
+	if assert.Len(t, tracks, 2) {
+		assert.Equal(t, "go", tracks[0].Slug)
+		assert.Equal(t, 12, tracks[0].NumCompletedExercises)
+		assert.Equal(t, "python", tracks[1].Slug)
+	}
+}
@@ -2,15 +2,20 @@ package cmd
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
-	"html/template"
+	"path/filepath"
 	"runtime"
+	"sort"
+	"strings"
 	"sync"
+	"text/template"
 	"time"
 
 	"github.com/exercism/cli/cli"
 	"github.com/exercism/cli/config"
 	"github.com/exercism/cli/debug"
+	"github.com/exercism/cli/workspace"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -20,9 +25,10 @@ var fullAPIKey bool
 
 // troubleshootCmd does a diagnostic self-check.
 var troubleshootCmd = &cobra.Command{
-	Use:     "troubleshoot",
-	Aliases: []string{"t", "debug"},
-	Short:   "Troubleshoot does a diagnostic self-check.",
+	Use:        "troubleshoot",
+	Aliases:    []string{"t", "debug"},
+	SuggestFor: []string{"diagnose", "info"},
+	Short:      "Troubleshoot does a diagnostic self-check.",
 	Long: `Provides output to help with troubleshooting.
 
 If you're running into trouble, copy and paste the output from the troubleshoot
@@ -40,6 +46,7 @@ command into a GitHub issue so we can help figure out what's going on.
 		v.SetConfigType("json")
 		// Ignore error. If the file doesn't exist, that is fine.
 		_ = v.ReadInConfig()
+		config.BindUserEnv(v)
 
 		cfg.UserViperConfig = v
 
@@ -62,6 +69,7 @@ type Status struct {
 	System          systemStatus
 	Configuration   configurationStatus
 	APIReachability apiReachabilityStatus
+	Workspace       workspaceHealthStatus
 	cfg             config.Config
 	cli             *cli.CLI
 }
@@ -88,6 +96,20 @@ type configurationStatus struct {
 	TokenURL  string
 }
 
+type workspaceHealthStatus struct {
+	Exercises int
+	Error     error
+	Issues    []healthIssue
+}
+
+// healthIssue is a class of problem found in the workspace,
+// along with the commands that fix it.
+type healthIssue struct {
+	Description string
+	Count       int
+	Fixes       []string
+}
+
 type apiReachabilityStatus struct {
 	Services []*apiPing
 }
@@ -114,6 +136,7 @@ func (status *Status) check() (string, error) {
 	status.System = newSystemStatus()
 	status.Configuration = newConfigurationStatus(status)
 	status.APIReachability = newAPIReachabilityStatus(status.cfg)
+	status.Workspace = newWorkspaceHealthStatus(status.cfg)
 
 	return status.compile()
 }
@@ -199,6 +222,85 @@ func newConfigurationStatus(status *Status) configurationStatus {
 	return cs
 }
 
+func newWorkspaceHealthStatus(cfg config.Config) workspaceHealthStatus {
+	dir := cfg.UserViperConfig.GetString("workspace")
+	if dir == "" {
+		return workspaceHealthStatus{Error: errors.New("no workspace configured")}
+	}
+	ws, err := openWorkspace(cfg.UserViperConfig)
+	if err != nil {
+		return workspaceHealthStatus{Error: err}
+	}
+	report, err := ws.CheckHealth()
+	if err != nil {
+		return workspaceHealthStatus{Error: err}
+	}
+	return workspaceHealthStatus{
+		Exercises: report.Exercises,
+		Issues:    healthIssues(report),
+	}
+}
+
+// healthIssues describes each class of problem in the report, and how to fix it.
+func healthIssues(report workspace.HealthReport) []healthIssue {
+	var issues []healthIssue
+
+	if n := len(report.SlugMismatches); n > 0 {
+		issue := healthIssue{Description: "Directories named differently from their exercise", Count: n}
+		for _, dir := range report.SlugMismatches {
+			issue.Fixes = append(issue.Fixes, fmt.Sprintf("mv %q %q", dir, report.RenameTo[dir]))
+		}
+		issues = append(issues, issue)
+	}
+	if n := len(report.LegacyMetadata); n > 0 {
+		issue := healthIssue{Description: "Exercises with legacy metadata (migrated automatically on submit)", Count: n}
+		for _, dir := range report.LegacyMetadata {
+			issue.Fixes = append(issue.Fixes, fmt.Sprintf("mkdir -p %q && mv %q %q",
+				filepath.Join(dir, ".exercism"),
+				filepath.Join(dir, ".solution.json"),
+				filepath.Join(dir, ".exercism", "metadata.json"),
+			))
+		}
+		issues = append(issues, issue)
+	}
+	if n := len(report.DuplicateIDs); n > 0 {
+		issue := healthIssue{Description: "Solutions downloaded into more than one directory", Count: n}
+		ids := make([]string, 0, n)
+		for id := range report.DuplicateIDs {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			dirs := report.DuplicateIDs[id]
+			issue.Fixes = append(issue.Fixes, fmt.Sprintf("keep one of %s and remove the others, or re-download with '%s download --uuid=%s'; compare them with 'diff -r %s %s'",
+				strings.Join(dirs, ", "), BinaryName, id, dirs[0], dirs[1]))
+		}
+		issues = append(issues, issue)
+	}
+	if n := len(report.Unwritable); n > 0 {
+		issue := healthIssue{Description: "Directories that cannot be written to", Count: n}
+		for _, dir := range report.Unwritable {
+			issue.Fixes = append(issue.Fixes, fmt.Sprintf("chmod u+w %q", dir))
+		}
+		issues = append(issues, issue)
+	}
+	if n := len(report.Unreadable); n > 0 {
+		issue := healthIssue{Description: "Directories that cannot be read, so weren't checked", Count: n}
+		for _, dir := range report.Unreadable {
+			issue.Fixes = append(issue.Fixes, fmt.Sprintf("chmod u+rx %q", dir))
+		}
+		issues = append(issues, issue)
+	}
+	if n := len(report.AbnormalNesting); n > 0 {
+		issue := healthIssue{Description: "Exercises outside of their expected location", Count: n}
+		for _, dir := range report.AbnormalNesting {
+			issue.Fixes = append(issue.Fixes, fmt.Sprintf("mv %q %q", dir, report.MoveTo[dir]))
+		}
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
 func (ping *apiPing) Call(wg *sync.WaitGroup) {
 	defer wg.Done()
 
@@ -246,6 +348,17 @@ Config:    {{ .Configuration.Dir }}
 API key:   {{ with .Configuration.Token }}{{ . }}{{ else }}<not configured>
 Find your API key at {{ .Configuration.TokenURL }}{{ end }}
 
+Workspace Health
+----------------
+{{ with .Workspace.Error -}}
+Unable to check the workspace: {{ . }}
+{{ else -}}
+Exercises: {{ .Workspace.Exercises }}
+{{ range .Workspace.Issues }}
+{{ .Description }}: {{ .Count }}
+{{ range .Fixes }}    {{ . }}
+{{ end }}{{ else }}No problems found.
+{{ end }}{{ end }}
 API Reachability
 ----------------
 {{ range .APIReachability.Services }}
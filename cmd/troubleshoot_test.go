@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/exercism/cli/config"
+	"github.com/exercism/cli/workspace"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkspaceHealthStatus(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "troubleshoot-workspace")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	ws, err := workspace.New(tmpDir)
+	assert.NoError(t, err)
+
+	metadata := &workspace.ExerciseMetadata{Track: "go", ExerciseSlug: "leap", ID: "id-1", IsRequester: true}
+	assert.NoError(t, metadata.Write(filepath.Join(ws.Dir, "go", "leap")))
+	assert.NoError(t, metadata.Write(filepath.Join(ws.Dir, "go", "leap-copy")))
+
+	v := viper.New()
+	v.Set("workspace", tmpDir)
+	status := Status{cfg: config.Config{UserViperConfig: v}}
+	status.Workspace = newWorkspaceHealthStatus(status.cfg)
+
+	assert.NoError(t, status.Workspace.Error)
+	assert.Equal(t, 2, status.Workspace.Exercises)
+	if assert.Equal(t, 2, len(status.Workspace.Issues)) {
+		mismatch := status.Workspace.Issues[0]
+		assert.Equal(t, 1, mismatch.Count)
+		assert.Equal(t, []string{`mv "` + filepath.Join(ws.Dir, "go", "leap-copy") + `" "` + filepath.Join(ws.Dir, "go", "leap") + `"`}, mismatch.Fixes)
+
+		duplicates := status.Workspace.Issues[1]
+		assert.Equal(t, 1, duplicates.Count)
+		assert.Regexp(t, "download --uuid=id-1", duplicates.Fixes[0])
+	}
+
+	s, err := status.compile()
+	assert.NoError(t, err)
+	assert.Contains(t, s, "Directories named differently from their exercise: 1")
+	assert.Contains(t, s, `mv "`)
+}
+
+func TestWorkspaceHealthStatusWithoutWorkspace(t *testing.T) {
+	status := newWorkspaceHealthStatus(config.Config{UserViperConfig: viper.New()})
+	if assert.Error(t, status.Error) {
+		assert.Regexp(t, "no workspace configured", status.Error.Error())
+	}
+}
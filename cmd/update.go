@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/exercism/cli/config"
+	"github.com/exercism/cli/workspace"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// updateCmd brings a downloaded exercise up to date with the track's latest version of it.
+var updateCmd = &cobra.Command{
+	Use:   "update [PATH]",
+	Short: "Update an exercise to the track's latest version.",
+	Long: `Update an exercise you've downloaded to the track's latest version of it.
+
+Tracks fix and improve their exercises, changing the tests, the stubs and the
+instructions. This fetches the exercise again and applies the changes to your
+copy, then reports what changed.
+
+Files that only the track has changed take the track's version. Files that
+both you and the track have changed are merged, using the version you
+downloaded to tell your changes from the track's. Lines that both of you
+changed are marked with <<<<<<< mine, ======= and >>>>>>> theirs for you to
+sort out. The files are backed up before they're merged, see
+'exercism backups --help'.
+
+Pass the path to the exercise, or run the command from within it.
+To upgrade the CLI itself, see 'exercism upgrade'.
+`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.NewConfig()
+
+		v := viper.New()
+		v.AddConfigPath(cfg.Dir)
+		v.SetConfigName("user")
+		v.SetConfigType("json")
+		// Ignore error. If the file doesn't exist, that is fine.
+		_ = v.ReadInConfig()
+		config.BindUserEnv(v)
+		cfg.UserViperConfig = v
+
+		return runUpdate(cfg, cmd.Flags(), args)
+	},
+}
+
+// How a file of an exercise is affected by updating it.
+const (
+	updateAdded     = "added"
+	updateUpdated   = "updated"
+	updateMerged    = "merged"
+	updateKept      = "kept"
+	updateUnchanged = "unchanged"
+)
+
+// updatedFile is how a file of an exercise is affected by updating it.
+type updatedFile struct {
+	Path   string
+	Change string
+}
+
+func runUpdate(cfg config.Config, flags *pflag.FlagSet, args []string) error {
+	usrCfg := cfg.UserViperConfig
+	if err := validateUserConfig(usrCfg); err != nil {
+		return err
+	}
+	dir, metadata, err := exerciseAt(usrCfg, args)
+	if err != nil {
+		return err
+	}
+
+	d, err := newDownloadForExercise(cfg, metadata)
+	if err != nil {
+		return err
+	}
+	d.resolution = resolveUpdate
+
+	err = withRetry(cfg, func() error {
+		return d.requestPayload()
+	})
+	if err != nil {
+		return err
+	}
+	var files []downloadedFile
+	err = withRetry(cfg, func() (err error) {
+		files, err = d.fetchFiles()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	d.convertLineEndings(files)
+
+	changes, err := updateChanges(dir, files)
+	if err != nil {
+		return err
+	}
+	unchanged := map[string]bool{}
+	for _, change := range changes {
+		unchanged[change.Path] = change.Change == updateUnchanged
+	}
+	var changed []downloadedFile
+	for _, file := range files {
+		if !unchanged[file.path] {
+			changed = append(changed, file)
+		}
+	}
+	if _, err := d.writeFiles(changed); err != nil {
+		return err
+	}
+	printUpdateChanges(metadata.ExerciseSlug, changes)
+	return nil
+}
+
+// newDownloadForExercise sets up downloading the exercise that was downloaded to the workspace before.
+func newDownloadForExercise(cfg config.Config, metadata *workspace.ExerciseMetadata) (*download, error) {
+	usrCfg := cfg.UserViperConfig
+	d := &download{
+		token:      usrCfg.GetString("token"),
+		apibaseurl: usrCfg.GetString("apibaseurl"),
+		workspace:  usrCfg.GetString("workspace"),
+		snapshots:  snapshotStore(cfg),
+		stateDir:   cfg.StateDir,
+		limit:      newJobLimit(defaultJobs()),
+	}
+	if metadata.IsRequester {
+		d.slug, d.track, d.team = metadata.ExerciseSlug, metadata.Track, metadata.Team
+	} else {
+		d.uuid = metadata.ID
+	}
+
+	var err error
+	if d.conceptDir, err = conceptDir(usrCfg); err != nil {
+		return nil, err
+	}
+	if d.fileMode, d.executableMode, err = downloadFileModes(usrCfg); err != nil {
+		return nil, err
+	}
+	if d.lineEndings, err = workspace.NewLineEndings(usrCfg.GetString("line_endings")); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// updateChanges works out how each of the files fetched affects the exercise in the given directory.
+// Files the track hasn't changed since they were downloaded are left as they are, even if they were changed locally.
+func updateChanges(dir string, files []downloadedFile) ([]updatedFile, error) {
+	checksums, err := workspace.NewChecksums(dir)
+	if err != nil {
+		checksums = workspace.Checksums{}
+	}
+	changes := make([]updatedFile, 0, len(files))
+	for _, file := range files {
+		change := updatedFile{Path: file.path}
+		existing, err := ioutil.ReadFile(workspace.LongPath(filepath.Join(dir, file.path)))
+		switch {
+		case os.IsNotExist(err):
+			change.Change = updateAdded
+		case err != nil:
+			return nil, err
+		case bytes.Equal(existing, file.contents):
+			change.Change = updateUnchanged
+		case !checksums.IsModified(file.path, file.contents):
+			// The track hasn't changed it since it was downloaded, only you have.
+			change.Change = updateUnchanged
+		case checksums.IsModified(file.path, existing):
+			// Both you and the track have changed it. It can only be merged if the original was kept.
+			change.Change = updateMerged
+			if _, err := workspace.ReadOriginal(dir, file.path); os.IsNotExist(err) {
+				change.Change = updateKept
+			}
+		default:
+			change.Change = updateUpdated
+		}
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+func printUpdateChanges(slug string, changes []updatedFile) {
+	var changed bool
+	for _, change := range changes {
+		if change.Change != updateUnchanged {
+			changed = true
+		}
+	}
+	if !changed {
+		fmt.Fprintf(Err, "%s is up to date.\n", slug)
+		return
+	}
+	fmt.Fprintf(Err, "Updated %s:\n\n", slug)
+	for _, change := range changes {
+		switch change.Change {
+		case updateAdded, updateUpdated, updateMerged:
+			fmt.Fprintf(Err, "    %-9s %s\n", change.Change, change.Path)
+		case updateKept:
+			fmt.Fprintf(Err, "    %-9s %s (downloaded before it could be merged)\n", change.Change, change.Path)
+		}
+	}
+}
+
+func init() {
+	RootCmd.AddCommand(updateCmd)
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/exercism/cli/config"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdate(t *testing.T) {
+	co := newCapturedOutput()
+	stderr := &bytes.Buffer{}
+	co.newErr = stderr
+	co.override()
+	defer co.reset()
+
+	tmpDir, err := ioutil.TempDir("", "update")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	version := "1"
+	files := `"leap.go", "helper.go", "leap_test.go", "README.md"`
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	mux.HandleFunc("/solutions/latest", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"solution": {"id": "bogus-id", "user": {"handle": "alice", "is_requester": true}, "exercise": {"id": "bogus-exercise", "track": {"id": "bogus-track"}}, "file_download_base_url": "%s/", "files": [%s]}}`, ts.URL, files)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/README.md" {
+			fmt.Fprint(w, "the instructions")
+			return
+		}
+		if r.URL.Path == "/leap.go" {
+			fmt.Fprintf(w, "// version %s\n\nfunc Leap() {}\n", version)
+			return
+		}
+		fmt.Fprintf(w, "%s version %s", r.URL.Path, version)
+	})
+
+	v := viper.New()
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	v.Set("token", "abc123")
+	cfg := config.Config{
+		UserViperConfig: v,
+		StateDir:        filepath.Join(tmpDir, "state"),
+	}
+
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupDownloadFlags(flags)
+	flags.Set("exercise", "bogus-exercise")
+	assert.NoError(t, runDownload(cfg, flags, []string{}))
+
+	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
+	read := func(path string) string {
+		b, err := ioutil.ReadFile(filepath.Join(dir, path))
+		assert.NoError(t, err)
+		return string(b)
+	}
+	write := func(path, contents string) {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, path), []byte(contents), os.FileMode(0644)))
+	}
+	write("leap.go", "// version 1\n\nfunc Leap() { return }\n")
+	write("README.md", "my notes on the instructions")
+
+	version = "2"
+	files = `"leap.go", "helper.go", "leap_test.go", "README.md", "HINTS.md"`
+	assert.NoError(t, runUpdate(cfg, pflag.NewFlagSet("fake", pflag.PanicOnError), []string{dir}))
+
+	// Files both you and the track changed are merged, and the ones only you changed are kept.
+	assert.Equal(t, "// version 2\n\nfunc Leap() { return }\n", read("leap.go"))
+	assert.Equal(t, "my notes on the instructions", read("README.md"))
+	assert.Equal(t, "/helper.go version 2", read("helper.go"))
+	assert.Equal(t, "/leap_test.go version 2", read("leap_test.go"))
+	assert.Equal(t, "/HINTS.md version 2", read("HINTS.md"))
+
+	out := stderr.String()
+	assert.Regexp(t, "updated +helper.go", out)
+	assert.Regexp(t, "updated +leap_test.go", out)
+	assert.Regexp(t, "added +HINTS.md", out)
+	assert.Regexp(t, "merged +leap.go", out)
+	assert.NotRegexp(t, "README.md", out)
+
+	stderr.Reset()
+	assert.NoError(t, runUpdate(cfg, pflag.NewFlagSet("fake", pflag.PanicOnError), []string{dir}))
+	assert.Regexp(t, "bogus-exercise is up to date", stderr.String())
+	assert.Equal(t, "// version 2\n\nfunc Leap() { return }\n", read("leap.go"))
+}
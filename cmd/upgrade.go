@@ -9,9 +9,10 @@ import (
 
 // upgradeCmd downloads and installs the most recent version of the CLI.
 var upgradeCmd = &cobra.Command{
-	Use:     "upgrade",
-	Aliases: []string{"u"},
-	Short:   "Upgrade to the latest version of the CLI.",
+	Use:        "upgrade",
+	Aliases:    []string{"u"},
+	SuggestFor: []string{"self-update"},
+	Short:      "Upgrade to the latest version of the CLI.",
 	Long: `Upgrade to the latest version of the CLI.
 
 This finds and downloads the latest release, if you don't
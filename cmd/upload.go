@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/exercism/cli/api"
+)
+
+// chunkedUploadThreshold is the size in bytes above which a submission
+// is uploaded in chunks, provided that the API supports it.
+const chunkedUploadThreshold = 256 * 1024
+
+var errChunkedUploadUnsupported = errors.New("the API does not support chunked uploads")
+
+// uploadState is the progress of a chunked upload.
+// It is persisted after each acknowledged chunk so that an interrupted
+// upload can be resumed rather than restarted.
+type uploadState struct {
+	ID           string `json:"id"`
+	URL          string `json:"url"`
+	Checksum     string `json:"checksum"`
+	Boundary     string `json:"boundary"`
+	ChunkSize    int    `json:"chunk_size"`
+	Acknowledged int    `json:"acknowledged"`
+}
+
+// uploadStatePath is where the progress of an upload for a solution is kept.
+func uploadStatePath(stateDir, solutionID string) string {
+	return filepath.Join(stateDir, "uploads", fmt.Sprintf("%s.json", solutionID))
+}
+
+// loadUploadState reads the state of an unfinished upload.
+// It returns nil if there is no unfinished upload.
+func loadUploadState(path string) (*uploadState, error) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state uploadState
+	if err := json.Unmarshal(b, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (state *uploadState) save(path string) error {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.FileMode(0755)); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, os.FileMode(0600))
+}
+
+// chunkedUpload sends a multipart submission body to the API in pieces.
+type chunkedUpload struct {
+	client    *api.Client
+	url       string
+	statePath string
+	state     *uploadState
+	// response is the body of the API's response to completing the upload.
+	response []byte
+}
+
+// run uploads the body, resuming a previous attempt if the body is unchanged.
+// It returns errChunkedUploadUnsupported if the API doesn't offer chunked uploads.
+func (u *chunkedUpload) run(body []byte, boundary string) error {
+	checksum := fmt.Sprintf("sha256:%x", sha256.Sum256(body))
+
+	resuming := u.state != nil && u.state.Checksum == checksum && u.state.ChunkSize > 0
+	for {
+		if !resuming {
+			if err := u.start(len(body), checksum, boundary); err != nil {
+				return err
+			}
+		}
+		err := u.sendChunks(body, resuming)
+		if err == errUploadDiscarded {
+			// Start again from scratch.
+			u.state = nil
+			if err := os.Remove(u.statePath); err != nil {
+				return err
+			}
+			resuming = false
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		break
+	}
+
+	resp, err := u.send("POST", fmt.Sprintf("%s/complete", u.state.URL), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return decodedAPIError(resp)
+	}
+	if u.response, err = ioutil.ReadAll(resp.Body); err != nil {
+		return err
+	}
+	return os.Remove(u.statePath)
+}
+
+// errUploadDiscarded means that the API no longer has the upload that is being resumed.
+var errUploadDiscarded = errors.New("the upload was discarded")
+
+// sendChunks sends the chunks of the body that the API hasn't acknowledged yet.
+func (u *chunkedUpload) sendChunks(body []byte, resuming bool) error {
+	total := (len(body) + u.state.ChunkSize - 1) / u.state.ChunkSize
+	for i := u.state.Acknowledged; i < total; i++ {
+		start := i * u.state.ChunkSize
+		end := start + u.state.ChunkSize
+		if end > len(body) {
+			end = len(body)
+		}
+		if err := u.sendChunk(i, body[start:end], resuming); err != nil {
+			return err
+		}
+
+		u.state.Acknowledged = i + 1
+		if err := u.state.save(u.statePath); err != nil {
+			return err
+		}
+		fmt.Fprintf(Err, "Uploaded chunk %d of %d\n", i+1, total)
+	}
+	return nil
+}
+
+// sendChunk sends one chunk of the body, done with the response before the next one is sent.
+func (u *chunkedUpload) sendChunk(i int, chunk []byte, resuming bool) error {
+	resp, err := u.send("PUT", fmt.Sprintf("%s/chunks/%d", u.state.URL, i), chunk)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// The API may have discarded an upload that was abandoned a while ago.
+	if resuming && resp.StatusCode == http.StatusNotFound {
+		return errUploadDiscarded
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return decodedAPIError(resp)
+	}
+	// Read the rest of the response, so that the connection can be reused for the next chunk.
+	_, err = io.Copy(ioutil.Discard, resp.Body)
+	return err
+}
+
+// start asks the API to open a new chunked upload.
+func (u *chunkedUpload) start(size int, checksum, boundary string) error {
+	payload := struct {
+		Size        int    `json:"size"`
+		Checksum    string `json:"checksum"`
+		ContentType string `json:"content_type"`
+	}{
+		Size:        size,
+		Checksum:    checksum,
+		ContentType: fmt.Sprintf("multipart/form-data; boundary=%s", boundary),
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := u.client.NewRequest("POST", fmt.Sprintf("%s/uploads", u.url), bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return errChunkedUploadUnsupported
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return decodedAPIError(resp)
+	}
+
+	var started struct {
+		Upload struct {
+			ID        string `json:"id"`
+			URL       string `json:"url"`
+			ChunkSize int    `json:"chunk_size"`
+		} `json:"upload"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&started); err != nil {
+		return fmt.Errorf("failed to parse API response: %s", err)
+	}
+	if started.Upload.URL == "" || started.Upload.ChunkSize <= 0 {
+		return errChunkedUploadUnsupported
+	}
+
+	u.state = &uploadState{
+		ID:        started.Upload.ID,
+		URL:       started.Upload.URL,
+		Checksum:  checksum,
+		Boundary:  boundary,
+		ChunkSize: started.Upload.ChunkSize,
+	}
+	return u.state.save(u.statePath)
+}
+
+func (u *chunkedUpload) send(method, url string, chunk []byte) (*http.Response, error) {
+	req, err := u.client.NewRequest(method, url, bytes.NewReader(chunk))
+	if err != nil {
+		return nil, err
+	}
+	if chunk != nil {
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+	return u.client.Do(req)
+}
@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/exercism/cli/api"
+	"github.com/exercism/cli/config"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubmitResumesChunkedUpload(t *testing.T) {
+	co := newCapturedOutput()
+	co.override()
+	defer co.reset()
+
+	var contentType string
+	var received [][]byte
+	var chunkRequests []string
+	failChunk := 2
+
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	mux.HandleFunc("/solutions/bogus-solution-uuid/uploads", func(w http.ResponseWriter, r *http.Request) {
+		var start struct {
+			ContentType string `json:"content_type"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&start)
+		assert.NoError(t, err)
+		contentType = start.ContentType
+		fmt.Fprintf(w, `{"upload": {"id": "up-1", "url": "%s/uploads/up-1", "chunk_size": 100000}}`, ts.URL)
+	})
+	mux.HandleFunc("/uploads/up-1/chunks/", func(w http.ResponseWriter, r *http.Request) {
+		chunkRequests = append(chunkRequests, r.URL.Path)
+		var n int
+		fmt.Sscanf(strings.TrimPrefix(r.URL.Path, "/uploads/up-1/chunks/"), "%d", &n)
+		if n == failChunk {
+			failChunk = -1
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"error": {"type": "error", "message": "connection reset"}}`)
+			return
+		}
+		b, _ := ioutil.ReadAll(r.Body)
+		for len(received) <= n {
+			received = append(received, nil)
+		}
+		received[n] = b
+	})
+	mux.HandleFunc("/uploads/up-1/complete", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "{}")
+	})
+	mux.HandleFunc("/solutions/bogus-solution-uuid", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("expected a chunked upload")
+	})
+
+	tmpDir, err := ioutil.TempDir("", "submit-chunked")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
+	os.MkdirAll(dir, os.FileMode(0755))
+	writeFakeMetadata(t, dir, "bogus-track", "bogus-exercise")
+
+	var files []string
+	for i := 0; i < 5; i++ {
+		file := filepath.Join(dir, fmt.Sprintf("file-%d.txt", i))
+		err = ioutil.WriteFile(file, bytes.Repeat([]byte{byte('a' + i)}, 60000), os.FileMode(0644))
+		assert.NoError(t, err)
+		files = append(files, file)
+	}
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	cfg := config.Config{
+		Persister:       config.InMemoryPersister{},
+		UserViperConfig: v,
+		StateDir:        filepath.Join(tmpDir, "state"),
+	}
+
+	err = runSubmit(cfg, pflag.NewFlagSet("fake", pflag.PanicOnError), files)
+	if assert.Error(t, err) {
+		assert.Regexp(t, "connection reset", err.Error())
+	}
+	statePath := uploadStatePath(cfg.StateDir, "bogus-solution-uuid")
+	state, err := loadUploadState(statePath)
+	assert.NoError(t, err)
+	if assert.NotNil(t, state) {
+		assert.Equal(t, 2, state.Acknowledged)
+	}
+
+	chunkRequests = nil
+	err = runSubmit(cfg, pflag.NewFlagSet("fake", pflag.PanicOnError), files)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/uploads/up-1/chunks/2", "/uploads/up-1/chunks/3"}, chunkRequests)
+
+	_, err = os.Stat(statePath)
+	assert.True(t, os.IsNotExist(err))
+
+	_, params, err := mime.ParseMediaType(contentType)
+	assert.NoError(t, err)
+	mr := multipart.NewReader(bytes.NewReader(bytes.Join(received, nil)), params["boundary"])
+	var count int
+	for {
+		part, err := mr.NextPart()
+		if err != nil {
+			break
+		}
+		b, _ := ioutil.ReadAll(part)
+		assert.Equal(t, 60000, len(b))
+		count++
+	}
+	assert.Equal(t, 5, count)
+}
+
+func TestSubmitChunkedUploadLargerThanPayloadLimit(t *testing.T) {
+	co := newCapturedOutput()
+	co.override()
+	defer co.reset()
+
+	var received int
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	mux.HandleFunc("/solutions/bogus-solution-uuid/uploads", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"upload": {"id": "up-1", "url": "%s/uploads/up-1", "chunk_size": 500000}}`, ts.URL)
+	})
+	mux.HandleFunc("/uploads/up-1/chunks/", func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		received += len(b)
+	})
+	mux.HandleFunc("/uploads/up-1/complete", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "{}")
+	})
+	mux.HandleFunc("/solutions/bogus-solution-uuid", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("expected a chunked upload")
+	})
+
+	tmpDir, err := ioutil.TempDir("", "submit-chunked-large")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
+	os.MkdirAll(dir, os.FileMode(0755))
+	writeFakeMetadata(t, dir, "bogus-track", "bogus-exercise")
+
+	var files []string
+	for i := 0; i < 25; i++ {
+		file := filepath.Join(dir, fmt.Sprintf("file-%02d.txt", i))
+		err = ioutil.WriteFile(file, make([]byte, 50000), os.FileMode(0644))
+		assert.NoError(t, err)
+		files = append(files, file)
+	}
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	cfg := config.Config{
+		Persister:       config.InMemoryPersister{},
+		UserViperConfig: v,
+		StateDir:        filepath.Join(tmpDir, "state"),
+	}
+
+	// The limit on the size of a submission is of what is sent in one request.
+	err = runSubmit(cfg, pflag.NewFlagSet("fake", pflag.PanicOnError), files)
+	assert.NoError(t, err)
+	assert.True(t, int64(received) > defaultMaxPayloadSize)
+}
+
+func TestSubmitFallsBackWhenChunkedUploadUnsupported(t *testing.T) {
+	co := newCapturedOutput()
+	co.override()
+	defer co.reset()
+
+	var submitted int
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	mux.HandleFunc("/solutions/bogus-solution-uuid/uploads", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/solutions/bogus-solution-uuid", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PATCH", r.Method)
+		err := r.ParseMultipartForm(1 << 20)
+		assert.NoError(t, err)
+		submitted = len(r.MultipartForm.File["files[]"])
+		fmt.Fprint(w, "{}")
+	})
+
+	tmpDir, err := ioutil.TempDir("", "submit-not-chunked")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
+	os.MkdirAll(dir, os.FileMode(0755))
+	writeFakeMetadata(t, dir, "bogus-track", "bogus-exercise")
+
+	var files []string
+	for i := 0; i < 5; i++ {
+		file := filepath.Join(dir, fmt.Sprintf("file-%d.txt", i))
+		err = ioutil.WriteFile(file, bytes.Repeat([]byte("x"), 60000), os.FileMode(0644))
+		assert.NoError(t, err)
+		files = append(files, file)
+	}
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	cfg := config.Config{
+		Persister:       config.InMemoryPersister{},
+		UserViperConfig: v,
+		StateDir:        filepath.Join(tmpDir, "state"),
+	}
+
+	err = runSubmit(cfg, pflag.NewFlagSet("fake", pflag.PanicOnError), files)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, submitted)
+}
+
+func TestChunkedUploadRestartsWhenDiscarded(t *testing.T) {
+	co := newCapturedOutput()
+	co.override()
+	defer co.reset()
+
+	var chunkRequests []string
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	mux.HandleFunc("/solutions/bogus-solution-uuid/uploads", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"upload": {"id": "up-2", "url": "%s/uploads/up-2", "chunk_size": 4}}`, ts.URL)
+	})
+	mux.HandleFunc("/uploads/up-1/chunks/", func(w http.ResponseWriter, r *http.Request) {
+		chunkRequests = append(chunkRequests, r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/uploads/up-2/chunks/", func(w http.ResponseWriter, r *http.Request) {
+		chunkRequests = append(chunkRequests, r.URL.Path)
+	})
+	mux.HandleFunc("/uploads/up-2/complete", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"iteration": {"id": 42}}`)
+	})
+
+	tmpDir, err := ioutil.TempDir("", "chunked-discarded")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	body := []byte("0123456789")
+	state := &uploadState{
+		ID:           "up-1",
+		URL:          ts.URL + "/uploads/up-1",
+		Checksum:     fmt.Sprintf("sha256:%x", sha256.Sum256(body)),
+		Boundary:     "bogus-boundary",
+		ChunkSize:    4,
+		Acknowledged: 1,
+	}
+	statePath := filepath.Join(tmpDir, "upload.json")
+	assert.NoError(t, state.save(statePath))
+
+	client, err := api.NewClient("abc123", ts.URL)
+	assert.NoError(t, err)
+	upload := &chunkedUpload{
+		client:    client,
+		url:       ts.URL + "/solutions/bogus-solution-uuid",
+		statePath: statePath,
+		state:     state,
+	}
+	assert.NoError(t, upload.run(body, "bogus-boundary"))
+	expected := []string{
+		"/uploads/up-1/chunks/1",
+		"/uploads/up-2/chunks/0",
+		"/uploads/up-2/chunks/1",
+		"/uploads/up-2/chunks/2",
+	}
+	assert.Equal(t, expected, chunkRequests)
+	assert.Equal(t, `{"iteration": {"id": 42}}`, string(upload.response))
+
+	_, err = os.Stat(statePath)
+	assert.True(t, os.IsNotExist(err))
+}
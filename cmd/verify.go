@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/exercism/cli/config"
+	"github.com/exercism/cli/workspace"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// verifyCmd checks an exercise for the problems that would keep it from being submitted.
+var verifyCmd = &cobra.Command{
+	Use:   "verify [PATH]",
+	Short: "Check an exercise for problems before submitting it.",
+	Long: `Check an exercise directory for the problems that submit would run into,
+and report them all at once, each with a way to fix it. It works offline,
+unless a file is large enough that the API needs to be asked for its limit.
+
+Pass the path to the exercise, or run the command from within it.
+
+It checks that:
+
+    - the exercise has metadata, and that it can be read
+    - the directory is named after the exercise
+    - the solution is connected to your account
+    - there are solution files, and none of them are missing or empty
+    - none of the files are too large, or look like they contain secrets
+    - no other files are left out of the submission by mistake
+`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.NewConfig()
+
+		v := viper.New()
+		v.AddConfigPath(cfg.Dir)
+		v.SetConfigName("user")
+		v.SetConfigType("json")
+		// Ignore error. If the file doesn't exist, that is fine.
+		_ = v.ReadInConfig()
+		config.BindUserEnv(v)
+		cfg.UserViperConfig = v
+
+		return runVerify(cfg, cmd.Flags(), args)
+	},
+}
+
+// verifyProblem is something that would keep an exercise from being submitted as intended,
+// along with how to fix it.
+type verifyProblem struct {
+	Description string
+	Fix         string
+}
+
+func runVerify(cfg config.Config, flags *pflag.FlagSet, args []string) error {
+	if err := validateUserConfig(cfg.UserViperConfig); err != nil {
+		return err
+	}
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if path, err = workspace.EvalSymlinks(path); err != nil {
+		return err
+	}
+
+	dir, problems, err := verifyExercise(cfg, path)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(Err, "Checked %s\n\n", dir)
+	if len(problems) == 0 {
+		fmt.Fprintf(Out, "%s The exercise is ready to submit.\n", glyphCompleted)
+		return nil
+	}
+	for _, problem := range problems {
+		fmt.Fprintf(Out, "%s %s\n", glyphFailed, problem.Description)
+		if problem.Fix != "" {
+			fmt.Fprintf(Out, "    %s\n", strings.Replace(problem.Fix, "\n", "\n    ", -1))
+		}
+		fmt.Fprintln(Out)
+	}
+	return fmt.Errorf("found %d problem(s) with the exercise in %s", len(problems), dir)
+}
+
+// verifyExercise finds the exercise that the path is in, and checks it for problems.
+// Problems with the metadata stop the checks, since the rest depend on it.
+func verifyExercise(cfg config.Config, path string) (string, []verifyProblem, error) {
+	ws, err := openWorkspace(cfg.UserViperConfig)
+	if err != nil {
+		return "", nil, err
+	}
+	if !workspace.HasPathPrefix(path, ws.Dir) {
+		return path, []verifyProblem{{
+			Description: "The directory isn't in your workspace.",
+			Fix:         fmt.Sprintf("Download the exercise into %s, or change the workspace with '%s configure --workspace'.", ws.Dir, BinaryName),
+		}}, nil
+	}
+	dir, err := ws.ExerciseDir(path)
+	if err != nil {
+		return path, []verifyProblem{{
+			Description: "The exercise doesn't have any metadata, so it can't be told which exercise it is.",
+			Fix:         fmt.Sprintf("Download the exercise again, and move your solution into the new directory:\n\n    %s download --exercise=SLUG --track=TRACK", BinaryName),
+		}}, nil
+	}
+	exercise := workspace.NewExerciseFromDir(dir)
+
+	if hasLegacy, _ := exercise.HasLegacyMetadata(); hasLegacy {
+		if has, _ := exercise.HasMetadata(); !has {
+			return dir, []verifyProblem{{
+				Description: "The exercise has metadata in the legacy format, which submit moves when it runs.",
+				Fix:         fmt.Sprintf("mkdir -p %q && mv %q %q", exercise.MetadataDir(), exercise.LegacyMetadataFilepath(), exercise.MetadataFilepath()),
+			}}, nil
+		}
+	}
+	metadata, err := workspace.NewExerciseMetadata(dir)
+	if err != nil {
+		return dir, []verifyProblem{{
+			Description: fmt.Sprintf("The exercise's metadata can't be read: %s", err),
+			Fix:         fmt.Sprintf("Download the exercise again to replace it:\n\n    %s download --exercise=%s --track=%s", BinaryName, exercise.Slug, exercise.Track),
+		}}, nil
+	}
+	if metadata.Track == "" || metadata.ExerciseSlug == "" || metadata.ID == "" {
+		return dir, []verifyProblem{{
+			Description: "The exercise's metadata is missing the track, the exercise or the ID of the solution.",
+			Fix:         fmt.Sprintf("Download the exercise again to replace it:\n\n    %s download --exercise=%s --track=%s", BinaryName, exercise.Slug, exercise.Track),
+		}}, nil
+	}
+
+	var problems []verifyProblem
+	if metadata.ExerciseSlug != exercise.Slug {
+		problems = append(problems, verifyProblem{
+			Description: fmt.Sprintf("The directory is named '%s', but the metadata is for '%s'.", exercise.Slug, metadata.ExerciseSlug),
+			Fix:         fmt.Sprintf("mv %q %q", dir, filepath.Join(filepath.Dir(dir), metadata.ExerciseSlug)),
+		})
+	}
+	if !metadata.IsRequester {
+		problems = append(problems, verifyProblem{
+			Description: "The solution isn't connected to your account.",
+			Fix:         fmt.Sprintf("Download the exercise again:\n\n    %s download --exercise=%s --track=%s", BinaryName, metadata.ExerciseSlug, metadata.Track),
+		})
+	}
+
+	fileProblems, err := verifySolutionFiles(cfg.UserViperConfig, cfg.StateDir, dir, metadata.Track)
+	if err != nil {
+		return dir, nil, err
+	}
+	return dir, append(problems, fileProblems...), nil
+}
+
+// verifySolutionFiles checks the files that submit would send, and the ones it would leave out.
+func verifySolutionFiles(usrCfg *viper.Viper, stateDir, dir, track string) ([]verifyProblem, error) {
+	ignored, err := ignorePatterns(usrCfg, track, dir)
+	if err != nil {
+		return nil, err
+	}
+	all, err := exerciseFiles(dir, ignored)
+	if err != nil {
+		return nil, err
+	}
+	exerciseConfig, err := workspace.NewExerciseConfig(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return []verifyProblem{{
+			Description: fmt.Sprintf("The exercise's configuration in .exercism/config.json can't be read: %s", err),
+			Fix:         "Download the exercise again to replace it.",
+		}}, nil
+	}
+
+	var problems []verifyProblem
+	var solution, leftOut []string
+	if exerciseConfig != nil && len(exerciseConfig.Files.Solution) > 0 {
+		for _, file := range exerciseConfig.Files.Solution {
+			if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(file))); os.IsNotExist(err) {
+				problems = append(problems, verifyProblem{
+					Description: fmt.Sprintf("The solution file '%s' listed in .exercism/config.json is missing.", file),
+					Fix:         "Restore the file, or submit the files your solution is in by name.",
+				})
+				continue
+			}
+			if pattern := ignored.Match(file); pattern != "" {
+				problems = append(problems, verifyProblem{
+					Description: fmt.Sprintf("The solution file '%s' matches the ignore pattern '%s', so it would be skipped.", file, pattern),
+					Fix:         fmt.Sprintf("Change the patterns with ignore.%s in your user config, or in a .exercismignore file.", track),
+				})
+				continue
+			}
+			solution = append(solution, file)
+		}
+		for _, file := range all {
+			if !exerciseConfig.IsSolutionFile(file) && !exerciseConfig.IsSupportFile(file) && !workspace.IsTestOrToolingFile(file) {
+				leftOut = append(leftOut, file)
+			}
+		}
+	} else {
+		for _, file := range all {
+			if workspace.IsTestOrToolingFile(file) || (exerciseConfig != nil && exerciseConfig.IsSupportFile(file)) {
+				continue
+			}
+			solution = append(solution, file)
+		}
+	}
+	if len(solution) == 0 && len(problems) == 0 {
+		problems = append(problems, verifyProblem{
+			Description: "There are no solution files to submit.",
+			Fix:         fmt.Sprintf("Write your solution, or submit the files it is in by name:\n\n    %s submit FILENAME", BinaryName),
+		})
+	}
+	if len(leftOut) > 0 {
+		includes := make([]string, len(leftOut))
+		for i, file := range leftOut {
+			includes[i] = "--include=" + file
+		}
+		problems = append(problems, verifyProblem{
+			Description: fmt.Sprintf("These files aren't part of the solution, so they wouldn't be submitted: %s", strings.Join(leftOut, ", ")),
+			Fix:         fmt.Sprintf("If the solution needs them, include them:\n\n    %s submit %s", BinaryName, strings.Join(includes, " ")),
+		})
+	}
+
+	var max int64
+	for _, file := range solution {
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if info.Size() == 0 {
+			problems = append(problems, verifyProblem{
+				Description: fmt.Sprintf("The file '%s' is empty, so it would be skipped.", file),
+				Fix:         "Write your solution in it, or remove it.",
+			})
+			continue
+		}
+		if info.Size() >= defaultMaxFileSize {
+			if max == 0 {
+				max = maxFileSize(usrCfg, stateDir)
+			}
+			if info.Size() >= max {
+				problems = append(problems, verifyProblem{
+					Description: fmt.Sprintf("The file '%s' is %s, which is larger than the max allowed file size of %s.", file, humanSize(info.Size()), humanSize(max)),
+					Fix:         "Reduce the size of the file.",
+				})
+				continue
+			}
+		}
+		if workspace.IsEnvFile(file) {
+			problems = append(problems, verifyProblem{
+				Description: fmt.Sprintf("The file '%s' is an environment file, which usually holds secrets.", file),
+				Fix:         "Remove it, or if it has no secrets, submit with --allow-secrets.",
+			})
+			continue
+		}
+		contents, err := ioutil.ReadFile(workspace.LongPath(path))
+		if err != nil {
+			return nil, err
+		}
+		for _, secret := range workspace.FindSecrets(contents) {
+			problems = append(problems, verifyProblem{
+				Description: fmt.Sprintf("%s:%d looks like it contains a %s.", file, secret.Line, secret.Kind),
+				Fix:         "Remove the secret, or if it isn't one, submit with --allow-secrets.",
+			})
+		}
+	}
+	return problems, nil
+}
+
+func init() {
+	RootCmd.AddCommand(verifyCmd)
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/exercism/cli/config"
+	"github.com/exercism/cli/workspace"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// verifyDownloadCmd checks the files of a downloaded exercise against the ones on the website.
+var verifyDownloadCmd = &cobra.Command{
+	Use:   "verify-download [PATH]",
+	Short: "Check a downloaded exercise's files against the website's.",
+	Long: `Check the files of an exercise you've downloaded against the ones on the
+website, to find the files that are missing or that no longer match.
+
+Every file is fetched again, and checked against the digest the server
+sends with it, if it sends one. Files that arrive damaged are fetched again.
+
+Pass the path to the exercise, or run the command from within it.
+`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.NewConfig()
+
+		v := viper.New()
+		v.AddConfigPath(cfg.Dir)
+		v.SetConfigName("user")
+		v.SetConfigType("json")
+		// Ignore error. If the file doesn't exist, that is fine.
+		_ = v.ReadInConfig()
+		config.BindUserEnv(v)
+		cfg.UserViperConfig = v
+
+		return runVerifyDownload(cfg, cmd.Flags(), args)
+	},
+}
+
+func runVerifyDownload(cfg config.Config, flags *pflag.FlagSet, args []string) error {
+	usrCfg := cfg.UserViperConfig
+	if err := validateUserConfig(usrCfg); err != nil {
+		return err
+	}
+	dir, metadata, err := exerciseAt(usrCfg, args)
+	if err != nil {
+		return err
+	}
+	d, err := newDownloadForExercise(cfg, metadata)
+	if err != nil {
+		return err
+	}
+	// Nothing is written, so there's nothing to resume.
+	d.stateDir = ""
+	err = withRetry(cfg, func() error {
+		return d.requestPayload()
+	})
+	if err != nil {
+		return err
+	}
+	var files []downloadedFile
+	err = withRetry(cfg, func() (err error) {
+		files, err = d.fetchFiles()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	d.convertLineEndings(files)
+
+	checksums, err := workspace.NewChecksums(dir)
+	if err != nil {
+		checksums = workspace.Checksums{}
+	}
+	fmt.Fprintf(Err, "Checked %s\n\n", dir)
+	var matching, missing int
+	for _, file := range files {
+		local, err := ioutil.ReadFile(workspace.LongPath(filepath.Join(dir, file.path)))
+		switch {
+		case os.IsNotExist(err):
+			missing++
+			fmt.Fprintf(Out, "%s %s is missing\n", glyphFailed, file.path)
+		case err != nil:
+			return err
+		case bytes.Equal(local, file.contents):
+			matching++
+		case !checksums.IsModified(file.path, local):
+			fmt.Fprintf(Out, "%s %s has been changed on the website since it was downloaded\n", glyphAvailable, file.path)
+		default:
+			fmt.Fprintf(Out, "%s %s has been changed since it was downloaded\n", glyphInProgress, file.path)
+		}
+	}
+	fmt.Fprintf(Out, "%s %d of %d file(s) match the website's\n", glyphCompleted, matching, len(files))
+
+	if missing > 0 {
+		fmt.Fprintf(Err, "\nGet the missing files back with '%s update %s'.\n", BinaryName, dir)
+		return fmt.Errorf("%d file(s) of the exercise in %s are missing", missing, dir)
+	}
+	if matching < len(files) {
+		fmt.Fprintf(Err, "\nTake the website's changes with '%s update %s'.\n", BinaryName, dir)
+	}
+	return nil
+}
+
+func init() {
+	RootCmd.AddCommand(verifyDownloadCmd)
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/exercism/cli/config"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyDownload(t *testing.T) {
+	co := newCapturedOutput()
+	stdout := &bytes.Buffer{}
+	co.newOut = stdout
+	co.override()
+	defer co.reset()
+
+	tmpDir, err := ioutil.TempDir("", "verify-download")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	ts := fakeDownloadServer("true", "")
+	defer ts.Close()
+
+	v := viper.New()
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	v.Set("token", "abc123")
+	cfg := config.Config{
+		UserViperConfig: v,
+	}
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupDownloadFlags(flags)
+	flags.Set("exercise", "bogus-exercise")
+	assert.NoError(t, runDownload(cfg, flags, []string{}))
+
+	dir := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
+	stdout.Reset()
+	assert.NoError(t, runVerifyDownload(cfg, pflag.NewFlagSet("fake", pflag.PanicOnError), []string{dir}))
+	assert.Regexp(t, "2 of 2 file\\(s\\) match", stdout.String())
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "file-1.txt"), []byte("my file 1"), os.FileMode(0644)))
+	assert.NoError(t, os.Remove(filepath.Join(dir, "subdir", "file-2.txt")))
+	stdout.Reset()
+	err = runVerifyDownload(cfg, pflag.NewFlagSet("fake", pflag.PanicOnError), []string{dir})
+	if assert.Error(t, err) {
+		assert.Regexp(t, "1 file\\(s\\) of the exercise .* are missing", err.Error())
+	}
+	out := stdout.String()
+	assert.Regexp(t, "file-1.txt has been changed since it was downloaded", out)
+	assert.Regexp(t, "file-2.txt is missing", out)
+	assert.Regexp(t, "0 of 2 file\\(s\\) match", out)
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/exercism/cli/workspace"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	co := newCapturedOutput()
+	co.newOut = &bytes.Buffer{}
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	tmpDir, err := ioutil.TempDir("", "verify")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+	dir := filepath.Join(tmpDir, "go", "leap")
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, ".exercism"), os.FileMode(0755)))
+	writeFakeMetadata(t, dir, "go", "leap")
+	exerciseConfig := `{"files": {"solution": ["leap.go"], "test": ["leap_test.go"]}}`
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, ".exercism", "config.json"), []byte(exerciseConfig), os.FileMode(0644)))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "leap.go"), []byte("package leap"), os.FileMode(0644)))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "leap_test.go"), []byte("package leap"), os.FileMode(0644)))
+
+	cfg := fakeUserConfig("http://example.com")
+	cfg.UserViperConfig.Set("workspace", tmpDir)
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+
+	assert.NoError(t, runVerify(cfg, flags, []string{dir}))
+	assert.Regexp(t, "ready to submit", co.newOut.(*bytes.Buffer).String())
+
+	// Every problem is reported at once.
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "leap.go"), []byte{}, os.FileMode(0644)))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "helper.go"), []byte("package leap"), os.FileMode(0644)))
+	metadata := &workspace.ExerciseMetadata{ID: "bogus-solution-uuid", Track: "go", ExerciseSlug: "leap", IsRequester: false}
+	assert.NoError(t, metadata.Write(dir))
+
+	co.newOut.(*bytes.Buffer).Reset()
+	err = runVerify(cfg, flags, []string{dir})
+	if assert.Error(t, err) {
+		assert.Regexp(t, "found 3 problem", err.Error())
+	}
+	out := co.newOut.(*bytes.Buffer).String()
+	assert.Regexp(t, "isn't connected to your account", out)
+	assert.Regexp(t, "'leap.go' is empty", out)
+	assert.Regexp(t, "wouldn't be submitted: helper.go", out)
+	assert.Regexp(t, "--include=helper.go", out)
+}
+
+func TestVerifyMetadataProblems(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "verify-metadata")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+	cfg := fakeUserConfig("http://example.com")
+	cfg.UserViperConfig.Set("workspace", tmpDir)
+
+	dir := filepath.Join(tmpDir, "go", "leap")
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, ".exercism"), os.FileMode(0755)))
+	_, problems, err := verifyExercise(cfg, dir)
+	assert.NoError(t, err)
+	if assert.Len(t, problems, 1) {
+		assert.Regexp(t, "doesn't have any metadata", problems[0].Description)
+	}
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, ".exercism", "metadata.json"), []byte("{"), os.FileMode(0644)))
+	_, problems, err = verifyExercise(cfg, dir)
+	assert.NoError(t, err)
+	if assert.Len(t, problems, 1) {
+		assert.Regexp(t, "metadata can't be read", problems[0].Description)
+		assert.Regexp(t, "download --exercise=leap --track=go", problems[0].Fix)
+	}
+
+	writeFakeMetadata(t, dir, "go", "leap-year")
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "leap.go"), []byte("package leap"), os.FileMode(0644)))
+	_, problems, err = verifyExercise(cfg, dir)
+	assert.NoError(t, err)
+	if assert.Len(t, problems, 1) {
+		assert.Regexp(t, "named 'leap', but the metadata is for 'leap-year'", problems[0].Description)
+	}
+}
@@ -2,8 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"github.com/exercism/cli/config"
+	"github.com/exercism/cli/workspace"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -35,12 +37,40 @@ nothing will happen.
 		v.SetConfigType("json")
 		// Ignore error. If the file doesn't exist, that is fine.
 		_ = v.ReadInConfig()
+		config.BindUserEnv(v)
 
 		fmt.Fprintf(Out, "%s\n", v.GetString("workspace"))
 		return nil
 	},
 }
 
+// openWorkspace opens the workspace in the user config, laid out as configured.
+func openWorkspace(usrCfg *viper.Viper) (workspace.Workspace, error) {
+	conceptDir, err := conceptDir(usrCfg)
+	if err != nil {
+		return workspace.Workspace{}, err
+	}
+	ws, err := workspace.New(usrCfg.GetString("workspace"))
+	if err != nil {
+		return workspace.Workspace{}, err
+	}
+	ws.ConceptDir = conceptDir
+	return ws, nil
+}
+
+// conceptDir is the directory in the workspace that concept exercises are downloaded to,
+// set with concept_dir in the user config. By default they go with the practice exercises.
+func conceptDir(usrCfg *viper.Viper) (string, error) {
+	dir := usrCfg.GetString("concept_dir")
+	if dir == "" {
+		return "", nil
+	}
+	if filepath.Base(dir) != dir || dir == "." || dir == ".." || dir == "users" || dir == "teams" {
+		return "", fmt.Errorf("concept_dir in your config has to be the name of a directory in the workspace, such as concepts, not '%s'", dir)
+	}
+	return dir, nil
+}
+
 func init() {
 	RootCmd.AddCommand(workspaceCmd)
 }
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/exercism/cli/config"
+	"github.com/exercism/cli/workspace"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// workspaceScanCmd looks for damaged exercises in the workspace, and offers to repair them.
+var workspaceScanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Look for damaged exercises in your workspace, and repair them.",
+	Long: `Walk the workspace looking for exercises whose metadata is missing or can't
+be read, that have legacy metadata left behind, or that have files that can't
+be read, and offer to repair them.
+
+Missing and damaged metadata is fetched again from the API, taking the track
+and the exercise from where the directory is. Damaged metadata is kept next to
+the new one, with .corrupt added to its name. Legacy metadata left behind is
+removed, and files that can't be read are made readable.
+
+Pass --yes to repair them without being asked.
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.NewConfig()
+
+		v := viper.New()
+		v.AddConfigPath(cfg.Dir)
+		v.SetConfigName("user")
+		v.SetConfigType("json")
+		// Ignore error. If the file doesn't exist, that is fine.
+		_ = v.ReadInConfig()
+		config.BindUserEnv(v)
+		cfg.UserViperConfig = v
+
+		return runWorkspaceScan(cfg, cmd.Flags())
+	},
+}
+
+func runWorkspaceScan(cfg config.Config, flags *pflag.FlagSet) error {
+	if err := validateUserConfig(cfg.UserViperConfig); err != nil {
+		return err
+	}
+	yes, err := flags.GetBool("yes")
+	if err != nil {
+		return err
+	}
+	ws, err := openWorkspace(cfg.UserViperConfig)
+	if err != nil {
+		return err
+	}
+	issues, err := ws.Scan()
+	if err != nil {
+		return err
+	}
+	if len(issues) == 0 {
+		fmt.Fprintf(Err, "No damaged exercises found in %s\n", ws.Dir)
+		return nil
+	}
+
+	for _, issue := range issues {
+		fmt.Fprintf(Out, "%s %s\n", glyphFailed, describeScanIssue(ws, issue))
+		fmt.Fprintf(Out, "    %s\n", issue.Path)
+	}
+	if !yes && !confirm(fmt.Sprintf("\nRepair %d problem(s)? [Y/n] ", len(issues))) {
+		fmt.Fprintf(Err, "\nTo repair them later, run\n\n    %s workspace scan --yes\n\n", BinaryName)
+		return fmt.Errorf("found %d problem(s) in the workspace", len(issues))
+	}
+
+	fmt.Fprintln(Err)
+	failed := 0
+	for _, issue := range issues {
+		if err := repairScanIssue(cfg.UserViperConfig, ws, issue); err != nil {
+			failed++
+			fmt.Fprintf(Err, "%s Unable to repair %s: %s\n", glyphFailed, issue.Path, err)
+			continue
+		}
+		fmt.Fprintf(Err, "%s Repaired %s\n", glyphCompleted, issue.Path)
+	}
+	if failed > 0 {
+		return fmt.Errorf("unable to repair %d of the %d problem(s) in the workspace", failed, len(issues))
+	}
+	return nil
+}
+
+// describeScanIssue says what is wrong, and with which exercise.
+func describeScanIssue(ws workspace.Workspace, issue workspace.ScanIssue) string {
+	exercise := issue.Dir
+	if rel, err := filepath.Rel(ws.Dir, issue.Dir); err == nil {
+		exercise = filepath.ToSlash(rel)
+	}
+	switch issue.Kind {
+	case workspace.ScanMissingMetadata:
+		return fmt.Sprintf("%s: the metadata is missing", exercise)
+	case workspace.ScanCorruptMetadata:
+		return fmt.Sprintf("%s: the metadata can't be read (%s)", exercise, issue.Err)
+	case workspace.ScanOrphanedLegacyMetadata:
+		return fmt.Sprintf("%s: legacy metadata was left behind", exercise)
+	}
+	return fmt.Sprintf("%s: a file can't be read (%s)", exercise, issue.Err)
+}
+
+// repairScanIssue fixes the problem.
+func repairScanIssue(usrCfg *viper.Viper, ws workspace.Workspace, issue workspace.ScanIssue) error {
+	switch issue.Kind {
+	case workspace.ScanMissingMetadata, workspace.ScanCorruptMetadata:
+		metadata, err := refetchMetadata(usrCfg, ws, issue.Dir)
+		if err != nil {
+			return err
+		}
+		if issue.Kind == workspace.ScanCorruptMetadata {
+			if err := os.Rename(workspace.LongPath(issue.Path), workspace.LongPath(issue.Path+".corrupt")); err != nil {
+				return err
+			}
+		}
+		return metadata.Write(issue.Dir)
+	case workspace.ScanOrphanedLegacyMetadata:
+		_, err := workspace.NewExerciseFromDir(issue.Dir).MigrateLegacyMetadataFile()
+		return err
+	}
+	info, err := os.Lstat(workspace.LongPath(issue.Path))
+	if err != nil {
+		return err
+	}
+	mode := info.Mode().Perm() | 0400
+	if info.IsDir() {
+		mode |= 0100
+	}
+	return os.Chmod(workspace.LongPath(issue.Path), mode)
+}
+
+// refetchMetadata asks the API for the metadata of the exercise in the directory.
+// The exercise and the track are taken from where the directory is in the workspace,
+// along with the team for exercises in teams/<team>.
+func refetchMetadata(usrCfg *viper.Viper, ws workspace.Workspace, dir string) (*workspace.ExerciseMetadata, error) {
+	rel, err := filepath.Rel(ws.Dir, dir)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("unable to tell the track and the exercise from %s", rel)
+	}
+	d := &download{
+		slug:       parts[len(parts)-1],
+		track:      parts[len(parts)-2],
+		token:      usrCfg.GetString("token"),
+		apibaseurl: usrCfg.GetString("apibaseurl"),
+		workspace:  ws.Dir,
+	}
+	if len(parts) == 4 && parts[0] == "teams" {
+		d.team = parts[1]
+	}
+	if err := d.requestPayload(); err != nil {
+		return nil, err
+	}
+	metadata := d.payload.metadata()
+	if metadata.ExerciseSlug == "" || metadata.ID == "" {
+		return nil, fmt.Errorf("the API doesn't know %s in the %s track", d.slug, d.track)
+	}
+	return &metadata, nil
+}
+
+func setupWorkspaceScanFlags(flags *pflag.FlagSet) {
+	flags.BoolP("yes", "y", false, "repair the problems without being asked")
+}
+
+func init() {
+	workspaceCmd.AddCommand(workspaceScanCmd)
+	setupWorkspaceScanFlags(workspaceScanCmd.Flags())
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/exercism/cli/config"
+	"github.com/exercism/cli/workspace"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkspaceScan(t *testing.T) {
+	co := newCapturedOutput()
+	co.newOut = &bytes.Buffer{}
+	co.newErr = &bytes.Buffer{}
+	co.override()
+	defer co.reset()
+
+	ts := fakeDownloadServer("true", "")
+	defer ts.Close()
+
+	tmpDir, err := ioutil.TempDir("", "workspace-scan")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	missing := filepath.Join(tmpDir, "bogus-track", "bogus-exercise")
+	assert.NoError(t, os.MkdirAll(missing, os.FileMode(0755)))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(missing, "HELP.md"), []byte("# Help"), os.FileMode(0644)))
+
+	corrupt := filepath.Join(tmpDir, "teams", "bogus-team", "bogus-track", "bogus-exercise")
+	assert.NoError(t, os.MkdirAll(filepath.Join(corrupt, ".exercism"), os.FileMode(0755)))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(corrupt, ".exercism", "metadata.json"), []byte("{"), os.FileMode(0644)))
+
+	v := viper.New()
+	v.Set("token", "abc123")
+	v.Set("workspace", tmpDir)
+	v.Set("apibaseurl", ts.URL)
+	cfg := config.Config{UserViperConfig: v}
+	flags := pflag.NewFlagSet("fake", pflag.PanicOnError)
+	setupWorkspaceScanFlags(flags)
+
+	// Nothing is repaired without an answer.
+	oldIn := In
+	defer func() { In = oldIn }()
+	In = &bytes.Buffer{}
+	err = runWorkspaceScan(cfg, flags)
+	if assert.Error(t, err) {
+		assert.Regexp(t, "found 2 problem", err.Error())
+	}
+	out := co.newOut.(*bytes.Buffer).String()
+	assert.Regexp(t, "bogus-track/bogus-exercise: the metadata is missing", out)
+	assert.Regexp(t, "teams/bogus-team/bogus-track/bogus-exercise: the metadata can't be read", out)
+
+	flags.Set("yes", "true")
+	assert.NoError(t, runWorkspaceScan(cfg, flags))
+
+	metadata, err := workspace.NewExerciseMetadata(missing)
+	assert.NoError(t, err)
+	assert.Equal(t, "bogus-id", metadata.ID)
+	metadata, err = workspace.NewExerciseMetadata(corrupt)
+	assert.NoError(t, err)
+	assert.Equal(t, "bogus-team", metadata.Team)
+	_, err = os.Stat(filepath.Join(corrupt, ".exercism", "metadata.json.corrupt"))
+	assert.NoError(t, err)
+
+	co.newErr.(*bytes.Buffer).Reset()
+	assert.NoError(t, runWorkspaceScan(cfg, flags))
+	assert.Regexp(t, "No damaged exercises", co.newErr.(*bytes.Buffer).String())
+}
@@ -16,6 +16,10 @@ var (
 
 	// DefaultDirName is the default name used for config and workspace directories.
 	DefaultDirName string
+
+	// DirOverride replaces the config directory for this invocation, e.g. to keep
+	// a test or a second account away from the real config. It's set by --config-dir.
+	DirOverride string
 )
 
 // Config lets us inject configuration options into commands.
@@ -23,6 +27,7 @@ type Config struct {
 	OS              string
 	Home            string
 	Dir             string
+	StateDir        string
 	DefaultBaseURL  string
 	DefaultDirName  string
 	UserViperConfig *viper.Viper
@@ -37,6 +42,7 @@ func NewConfig() Config {
 	return Config{
 		OS:             runtime.GOOS,
 		Dir:            Dir(),
+		StateDir:       StateDir(),
 		Home:           home,
 		DefaultBaseURL: defaultBaseURL,
 		DefaultDirName: DefaultDirName,
@@ -52,6 +58,9 @@ func SetDefaultDirName(binaryName string) {
 // Dir is the configured config home directory.
 // All the cli-related config files live in this directory.
 func Dir() string {
+	if DirOverride != "" {
+		return DirOverride
+	}
 	var dir string
 	if runtime.GOOS == "windows" {
 		dir = os.Getenv("APPDATA")
@@ -76,6 +85,36 @@ func Dir() string {
 	return dir
 }
 
+// StateDir is the directory where the CLI keeps data that needs to
+// survive between invocations, but which isn't configuration.
+func StateDir() string {
+	// An alternate config directory gets its own state, unless the state is placed explicitly.
+	if DirOverride != "" && os.Getenv("EXERCISM_STATE_HOME") == "" {
+		return filepath.Join(DirOverride, "state")
+	}
+	var dir string
+	if runtime.GOOS == "windows" {
+		dir = os.Getenv("LOCALAPPDATA")
+		if dir != "" {
+			return filepath.Join(dir, DefaultDirName)
+		}
+	} else {
+		dir = os.Getenv("EXERCISM_STATE_HOME")
+		if dir != "" {
+			return dir
+		}
+		dir = os.Getenv("XDG_STATE_HOME")
+		if dir == "" && os.Getenv("HOME") != "" {
+			dir = filepath.Join(os.Getenv("HOME"), ".local", "state")
+		}
+		if dir != "" {
+			return filepath.Join(dir, DefaultDirName)
+		}
+	}
+	// If all else fails, keep it next to the config.
+	return filepath.Join(Dir(), "state")
+}
+
 func userHome() string {
 	var dir string
 	if runtime.GOOS == "windows" {
@@ -98,6 +137,28 @@ func userHome() string {
 	return dir
 }
 
+// userEnv are the environment variables that take precedence over the user config,
+// by the key they stand in for.
+var userEnv = map[string]string{
+	"token":      "EXERCISM_TOKEN",
+	"workspace":  "EXERCISM_WORKSPACE",
+	"apibaseurl": "EXERCISM_API_BASE_URL",
+}
+
+// BindUserEnv lets the environment override the token, workspace and API base URL of the user config.
+// It isn't meant for a config that is about to be saved, which would keep the values from the environment.
+func BindUserEnv(v *viper.Viper) {
+	for key, name := range userEnv {
+		// It only fails without a key.
+		_ = v.BindEnv(key, name)
+	}
+}
+
+// UserEnv is the environment variable that overrides the key of the user config, if there is one.
+func UserEnv(key string) string {
+	return userEnv[key]
+}
+
 // DefaultWorkspaceDir provides a sensible default for the Exercism workspace.
 // The default is different depending on the platform, in order to best match
 // the conventions for that platform.
@@ -4,6 +4,7 @@ package config
 
 import (
 	"fmt"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -28,3 +29,45 @@ func TestDefaultWorkspaceDir(t *testing.T) {
 		assert.Equal(t, tc.expected, DefaultWorkspaceDir(tc.cfg), fmt.Sprintf("Operating System: %s", tc.cfg.OS))
 	}
 }
+
+func TestStateDir(t *testing.T) {
+	home, xdg, exercism := os.Getenv("HOME"), os.Getenv("XDG_STATE_HOME"), os.Getenv("EXERCISM_STATE_HOME")
+	defer func() {
+		os.Setenv("HOME", home)
+		os.Setenv("XDG_STATE_HOME", xdg)
+		os.Setenv("EXERCISM_STATE_HOME", exercism)
+	}()
+	defaultDirName := DefaultDirName
+	DefaultDirName = "exercism"
+	defer func() { DefaultDirName = defaultDirName }()
+
+	os.Setenv("HOME", "/home/alice")
+	os.Setenv("XDG_STATE_HOME", "")
+	os.Setenv("EXERCISM_STATE_HOME", "")
+	assert.Equal(t, "/home/alice/.local/state/exercism", StateDir())
+
+	os.Setenv("XDG_STATE_HOME", "/xdg/state")
+	assert.Equal(t, "/xdg/state/exercism", StateDir())
+
+	os.Setenv("EXERCISM_STATE_HOME", "/exercism/state")
+	assert.Equal(t, "/exercism/state", StateDir())
+}
+
+func TestDirOverride(t *testing.T) {
+	exercism, state := os.Getenv("EXERCISM_CONFIG_HOME"), os.Getenv("EXERCISM_STATE_HOME")
+	defer func() {
+		os.Setenv("EXERCISM_CONFIG_HOME", exercism)
+		os.Setenv("EXERCISM_STATE_HOME", state)
+		DirOverride = ""
+	}()
+
+	os.Setenv("EXERCISM_CONFIG_HOME", "/exercism/config")
+	os.Setenv("EXERCISM_STATE_HOME", "")
+	DirOverride = "/tmp/sandbox"
+	assert.Equal(t, "/tmp/sandbox", Dir())
+	assert.Equal(t, "/tmp/sandbox/state", StateDir())
+	assert.Equal(t, "/tmp/sandbox", NewConfig().Dir)
+
+	os.Setenv("EXERCISM_STATE_HOME", "/exercism/state")
+	assert.Equal(t, "/exercism/state", StateDir())
+}
@@ -0,0 +1,146 @@
+package editor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrNotFound signals that no editor could be found.
+var ErrNotFound = errors.New("no editor found: set $VISUAL or $EDITOR, or configure editor.command")
+
+// Placeholders that can be used in an editor command template.
+const (
+	PathPlaceholder = "{path}"
+	LinePlaceholder = "{line}"
+)
+
+// Editor describes how to invoke a particular editor.
+type Editor struct {
+	Name string
+	// Bin is the name of the executable.
+	Bin string
+	// Template is the invocation used to open a file at a given line.
+	Template string
+}
+
+// Known are the editors we know how to invoke, in order of preference
+// when none has been configured.
+var Known = []Editor{
+	{Name: "Visual Studio Code", Bin: "code", Template: "code -g {path}:{line}"},
+	{Name: "VSCodium", Bin: "codium", Template: "codium -g {path}:{line}"},
+	{Name: "Sublime Text", Bin: "subl", Template: "subl {path}:{line}"},
+	{Name: "IntelliJ IDEA", Bin: "idea", Template: "idea --line {line} {path}"},
+	{Name: "GoLand", Bin: "goland", Template: "goland --line {line} {path}"},
+	{Name: "PyCharm", Bin: "pycharm", Template: "pycharm --line {line} {path}"},
+	{Name: "WebStorm", Bin: "webstorm", Template: "webstorm --line {line} {path}"},
+	{Name: "RubyMine", Bin: "rubymine", Template: "rubymine --line {line} {path}"},
+	{Name: "CLion", Bin: "clion", Template: "clion --line {line} {path}"},
+	{Name: "Rider", Bin: "rider", Template: "rider --line {line} {path}"},
+	{Name: "Neovim", Bin: "nvim", Template: "nvim +{line} {path}"},
+	{Name: "Vim", Bin: "vim", Template: "vim +{line} {path}"},
+	{Name: "Emacs", Bin: "emacs", Template: "emacs +{line} {path}"},
+	{Name: "nano", Bin: "nano", Template: "nano +{line} {path}"},
+}
+
+// lookPath is swapped out in tests.
+var lookPath = exec.LookPath
+
+// Resolve determines the command template to open files with.
+// An explicitly configured template wins, followed by $VISUAL and $EDITOR,
+// and finally the first known editor that is installed.
+func Resolve(configured string) (string, error) {
+	if strings.TrimSpace(configured) != "" {
+		return configured, nil
+	}
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if cmd := strings.TrimSpace(os.Getenv(name)); cmd != "" {
+			return FromCommand(cmd), nil
+		}
+	}
+	for _, e := range Known {
+		if _, err := lookPath(e.Bin); err == nil {
+			return e.Template, nil
+		}
+	}
+	return "", ErrNotFound
+}
+
+// FromCommand turns a plain command such as the value of $EDITOR into a template.
+// If the command is a known editor, it is taught how to jump to a line.
+func FromCommand(cmd string) string {
+	fields := strings.Fields(cmd)
+	if len(fields) == 0 {
+		return ""
+	}
+	bin := strings.TrimSuffix(filepath.Base(fields[0]), filepath.Ext(fields[0]))
+	for _, e := range Known {
+		if e.Bin != bin {
+			continue
+		}
+		// Keep the user's own executable and options, e.g. "code --wait".
+		args := strings.Fields(e.Template)[1:]
+		return strings.Join(append(fields, args...), " ")
+	}
+	return fmt.Sprintf("%s %s", cmd, PathPlaceholder)
+}
+
+// Args builds the command line for opening the path at the given line.
+// When the path is a directory, or no line is given, line-related
+// arguments are dropped.
+func Args(template, path string, line int) []string {
+	fields := strings.Fields(template)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	var hasPath bool
+	args := make([]string, 0, len(fields)+1)
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		if strings.Contains(field, PathPlaceholder) {
+			hasPath = true
+		}
+		if strings.Contains(field, LinePlaceholder) && line <= 0 {
+			// Drop "+{line}" entirely, and ":{line}" from "{path}:{line}".
+			field = strings.Replace(field, ":"+LinePlaceholder, "", -1)
+			if strings.Contains(field, LinePlaceholder) {
+				// A flag such as "--line" preceding the placeholder goes too.
+				if len(args) > 0 && strings.HasPrefix(args[len(args)-1], "-") && field == LinePlaceholder {
+					args = args[:len(args)-1]
+				}
+				continue
+			}
+		}
+		field = strings.Replace(field, PathPlaceholder, path, -1)
+		field = strings.Replace(field, LinePlaceholder, strconv.Itoa(line), -1)
+		args = append(args, field)
+	}
+	if !hasPath {
+		args = append(args, path)
+	}
+	return args
+}
+
+// Open opens the path in the editor described by the template,
+// at the given line if it is greater than zero.
+func Open(template, path string, line int) error {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		line = 0
+	}
+	args := Args(template, path, line)
+	if len(args) == 0 {
+		return ErrNotFound
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	// Terminal editors need to be attached to the terminal.
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
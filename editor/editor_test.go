@@ -0,0 +1,78 @@
+package editor
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArgs(t *testing.T) {
+	testCases := []struct {
+		desc, template string
+		line           int
+		expected       []string
+	}{
+		{"vs code with line", "code -g {path}:{line}", 12, []string{"code", "-g", "main.go:12"}},
+		{"vs code without line", "code -g {path}:{line}", 0, []string{"code", "-g", "main.go"}},
+		{"jetbrains with line", "idea --line {line} {path}", 3, []string{"idea", "--line", "3", "main.go"}},
+		{"jetbrains without line", "idea --line {line} {path}", 0, []string{"idea", "main.go"}},
+		{"vim without line", "vim +{line} {path}", 0, []string{"vim", "main.go"}},
+		{"no placeholders", "mate -w", 7, []string{"mate", "-w", "main.go"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Args(tc.template, "main.go", tc.line))
+		})
+	}
+}
+
+func TestFromCommand(t *testing.T) {
+	assert.Equal(t, "code --wait -g {path}:{line}", FromCommand("code --wait"))
+	assert.Equal(t, "/usr/local/bin/nvim +{line} {path}", FromCommand("/usr/local/bin/nvim"))
+	assert.Equal(t, "ed {path}", FromCommand("ed"))
+}
+
+func TestResolve(t *testing.T) {
+	visual, editor, originalLookPath := os.Getenv("VISUAL"), os.Getenv("EDITOR"), lookPath
+	defer func() {
+		os.Setenv("VISUAL", visual)
+		os.Setenv("EDITOR", editor)
+		lookPath = originalLookPath
+	}()
+	os.Setenv("VISUAL", "")
+	os.Setenv("EDITOR", "")
+
+	lookPath = func(bin string) (string, error) {
+		if bin == "subl" || bin == "vim" {
+			return "/usr/bin/" + bin, nil
+		}
+		return "", errors.New("not found")
+	}
+
+	template, err := Resolve("")
+	assert.NoError(t, err)
+	assert.Equal(t, "subl {path}:{line}", template)
+
+	os.Setenv("EDITOR", "vim")
+	template, err = Resolve("")
+	assert.NoError(t, err)
+	assert.Equal(t, "vim +{line} {path}", template)
+
+	os.Setenv("VISUAL", "emacs -nw")
+	template, err = Resolve("")
+	assert.NoError(t, err)
+	assert.Equal(t, "emacs -nw +{line} {path}", template)
+
+	template, err = Resolve("my-editor {path}")
+	assert.NoError(t, err)
+	assert.Equal(t, "my-editor {path}", template)
+
+	os.Setenv("VISUAL", "")
+	os.Setenv("EDITOR", "")
+	lookPath = func(string) (string, error) { return "", errors.New("not found") }
+	_, err = Resolve("")
+	assert.Equal(t, ErrNotFound, err)
+}
@@ -0,0 +1,158 @@
+// Package merge combines two sets of changes made to the same file, line by line.
+package merge
+
+import (
+	"bytes"
+)
+
+// Markers around the conflicting lines, with the local lines first.
+const (
+	MarkerMine   = "<<<<<<< mine"
+	MarkerSep    = "======="
+	MarkerTheirs = ">>>>>>> theirs"
+)
+
+// Result is the outcome of a merge.
+type Result struct {
+	Contents []byte
+	// Conflicts is how many places both sides changed differently,
+	// which are marked in the contents.
+	Conflicts int
+}
+
+// ThreeWay merges the changes from base to mine with those from base to theirs.
+// Where only one side changed some lines, its change is taken. Where both
+// changed the same lines differently, both versions are kept between conflict markers.
+func ThreeWay(base, mine, theirs []byte) Result {
+	b, m, t := splitLines(base), splitLines(mine), splitLines(theirs)
+	toMine := matches(b, m)
+	toTheirs := matches(b, t)
+
+	var out bytes.Buffer
+	conflicts := 0
+	i, j, k := 0, 0, 0
+	for {
+		// Find the next base line that both sides kept.
+		next := i
+		for next < len(b) && (toMine[next] < 0 || toTheirs[next] < 0) {
+			next++
+		}
+		endMine, endTheirs := len(m), len(t)
+		if next < len(b) {
+			endMine, endTheirs = toMine[next], toTheirs[next]
+		}
+		if !resolve(&out, b[i:next], m[j:endMine], t[k:endTheirs]) {
+			conflicts++
+		}
+		if next == len(b) {
+			break
+		}
+		out.Write(b[next])
+		i, j, k = next+1, endMine+1, endTheirs+1
+	}
+	return Result{Contents: out.Bytes(), Conflicts: conflicts}
+}
+
+// resolve writes the outcome of a stretch of lines that at least one side may have changed.
+// It returns false if both sides changed them differently.
+func resolve(out *bytes.Buffer, base, mine, theirs [][]byte) bool {
+	switch {
+	case equal(mine, theirs), equal(base, theirs):
+		writeLines(out, mine)
+	case equal(base, mine):
+		writeLines(out, theirs)
+	default:
+		writeMarker(out, MarkerMine)
+		writeLines(out, mine)
+		endLine(out)
+		writeMarker(out, MarkerSep)
+		writeLines(out, theirs)
+		endLine(out)
+		writeMarker(out, MarkerTheirs)
+		return false
+	}
+	return true
+}
+
+func writeLines(out *bytes.Buffer, lines [][]byte) {
+	for _, line := range lines {
+		out.Write(line)
+	}
+}
+
+func writeMarker(out *bytes.Buffer, marker string) {
+	out.WriteString(marker)
+	out.WriteByte('\n')
+}
+
+// endLine makes sure a marker starts on a line of its own,
+// even if the lines before it ended the file without a newline.
+func endLine(out *bytes.Buffer) {
+	if out.Len() > 0 && out.Bytes()[out.Len()-1] != '\n' {
+		out.WriteByte('\n')
+	}
+}
+
+func equal(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// splitLines splits the contents into lines, keeping the line endings
+// so that the merged contents end the way the originals did.
+func splitLines(contents []byte) [][]byte {
+	lines := bytes.SplitAfter(contents, []byte("\n"))
+	if len(lines) > 0 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// matches pairs up the lines of a and b that are part of their longest common subsequence.
+// For each line of a, it gives the index of the matching line of b, or -1 if there is none.
+func matches(a, b [][]byte) []int {
+	// lengths[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case bytes.Equal(a[i], b[j]):
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			case lengths[i+1][j] >= lengths[i][j+1]:
+				lengths[i][j] = lengths[i+1][j]
+			default:
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	result := make([]int, len(a))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case bytes.Equal(a[i], b[j]):
+			result[i] = j
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			result[i] = -1
+			i++
+		default:
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		result[i] = -1
+	}
+	return result
+}
@@ -0,0 +1,76 @@
+package merge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThreeWay(t *testing.T) {
+	base := "package clock\n\n// New creates a clock.\nfunc New() {\n}\n\nfunc (c Clock) String() string {\n\treturn \"\"\n}\n"
+
+	tests := []struct {
+		desc      string
+		mine      string
+		theirs    string
+		expected  string
+		conflicts int
+	}{
+		{
+			desc:     "nothing changed",
+			mine:     base,
+			theirs:   base,
+			expected: base,
+		},
+		{
+			desc:     "only mine changed",
+			mine:     "package clock\n\n// New creates a clock.\nfunc New() {\n\treturn Clock{}\n}\n\nfunc (c Clock) String() string {\n\treturn \"\"\n}\n",
+			theirs:   base,
+			expected: "package clock\n\n// New creates a clock.\nfunc New() {\n\treturn Clock{}\n}\n\nfunc (c Clock) String() string {\n\treturn \"\"\n}\n",
+		},
+		{
+			desc:     "only theirs changed",
+			mine:     base,
+			theirs:   "package clock\n\n// New creates a clock at the given time.\nfunc New(h, m int) {\n}\n\nfunc (c Clock) String() string {\n\treturn \"\"\n}\n",
+			expected: "package clock\n\n// New creates a clock at the given time.\nfunc New(h, m int) {\n}\n\nfunc (c Clock) String() string {\n\treturn \"\"\n}\n",
+		},
+		{
+			desc:     "both changed different lines",
+			mine:     "package clock\n\n// New creates a clock.\nfunc New() {\n}\n\nfunc (c Clock) String() string {\n\treturn \"00:00\"\n}\n",
+			theirs:   "package clock\n\n// New creates a clock at the given time.\nfunc New() {\n}\n\nfunc (c Clock) String() string {\n\treturn \"\"\n}\n",
+			expected: "package clock\n\n// New creates a clock at the given time.\nfunc New() {\n}\n\nfunc (c Clock) String() string {\n\treturn \"00:00\"\n}\n",
+		},
+		{
+			desc:     "both made the same change",
+			mine:     "package clock\n",
+			theirs:   "package clock\n",
+			expected: "package clock\n",
+		},
+		{
+			desc:      "both changed the same lines",
+			mine:      "package clock\n\n// New creates a clock.\nfunc New() Clock {\n}\n\nfunc (c Clock) String() string {\n\treturn \"\"\n}\n",
+			theirs:    "package clock\n\n// New creates a clock.\nfunc New(h, m int) {\n}\n\nfunc (c Clock) String() string {\n\treturn \"\"\n}\n",
+			expected:  "package clock\n\n// New creates a clock.\n<<<<<<< mine\nfunc New() Clock {\n=======\nfunc New(h, m int) {\n>>>>>>> theirs\n}\n\nfunc (c Clock) String() string {\n\treturn \"\"\n}\n",
+			conflicts: 1,
+		},
+		{
+			desc:      "conflict at the end without a newline",
+			mine:      "package clock\n\nfunc New() {}",
+			theirs:    "package clock\n\nfunc New(h int) {}",
+			expected:  "package clock\n\n<<<<<<< mine\nfunc New() {}\n=======\nfunc New(h int) {}\n>>>>>>> theirs\n",
+			conflicts: 1,
+		},
+		{
+			desc:     "additions at either end",
+			mine:     "// Copyright me\n" + base,
+			theirs:   base + "\nfunc (c Clock) Add(m int) Clock {\n}\n",
+			expected: "// Copyright me\n" + base + "\nfunc (c Clock) Add(m int) Clock {\n}\n",
+		},
+	}
+
+	for _, test := range tests {
+		result := ThreeWay([]byte(base), []byte(test.mine), []byte(test.theirs))
+		assert.Equal(t, test.expected, string(result.Contents), test.desc)
+		assert.Equal(t, test.conflicts, result.Conflicts, test.desc)
+	}
+}
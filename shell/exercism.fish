@@ -27,7 +27,7 @@ complete -f -c exercism -n "__fish_seen_subcommand_from submit" -s h -l help -d
 
 # Troubleshoot
 complete -f -c exercism -n "__fish_use_subcommand" -a "troubleshoot" -d "Outputs useful debug information."
-complete -f -c exercism -n "__fish_seen_subcommand_from troubleshoot" -s f -l full-api-key -d "display full API key (censored by default)"
+complete -f -c exercism -n "__fish_seen_subcommand_from troubleshoot" -s f -l reveal-token -d "display full API key (censored by default)"
 complete -f -c exercism -n "__fish_seen_subcommand_from troubleshoot" -s h -l help -d "help for troubleshoot"
 
 # Upgrade
@@ -0,0 +1,338 @@
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultMaxSize is the size in bytes that the stored file contents are capped at
+// when no other limit is given.
+const DefaultMaxSize = 100 * 1024 * 1024
+
+// ErrNotFound signals that no snapshot matches the given ID.
+var ErrNotFound = errors.New("no such snapshot")
+
+// File is a file captured in a snapshot.
+type File struct {
+	// Path is relative to the directory of the snapshot.
+	Path string      `json:"path"`
+	Hash string      `json:"hash"`
+	Mode os.FileMode `json:"mode"`
+	Size int64       `json:"size"`
+}
+
+// Snapshot is a copy of some of the files in a directory at a point in time.
+type Snapshot struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	// Reason describes the operation the snapshot was taken before.
+	Reason string `json:"reason"`
+	Dir    string `json:"dir"`
+	Files  []File `json:"files"`
+}
+
+// Size is the total size of the files in the snapshot.
+func (s Snapshot) Size() int64 {
+	var size int64
+	for _, f := range s.Files {
+		size += f.Size
+	}
+	return size
+}
+
+// Store keeps snapshots, storing the contents of each file once no matter
+// how many snapshots it appears in.
+// Once the contents exceed MaxSize, the oldest snapshots are discarded,
+// as are snapshots older than MaxAge, if it is set.
+type Store struct {
+	Dir     string
+	MaxSize int64
+	MaxAge  time.Duration
+}
+
+// New creates a store in the given directory.
+// A maxAge of zero keeps snapshots for as long as there is room for them.
+func New(dir string, maxSize int64, maxAge time.Duration) Store {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	return Store{Dir: dir, MaxSize: maxSize, MaxAge: maxAge}
+}
+
+func (s Store) objectPath(hash string) string {
+	return filepath.Join(s.Dir, "objects", hash[:2], hash[2:])
+}
+
+func (s Store) manifestPath(id string) string {
+	return filepath.Join(s.Dir, "snapshots", fmt.Sprintf("%s.json", id))
+}
+
+// Take captures the given files, relative to dir, before they are changed.
+// Files that don't exist are skipped. If none of them exist, no snapshot is taken and it returns nil.
+func (s Store) Take(dir string, paths []string, reason string) (*Snapshot, error) {
+	snap := &Snapshot{
+		CreatedAt: time.Now(),
+		Reason:    reason,
+		Dir:       dir,
+	}
+	for _, path := range paths {
+		f, err := s.store(filepath.Join(dir, path))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		f.Path = filepath.ToSlash(path)
+		snap.Files = append(snap.Files, *f)
+	}
+	if len(snap.Files) == 0 {
+		return nil, nil
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%d", dir, reason, snap.CreatedAt.UnixNano())
+	for _, f := range snap.Files {
+		fmt.Fprintf(h, "\x00%s\x00%s", f.Path, f.Hash)
+	}
+	snap.ID = fmt.Sprintf("%s-%x", snap.CreatedAt.Format("20060102-150405"), h.Sum(nil)[:4])
+
+	b, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	path := s.manifestPath(snap.ID)
+	if err := os.MkdirAll(filepath.Dir(path), os.FileMode(0755)); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(path, b, os.FileMode(0644)); err != nil {
+		return nil, err
+	}
+	return snap, s.prune(snap.ID)
+}
+
+// store copies a file into the object store, unless its contents are already there.
+func (s Store) store(path string) (*File, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("cannot snapshot directory %s", path)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	hash := fmt.Sprintf("%x", sha256.Sum256(b))
+
+	object := s.objectPath(hash)
+	if _, err := os.Stat(object); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(object), os.FileMode(0755)); err != nil {
+			return nil, err
+		}
+		// Write to a temporary file first, so that a partial object is never mistaken for a complete one.
+		tmp := object + ".tmp"
+		if err := ioutil.WriteFile(tmp, b, os.FileMode(0644)); err != nil {
+			return nil, err
+		}
+		if err := os.Rename(tmp, object); err != nil {
+			return nil, err
+		}
+	}
+	return &File{Hash: hash, Mode: info.Mode().Perm(), Size: int64(len(b))}, nil
+}
+
+// List returns the snapshots, newest first.
+func (s Store) List() ([]Snapshot, error) {
+	infos, err := ioutil.ReadDir(filepath.Join(s.Dir, "snapshots"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snaps []Snapshot
+	for _, info := range infos {
+		if info.IsDir() || filepath.Ext(info.Name()) != ".json" {
+			continue
+		}
+		snap, err := s.read(strings.TrimSuffix(info.Name(), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		snaps = append(snaps, *snap)
+	}
+	sort.SliceStable(snaps, func(i, j int) bool {
+		return snaps[i].CreatedAt.After(snaps[j].CreatedAt)
+	})
+	return snaps, nil
+}
+
+func (s Store) read(id string) (*Snapshot, error) {
+	b, err := ioutil.ReadFile(s.manifestPath(id))
+	if err != nil {
+		return nil, err
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(b, &snap); err != nil {
+		return nil, fmt.Errorf("unable to read snapshot %s: %s", id, err)
+	}
+	return &snap, nil
+}
+
+// Get finds a snapshot by its ID, or by a prefix that only one snapshot's ID starts with.
+func (s Store) Get(id string) (*Snapshot, error) {
+	snaps, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	var matches []Snapshot
+	for _, snap := range snaps {
+		if snap.ID == id {
+			return &snap, nil
+		}
+		if strings.HasPrefix(snap.ID, id) {
+			matches = append(matches, snap)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, ErrNotFound
+	case 1:
+		return &matches[0], nil
+	}
+	return nil, fmt.Errorf("'%s' matches %d snapshots, give more of the ID", id, len(matches))
+}
+
+// Restore writes the files of a snapshot back into its directory.
+func (s Store) Restore(snap Snapshot) error {
+	for _, f := range snap.Files {
+		b, err := ioutil.ReadFile(s.objectPath(f.Hash))
+		if err != nil {
+			return fmt.Errorf("the contents of %s are missing from the snapshot: %s", f.Path, err)
+		}
+		path := filepath.Join(snap.Dir, filepath.FromSlash(f.Path))
+		if err := os.MkdirAll(filepath.Dir(path), os.FileMode(0755)); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path, b, f.Mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Remove deletes a snapshot, along with any contents no other snapshot refers to.
+func (s Store) Remove(id string) error {
+	return s.discard([]string{id})
+}
+
+// Purge removes the snapshots taken before the given time, and returns how many were removed.
+func (s Store) Purge(before time.Time) (int, error) {
+	snaps, err := s.List()
+	if err != nil {
+		return 0, err
+	}
+	var discard []string
+	for _, snap := range snaps {
+		if snap.CreatedAt.Before(before) {
+			discard = append(discard, snap.ID)
+		}
+	}
+	return len(discard), s.discard(discard)
+}
+
+// prune discards the snapshots that are older than MaxAge, then the oldest
+// snapshots until the stored contents fit in MaxSize.
+// The snapshot that was just taken is always kept.
+func (s Store) prune(keep string) error {
+	snaps, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	var expiry time.Time
+	if s.MaxAge > 0 {
+		expiry = time.Now().Add(-s.MaxAge)
+	}
+
+	seen := map[string]bool{}
+	var size int64
+	var discard []string
+	full := false
+	for _, snap := range snaps {
+		var added int64
+		for _, f := range snap.Files {
+			if !seen[f.Hash] {
+				added += f.Size
+			}
+		}
+		if snap.ID != keep && (full || size+added > s.MaxSize || snap.CreatedAt.Before(expiry)) {
+			// The snapshots are newest first, so once one goes, all the older ones go too.
+			full = true
+			discard = append(discard, snap.ID)
+			continue
+		}
+		for _, f := range snap.Files {
+			seen[f.Hash] = true
+		}
+		size += added
+	}
+	return s.discard(discard)
+}
+
+// discard removes snapshots, along with any contents no other snapshot refers to.
+func (s Store) discard(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	for _, id := range ids {
+		if err := os.Remove(s.manifestPath(id)); err != nil {
+			return err
+		}
+	}
+	return s.collectGarbage()
+}
+
+// collectGarbage removes the contents that no snapshot refers to.
+func (s Store) collectGarbage() error {
+	snaps, err := s.List()
+	if err != nil {
+		return err
+	}
+	referenced := map[string]bool{}
+	for _, snap := range snaps {
+		for _, f := range snap.Files {
+			referenced[f.Hash] = true
+		}
+	}
+
+	root := filepath.Join(s.Dir, "objects")
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if !referenced[strings.Replace(filepath.ToSlash(rel), "/", "", 1)] {
+			return os.Remove(path)
+		}
+		return nil
+	})
+}
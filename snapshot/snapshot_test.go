@@ -0,0 +1,209 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, dir, name, contents string) {
+	path := filepath.Join(dir, name)
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), os.FileMode(0755)))
+	assert.NoError(t, ioutil.WriteFile(path, []byte(contents), os.FileMode(0644)))
+}
+
+func readFile(t *testing.T, dir, name string) string {
+	b, err := ioutil.ReadFile(filepath.Join(dir, name))
+	assert.NoError(t, err)
+	return string(b)
+}
+
+func countObjects(t *testing.T, store Store) int {
+	n := 0
+	filepath.Walk(filepath.Join(store.Dir, "objects"), func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			n++
+		}
+		return nil
+	})
+	return n
+}
+
+func TestTakeAndRestore(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "snapshot")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	store := New(filepath.Join(tmpDir, "store"), 0, 0)
+	assert.Equal(t, int64(DefaultMaxSize), store.MaxSize)
+
+	dir := filepath.Join(tmpDir, "exercise")
+	writeFile(t, dir, "main.go", "package main")
+	writeFile(t, dir, "sub/helper.go", "package sub")
+
+	snap, err := store.Take(dir, []string{"main.go", filepath.Join("sub", "helper.go"), "missing.go"}, "testing")
+	assert.NoError(t, err)
+	if assert.NotNil(t, snap) {
+		assert.Len(t, snap.Files, 2)
+		assert.Equal(t, "sub/helper.go", snap.Files[1].Path)
+		assert.Equal(t, int64(len("package main")+len("package sub")), snap.Size())
+	}
+
+	writeFile(t, dir, "main.go", "overwritten")
+	assert.NoError(t, os.RemoveAll(filepath.Join(dir, "sub")))
+
+	found, err := store.Get(snap.ID[:len(snap.ID)-2])
+	assert.NoError(t, err)
+	assert.NoError(t, store.Restore(*found))
+
+	assert.Equal(t, "package main", readFile(t, dir, "main.go"))
+	assert.Equal(t, "package sub", readFile(t, dir, "sub/helper.go"))
+}
+
+func TestTakeWithoutFiles(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "snapshot")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	store := New(tmpDir, 0, 0)
+	snap, err := store.Take(tmpDir, []string{"missing.go"}, "testing")
+	assert.NoError(t, err)
+	assert.Nil(t, snap)
+
+	snaps, err := store.List()
+	assert.NoError(t, err)
+	assert.Empty(t, snaps)
+
+	_, err = store.Get("anything")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestIdenticalContentsAreStoredOnce(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "snapshot")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	store := New(filepath.Join(tmpDir, "store"), 0, 0)
+	dir := filepath.Join(tmpDir, "exercise")
+	writeFile(t, dir, "a.txt", "same")
+	writeFile(t, dir, "b.txt", "same")
+
+	_, err = store.Take(dir, []string{"a.txt", "b.txt"}, "first")
+	assert.NoError(t, err)
+	_, err = store.Take(dir, []string{"a.txt"}, "second")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, countObjects(t, store))
+
+	snaps, err := store.List()
+	assert.NoError(t, err)
+	assert.Len(t, snaps, 2)
+}
+
+func TestOldestSnapshotsArePruned(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "snapshot")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	store := New(filepath.Join(tmpDir, "store"), 25, 0)
+	dir := filepath.Join(tmpDir, "exercise")
+
+	var ids []string
+	for _, contents := range []string{"first version", "second version", "third version"} {
+		writeFile(t, dir, "main.go", contents)
+		snap, err := store.Take(dir, []string{"main.go"}, contents)
+		assert.NoError(t, err)
+		ids = append(ids, snap.ID)
+	}
+
+	snaps, err := store.List()
+	assert.NoError(t, err)
+	if assert.Len(t, snaps, 1) {
+		assert.Equal(t, ids[2], snaps[0].ID)
+	}
+	assert.Equal(t, 1, countObjects(t, store))
+
+	// The newest snapshot is kept, even if it is too big by itself.
+	writeFile(t, dir, "main.go", "a version far bigger than the store allows")
+	snap, err := store.Take(dir, []string{"main.go"}, "big")
+	assert.NoError(t, err)
+	snaps, err = store.List()
+	assert.NoError(t, err)
+	if assert.Len(t, snaps, 1) {
+		assert.Equal(t, snap.ID, snaps[0].ID)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "snapshot")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	store := New(filepath.Join(tmpDir, "store"), 0, 0)
+	dir := filepath.Join(tmpDir, "exercise")
+	writeFile(t, dir, "main.go", "package main")
+
+	snap, err := store.Take(dir, []string{"main.go"}, "testing")
+	assert.NoError(t, err)
+	assert.NoError(t, store.Remove(snap.ID))
+
+	snaps, err := store.List()
+	assert.NoError(t, err)
+	assert.Empty(t, snaps)
+	assert.Equal(t, 0, countObjects(t, store))
+}
+
+func TestExpiredSnapshotsArePruned(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "snapshot")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	store := New(filepath.Join(tmpDir, "store"), 0, 7*24*time.Hour)
+	dir := filepath.Join(tmpDir, "exercise")
+	writeFile(t, dir, "main.go", "old")
+
+	old, err := store.Take(dir, []string{"main.go"}, "old")
+	assert.NoError(t, err)
+
+	// Pretend it was taken a while ago.
+	old.CreatedAt = time.Now().Add(-10 * 24 * time.Hour)
+	b, err := json.Marshal(old)
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(store.manifestPath(old.ID), b, os.FileMode(0644)))
+
+	writeFile(t, dir, "main.go", "new")
+	recent, err := store.Take(dir, []string{"main.go"}, "new")
+	assert.NoError(t, err)
+
+	snaps, err := store.List()
+	assert.NoError(t, err)
+	if assert.Len(t, snaps, 1) {
+		assert.Equal(t, recent.ID, snaps[0].ID)
+	}
+}
+
+func TestPurge(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "snapshot")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	store := New(filepath.Join(tmpDir, "store"), 0, 0)
+	dir := filepath.Join(tmpDir, "exercise")
+	writeFile(t, dir, "main.go", "package main")
+	_, err = store.Take(dir, []string{"main.go"}, "testing")
+	assert.NoError(t, err)
+
+	n, err := store.Purge(time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	n, err = store.Purge(time.Now().Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, 0, countObjects(t, store))
+}
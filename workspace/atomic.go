@@ -0,0 +1,62 @@
+package workspace
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// WriteFileAtomically writes a file by way of a temporary file next to it that is renamed into place,
+// so that it is either written whole or left as it was.
+// The file gets mode less the umask, like any file that is created, whether or not it was already there.
+func WriteFileAtomically(path string, contents []byte, mode os.FileMode) error {
+	return writeFileAtomically(path, contents, mode, false)
+}
+
+// ReplaceFileAtomically writes a file like WriteFileAtomically, but gives it exactly mode,
+// such as the permissions of the file it replaces.
+func ReplaceFileAtomically(path string, contents []byte, mode os.FileMode) error {
+	return writeFileAtomically(path, contents, mode, true)
+}
+
+func writeFileAtomically(path string, contents []byte, mode os.FileMode, exact bool) error {
+	f, err := createTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp", mode)
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	// Once renamed, there's nothing left to remove.
+	defer os.Remove(tmp)
+
+	if _, err := f.Write(contents); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if exact {
+		if err := os.Chmod(tmp, mode); err != nil {
+			return err
+		}
+	}
+	return os.Rename(tmp, path)
+}
+
+// createTemp creates a new file with a random name in dir.
+// It is created with mode, so that the umask applies to it.
+func createTemp(dir, prefix string, mode os.FileMode) (*os.File, error) {
+	for i := 0; ; i++ {
+		name := filepath.Join(dir, prefix+strconv.FormatUint(uint64(rand.Uint32()), 10))
+		f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, mode)
+		if os.IsExist(err) && i < 10000 {
+			continue
+		}
+		return f, err
+	}
+}
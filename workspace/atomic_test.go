@@ -0,0 +1,52 @@
+package workspace
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteFileAtomically(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atomic")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "file.txt")
+	assert.NoError(t, WriteFileAtomically(path, []byte("first"), os.FileMode(0644)))
+	assert.NoError(t, WriteFileAtomically(path, []byte("second"), os.FileMode(0755)))
+
+	b, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "second", string(b))
+
+	if runtime.GOOS != "windows" {
+		// The umask is whatever permissions a file that is created doesn't get.
+		probe := filepath.Join(dir, "umask")
+		f, err := os.OpenFile(probe, os.O_CREATE|os.O_EXCL|os.O_WRONLY, os.FileMode(0777))
+		assert.NoError(t, err)
+		f.Close()
+		info, err := os.Stat(probe)
+		assert.NoError(t, err)
+		umask := os.FileMode(0777) &^ info.Mode().Perm()
+		assert.NoError(t, os.Remove(probe))
+
+		info, err = os.Stat(path)
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0755)&^umask, info.Mode().Perm())
+
+		// A file replaced with the permissions it had keeps them, whatever the umask.
+		assert.NoError(t, ReplaceFileAtomically(path, []byte("third"), os.FileMode(0777)))
+		info, err = os.Stat(path)
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0777), info.Mode().Perm())
+	}
+
+	// No temporary files are left behind.
+	infos, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, infos, 1)
+}
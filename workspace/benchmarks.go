@@ -0,0 +1,76 @@
+package workspace
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const benchmarksFilename = "bench.json"
+
+var benchmarksFilepath = filepath.Join(ignoreSubdir, benchmarksFilename)
+
+// BenchmarkRun is the outcome of the last time the benchmarks of an exercise were run,
+// kept so that the next run can be compared against it.
+type BenchmarkRun struct {
+	RanAt   time.Time   `json:"ran_at"`
+	Command string      `json:"command"`
+	Results []Benchmark `json:"results"`
+}
+
+// Benchmark is how long one of the benchmarks took per operation.
+type Benchmark struct {
+	Name    string  `json:"name"`
+	NsPerOp float64 `json:"ns_per_op"`
+}
+
+// BenchmarksFilepath is the absolute path to where the exercise in the given directory
+// keeps its last benchmark run.
+func BenchmarksFilepath(dir string) string {
+	return filepath.Join(dir, benchmarksFilepath)
+}
+
+// NewBenchmarkRun reads the last benchmark run of the exercise in the given directory.
+// It returns nil if the benchmarks have not been run.
+func NewBenchmarkRun(dir string) (*BenchmarkRun, error) {
+	b, err := ioutil.ReadFile(LongPath(BenchmarksFilepath(dir)))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var run BenchmarkRun
+	if err := json.Unmarshal(b, &run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// Write stores the run in the given exercise directory, replacing the one before it.
+func (r BenchmarkRun) Write(dir string) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := LongPath(BenchmarksFilepath(dir))
+	if err = os.MkdirAll(filepath.Dir(path), os.FileMode(0755)); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, os.FileMode(0644))
+}
+
+// Lookup returns the result of the named benchmark, if the run has it.
+func (r *BenchmarkRun) Lookup(name string) (Benchmark, bool) {
+	if r == nil {
+		return Benchmark{}, false
+	}
+	for _, result := range r.Results {
+		if result.Name == name {
+			return result, true
+		}
+	}
+	return Benchmark{}, false
+}
@@ -0,0 +1,35 @@
+package workspace
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBenchmarkRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "benchmarks")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	run, err := NewBenchmarkRun(dir)
+	assert.NoError(t, err)
+	assert.Nil(t, run)
+	_, ok := run.Lookup("BenchmarkLeap")
+	assert.False(t, ok)
+
+	ranAt := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	written := BenchmarkRun{RanAt: ranAt, Command: "go test -bench .", Results: []Benchmark{{Name: "BenchmarkLeap", NsPerOp: 12.5}}}
+	assert.NoError(t, written.Write(dir))
+
+	run, err = NewBenchmarkRun(dir)
+	assert.NoError(t, err)
+	if assert.NotNil(t, run) {
+		assert.True(t, ranAt.Equal(run.RanAt))
+		result, ok := run.Lookup("BenchmarkLeap")
+		assert.True(t, ok)
+		assert.Equal(t, 12.5, result.NsPerOp)
+	}
+}
@@ -0,0 +1,71 @@
+package workspace
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const checksumsFilename = "checksums.json"
+
+var checksumsFilepath = filepath.Join(ignoreSubdir, checksumsFilename)
+
+// Checksums are the checksums of the exercise files as they were downloaded,
+// keyed by their path relative to the exercise directory.
+// Comparing against them tells which files have been changed locally.
+type Checksums map[string]string
+
+// Checksum fingerprints the contents of a file.
+func Checksum(contents []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(contents))
+}
+
+// ChecksumsFilepath is the absolute path to the checksums file of the exercise in the given directory.
+func ChecksumsFilepath(dir string) string {
+	return filepath.Join(dir, checksumsFilepath)
+}
+
+// NewChecksums reads the checksums stored in the given exercise directory.
+// Exercises downloaded before checksums were stored have none.
+func NewChecksums(dir string) (Checksums, error) {
+	b, err := ioutil.ReadFile(LongPath(ChecksumsFilepath(dir)))
+	if os.IsNotExist(err) {
+		return Checksums{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	checksums := Checksums{}
+	if err := json.Unmarshal(b, &checksums); err != nil {
+		return nil, err
+	}
+	return checksums, nil
+}
+
+// Write stores the checksums in the given exercise directory.
+func (c Checksums) Write(dir string) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	path := LongPath(ChecksumsFilepath(dir))
+	if err = os.MkdirAll(filepath.Dir(path), os.FileMode(0755)); err != nil {
+		return err
+	}
+	return WriteFileAtomically(path, b, os.FileMode(0644))
+}
+
+// IsModified tells whether the contents of a file differ from when it was downloaded.
+// A file without a recorded checksum is assumed to have been modified.
+func (c Checksums) IsModified(path string, contents []byte) bool {
+	checksum, ok := c[filepath.ToSlash(path)]
+	return !ok || checksum != Checksum(contents)
+}
+
+// Set records the checksum of a file.
+func (c Checksums) Set(path string, contents []byte) {
+	c[filepath.ToSlash(path)] = Checksum(contents)
+}
@@ -0,0 +1,29 @@
+package workspace
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecksums(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checksums")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	checksums, err := NewChecksums(dir)
+	assert.NoError(t, err)
+	assert.True(t, checksums.IsModified("main.go", []byte("package main")), "files without a checksum count as modified")
+
+	checksums.Set(filepath.Join("sub", "main.go"), []byte("package main"))
+	assert.NoError(t, checksums.Write(dir))
+
+	checksums, err = NewChecksums(dir)
+	assert.NoError(t, err)
+	assert.False(t, checksums.IsModified(filepath.Join("sub", "main.go"), []byte("package main")))
+	assert.True(t, checksums.IsModified(filepath.Join("sub", "main.go"), []byte("package main // changed")))
+	assert.Equal(t, Checksum([]byte("package main")), checksums["sub/main.go"])
+}
@@ -0,0 +1,134 @@
+package workspace
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encoding is the character encoding of a file, as far as it can be told from its contents.
+type Encoding string
+
+// The encodings that are told apart.
+const (
+	EncodingUTF8    Encoding = "UTF-8"
+	EncodingUTF8BOM Encoding = "UTF-8 with a byte order mark"
+	EncodingUTF16LE Encoding = "UTF-16 (little endian)"
+	EncodingUTF16BE Encoding = "UTF-16 (big endian)"
+	// EncodingLegacy is text that isn't valid UTF-8, most likely Windows-1252 or Latin-1.
+	EncodingLegacy Encoding = "not UTF-8"
+	// EncodingBinary is a file that isn't text.
+	EncodingBinary Encoding = "binary"
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// DetectEncoding tells the encoding of a file from its contents.
+func DetectEncoding(contents []byte) Encoding {
+	switch {
+	case bytes.HasPrefix(contents, bomUTF8):
+		return EncodingUTF8BOM
+	case bytes.HasPrefix(contents, bomUTF16LE):
+		return EncodingUTF16LE
+	case bytes.HasPrefix(contents, bomUTF16BE):
+		return EncodingUTF16BE
+	case IsBinary(contents):
+		return EncodingBinary
+	case !utf8.Valid(contents):
+		return EncodingLegacy
+	}
+	return EncodingUTF8
+}
+
+// IsBinary tells whether the contents are those of a binary file, such as an image, rather than text.
+// Text in any encoding, or with a byte order mark, isn't binary.
+func IsBinary(contents []byte) bool {
+	return !strings.HasPrefix(http.DetectContentType(contents), "text/")
+}
+
+// ContentType tells the media type of a binary file from its contents,
+// which is application/octet-stream if it isn't a type that is known.
+func ContentType(contents []byte) string {
+	return http.DetectContentType(contents)
+}
+
+// StripBOM removes the UTF-8 byte order mark from the start of the contents,
+// and tells whether there was one.
+func StripBOM(contents []byte) ([]byte, bool) {
+	if !bytes.HasPrefix(contents, bomUTF8) {
+		return contents, false
+	}
+	return contents[len(bomUTF8):], true
+}
+
+// ToUTF8 converts the contents to UTF-8 without a byte order mark.
+// Text that isn't valid UTF-8 is read as Windows-1252, which Latin-1 is a subset of.
+// Binary contents are returned as they are.
+func ToUTF8(contents []byte) []byte {
+	switch DetectEncoding(contents) {
+	case EncodingUTF8BOM:
+		return contents[len(bomUTF8):]
+	case EncodingUTF16LE:
+		return decodeUTF16(contents[len(bomUTF16LE):], func(b []byte) uint16 { return uint16(b[0]) | uint16(b[1])<<8 })
+	case EncodingUTF16BE:
+		return decodeUTF16(contents[len(bomUTF16BE):], func(b []byte) uint16 { return uint16(b[0])<<8 | uint16(b[1]) })
+	case EncodingLegacy:
+		var buf bytes.Buffer
+		for _, b := range contents {
+			r := rune(b)
+			if b >= 0x80 && b < 0xA0 {
+				r = windows1252[b-0x80]
+			}
+			buf.WriteRune(r)
+		}
+		return buf.Bytes()
+	}
+	return contents
+}
+
+func decodeUTF16(contents []byte, unit func([]byte) uint16) []byte {
+	units := make([]uint16, 0, len(contents)/2)
+	for i := 0; i+1 < len(contents); i += 2 {
+		units = append(units, unit(contents[i:i+2]))
+	}
+	return []byte(string(utf16.Decode(units)))
+}
+
+// windows1252 maps the bytes 0x80 to 0x9F, where Windows-1252 differs from Latin-1.
+// The bytes it leaves undefined become the replacement character.
+var windows1252 = [32]rune{
+	'€', '�', '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', '�', 'Ž', '�',
+	'�', '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', '�', 'ž', 'Ÿ',
+}
+
+// EncodingPolicy is what to do when submitting files that aren't plain UTF-8.
+type EncodingPolicy string
+
+// The encoding policies.
+const (
+	// EncodingWarn submits the files as they are, with a warning.
+	EncodingWarn EncodingPolicy = "warn"
+	// EncodingTranscode submits the files converted to UTF-8 without a byte order mark.
+	EncodingTranscode EncodingPolicy = "transcode"
+	// EncodingIgnore submits the files as they are.
+	EncodingIgnore EncodingPolicy = "ignore"
+)
+
+// NewEncodingPolicy reads an encoding policy. The default is to warn.
+func NewEncodingPolicy(s string) (EncodingPolicy, error) {
+	policy := EncodingPolicy(strings.ToLower(strings.TrimSpace(s)))
+	switch policy {
+	case "":
+		return EncodingWarn, nil
+	case EncodingWarn, EncodingTranscode, EncodingIgnore:
+		return policy, nil
+	}
+	return "", fmt.Errorf("unknown encoding policy '%s', use one of warn, transcode and ignore", s)
+}
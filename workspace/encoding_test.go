@@ -0,0 +1,60 @@
+package workspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodings(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		in       []byte
+		encoding Encoding
+		utf8     string
+	}{
+		{"plain UTF-8", []byte("héllo"), EncodingUTF8, "héllo"},
+		{"UTF-8 with a BOM", []byte("\xEF\xBB\xBFhéllo"), EncodingUTF8BOM, "héllo"},
+		{"UTF-16 LE", []byte{0xFF, 0xFE, 'h', 0, 0xE9, 0, '\n', 0}, EncodingUTF16LE, "hé\n"},
+		{"UTF-16 BE", []byte{0xFE, 0xFF, 0, 'h', 0, 0xE9}, EncodingUTF16BE, "hé"},
+		{"Latin-1", []byte("h\xE9llo"), EncodingLegacy, "héllo"},
+		{"Windows-1252", []byte("\x93quoted\x94 \x80"), EncodingLegacy, "“quoted” €"},
+		{"binary", []byte("\x00\x01\xFF"), EncodingBinary, "\x00\x01\xFF"},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.encoding, DetectEncoding(tc.in), tc.desc)
+		assert.Equal(t, tc.utf8, string(ToUTF8(tc.in)), tc.desc)
+	}
+}
+
+func TestNewEncodingPolicy(t *testing.T) {
+	policy, err := NewEncodingPolicy("")
+	assert.NoError(t, err)
+	assert.Equal(t, EncodingWarn, policy)
+
+	policy, err = NewEncodingPolicy("Transcode")
+	assert.NoError(t, err)
+	assert.Equal(t, EncodingTranscode, policy)
+
+	_, err = NewEncodingPolicy("latin1")
+	assert.Error(t, err)
+}
+
+func TestIsBinary(t *testing.T) {
+	assert.True(t, IsBinary([]byte("\x89PNG\r\n\x1a\n\x00\x00")))
+	assert.True(t, IsBinary([]byte("GIF89a\x01\x02")))
+	assert.Equal(t, "image/gif", ContentType([]byte("GIF89a\x01\x02")))
+	assert.False(t, IsBinary([]byte("h\xE9llo\r\n")))
+	assert.False(t, IsBinary([]byte{0xFF, 0xFE, 'h', 0}))
+}
+
+func TestStripBOM(t *testing.T) {
+	contents, stripped := StripBOM([]byte("\xEF\xBB\xBFhello"))
+	assert.True(t, stripped)
+	assert.Equal(t, "hello", string(contents))
+
+	contents, stripped = StripBOM([]byte("hello"))
+	assert.False(t, stripped)
+	assert.Equal(t, "hello", string(contents))
+}
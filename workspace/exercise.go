@@ -54,7 +54,7 @@ func (e Exercise) MetadataDir() string {
 // If there is no such file, this may be a legacy exercise.
 // It could also be an unrelated directory.
 func (e Exercise) HasMetadata() (bool, error) {
-	_, err := os.Lstat(e.MetadataFilepath())
+	_, err := os.Lstat(LongPath(e.MetadataFilepath()))
 	if os.IsNotExist(err) {
 		return false, nil
 	}
@@ -67,7 +67,7 @@ func (e Exercise) HasMetadata() (bool, error) {
 // HasLegacyMetadata checks for the presence of a legacy exercise metadata file.
 // If there is no such file, it could also be an unrelated directory.
 func (e Exercise) HasLegacyMetadata() (bool, error) {
-	_, err := os.Lstat(e.LegacyMetadataFilepath())
+	_, err := os.Lstat(LongPath(e.LegacyMetadataFilepath()))
 	if os.IsNotExist(err) {
 		return false, nil
 	}
@@ -105,16 +105,16 @@ func (e Exercise) MigrateLegacyMetadataFile() (MigrationStatus, error) {
 	if ok, _ := e.HasLegacyMetadata(); !ok {
 		return MigrationStatusNoop, nil
 	}
-	if err := os.MkdirAll(filepath.Dir(e.MetadataFilepath()), os.FileMode(0755)); err != nil {
+	if err := os.MkdirAll(LongPath(filepath.Dir(e.MetadataFilepath())), os.FileMode(0755)); err != nil {
 		return MigrationStatusNoop, err
 	}
 	if ok, _ := e.HasMetadata(); !ok {
-		if err := os.Rename(e.LegacyMetadataFilepath(), e.MetadataFilepath()); err != nil {
+		if err := os.Rename(LongPath(e.LegacyMetadataFilepath()), LongPath(e.MetadataFilepath())); err != nil {
 			return MigrationStatusNoop, err
 		}
 		return MigrationStatusMigrated, nil
 	}
-	if err := os.Remove(e.LegacyMetadataFilepath()); err != nil {
+	if err := os.Remove(LongPath(e.LegacyMetadataFilepath())); err != nil {
 		return MigrationStatusNoop, err
 	}
 	return MigrationStatusRemoved, nil
@@ -0,0 +1,130 @@
+package workspace
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// exerciseConfigFilepath is where the track's configuration of an exercise is downloaded to.
+var exerciseConfigFilepath = filepath.Join(ignoreSubdir, "config.json")
+
+// ExerciseConfig is the track's configuration of an exercise, which says what its files are for.
+// The paths are relative to the exercise directory and use forward slashes.
+type ExerciseConfig struct {
+	Files struct {
+		Solution    []string `json:"solution"`
+		Test        []string `json:"test"`
+		Example     []string `json:"example"`
+		Exemplar    []string `json:"exemplar"`
+		Editor      []string `json:"editor"`
+		Invalidator []string `json:"invalidator"`
+	} `json:"files"`
+}
+
+// NewExerciseConfig reads the track's configuration of the exercise in the given directory.
+// Exercises downloaded before tracks had one don't have it, which os.IsNotExist tells.
+func NewExerciseConfig(dir string) (*ExerciseConfig, error) {
+	b, err := ioutil.ReadFile(LongPath(filepath.Join(dir, exerciseConfigFilepath)))
+	if err != nil {
+		return nil, err
+	}
+	var config ExerciseConfig
+	if err := json.Unmarshal(b, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// IsSolutionFile tells whether the path is one of the files the solution is written in.
+func (c *ExerciseConfig) IsSolutionFile(relPath string) bool {
+	return containsPath(c.Files.Solution, relPath)
+}
+
+// IsSupportFile tells whether the path is one of the files that come with the exercise
+// to test it or to build it, rather than being part of the solution.
+func (c *ExerciseConfig) IsSupportFile(relPath string) bool {
+	for _, paths := range [][]string{c.Files.Test, c.Files.Example, c.Files.Exemplar, c.Files.Editor, c.Files.Invalidator} {
+		if containsPath(paths, relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsPath(paths []string, relPath string) bool {
+	relPath = path.Clean(relPath)
+	for _, p := range paths {
+		if path.Clean(p) == relPath {
+			return true
+		}
+	}
+	return false
+}
+
+// testDirs are directories that hold tests, in any track.
+var testDirs = map[string]bool{"test": true, "tests": true, "spec": true, "__tests__": true}
+
+// testFilePatterns match the names of test files in the tracks' usual layouts.
+var testFilePatterns = []string{
+	"*_test.*", "*_tests.*", "*_spec.*", "test_*.*",
+	"*.test.*", "*.spec.*", "*Test.*", "*Tests.*", "*Spec.*",
+}
+
+// toolingFiles are the build and editor configuration files that tracks ship with their exercises.
+var toolingFiles = map[string]bool{
+	"README.md": true, "HELP.md": true, "HINTS.md": true,
+	"Makefile": true, "CMakeLists.txt": true,
+	"package.json": true, "package-lock.json": true, "yarn.lock": true, "tsconfig.json": true,
+	"babel.config.js": true, "jest.config.js": true, ".eslintrc": true,
+	"Cargo.toml": true, "Cargo.lock": true,
+	"go.mod": true, "go.sum": true,
+	"build.gradle": true, "build.gradle.kts": true, "settings.gradle": true, "pom.xml": true,
+	"gradlew": true, "gradlew.bat": true,
+	"mix.exs": true, "rebar.config": true,
+	"stack.yaml": true, "package.yaml": true,
+	"project.clj": true, "deps.edn": true, "build.sbt": true,
+	"Gemfile": true, "Gemfile.lock": true, "Rakefile": true,
+	"Package.swift": true, "pubspec.yaml": true, "elm.json": true, "spago.dhall": true,
+}
+
+// IsTestOrToolingFile guesses whether the path is a test, or a build or editor file,
+// for exercises without a configuration saying so.
+// The path is relative to the exercise directory and uses forward slashes.
+func IsTestOrToolingFile(relPath string) bool {
+	parts := strings.Split(path.Clean(relPath), "/")
+	for _, dir := range parts[:len(parts)-1] {
+		if testDirs[dir] {
+			return true
+		}
+	}
+	name := parts[len(parts)-1]
+	if toolingFiles[name] || strings.HasSuffix(name, ".csproj") || strings.HasSuffix(name, ".fsproj") || strings.HasSuffix(name, ".sln") {
+		return true
+	}
+	for _, pattern := range testFilePatterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// docFiles are the documents that tracks ship with their exercises, next to the solution.
+var docFiles = map[string]bool{"README.md": true, "HELP.md": true, "HINTS.md": true}
+
+// docsDir is the directory of an exercise that holds its instructions and other documents.
+const docsDir = ".docs"
+
+// IsDocFile tells whether the path is one of the exercise's instructions or other documents,
+// as opposed to a solution, test or tooling file.
+// The path is relative to the exercise directory and uses forward slashes.
+func IsDocFile(relPath string) bool {
+	parts := strings.Split(path.Clean(relPath), "/")
+	if len(parts) == 1 {
+		return docFiles[parts[0]]
+	}
+	return parts[0] == docsDir
+}
@@ -0,0 +1,75 @@
+package workspace
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExerciseConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "exercise-config")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = NewExerciseConfig(dir)
+	assert.True(t, os.IsNotExist(err))
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, ".exercism"), os.FileMode(0755)))
+	contents := `{"files": {"solution": ["src/lib.rs"], "test": ["tests/leap.rs"], "editor": ["src/helpers.rs"], "example": [".meta/example.rs"]}}`
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, ".exercism", "config.json"), []byte(contents), os.FileMode(0644)))
+
+	config, err := NewExerciseConfig(dir)
+	assert.NoError(t, err)
+	assert.True(t, config.IsSolutionFile("src/lib.rs"))
+	assert.True(t, config.IsSolutionFile("./src/lib.rs"))
+	assert.False(t, config.IsSolutionFile("src/helpers.rs"))
+	assert.True(t, config.IsSupportFile("tests/leap.rs"))
+	assert.True(t, config.IsSupportFile("src/helpers.rs"))
+	assert.False(t, config.IsSupportFile("src/lib.rs"))
+}
+
+func TestIsTestOrToolingFile(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected bool
+	}{
+		{"leap.go", false},
+		{"leap_test.go", true},
+		{"test_leap.py", true},
+		{"leap.spec.js", true},
+		{"LeapTest.java", true},
+		{"src/test/java/LeapTest.java", true},
+		{"src/main/java/Leap.java", false},
+		{"tests/leap.rs", true},
+		{"Cargo.toml", true},
+		{"Leap.csproj", true},
+		{"README.md", true},
+		{"notes.md", false},
+		{"contest.py", false},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, IsTestOrToolingFile(tc.path), tc.path)
+	}
+}
+
+func TestIsDocFile(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected bool
+	}{
+		{"README.md", true},
+		{"HELP.md", true},
+		{".docs/instructions.md", true},
+		{".docs/concepts/strings/about.md", true},
+		{"leap.go", false},
+		{"leap_test.go", false},
+		{"docs/README.md", false},
+		{"notes.md", false},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, IsDocFile(tc.path), tc.path)
+	}
+}
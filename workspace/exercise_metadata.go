@@ -16,6 +16,13 @@ const ignoreSubdir = ".exercism"
 
 var metadataFilepath = filepath.Join(ignoreSubdir, metadataFilename)
 
+// Exercise types. Concept exercises teach a concept and unlock what builds on it;
+// practice exercises are for practicing concepts already learned.
+const (
+	ExerciseTypeConcept  = "concept"
+	ExerciseTypePractice = "practice"
+)
+
 // ExerciseMetadata contains metadata about a user's exercise.
 type ExerciseMetadata struct {
 	Track        string     `json:"track"`
@@ -28,11 +35,15 @@ type ExerciseMetadata struct {
 	SubmittedAt  *time.Time `json:"submitted_at,omitempty"`
 	Dir          string     `json:"-"`
 	AutoApprove  bool       `json:"auto_approve"`
+	Type         string     `json:"type,omitempty"`
+	Difficulty   string     `json:"difficulty,omitempty"`
+	Topics       []string   `json:"topics,omitempty"`
+	Blurb        string     `json:"blurb,omitempty"`
 }
 
 // NewExerciseMetadata reads exercise metadata from a file in the given directory.
 func NewExerciseMetadata(dir string) (*ExerciseMetadata, error) {
-	b, err := ioutil.ReadFile(filepath.Join(dir, metadataFilepath))
+	b, err := ioutil.ReadFile(LongPath(filepath.Join(dir, metadataFilepath)))
 	if err != nil {
 		return nil, err
 	}
@@ -62,17 +73,33 @@ func (em *ExerciseMetadata) String() string {
 	return str
 }
 
+// IsConcept tells whether the exercise is a concept exercise.
+func (em *ExerciseMetadata) IsConcept() bool {
+	return em.Type == ExerciseTypeConcept
+}
+
+// Details summarizes the exercise type and difficulty, e.g. "practice, easy".
+func (em *ExerciseMetadata) Details() string {
+	var details []string
+	for _, detail := range []string{em.Type, em.Difficulty} {
+		if detail != "" {
+			details = append(details, detail)
+		}
+	}
+	return strings.Join(details, ", ")
+}
+
 // Write stores exercise metadata to a file.
 func (em *ExerciseMetadata) Write(dir string) error {
 	b, err := json.Marshal(em)
 	if err != nil {
 		return err
 	}
-	metadataAbsoluteFilepath := filepath.Join(dir, metadataFilepath)
+	metadataAbsoluteFilepath := LongPath(filepath.Join(dir, metadataFilepath))
 	if err = os.MkdirAll(filepath.Dir(metadataAbsoluteFilepath), os.FileMode(0755)); err != nil {
 		return err
 	}
-	if err = ioutil.WriteFile(metadataAbsoluteFilepath, b, os.FileMode(0600)); err != nil {
+	if err = WriteFileAtomically(metadataAbsoluteFilepath, b, os.FileMode(0600)); err != nil {
 		return err
 	}
 	em.Dir = dir
@@ -146,3 +146,19 @@ func TestExerciseMetadataString(t *testing.T) {
 		})
 	}
 }
+
+func TestExerciseMetadataDetails(t *testing.T) {
+	testCases := []struct {
+		metadata ExerciseMetadata
+		details  string
+	}{
+		{metadata: ExerciseMetadata{Type: "practice", Difficulty: "easy"}, details: "practice, easy"},
+		{metadata: ExerciseMetadata{Type: "concept"}, details: "concept"},
+		{metadata: ExerciseMetadata{Difficulty: "hard"}, details: "hard"},
+		{metadata: ExerciseMetadata{}, details: ""},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.details, tc.metadata.Details())
+	}
+}
@@ -0,0 +1,35 @@
+package workspace
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// HeaderPatterns match blocks at the top of files that aren't part of a solution,
+// such as license banners that an employer requires in every file.
+type HeaderPatterns []*regexp.Regexp
+
+// NewHeaderPatterns compiles regular expressions for headers.
+// Each one only matches at the very start of a file.
+func NewHeaderPatterns(patterns []string) (HeaderPatterns, error) {
+	headers := make(HeaderPatterns, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(`\A(?:` + pattern + `)[\r\n]*`)
+		if err != nil {
+			return nil, fmt.Errorf("invalid header pattern %q: %s", pattern, err)
+		}
+		headers = append(headers, re)
+	}
+	return headers, nil
+}
+
+// Strip removes the first header that matches from the start of the contents,
+// along with the blank lines after it. It reports whether there was one.
+func (h HeaderPatterns) Strip(contents []byte) ([]byte, bool) {
+	for _, re := range h {
+		if loc := re.FindIndex(contents); loc != nil && loc[1] > 0 {
+			return contents[loc[1]:], true
+		}
+	}
+	return contents, false
+}
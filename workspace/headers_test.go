@@ -0,0 +1,62 @@
+package workspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaderPatternsStrip(t *testing.T) {
+	headers, err := NewHeaderPatterns([]string{
+		`(?s)/\*.*?Copyright.*?\*/`,
+		`(# SPDX-License-Identifier: .*\n)`,
+	})
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		contents string
+		expected string
+		stripped bool
+	}{
+		{
+			desc:     "block comment banner",
+			contents: "/*\n * Copyright 2020 ACME Corp.\n * All rights reserved.\n */\n\npackage leap\n",
+			expected: "package leap\n",
+			stripped: true,
+		},
+		{
+			desc:     "banner with CRLF line endings",
+			contents: "/* Copyright ACME */\r\n\r\npackage leap\r\n",
+			expected: "package leap\r\n",
+			stripped: true,
+		},
+		{
+			desc:     "line comment",
+			contents: "# SPDX-License-Identifier: MIT\ndef leap_year(year):\n",
+			expected: "def leap_year(year):\n",
+			stripped: true,
+		},
+		{
+			desc:     "banner that isn't at the top",
+			contents: "package leap\n\n/* Copyright ACME */\n",
+			expected: "package leap\n\n/* Copyright ACME */\n",
+		},
+		{
+			desc:     "no banner",
+			contents: "package leap\n",
+			expected: "package leap\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		contents, stripped := headers.Strip([]byte(tc.contents))
+		assert.Equal(t, tc.expected, string(contents), tc.desc)
+		assert.Equal(t, tc.stripped, stripped, tc.desc)
+	}
+}
+
+func TestNewHeaderPatternsRejectsInvalidPatterns(t *testing.T) {
+	_, err := NewHeaderPatterns([]string{"(unclosed"})
+	assert.Error(t, err)
+}
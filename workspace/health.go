@@ -0,0 +1,229 @@
+package workspace
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// skippedDirs are never scanned for exercises, since they tend to be
+// enormous and can't contain exercises of their own.
+var skippedDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+}
+
+// HealthReport describes problems found in a workspace.
+// Each field holds the exercise directories with that kind of problem.
+type HealthReport struct {
+	// Exercises is the number of exercise directories that were found.
+	Exercises int
+	// SlugMismatches are directories named differently from the exercise in their metadata.
+	SlugMismatches []string
+	// LegacyMetadata are directories still using the legacy metadata file.
+	LegacyMetadata []string
+	// DuplicateIDs are directories that share a solution ID, keyed by ID.
+	DuplicateIDs map[string][]string
+	// Unwritable are directories the CLI can't write to.
+	Unwritable []string
+	// Unreadable are directories the CLI can't look inside, so they weren't checked.
+	Unreadable []string
+	// AbnormalNesting are directories that are not where their metadata says they belong.
+	AbnormalNesting []string
+	// RenameTo is what each directory in SlugMismatches should be renamed to.
+	RenameTo map[string]string
+	// MoveTo is where each directory in AbnormalNesting belongs.
+	MoveTo map[string]string
+}
+
+// IsHealthy reports whether no problems were found.
+func (r HealthReport) IsHealthy() bool {
+	return len(r.SlugMismatches) == 0 &&
+		len(r.LegacyMetadata) == 0 &&
+		len(r.DuplicateIDs) == 0 &&
+		len(r.Unwritable) == 0 &&
+		len(r.Unreadable) == 0 &&
+		len(r.AbnormalNesting) == 0
+}
+
+// CheckHealth scans the workspace for exercise directories and checks them for problems.
+func (ws Workspace) CheckHealth() (HealthReport, error) {
+	report := HealthReport{
+		DuplicateIDs: map[string][]string{},
+		RenameTo:     map[string]string{},
+		MoveTo:       map[string]string{},
+	}
+	dirsByID := map[string][]string{}
+
+	err := filepath.Walk(ws.Dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if info != nil && info.IsDir() {
+				report.Unreadable = append(report.Unreadable, path)
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if skippedDirs[info.Name()] || info.Name() == ignoreSubdir {
+			return filepath.SkipDir
+		}
+
+		metadata, legacy, err := readAnyMetadata(path)
+		if err != nil || metadata == nil {
+			return nil
+		}
+		report.Exercises++
+
+		if legacy {
+			report.LegacyMetadata = append(report.LegacyMetadata, path)
+		}
+		if metadata.ExerciseSlug != "" && metadata.ExerciseSlug != filepath.Base(path) {
+			report.SlugMismatches = append(report.SlugMismatches, path)
+			report.RenameTo[path] = filepath.Join(filepath.Dir(path), metadata.ExerciseSlug)
+		}
+		if metadata.ID != "" {
+			dirsByID[metadata.ID] = append(dirsByID[metadata.ID], path)
+		}
+		if !isWritable(path) {
+			report.Unwritable = append(report.Unwritable, path)
+		}
+		expected := ws.ExerciseFor(metadata).Filepath()
+		if filepath.Dir(expected) != filepath.Dir(path) {
+			report.AbnormalNesting = append(report.AbnormalNesting, path)
+			report.MoveTo[path] = expected
+		}
+		return nil
+	})
+	if err != nil {
+		return HealthReport{}, err
+	}
+
+	for id, dirs := range dirsByID {
+		if len(dirs) > 1 {
+			sort.Strings(dirs)
+			report.DuplicateIDs[id] = dirs
+		}
+	}
+	return report, nil
+}
+
+// readAnyMetadata reads the exercise metadata in the directory, if there is any.
+// It reports whether the metadata came from a legacy metadata file.
+func readAnyMetadata(dir string) (*ExerciseMetadata, bool, error) {
+	metadata, err := NewExerciseMetadata(dir)
+	if err == nil {
+		return metadata, false, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, false, err
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, legacyMetadataFilename))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	metadata = &ExerciseMetadata{}
+	if err := json.Unmarshal(b, metadata); err != nil {
+		return nil, true, err
+	}
+	metadata.Dir = dir
+	return metadata, true, nil
+}
+
+// isWritable checks whether files can be created in the directory.
+func isWritable(dir string) bool {
+	f, err := ioutil.TempFile(dir, ".exercism-write-check")
+	if err != nil {
+		return false
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return true
+}
+
+// CopiesOf finds the other directories next to the exercise directory that have metadata
+// for the same solution, such as a copy made by hand to keep a backup.
+// Only the directory's siblings are read, so that it is quick enough to check on every submit.
+func CopiesOf(dir, solutionID string) ([]string, error) {
+	if solutionID == "" {
+		return nil, nil
+	}
+	parent := filepath.Dir(dir)
+	infos, err := ioutil.ReadDir(LongPath(parent))
+	if err != nil {
+		return nil, err
+	}
+	var copies []string
+	for _, info := range infos {
+		sibling := filepath.Join(parent, info.Name())
+		if !info.IsDir() || sibling == dir {
+			continue
+		}
+		metadata, _, err := readAnyMetadata(sibling)
+		if err != nil || metadata == nil {
+			continue
+		}
+		if metadata.ID == solutionID {
+			copies = append(copies, sibling)
+		}
+	}
+	return copies, nil
+}
+
+// SameFiles tells whether two exercise directories have the same files with the same contents,
+// leaving out what the CLI keeps in .exercism, which records what happened in each of them.
+func SameFiles(a, b string) (bool, error) {
+	as, err := solutionChecksums(a)
+	if err != nil {
+		return false, err
+	}
+	bs, err := solutionChecksums(b)
+	if err != nil {
+		return false, err
+	}
+	if len(as) != len(bs) {
+		return false, nil
+	}
+	for path, sum := range as {
+		if bs[path] != sum {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func solutionChecksums(dir string) (Checksums, error) {
+	sums := Checksums{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ignoreSubdir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Name() == legacyMetadataFilename || IsJunkFile(info.Name()) {
+			return nil
+		}
+		b, err := ioutil.ReadFile(LongPath(path))
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		sums[filepath.ToSlash(rel)] = Checksum(b)
+		return nil
+	})
+	return sums, err
+}
@@ -0,0 +1,127 @@
+package workspace
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckHealth(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "health")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	ws, err := New(tmpDir)
+	assert.NoError(t, err)
+
+	write := func(dir, track, slug, id string, legacy bool) string {
+		dir = filepath.Join(ws.Dir, dir)
+		metadata := &ExerciseMetadata{Track: track, ExerciseSlug: slug, ID: id, IsRequester: true}
+		err := metadata.Write(dir)
+		assert.NoError(t, err)
+		if legacy {
+			err = os.Rename(filepath.Join(dir, metadataFilepath), filepath.Join(dir, legacyMetadataFilename))
+			assert.NoError(t, err)
+		}
+		return dir
+	}
+
+	healthy := write(filepath.Join("go", "leap"), "go", "leap", "id-1", false)
+	renamed := write(filepath.Join("go", "leap-copy"), "go", "leap", "id-1", false)
+	legacy := write(filepath.Join("go", "bob"), "go", "bob", "id-2", true)
+	nested := write(filepath.Join("go", "bob", "go", "hamming"), "go", "hamming", "id-3", false)
+	// Misnamed and misplaced, it belongs elsewhere on both counts.
+	both := write(filepath.Join("go", "bob", "go", "clock-copy"), "go", "clock", "id-5", false)
+
+	// It doesn't look inside dependency directories.
+	write(filepath.Join("go", "leap", "node_modules", "x"), "go", "x", "id-4", false)
+
+	report, err := ws.CheckHealth()
+	assert.NoError(t, err)
+
+	assert.Equal(t, 5, report.Exercises)
+	assert.Equal(t, []string{both, renamed}, report.SlugMismatches)
+	assert.Equal(t, []string{legacy}, report.LegacyMetadata)
+	assert.Equal(t, map[string][]string{"id-1": {healthy, renamed}}, report.DuplicateIDs)
+	assert.Equal(t, []string{both, nested}, report.AbnormalNesting)
+	assert.Equal(t, healthy, report.RenameTo[renamed])
+	assert.Equal(t, filepath.Join(ws.Dir, "go", "bob", "go", "clock"), report.RenameTo[both])
+	assert.Equal(t, filepath.Join(ws.Dir, "go", "hamming"), report.MoveTo[nested])
+	assert.Equal(t, filepath.Join(ws.Dir, "go", "clock"), report.MoveTo[both])
+	assert.False(t, report.IsHealthy())
+
+	if runtime.GOOS != "windows" && os.Getuid() != 0 {
+		err = os.Chmod(healthy, os.FileMode(0555))
+		assert.NoError(t, err)
+		defer os.Chmod(healthy, os.FileMode(0755))
+
+		report, err = ws.CheckHealth()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{healthy}, report.Unwritable)
+		assert.Empty(t, report.Unreadable)
+
+		// A directory that can't be read isn't taken for one that can't be written to.
+		assert.NoError(t, os.Chmod(healthy, os.FileMode(0755)))
+		assert.NoError(t, os.Chmod(legacy, os.FileMode(0)))
+		defer os.Chmod(legacy, os.FileMode(0755))
+
+		report, err = ws.CheckHealth()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{legacy}, report.Unreadable)
+		assert.Empty(t, report.Unwritable)
+	}
+}
+
+func TestCheckHealthOfHealthyWorkspace(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "healthy")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	ws, err := New(tmpDir)
+	assert.NoError(t, err)
+
+	metadata := &ExerciseMetadata{Track: "go", ExerciseSlug: "leap", ID: "id-1", IsRequester: true}
+	assert.NoError(t, metadata.Write(filepath.Join(ws.Dir, "go", "leap")))
+
+	metadata = &ExerciseMetadata{Track: "go", ExerciseSlug: "leap", ID: "id-2", Handle: "alice"}
+	assert.NoError(t, metadata.Write(filepath.Join(ws.Dir, "users", "alice", "go", "leap")))
+
+	report, err := ws.CheckHealth()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.Exercises)
+	assert.True(t, report.IsHealthy())
+}
+
+func TestCopiesOf(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "copies")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	write := func(dir, id, contents string) string {
+		dir = filepath.Join(tmpDir, "go", dir)
+		assert.NoError(t, (&ExerciseMetadata{Track: "go", ExerciseSlug: "leap", ID: id}).Write(dir))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "leap.go"), []byte(contents), os.FileMode(0644)))
+		return dir
+	}
+	leap := write("leap", "id-1", "package leap")
+	same := write("leap-backup", "id-1", "package leap")
+	changed := write("leap-2", "id-1", "package leap // changed")
+	write("clock", "id-2", "package clock")
+
+	copies, err := CopiesOf(leap, "id-1")
+	assert.NoError(t, err)
+	sort.Strings(copies)
+	assert.Equal(t, []string{changed, same}, copies)
+
+	ok, err := SameFiles(leap, same)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = SameFiles(leap, changed)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,151 @@
+package workspace
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"strings"
+)
+
+// IgnoreFilename is the name of the file that lists more patterns of files not to submit,
+// in the workspace or in an exercise directory.
+const IgnoreFilename = ".exercismignore"
+
+// commonIgnorePatterns are ignored in every track.
+var commonIgnorePatterns = []string{".git/", ignoreSubdir + "/", IgnoreFilename}
+
+// junkPatterns match the files that editors, operating systems and version control leave
+// next to the files they are about, which are never part of a solution.
+var junkPatterns = IgnorePatterns{
+	".DS_Store", "._*", "Thumbs.db", "desktop.ini",
+	"*.swp", "*.swo", "*~", ".#*", "#*#", "*.orig", "*.rej",
+	".git/", ".hg/", ".svn/", ".bzr/", ".idea/", ".vscode/",
+}
+
+// defaultIgnorePatterns are the build output and dependencies of each track,
+// which don't belong in a submission.
+var defaultIgnorePatterns = map[string][]string{
+	"c":            {"build/", "*.o"},
+	"clojure":      {"target/", ".cpcache/"},
+	"cpp":          {"build/", "*.o"},
+	"crystal":      {"lib/", ".crystal/"},
+	"csharp":       {"bin/", "obj/"},
+	"dart":         {".dart_tool/", "build/"},
+	"elixir":       {"_build/", "deps/"},
+	"elm":          {"elm-stuff/", "node_modules/"},
+	"erlang":       {"_build/"},
+	"fsharp":       {"bin/", "obj/"},
+	"go":           {"vendor/"},
+	"haskell":      {".stack-work/", "dist-newstyle/"},
+	"java":         {"build/", "target/", ".gradle/", "*.class"},
+	"javascript":   {"node_modules/", "coverage/"},
+	"julia":        {"Manifest.toml"},
+	"kotlin":       {"build/", ".gradle/", "*.class"},
+	"ocaml":        {"_build/"},
+	"purescript":   {".spago/", "output/", "node_modules/"},
+	"python":       {"__pycache__/", ".pytest_cache/", "*.pyc"},
+	"reasonml":     {"node_modules/", "lib/"},
+	"ruby":         {".bundle/"},
+	"rust":         {"target/"},
+	"scala":        {"target/", "project/target/", ".bsp/"},
+	"swift":        {".build/"},
+	"typescript":   {"node_modules/", "coverage/"},
+	"vbnet":        {"bin/", "obj/"},
+	"zig":          {"zig-cache/", "zig-out/"},
+	"common-lisp":  {"*.fasl"},
+	"objective-c":  {"build/"},
+	"emacs-lisp":   {"*.elc"},
+	"coffeescript": {"node_modules/"},
+}
+
+// IgnorePatterns match the files in an exercise that shouldn't be submitted.
+// A pattern ending in a slash matches a directory and everything in it.
+// A pattern with a slash elsewhere is matched against the path from the exercise
+// directory, and any other pattern against the file name or a directory name, e.g. *.pyc.
+// A pattern starting with ! brings back the files that earlier patterns matched, as in .gitignore.
+type IgnorePatterns []string
+
+// DefaultIgnorePatterns returns the patterns that are ignored in a track, unless configured otherwise.
+func DefaultIgnorePatterns(track string) IgnorePatterns {
+	patterns := append(IgnorePatterns{}, commonIgnorePatterns...)
+	return append(patterns, defaultIgnorePatterns[track]...)
+}
+
+// IsJunkFile tells whether the path is a file left behind by an editor, the operating system
+// or version control. The path is relative to the exercise directory and uses forward slashes.
+func IsJunkFile(relPath string) bool {
+	return junkPatterns.Match(relPath) != ""
+}
+
+// ReadIgnoreFile reads the patterns in an ignore file, one per line, in the syntax of .gitignore.
+// Blank lines and comments starting with # are left out.
+func ReadIgnoreFile(filename string) (IgnorePatterns, error) {
+	f, err := os.Open(LongPath(filename))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var patterns IgnorePatterns
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// A leading **/ matches in any directory, which a pattern without a slash already does.
+		line = strings.Replace(line, "**/", "", 1)
+		patterns = append(patterns, line)
+	}
+	return patterns, scanner.Err()
+}
+
+// Match returns the pattern that matches the path, which is relative to the exercise directory
+// and uses forward slashes. It returns blank if no pattern matches.
+func (p IgnorePatterns) Match(relPath string) string {
+	relPath = strings.TrimPrefix(path.Clean(relPath), "/")
+	parts := strings.Split(relPath, "/")
+	var matched string
+	for _, pattern := range p {
+		if negated := strings.TrimPrefix(pattern, "!"); negated != pattern {
+			if matchPattern(negated, relPath, parts) {
+				matched = ""
+			}
+			continue
+		}
+		if matched == "" && matchPattern(pattern, relPath, parts) {
+			matched = pattern
+		}
+	}
+	return matched
+}
+
+func matchPattern(pattern, relPath string, parts []string) bool {
+	if dir := strings.TrimSuffix(pattern, "/"); dir != pattern {
+		return matchDir(dir, parts[:len(parts)-1])
+	}
+	if strings.Contains(pattern, "/") {
+		ok, _ := path.Match(strings.TrimPrefix(pattern, "/"), relPath)
+		return ok
+	}
+	return matchDir(pattern, parts)
+}
+
+// matchDir tells whether the directory pattern matches any of the directories in a path,
+// or, if it has a slash in it, the directories the path starts with.
+func matchDir(pattern string, dirs []string) bool {
+	if strings.Contains(pattern, "/") {
+		n := strings.Count(strings.Trim(pattern, "/"), "/") + 1
+		if len(dirs) < n {
+			return false
+		}
+		ok, _ := path.Match(strings.Trim(pattern, "/"), strings.Join(dirs[:n], "/"))
+		return ok
+	}
+	for _, dir := range dirs {
+		if ok, _ := path.Match(pattern, dir); ok {
+			return true
+		}
+	}
+	return false
+}
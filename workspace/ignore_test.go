@@ -0,0 +1,77 @@
+package workspace
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIgnorePatterns(t *testing.T) {
+	patterns := IgnorePatterns{"target/", "project/target/", "*.pyc", "docs/*.html"}
+
+	testCases := []struct {
+		path, pattern string
+	}{
+		{"src/lib.rs", ""},
+		{"target/debug/app", "target/"},
+		{"nested/target/out.txt", "target/"},
+		{"target", ""},
+		{"project/target/x", "target/"},
+		{"__pycache__/leap.cpython-37.pyc", "*.pyc"},
+		{"docs/index.html", "docs/*.html"},
+		{"src/docs/index.html", ""},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.pattern, patterns.Match(tc.path), tc.path)
+	}
+
+	assert.Equal(t, "project/target/", IgnorePatterns{"project/target/"}.Match("project/target/x"))
+	assert.Equal(t, "", IgnorePatterns{"project/target/"}.Match("other/project/target/x"))
+}
+
+func TestDefaultIgnorePatterns(t *testing.T) {
+	rust := DefaultIgnorePatterns("rust")
+	assert.Equal(t, "target/", rust.Match("target/debug/leap"))
+	assert.Equal(t, ".exercism/", rust.Match(".exercism/metadata.json"))
+	assert.Equal(t, "", rust.Match("src/lib.rs"))
+
+	assert.Equal(t, "node_modules/", DefaultIgnorePatterns("javascript").Match("node_modules/jest/index.js"))
+	assert.Equal(t, "bin/", DefaultIgnorePatterns("csharp").Match("bin/Debug/Leap.dll"))
+	assert.Equal(t, "", DefaultIgnorePatterns("unknown-track").Match("bin/Debug/Leap.dll"))
+}
+
+func TestIsJunkFile(t *testing.T) {
+	for _, path := range []string{".DS_Store", "src/.DS_Store", "Thumbs.db", ".leap.go.swp", "leap.go~", "#leap.el#", ".git/HEAD", "src/.idea/workspace.xml"} {
+		assert.True(t, IsJunkFile(path), path)
+	}
+	for _, path := range []string{"leap.go", "src/lib.rs", "gitignore.txt", "swp.go"} {
+		assert.False(t, IsJunkFile(path), path)
+	}
+}
+
+func TestIgnorePatternsNegation(t *testing.T) {
+	patterns := IgnorePatterns{"*.log", "!keep.log", "coverage"}
+	assert.Equal(t, "*.log", patterns.Match("debug.log"))
+	assert.Equal(t, "", patterns.Match("keep.log"))
+	assert.Equal(t, "coverage", patterns.Match("coverage/index.html"))
+	assert.Equal(t, "", patterns.Match("src/leap.go"))
+}
+
+func TestReadIgnoreFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignore-file")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, IgnoreFilename)
+	_, err = ReadIgnoreFile(filename)
+	assert.True(t, os.IsNotExist(err))
+
+	contents := "# generated\n\nbin/\n  **/coverage/  \n!keep.log\n"
+	assert.NoError(t, ioutil.WriteFile(filename, []byte(contents), os.FileMode(0644)))
+	patterns, err := ReadIgnoreFile(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, IgnorePatterns{"bin/", "coverage/", "!keep.log"}, patterns)
+}
@@ -0,0 +1,80 @@
+package workspace
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const iterationsDirname = "iterations"
+
+var iterationsDirpath = filepath.Join(ignoreSubdir, iterationsDirname)
+
+// IterationRecord is what an exercise directory keeps about one of the iterations submitted from it,
+// so that its history can be seen without asking the API.
+type IterationRecord struct {
+	SubmittedAt time.Time `json:"submitted_at"`
+	IterationID string    `json:"iteration_id,omitempty"`
+	// Index is the number of the iteration on the website, if the API said.
+	Index   int    `json:"index,omitempty"`
+	Message string `json:"message,omitempty"`
+	// Files are the checksums of the files as they were on disk when they were submitted.
+	Files Checksums `json:"files"`
+	// Response is the API's response to the submission, as it was given.
+	Response json.RawMessage `json:"response,omitempty"`
+}
+
+// IterationsDir is the absolute path to where the exercise in the given directory
+// keeps the records of its iterations.
+func IterationsDir(dir string) string {
+	return filepath.Join(dir, iterationsDirpath)
+}
+
+// Write stores the record in the given exercise directory, in a file named after when it was submitted.
+func (r IterationRecord) Write(dir string) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := LongPath(filepath.Join(IterationsDir(dir), r.SubmittedAt.UTC().Format("20060102T150405.000000000Z")+".json"))
+	if err = os.MkdirAll(filepath.Dir(path), os.FileMode(0755)); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, os.FileMode(0644))
+}
+
+// NewIterationRecords reads the records of the iterations of the exercise in the given directory,
+// oldest first. Exercises that nothing has been submitted from have none.
+func NewIterationRecords(dir string) ([]IterationRecord, error) {
+	infos, err := ioutil.ReadDir(LongPath(IterationsDir(dir)))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var records []IterationRecord
+	for _, info := range infos {
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".json") {
+			continue
+		}
+		b, err := ioutil.ReadFile(LongPath(filepath.Join(IterationsDir(dir), info.Name())))
+		if err != nil {
+			return nil, err
+		}
+		var record IterationRecord
+		if err := json.Unmarshal(b, &record); err != nil {
+			// A damaged record shouldn't hide the others.
+			continue
+		}
+		records = append(records, record)
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].SubmittedAt.Before(records[j].SubmittedAt)
+	})
+	return records, nil
+}
@@ -0,0 +1,35 @@
+package workspace
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIterationRecords(t *testing.T) {
+	dir, err := ioutil.TempDir("", "iterations")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	records, err := NewIterationRecords(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+
+	first := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	second := IterationRecord{SubmittedAt: first.Add(time.Hour), Index: 2, Files: Checksums{}}
+	second.Files.Set("leap.go", []byte("package leap"))
+	assert.NoError(t, second.Write(dir))
+	assert.NoError(t, IterationRecord{SubmittedAt: first, Index: 1, Response: []byte(`{"iteration":{"idx":1}}`)}.Write(dir))
+
+	records, err = NewIterationRecords(dir)
+	assert.NoError(t, err)
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, 1, records[0].Index)
+		assert.JSONEq(t, `{"iteration":{"idx":1}}`, string(records[0].Response))
+		assert.Equal(t, 2, records[1].Index)
+		assert.Equal(t, Checksum([]byte("package leap")), records[1].Files["leap.go"])
+	}
+}
@@ -0,0 +1,95 @@
+package workspace
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// LineEndings is a policy for the line endings of exercise files,
+// applied when they are downloaded and when they are submitted.
+//
+//	auto     downloads are written as they are; submissions are converted to LF on Windows only
+//	lf       downloads and submissions are converted to LF
+//	crlf     downloads and submissions are converted to CRLF
+//	native   downloads get the platform's line endings; submissions are converted to LF
+type LineEndings string
+
+// The line ending policies.
+const (
+	LineEndingsAuto   LineEndings = "auto"
+	LineEndingsLF     LineEndings = "lf"
+	LineEndingsCRLF   LineEndings = "crlf"
+	LineEndingsNative LineEndings = "native"
+)
+
+// Line endings.
+const (
+	lf   = "\n"
+	crlf = "\r\n"
+)
+
+// NewLineEndings reads a line ending policy. The default is auto.
+func NewLineEndings(s string) (LineEndings, error) {
+	policy := LineEndings(strings.ToLower(strings.TrimSpace(s)))
+	switch policy {
+	case "":
+		return LineEndingsAuto, nil
+	case LineEndingsAuto, LineEndingsLF, LineEndingsCRLF, LineEndingsNative:
+		return policy, nil
+	}
+	return "", fmt.Errorf("unknown line endings '%s', use one of auto, lf, crlf and native", s)
+}
+
+// ForDownload is the line ending downloaded files get, or blank to leave them as they are.
+func (le LineEndings) ForDownload() string {
+	switch le {
+	case LineEndingsLF:
+		return lf
+	case LineEndingsCRLF:
+		return crlf
+	case LineEndingsNative:
+		if runtime.GOOS == "windows" {
+			return crlf
+		}
+		return lf
+	}
+	return ""
+}
+
+// ForSubmission is the line ending submitted files get, or blank to submit them as they are.
+// Left to auto, only files on Windows are converted, where editors are apt to add CRLF line endings.
+func (le LineEndings) ForSubmission() string {
+	switch le {
+	case LineEndingsCRLF:
+		return crlf
+	case LineEndingsAuto:
+		if runtime.GOOS == "windows" {
+			return lf
+		}
+		return ""
+	}
+	return lf
+}
+
+// ConvertLineEndings gives all lines the given line ending, and tells whether anything changed.
+// Binary files, and a blank line ending, leave the contents as they are.
+func ConvertLineEndings(contents []byte, ending string) ([]byte, bool) {
+	if ending == "" || bytes.IndexByte(contents, 0) >= 0 {
+		return contents, false
+	}
+	converted := bytes.Replace(contents, []byte(crlf), []byte(lf), -1)
+	if ending == crlf {
+		converted = bytes.Replace(converted, []byte(lf), []byte(crlf), -1)
+	}
+	return converted, !bytes.Equal(converted, contents)
+}
+
+// LineEndingName names a line ending for people to read.
+func LineEndingName(ending string) string {
+	if ending == crlf {
+		return "CRLF"
+	}
+	return "LF"
+}
@@ -0,0 +1,56 @@
+package workspace
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLineEndings(t *testing.T) {
+	policy, err := NewLineEndings("")
+	assert.NoError(t, err)
+	assert.Equal(t, LineEndingsAuto, policy)
+
+	policy, err = NewLineEndings(" CRLF ")
+	assert.NoError(t, err)
+	assert.Equal(t, LineEndingsCRLF, policy)
+
+	_, err = NewLineEndings("cr")
+	assert.Error(t, err)
+}
+
+func TestLineEndingsPolicies(t *testing.T) {
+	assert.Equal(t, "", LineEndingsAuto.ForDownload())
+	if runtime.GOOS == "windows" {
+		assert.Equal(t, "\n", LineEndingsAuto.ForSubmission())
+	} else {
+		assert.Equal(t, "", LineEndingsAuto.ForSubmission())
+	}
+	assert.Equal(t, "\n", LineEndingsLF.ForDownload())
+	assert.Equal(t, "\n", LineEndingsLF.ForSubmission())
+	assert.Equal(t, "\r\n", LineEndingsCRLF.ForDownload())
+	assert.Equal(t, "\r\n", LineEndingsCRLF.ForSubmission())
+	assert.Equal(t, "\n", LineEndingsNative.ForSubmission())
+}
+
+func TestConvertLineEndings(t *testing.T) {
+	testCases := []struct {
+		desc, in, ending, out string
+		changed               bool
+	}{
+		{"CRLF to LF", "a\r\nb\r\n", "\n", "a\nb\n", true},
+		{"mixed to LF", "a\r\nb\n", "\n", "a\nb\n", true},
+		{"LF to CRLF", "a\nb", "\r\n", "a\r\nb", true},
+		{"mixed to CRLF", "a\r\nb\n", "\r\n", "a\r\nb\r\n", true},
+		{"already LF", "a\nb\n", "\n", "a\nb\n", false},
+		{"left alone", "a\r\nb\n", "", "a\r\nb\n", false},
+		{"binary", "a\r\n\x00b", "\n", "a\r\n\x00b", false},
+	}
+
+	for _, tc := range testCases {
+		out, changed := ConvertLineEndings([]byte(tc.in), tc.ending)
+		assert.Equal(t, tc.out, string(out), tc.desc)
+		assert.Equal(t, tc.changed, changed, tc.desc)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+// +build !windows
+
+package workspace
+
+// LongPath returns the path as it is. Only Windows limits the length of paths.
+func LongPath(path string) string {
+	return path
+}
@@ -0,0 +1,16 @@
+//go:build !windows
+// +build !windows
+
+package workspace
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLongPath(t *testing.T) {
+	path := "/exercism/" + strings.Repeat("a-long-exercise-name/", 15)
+	assert.Equal(t, path, LongPath(path))
+}
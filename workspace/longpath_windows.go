@@ -0,0 +1,26 @@
+package workspace
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// maxPath is the longest path Windows handles without the long path prefix.
+// Directories are limited to 248 characters, leaving room for an 8.3 file name.
+const maxPath = 248
+
+// LongPath lets Windows handle paths longer than MAX_PATH, which deep track
+// layouts with long exercise names easily exceed. It gives long absolute paths
+// the \\?\ prefix, which lifts the limit. Other paths are returned as they are.
+func LongPath(path string) string {
+	if len(path) < maxPath || !filepath.IsAbs(path) || strings.HasPrefix(path, `\\?\`) {
+		return path
+	}
+	// Windows doesn't normalize prefixed paths, so they have to be clean already.
+	path = filepath.Clean(path)
+	if strings.HasPrefix(path, `\\`) {
+		// A UNC path, \\server\share\...
+		return `\\?\UNC\` + path[2:]
+	}
+	return `\\?\` + path
+}
@@ -0,0 +1,26 @@
+package workspace
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLongPath(t *testing.T) {
+	long := strings.Repeat("a-long-exercise-name\\", 15)
+
+	testCases := []struct {
+		desc, in, out string
+	}{
+		{"short paths are left alone", `C:\exercism\go\clock`, `C:\exercism\go\clock`},
+		{"relative paths are left alone", long, long},
+		{"long paths get the prefix", `C:\exercism\` + long, `\\?\C:\exercism\` + strings.TrimSuffix(long, `\`)},
+		{"long UNC paths get the UNC prefix", `\\server\share\` + long, `\\?\UNC\server\share\` + strings.TrimSuffix(long, `\`)},
+		{"prefixed paths are left alone", `\\?\C:\exercism\` + long, `\\?\C:\exercism\` + long},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.out, LongPath(tc.in), tc.desc)
+	}
+}
@@ -0,0 +1,33 @@
+package workspace
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const originalsDirname = "originals"
+
+var originalsDirpath = filepath.Join(ignoreSubdir, originalsDirname)
+
+// OriginalsDir is the absolute path to where the exercise in the given directory
+// keeps the files as they were downloaded.
+// They are the common ancestor when merging updated files with local changes.
+func OriginalsDir(dir string) string {
+	return filepath.Join(dir, originalsDirpath)
+}
+
+// ReadOriginal returns a file as it was downloaded, given its path relative to the exercise directory.
+// Exercises downloaded before originals were kept have none, in which case the error satisfies os.IsNotExist.
+func ReadOriginal(dir, path string) ([]byte, error) {
+	return ioutil.ReadFile(LongPath(filepath.Join(OriginalsDir(dir), path)))
+}
+
+// WriteOriginal keeps a file as it was downloaded, given its path relative to the exercise directory.
+func WriteOriginal(dir, path string, contents []byte) error {
+	target := LongPath(filepath.Join(OriginalsDir(dir), path))
+	if err := os.MkdirAll(filepath.Dir(target), os.FileMode(0755)); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(target, contents, os.FileMode(0644))
+}
@@ -0,0 +1,25 @@
+package workspace
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOriginals(t *testing.T) {
+	dir, err := ioutil.TempDir("", "originals")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = ReadOriginal(dir, "main.go")
+	assert.True(t, os.IsNotExist(err))
+
+	path := filepath.Join("sub", "main.go")
+	assert.NoError(t, WriteOriginal(dir, path, []byte("package main")))
+	b, err := ReadOriginal(dir, path)
+	assert.NoError(t, err)
+	assert.Equal(t, "package main", string(b))
+}
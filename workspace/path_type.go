@@ -42,7 +42,7 @@ func DetectPathType(path string) (PathType, error) {
 
 	// If it's a symlink, resolve it.
 	if info.Mode()&os.ModeSymlink == os.ModeSymlink {
-		src, err := filepath.EvalSymlinks(path)
+		src, err := EvalSymlinks(path)
 		if err != nil {
 			return -1, err
 		}
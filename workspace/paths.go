@@ -0,0 +1,43 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// caseInsensitivePaths is set where paths that differ only in case refer to the same file,
+// as on Windows, where the same network share is often spelled several ways.
+var caseInsensitivePaths = runtime.GOOS == "windows"
+
+// EvalSymlinks returns the path after evaluating any symlinks, like filepath.EvalSymlinks.
+// Some network shares can't evaluate links, so if the path exists but can't be evaluated,
+// it returns the cleaned path as it is.
+func EvalSymlinks(path string) (string, error) {
+	evaluated, err := filepath.EvalSymlinks(path)
+	if err == nil {
+		return evaluated, nil
+	}
+	if _, lerr := os.Lstat(LongPath(path)); lerr != nil {
+		return "", err
+	}
+	return filepath.Clean(path), nil
+}
+
+// SamePath tells whether two cleaned paths are the same, ignoring case where the file system does.
+func SamePath(a, b string) bool {
+	if caseInsensitivePaths {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// HasPathPrefix tells whether the path is the given directory or is inside it.
+func HasPathPrefix(path, dir string) bool {
+	if len(path) < len(dir) || !SamePath(path[:len(dir)], dir) {
+		return false
+	}
+	// Make sure the prefix ends at a path element, so that /ws-old isn't taken to be in /ws.
+	return len(path) == len(dir) || os.IsPathSeparator(path[len(dir)]) || os.IsPathSeparator(dir[len(dir)-1])
+}
@@ -0,0 +1,77 @@
+package workspace
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasPathPrefix(t *testing.T) {
+	defer func(original bool) { caseInsensitivePaths = original }(caseInsensitivePaths)
+
+	dir := filepath.Join("share", "workspace")
+	testCases := []struct {
+		desc            string
+		path            string
+		sensitive, fold bool
+	}{
+		{"the directory itself", dir, true, true},
+		{"a file inside", filepath.Join(dir, "go", "clock"), true, true},
+		{"a sibling with the same prefix", dir + "-old", false, false},
+		{"a different case", filepath.Join("Share", "Workspace", "go"), false, true},
+		{"a parent", "share", false, false},
+	}
+
+	for _, tc := range testCases {
+		caseInsensitivePaths = false
+		assert.Equal(t, tc.sensitive, HasPathPrefix(tc.path, dir), tc.desc)
+		caseInsensitivePaths = true
+		assert.Equal(t, tc.fold, HasPathPrefix(tc.path, dir), tc.desc)
+	}
+
+	root := string(filepath.Separator)
+	assert.True(t, HasPathPrefix(filepath.Join(root, "workspace"), root))
+}
+
+func TestEvalSymlinks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eval-symlinks")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	expected, err := filepath.EvalSymlinks(dir)
+	assert.NoError(t, err)
+	path, err := EvalSymlinks(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, path)
+
+	_, err = EvalSymlinks(filepath.Join(dir, "no-such-dir"))
+	assert.Error(t, err)
+}
+
+func TestExerciseDirIgnoresCase(t *testing.T) {
+	defer func(original bool) { caseInsensitivePaths = original }(caseInsensitivePaths)
+	caseInsensitivePaths = true
+
+	dir, err := ioutil.TempDir("", "exercise-dir")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	exercise := filepath.Join(dir, "go", "clock")
+	assert.NoError(t, os.MkdirAll(exercise, os.FileMode(0755)))
+	metadata := ExerciseMetadata{Track: "go", ExerciseSlug: "clock"}
+	assert.NoError(t, metadata.Write(exercise))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(exercise, "clock.go"), []byte("package clock"), os.FileMode(0644)))
+
+	// The workspace is spelled differently, as network shares often are.
+	ws := Workspace{Dir: strings.ToUpper(dir)}
+	found, err := ws.ExerciseDir(filepath.Join(exercise, "clock.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, exercise, found)
+
+	_, err = ws.ExerciseDir(filepath.Join(dir, "go"))
+	assert.True(t, IsMissingMetadata(err))
+}
@@ -0,0 +1,101 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Kinds of problem that a scan of the workspace finds.
+const (
+	// ScanMissingMetadata is an exercise directory without metadata.
+	ScanMissingMetadata = "missing_metadata"
+	// ScanCorruptMetadata is an exercise directory whose metadata can't be read.
+	ScanCorruptMetadata = "corrupt_metadata"
+	// ScanOrphanedLegacyMetadata is a legacy metadata file left behind next to the metadata.
+	ScanOrphanedLegacyMetadata = "orphaned_legacy_metadata"
+	// ScanUnreadableFile is a file in an exercise that can't be read.
+	ScanUnreadableFile = "unreadable_file"
+)
+
+// helpFilename is the file that every downloaded exercise comes with,
+// so a directory with it is an exercise even if its metadata is gone.
+const helpFilename = "HELP.md"
+
+// ScanIssue is a problem found in an exercise directory.
+type ScanIssue struct {
+	Kind string
+	// Dir is the exercise directory.
+	Dir string
+	// Path is the file with the problem.
+	Path string
+	Err  error
+}
+
+// Scan walks the workspace looking for exercises that are damaged: ones whose metadata
+// is missing or can't be read, that have legacy metadata left behind, or that have files
+// that can't be read. Unlike CheckHealth, it also looks at exercises it can't read the metadata of.
+func (ws Workspace) Scan() ([]ScanIssue, error) {
+	var issues []ScanIssue
+	err := filepath.Walk(ws.Dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			issues = append(issues, ScanIssue{Kind: ScanUnreadableFile, Dir: filepath.Dir(path), Path: path, Err: err})
+			if info != nil && info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.IsDir() {
+			if f, err := os.Open(LongPath(path)); err != nil {
+				issues = append(issues, ScanIssue{Kind: ScanUnreadableFile, Dir: filepath.Dir(path), Path: path, Err: err})
+			} else {
+				f.Close()
+			}
+			return nil
+		}
+		if skippedDirs[info.Name()] {
+			return filepath.SkipDir
+		}
+		if path == ws.Dir || info.Name() == ignoreSubdir {
+			return nil
+		}
+		issues = append(issues, scanExerciseDir(path)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+// scanExerciseDir checks the metadata of the directory, if it is an exercise.
+func scanExerciseDir(dir string) []ScanIssue {
+	exercise := NewExerciseFromDir(dir)
+	metadataPath := exercise.MetadataFilepath()
+	legacyPath := exercise.LegacyMetadataFilepath()
+	hasMetadata, _ := exercise.HasMetadata()
+	hasLegacy, _ := exercise.HasLegacyMetadata()
+
+	if hasMetadata {
+		var issues []ScanIssue
+		if _, err := NewExerciseMetadata(dir); err != nil {
+			issues = append(issues, ScanIssue{Kind: ScanCorruptMetadata, Dir: dir, Path: metadataPath, Err: err})
+		}
+		if hasLegacy {
+			issues = append(issues, ScanIssue{Kind: ScanOrphanedLegacyMetadata, Dir: dir, Path: legacyPath})
+		}
+		return issues
+	}
+	if hasLegacy {
+		// Legacy metadata is moved to where it belongs when the exercise is submitted.
+		if _, _, err := readAnyMetadata(dir); err != nil {
+			return []ScanIssue{{Kind: ScanCorruptMetadata, Dir: dir, Path: legacyPath, Err: err}}
+		}
+		return nil
+	}
+	_, errConfig := os.Lstat(LongPath(filepath.Join(dir, exerciseConfigFilepath)))
+	_, errHelp := os.Lstat(LongPath(filepath.Join(dir, helpFilename)))
+	if errConfig == nil || errHelp == nil {
+		return []ScanIssue{{Kind: ScanMissingMetadata, Dir: dir, Path: metadataPath}}
+	}
+	return nil
+}
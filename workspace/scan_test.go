@@ -0,0 +1,46 @@
+package workspace
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScan(t *testing.T) {
+	root, err := ioutil.TempDir("", "scan")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	write := func(path, contents string) {
+		path = filepath.Join(root, filepath.FromSlash(path))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), os.FileMode(0755)))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(contents), os.FileMode(0644)))
+	}
+	write("go/healthy/.exercism/metadata.json", `{"track": "go", "exercise": "healthy"}`)
+	write("go/healthy/healthy.go", "package healthy")
+	write("go/missing/HELP.md", "# Help")
+	write("go/corrupt/.exercism/metadata.json", `{"track": `)
+	write("go/orphan/.exercism/metadata.json", `{"track": "go", "exercise": "orphan"}`)
+	write("go/orphan/.solution.json", `{"track": "go", "exercise": "orphan"}`)
+	write("go/legacy/.solution.json", `{"track": "go", "exercise": "legacy"}`)
+	write("notes/todo.txt", "nothing to see here")
+
+	ws, err := New(root)
+	assert.NoError(t, err)
+	issues, err := ws.Scan()
+	assert.NoError(t, err)
+
+	kinds := map[string]string{}
+	for _, issue := range issues {
+		rel, _ := filepath.Rel(root, issue.Dir)
+		kinds[filepath.ToSlash(rel)] = issue.Kind
+	}
+	assert.Equal(t, map[string]string{
+		"go/missing": ScanMissingMetadata,
+		"go/corrupt": ScanCorruptMetadata,
+		"go/orphan":  ScanOrphanedLegacyMetadata,
+	}, kinds)
+}
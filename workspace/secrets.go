@@ -0,0 +1,99 @@
+package workspace
+
+import (
+	"bufio"
+	"bytes"
+	"math"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Secret is a line in a file that looks like it holds a credential.
+type Secret struct {
+	// Line is the number of the line, counting from 1.
+	Line int
+	// Kind describes what the secret looks like, e.g. "AWS access key ID".
+	Kind string
+}
+
+// secretPatterns recognize credentials that have a well-known shape.
+var secretPatterns = []struct {
+	kind    string
+	pattern *regexp.Regexp
+}{
+	{"AWS access key ID", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"AWS secret access key", regexp.MustCompile(`(?i)aws_?secret_?access_?key\s*[:=]\s*["']?[A-Za-z0-9/+=]{40}\b`)},
+	{"private key", regexp.MustCompile(`-----BEGIN ((RSA|EC|DSA|OPENSSH|ENCRYPTED|PGP) )?PRIVATE KEY( BLOCK)?-----`)},
+	{"GitHub token", regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+	{"Stripe key", regexp.MustCompile(`\b[sr]k_live_[A-Za-z0-9]{20,}\b`)},
+	{"Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`)},
+}
+
+// secretAssignment finds values assigned to names that suggest a credential.
+// The value only counts as a secret if it's random enough, so that placeholders
+// like "changeme" or "YOUR_API_KEY" don't get in the way.
+var secretAssignment = regexp.MustCompile(`(?i)(api_?key|secret|token|passw(or)?d|credentials?|auth)[A-Za-z0-9_]*["']?\s*(:=|=|:)\s*["']([^"'\s]{16,})["']`)
+
+// minSecretEntropy is the entropy, in bits per character, above which an assigned value looks random.
+// English words and identifiers are well below it; keys and tokens are above.
+const minSecretEntropy = 3.5
+
+// IsEnvFile tells whether the path is a dotenv file, which holds settings that are often secret.
+// Examples of such files, like .env.example, are fine.
+func IsEnvFile(path string) bool {
+	name := filepath.Base(path)
+	if name != ".env" && !strings.HasPrefix(name, ".env.") {
+		return false
+	}
+	switch strings.TrimPrefix(name, ".env.") {
+	case "example", "sample", "template", "dist":
+		return false
+	}
+	return true
+}
+
+// FindSecrets returns the lines of the contents that look like they hold credentials.
+func FindSecrets(contents []byte) []Secret {
+	var secrets []Secret
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(contents)+1)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if kind := secretKind(line); kind != "" {
+			secrets = append(secrets, Secret{Line: n, Kind: kind})
+		}
+	}
+	return secrets
+}
+
+func secretKind(line string) string {
+	for _, p := range secretPatterns {
+		if p.pattern.MatchString(line) {
+			return p.kind
+		}
+	}
+	for _, match := range secretAssignment.FindAllStringSubmatch(line, -1) {
+		if entropy(match[4]) >= minSecretEntropy {
+			return "high-entropy value assigned to " + match[1]
+		}
+	}
+	return ""
+}
+
+// entropy is the Shannon entropy of the string in bits per character.
+func entropy(s string) float64 {
+	counts := make(map[rune]int)
+	var total int
+	for _, r := range s {
+		counts[r]++
+		total++
+	}
+	var h float64
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		h -= p * math.Log2(p)
+	}
+	return h
+}
@@ -5,7 +5,6 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
-	"strings"
 )
 
 var errMissingMetadata = errors.New("no exercise metadata file found")
@@ -20,21 +19,34 @@ func IsMissingMetadata(err error) bool {
 // exercises that they've downloaded to look at or run locally.
 type Workspace struct {
 	Dir string
+	// ConceptDir is the name of the directory in the workspace that concept exercises
+	// are kept in, if they're kept apart from the practice exercises.
+	// Within it they are laid out as in the workspace itself.
+	ConceptDir string
 }
 
 // New returns a configured workspace.
 func New(dir string) (Workspace, error) {
-	_, err := os.Lstat(dir)
+	_, err := os.Lstat(LongPath(dir))
 	if err != nil {
 		return Workspace{}, err
 	}
-	dir, err = filepath.EvalSymlinks(dir)
+	dir, err = EvalSymlinks(dir)
 	if err != nil {
 		return Workspace{}, err
 	}
 	return Workspace{Dir: dir}, nil
 }
 
+// ExerciseFor is where the exercise with the given metadata belongs in the workspace.
+func (ws Workspace) ExerciseFor(metadata *ExerciseMetadata) Exercise {
+	root := ws.Dir
+	if ws.ConceptDir != "" && metadata.IsConcept() {
+		root = filepath.Join(ws.Dir, ws.ConceptDir)
+	}
+	return metadata.Exercise(root)
+}
+
 // PotentialExercises are a first-level guess at the user's exercises.
 // It looks at the workspace structurally, and guesses based on
 // the location of the directory. E.g. any top level directory
@@ -57,6 +69,16 @@ func (ws Workspace) PotentialExercises() ([]Exercise, error) {
 			continue
 		}
 
+		if ws.ConceptDir != "" && topInfo.Name() == ws.ConceptDir {
+			conceptWs := Workspace{Dir: filepath.Join(ws.Dir, ws.ConceptDir)}
+			conceptExercises, err := conceptWs.PotentialExercises()
+			if err != nil {
+				return nil, err
+			}
+			exercises = append(exercises, conceptExercises...)
+			continue
+		}
+
 		if topInfo.Name() == "teams" {
 			subInfos, err := ioutil.ReadDir(filepath.Join(ws.Dir, "teams"))
 			if err != nil {
@@ -120,24 +142,29 @@ func (ws Workspace) Exercises() ([]Exercise, error) {
 // ExerciseDir determines the root directory of an exercise.
 // This is the directory that contains the exercise metadata file.
 func (ws Workspace) ExerciseDir(s string) (string, error) {
-	if !strings.HasPrefix(s, ws.Dir) {
+	if !HasPathPrefix(s, ws.Dir) {
 		return "", errors.New("not in workspace")
 	}
 
 	path := s
 	for {
-		if path == ws.Dir {
+		if SamePath(path, ws.Dir) {
 			return "", errMissingMetadata
 		}
-		if _, err := os.Lstat(path); os.IsNotExist(err) {
+		if _, err := os.Lstat(LongPath(path)); os.IsNotExist(err) {
 			return "", err
 		}
-		if _, err := os.Lstat(filepath.Join(path, metadataFilepath)); err == nil {
+		if _, err := os.Lstat(LongPath(filepath.Join(path, metadataFilepath))); err == nil {
 			return path, nil
 		}
-		if _, err := os.Lstat(filepath.Join(path, legacyMetadataFilename)); err == nil {
+		if _, err := os.Lstat(LongPath(filepath.Join(path, legacyMetadataFilename))); err == nil {
 			return path, nil
 		}
-		path = filepath.Dir(path)
+		parent := filepath.Dir(path)
+		if parent == path {
+			// The root of a drive or share.
+			return "", errMissingMetadata
+		}
+		path = parent
 	}
 }
@@ -92,6 +92,42 @@ func TestWorkspaceExercises(t *testing.T) {
 	}
 }
 
+func TestWorkspaceConceptDir(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "walk-concepts")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	ws := Workspace{Dir: tmpDir, ConceptDir: "concepts"}
+
+	concept := ws.ExerciseFor(&ExerciseMetadata{Track: "go", ExerciseSlug: "lasagna", Type: ExerciseTypeConcept, IsRequester: true})
+	assert.Equal(t, filepath.Join(tmpDir, "concepts", "go", "lasagna"), concept.Filepath())
+
+	practice := ws.ExerciseFor(&ExerciseMetadata{Track: "go", ExerciseSlug: "leap", Type: ExerciseTypePractice, IsRequester: true})
+	assert.Equal(t, filepath.Join(tmpDir, "go", "leap"), practice.Filepath())
+
+	// Without a concept dir, concept exercises go with the others.
+	flat := Workspace{Dir: tmpDir}.ExerciseFor(&ExerciseMetadata{Track: "go", ExerciseSlug: "lasagna", Type: ExerciseTypeConcept, IsRequester: true})
+	assert.Equal(t, filepath.Join(tmpDir, "go", "lasagna"), flat.Filepath())
+
+	for _, path := range []string{concept.Filepath(), practice.Filepath()} {
+		err := os.MkdirAll(path, os.FileMode(0755))
+		assert.NoError(t, err)
+	}
+
+	exercises, err := ws.PotentialExercises()
+	assert.NoError(t, err)
+	if assert.Equal(t, 2, len(exercises)) {
+		paths := make([]string, len(exercises))
+		for i, e := range exercises {
+			paths[i] = e.Filepath()
+		}
+
+		sort.Strings(paths)
+		assert.Equal(t, concept.Filepath(), paths[0])
+		assert.Equal(t, practice.Filepath(), paths[1])
+	}
+}
+
 func TestExerciseDir(t *testing.T) {
 	_, cwd, _, _ := runtime.Caller(0)
 	root := filepath.Join(cwd, "..", "..", "fixtures", "solution-dir")